@@ -28,6 +28,7 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/pkg/utils"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	commonMgmt "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 
 	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	cpv1alpha2 "github.com/wso2/apk/common-go-libs/apis/cp/v1alpha2"
@@ -56,6 +57,7 @@ func PreRun(conf *config.Config, scheme *runtime.Scheme) {
 	utilruntime.Must(gwapiv1.Install(scheme))
 	utilruntime.Must(gwapiv1a2.Install(scheme))
 	utilruntime.Must(gwapiv1a3.Install(scheme))
+	managementserver.RegisterSnapshotStores()
 }
 
 // initializeAPKIntegrations sets up APK-specific integrations with the common agent
@@ -66,11 +68,12 @@ func initializeAPKIntegrations() {
 }
 
 // Run starts the GRPC server and Rest API server.
-func Run(conf *config.Config, mgr manager.Manager) {
+func Run(conf *config.Config, mgr manager.Manager) error {
 	loggers.LoggerAgent.Info("Starting APK Gateway Connector Agent...")
 	AgentMode := conf.Agent.Mode
 	loggers.LoggerAgent.Infof("Agent Mode: %s", AgentMode)
 	initializeAPKIntegrations()
+	metrics.RegisterCacheSize("applications", managementserver.GetApplicationCount)
 
 	go managementserver.StartInternalServer(restPort)
 
@@ -87,5 +90,5 @@ func Run(conf *config.Config, mgr manager.Manager) {
 	synchronizer.FetchAIProvidersOnEvent("", "", "", mgr.GetClient(), true)
 
 	// Load initial data from control plane
-	eventhub.LoadInitialData(conf, mgr.GetClient())
+	return eventhub.LoadInitialData(conf, mgr.GetClient())
 }
@@ -0,0 +1,89 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+package eventhub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func useAPIFetcher(t *testing.T, fetcher func(context.Context, *config.Config, *string, client.Client) (*[]string, error)) {
+	previous := fetchAPIs
+	fetchAPIs = fetcher
+	t.Cleanup(func() { fetchAPIs = previous })
+}
+
+func newRouteMetadataClient(t *testing.T, conf *config.Config, apiUUIDs ...string) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, dpv2alpha1.AddToScheme(scheme))
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, apiUUID := range apiUUIDs {
+		builder.WithObjects(&dpv2alpha1.RouteMetadata{ObjectMeta: metav1.ObjectMeta{Name: apiUUID,
+			Namespace: conf.DataPlane.GetLookupNamespace(), Labels: map[string]string{"apiUUID": apiUUID}}})
+	}
+	return builder.Build()
+}
+
+func listRouteMetadata(t *testing.T, k8sClient client.Client) []string {
+	routeMetadataList := &dpv2alpha1.RouteMetadataList{}
+	require.NoError(t, k8sClient.List(context.Background(), routeMetadataList))
+	names := make([]string, 0, len(routeMetadataList.Items))
+	for _, routeMetadata := range routeMetadataList.Items {
+		names = append(names, routeMetadata.Name)
+	}
+	return names
+}
+
+func TestFetchAPIsOnStartUpKeepsAPIsWhenFetchFails(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	k8sClient := newRouteMetadataClient(t, conf, "api-1", "api-2")
+
+	useAPIFetcher(t, func(context.Context, *config.Config, *string, client.Client) (*[]string, error) {
+		return nil, errors.New("control plane is unreachable")
+	})
+	assert.Error(t, FetchAPIsOnStartUp(conf, k8sClient))
+	assert.ElementsMatch(t, []string{"api-1", "api-2"}, listRouteMetadata(t, k8sClient))
+
+	useAPIFetcher(t, func(context.Context, *config.Config, *string, client.Client) (*[]string, error) {
+		return nil, nil
+	})
+	assert.Error(t, FetchAPIsOnStartUp(conf, k8sClient))
+	assert.ElementsMatch(t, []string{"api-1", "api-2"}, listRouteMetadata(t, k8sClient))
+}
+
+func TestFetchAPIsOnStartUpRemovesUndeployedAPIs(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	k8sClient := newRouteMetadataClient(t, conf, "api-1", "api-2")
+
+	useAPIFetcher(t, func(context.Context, *config.Config, *string, client.Client) (*[]string, error) {
+		return &[]string{"api-1"}, nil
+	})
+	assert.NoError(t, FetchAPIsOnStartUp(conf, k8sClient))
+	assert.Equal(t, []string{"api-1"}, listRouteMetadata(t, k8sClient))
+}
@@ -0,0 +1,74 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/k8sClient"
+	logger "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
+	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/utils"
+	"github.com/wso2/apk/common-go-libs/constants"
+	event "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of the cached data removed when an organization is purged
+const (
+	cachedApplicationKind           = "CachedApplication"
+	cachedApplicationMappingKind    = "CachedApplicationMapping"
+	cachedApplicationKeyMappingKind = "CachedApplicationKeyMapping"
+)
+
+// HandleOrganizationPurge removes the APIs, policies, backends and secrets of a purged organization and the
+// applications cached for it
+func HandleOrganizationPurge(organization msg.Organization, report *agent.PurgeReport, c client.Client) {
+	logger.LoggerMessaging.Infof("Organization purge event is received for organization: %s", report.Organization)
+	for _, identifier := range organization.Identifiers() {
+		internalk8sClient.UndeployOrganizationCRs(identifier, c, report)
+		purgeOrganizationApplications(identifier, report)
+	}
+}
+
+// purgeOrganizationApplications removes the cached applications of the organization and notifies the
+// removal to the enforcer
+func purgeOrganizationApplications(organization string, report *agent.PurgeReport) {
+	timeStamp := time.Now().UnixMilli()
+	for _, keyMapping := range managementserver.DeleteApplicationKeyMappingsByOrganization(organization) {
+		report.AddDeleted(cachedApplicationKeyMappingKind, keyMapping.ApplicationUUID+":"+keyMapping.KeyType)
+		go utils.SendEvent(&event.Event{Type: constants.ApplicationKeyMappingDeleted, Uuid: uuid.New().String(), TimeStamp: timeStamp,
+			ApplicationKeyMapping: &event.ApplicationKeyMapping{ApplicationUUID: keyMapping.ApplicationUUID, SecurityScheme: keyMapping.SecurityScheme,
+				ApplicationIdentifier: keyMapping.ApplicationIdentifier, KeyType: keyMapping.KeyType, Organization: keyMapping.Organization, EnvID: keyMapping.EnvID}})
+	}
+	for _, applicationMapping := range managementserver.DeleteApplicationMappingsByOrganization(organization) {
+		report.AddDeleted(cachedApplicationMappingKind, applicationMapping.UUID)
+		go utils.SendEvent(&event.Event{Type: constants.ApplicationMappingDeleted, Uuid: uuid.New().String(), TimeStamp: timeStamp,
+			ApplicationMapping: &event.ApplicationMapping{Uuid: applicationMapping.UUID, ApplicationRef: applicationMapping.ApplicationRef,
+				SubscriptionRef: applicationMapping.SubscriptionRef, Organization: applicationMapping.Organization}})
+	}
+	for _, application := range managementserver.DeleteApplicationsByOrganization(organization) {
+		report.AddDeleted(cachedApplicationKind, application.UUID)
+		go utils.SendEvent(&event.Event{Type: constants.ApplicationDeleted, Uuid: uuid.New().String(), TimeStamp: timeStamp,
+			Application: &event.Application{Uuid: application.UUID, Name: application.Name, Owner: application.Owner,
+				Organization: application.Organization, Attributes: application.Attributes}})
+	}
+}
@@ -30,13 +30,16 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/logging"
 	"github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/utils"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/cache"
 	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -60,7 +63,7 @@ func UndeployRouteMetadataCRs(apiID string, k8sClient client.Client) {
 		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
 	}
 	routeMetadataList := &dpv2alpha1.RouteMetadataList{}
-	err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{"apiUUID": apiID})})
+	err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{"apiUUID": apiID})})
 	// Retrieve all API CRs from the Kubernetes cluster
 	if err != nil {
 		loggers.LoggerK8sClient.Errorf("Unable to list RouteMetadata CRs: %v", err)
@@ -486,7 +489,7 @@ func UpdateRateLimitPolicyCR(policy eventhubTypes.RateLimitPolicy, k8sClient cli
 	labelMap := map[string]string{"kgw.wso2.com/cpInitiated": "true", "kgw.wso2.com/organization": policy.TenantDomain}
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(policy.TenantDomain),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	err := k8sClient.List(context.Background(), rlBackendTrafficPolicyList, listOption)
@@ -918,7 +921,7 @@ func UpdateSecurityPolicyCRs(keymanagerName string, tenantDomain string, k8sClie
 	// SecurityPolicy CRs are filtered by organization
 	labelMap := map[string]string{"kgw.wso2.com/organization": tenantDomain}
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(tenantDomain),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 
@@ -1056,7 +1059,7 @@ func UpdateSecurityPolicyCR(keyManager eventhubTypes.ResolvedKeyManager, k8sClie
 
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(keyManager.Organization),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 
@@ -1198,7 +1201,7 @@ func RetrieveAllRouteMetasFromK8s(k8sClient client.Client, nextToken string) ([]
 	conf, _ := config.ReadConfigs()
 	routeMetaList := dpv2alpha1.RouteMetadataList{}
 	err := k8sClient.List(context.Background(), &routeMetaList, &client.ListOptions{
-		Namespace: conf.DataPlane.Namespace,
+		Namespace: conf.DataPlane.GetLookupNamespace(),
 	})
 
 	if err != nil {
@@ -1236,7 +1239,7 @@ func RetrieveAllRatelimitPoliciesSFromK8s(ratelimitName string, organization str
 	// !!! Might need to change this later
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(organization),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	var err error
@@ -1260,7 +1263,7 @@ func RetrieveAllAIRatelimitPoliciesSFromK8s(aiRatelimitName string, organization
 	// !!! Might need to change this later
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(organization),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	var err error
@@ -1757,3 +1760,79 @@ func UnDeploySharedSubscriptionRateLimitPolicyCR(policyName string, tenantDomain
 		}
 	}
 }
+
+// UndeployOrganizationCRs removes the Custom Resources of an organization from the Kubernetes cluster and records
+// them in the purge report. Deleting the RouteMetadata CRs of the organization removes the API CRs owned by them,
+// while the policies, backends and secrets created from the control plane are found by the organization label.
+func UndeployOrganizationCRs(organization string, k8sClient client.Client, report *agent.PurgeReport) {
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
+	}
+
+	namespace := conf.DataPlane.GetOrganizationNamespace(organization)
+	routeMetadataList := &dpv2alpha1.RouteMetadataList{}
+	if err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: namespace}); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list RouteMetadata CRs: %v", err)
+		report.AddFailed("RouteMetadata", organization)
+	} else {
+		for _, routeMetadata := range routeMetadataList.Items {
+			if routeMetadata.Spec.API.Organization != organization {
+				continue
+			}
+			if err := k8sClient.Delete(context.Background(), &routeMetadata, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+				loggers.LoggerK8sClient.Errorf("Unable to delete RouteMetadata CR %s: %v", routeMetadata.Name, err)
+				report.AddFailed("RouteMetadata", routeMetadata.Name)
+				continue
+			}
+			loggers.LoggerK8sClient.Infof("Deleted RouteMetadata CR: %s", routeMetadata.Name)
+			report.AddDeleted("RouteMetadata", routeMetadata.Name)
+		}
+	}
+
+	selector := labels.SelectorFromSet(map[string]string{
+		"kgw.wso2.com/organization": organization,
+		"kgw.wso2.com/cpInitiated":  "true",
+	})
+	for _, resourceType := range []struct {
+		list client.ObjectList
+		kind string
+	}{
+		{&gatewayv1alpha1.BackendTrafficPolicyList{}, "BackendTrafficPolicy"},
+		{&gatewayv1alpha1.SecurityPolicyList{}, "SecurityPolicy"},
+		{&gwapiv1a3.BackendTLSPolicyList{}, "BackendTLSPolicy"},
+		{&gatewayv1alpha1.BackendList{}, "Backend"},
+		{&corev1.SecretList{}, "Secret"},
+	} {
+		purgeResources(resourceType.list, resourceType.kind, namespace, selector, k8sClient, report)
+	}
+}
+
+// purgeResources deletes the resources of the given kind in the namespace that match the label selector
+func purgeResources(resourceList client.ObjectList, kind string, namespace string, selector labels.Selector,
+	k8sClient client.Client, report *agent.PurgeReport) {
+	listOpts := &client.ListOptions{Namespace: namespace, LabelSelector: selector}
+	if err := k8sClient.List(context.Background(), resourceList, listOpts); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list %s CRs: %v", kind, err)
+		report.AddFailed(kind, selector.String())
+		return
+	}
+	err := meta.EachListItem(resourceList, func(item runtime.Object) error {
+		resource, ok := item.(client.Object)
+		if !ok {
+			return nil
+		}
+		if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Errorf("Unable to delete %s CR %s: %v", kind, resource.GetName(), err)
+			report.AddFailed(kind, resource.GetName())
+			return nil
+		}
+		loggers.LoggerK8sClient.Infof("Deleted %s CR: %s", kind, resource.GetName())
+		report.AddDeleted(kind, resource.GetName())
+		return nil
+	})
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to read the listed %s CRs: %v", kind, err)
+		report.AddFailed(kind, selector.String())
+	}
+}
@@ -24,19 +24,23 @@
 package mapper
 
 import (
+	"context"
+
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/pkg/transformer"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // MapAndCreateCR will read the CRD Yaml and based on the Kind of the CR, unmarshal and maps the
 // data and sends to the K8-Client for creating the respective CR inside the cluster
-func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Client) *error {
-	namespace, err := getDeploymentNamespace(k8sArtifact)
-	logger.LoggerMapper.Debugf("Namespace: %s", namespace)
+func MapAndCreateCR(ctx context.Context, k8sArtifact transformer.K8sArtifacts, k8sClient client.Client) *error {
+	namespace, gatewayNamespace, err := getDeploymentNamespace(ctx, k8sArtifact)
+	logger.LoggerMapper.FromContext(ctx).Debugf("Namespace: %s", namespace)
 	if err != nil {
 		return &err
 	}
@@ -49,7 +53,7 @@ func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Clien
 		Name:       routeMeta.ObjectMeta.Name,
 		UID:        uid,
 	}
-	logger.LoggerMapper.Debugf("OwnerRef: %+v", ownerRef)
+	logger.LoggerMapper.FromContext(ctx).Debugf("OwnerRef: %+v", ownerRef)
 
 	for _, configMaps := range k8sArtifact.ConfigMaps {
 		configMaps.Namespace = namespace
@@ -61,6 +65,7 @@ func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Clien
 	}
 	for _, httpRoutes := range k8sArtifact.HTTPRoutes {
 		httpRoutes.Namespace = namespace
+		setGatewayNamespace(httpRoutes.Spec.ParentRefs, namespace, gatewayNamespace)
 		internalk8sClient.DeployHTTPRouteCR(httpRoutes, ownerRef, k8sClient)
 	}
 	for _, httpRouteFilters := range k8sArtifact.HTTPRouteFilters {
@@ -93,15 +98,35 @@ func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Clien
 	}
 	for _, grpcRoute := range k8sArtifact.GRPCRoutes {
 		grpcRoute.Namespace = namespace
+		setGatewayNamespace(grpcRoute.Spec.ParentRefs, namespace, gatewayNamespace)
 		internalk8sClient.DeployGRPCRouteCR(grpcRoute, ownerRef, k8sClient)
 	}
 	return nil
 }
-func getDeploymentNamespace(k8sArtifact transformer.K8sArtifacts) (string, error) {
+
+// getDeploymentNamespace returns the namespace of the organization the API belongs to along with the namespace
+// of the gateway
+func getDeploymentNamespace(ctx context.Context, k8sArtifact transformer.K8sArtifacts) (string, string, error) {
 	conf, errReadConfig := config.ReadConfigs()
 	if errReadConfig != nil {
-		logger.LoggerMapper.Errorf("Error reading configs: %v", errReadConfig)
-		return "", errReadConfig
+		logger.LoggerMapper.FromContext(ctx).Errorf("Error reading configs: %v", errReadConfig)
+		return "", "", errReadConfig
+	}
+	organization := ""
+	if k8sArtifact.RouteMetadata != nil {
+		organization = k8sArtifact.RouteMetadata.Spec.API.Organization
+	}
+	return conf.DataPlane.GetOrganizationNamespace(organization), conf.DataPlane.Namespace, nil
+}
+
+// setGatewayNamespace points the routes deployed outside the gateway namespace to the gateway
+func setGatewayNamespace(parentRefs []gwapiv1.ParentReference, namespace string, gatewayNamespace string) {
+	if namespace == gatewayNamespace {
+		return
+	}
+	for i := range parentRefs {
+		if parentRefs[i].Namespace == nil {
+			parentRefs[i].Namespace = ptr.To(gwapiv1.Namespace(gatewayNamespace))
+		}
 	}
-	return conf.DataPlane.Namespace, nil
 }
@@ -24,6 +24,7 @@
 package synchronizer
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
@@ -31,8 +32,12 @@ import (
 	logger "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/loggers"
 	apkTransformer "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/pkg/transformer"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 	sync "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/synchronizer"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 	transformer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	mapperUtil "github.com/wso2-extensions/apim-gw-connectors/apk/gateway-connector/internal/mapper"
@@ -43,16 +48,17 @@ func init() {
 	sync.InitializeWorkerPool(conf.ControlPlane.RequestWorkerPool.PoolSize, conf.ControlPlane.RequestWorkerPool.QueueSizePerPool,
 		conf.ControlPlane.RequestWorkerPool.PauseTimeAfterFailure, conf.Agent.TrustStore.Location,
 		conf.ControlPlane.SkipSSLVerification, conf.ControlPlane.HTTPClient.RequestTimeOut, conf.ControlPlane.RetryInterval,
-		conf.ControlPlane.ServiceURL, conf.ControlPlane.Username, conf.ControlPlane.Password)
+		conf.ControlPlane.ServiceURL, conf.ControlPlane.Username, conf.ControlPlane.Password,
+		sync.WorkerPoolOptionsFromConfig(conf))
 }
 
 // FetchAPIsOnEvent  will fetch API from control plane during the API Notification Event
-func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Client) (*[]string, error) {
+func FetchAPIsOnEvent(ctx context.Context, conf *config.Config, apiUUID *string, k8sClient client.Client) (*[]string, error) {
 	// Populate data from config.
 	apis := make([]string, 0)
 	// Common agent logic for control plane communication and artifact fetching
 	// including Handles HTTP requests, ZIP processing, and retry logic
-	apiResult, err := sync.FetchAPIsOnEvent(conf, apiUUID, k8sClient)
+	apiResult, err := sync.FetchAPIsOnEvent(ctx, conf, apiUUID, k8sClient)
 	if err != nil {
 		return nil, err
 	}
@@ -61,36 +67,44 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 		apis = *apiResult.APIs
 		if apiResult.APIDeployments != nil {
 			for _, apiDeployment := range *apiResult.APIDeployments {
+				deployCtx := logging.ContextWithFields(ctx, logging.Fields{logging.ORGANIZATION: apiDeployment.OrganizationID})
 				apiZip, exists := apiResult.APIFiles[apiDeployment.APIFile]
 				if exists {
 					artifact, decodingError := transformer.DecodeAPIArtifact(apiZip)
 					if decodingError != nil {
-						logger.LoggerUtils.Errorf("Error while decoding the API Project Artifact: %v", decodingError)
+						logger.LoggerUtils.FromContext(deployCtx).Errorf("Error while decoding the API Project Artifact: %v", decodingError)
 						return nil, err
 					}
 
-					logger.LoggerUtils.Infof("Environments: %+v", apiDeployment.Environments)
+					logger.LoggerUtils.FromContext(deployCtx).Infof("Environments: %+v", apiDeployment.Environments)
 					envLabel := "Default" // fallback default
 					if apiDeployment.Environments != nil && len(*apiDeployment.Environments) > 0 {
 						envLabel = (*apiDeployment.Environments)[0].Name
 					}
-					logger.LoggerUtils.Infof("Selected Environment Label: %s", envLabel)
+					logger.LoggerUtils.FromContext(deployCtx).Infof("Selected Environment Label: %s", envLabel)
 
+					transformCtx, transformSpan := tracing.StartSpan(deployCtx, "TransformAPI",
+						attribute.String("organization", apiDeployment.OrganizationID))
 					apkConf, _, apiUUID, revisionID, configuredRateLimitPoliciesMap, endpointSecurityData, api, prodAIRL, sandAIRL, apkErr := transformer.GenerateConf(artifact.APIJson, artifact.CertArtifact, artifact.Endpoints, apiDeployment.OrganizationID, envLabel)
+					deployCtx = logging.ContextWithFields(deployCtx, logging.Fields{
+						logging.APIUUID:    apiUUID,
+						logging.REVISIONID: fmt.Sprint(revisionID),
+					})
 					if prodAIRL == nil {
 						// Try to delete production AI ratelimit for this api
 						// !!!TODO: Might hava to change the implementation becuase now we use BackendTrafficPolicy + RoutePolicy
 						// k8sclientUtil.DeleteAIRatelimitPolicy(generateSHA1HexHash(api.Name, api.Version, "production"), k8sClient)
-						logger.LoggerUtils.Debugf("Trying to delete production AI ratelimit for API: %v", api.Name)
+						logger.LoggerUtils.FromContext(deployCtx).Debugf("Trying to delete production AI ratelimit for API: %v", api.Name)
 					}
 					if sandAIRL == nil {
 						// Try to delete sandbox AI ratelimit for this api
 						// !!!TODO: Might hava to change the implementation becuase now we use BackendTrafficPolicy + RoutePolicy
 						// k8sclientUtil.DeleteAIRatelimitPolicy(generateSHA1HexHash(api.Name, api.Version, "sandbox"), k8sClient)
-						logger.LoggerUtils.Debugf("Trying to delete sandbox AI ratelimit for API: %v", api.Name)
+						logger.LoggerUtils.FromContext(deployCtx).Debugf("Trying to delete sandbox AI ratelimit for API: %v", api.Name)
 					}
 					if apkErr != nil {
-						logger.LoggerUtils.Errorf("Unable to generate APK-Conf: %+v", apkErr)
+						tracing.EndSpan(transformSpan, apkErr)
+						logger.LoggerUtils.FromContext(deployCtx).Errorf("Unable to generate APK-Conf: %+v", apkErr)
 						return nil, err
 					}
 					certContainer := transformer.CertContainer{
@@ -99,16 +113,28 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 						SecretData:      endpointSecurityData,
 					}
 					k8ResourceEndpoint := conf.DataPlane.K8ResourceEndpoint
+					_, generateSpan := tracing.StartSpan(transformCtx, "GenerateCRs")
 					crResponse, err := apkTransformer.GenerateCRs(apkConf, artifact.Schema, certContainer, k8ResourceEndpoint, apiDeployment.OrganizationID)
+					tracing.EndSpan(generateSpan, err)
 					if err != nil {
-						logger.LoggerUtils.Errorf("Error occured in receiving the updated CRDs: %+v", err)
+						tracing.EndSpan(transformSpan, err)
+						logger.LoggerUtils.FromContext(deployCtx).Errorf("Error occured in receiving the updated CRDs: %+v", err)
 						return nil, err
 					}
-					logger.LoggerUtils.Debugf("\nAPK Conf: \n%+v\n", apkConf)
+					logger.LoggerUtils.FromContext(deployCtx).Debugf("\nAPK Conf: \n%+v\n", apkConf)
 					apkTransformer.UpdateCRS(crResponse, apiDeployment.Environments, apiDeployment.OrganizationID, apiUUID, fmt.Sprint(revisionID), "namespace", configuredRateLimitPoliciesMap)
-					mapperUtil.MapAndCreateCR(*crResponse, k8sClient)
+					transformSpan.End()
+					applyCtx, applySpan := tracing.StartSpan(deployCtx, "MapAndCreateCR", attribute.String("api.uuid", apiUUID))
+					var applyErr error
+					if errMapping := mapperUtil.MapAndCreateCR(applyCtx, *crResponse, k8sClient); errMapping != nil {
+						applyErr = *errMapping
+					}
+					tracing.EndSpan(applySpan, applyErr)
+					if applyErr == nil {
+						metrics.ObserveDeploymentLatency(ctx)
+					}
 					apis = append(apis, apiUUID)
-					logger.LoggerUtils.Info("API applied successfully.\n")
+					logger.LoggerUtils.FromContext(deployCtx).Info("API applied successfully.\n")
 				}
 			}
 		}
@@ -127,10 +153,10 @@ func generateSHA1HexHash(name, version, env string) string {
 }
 
 // GetAPI function calls the FetchAPIs() with relevant environment labels defined in the config.
-func GetAPI(c chan sync.SyncAPIResponse, id *string, envs []string, endpoint string, sendType bool) {
+func GetAPI(ctx context.Context, c chan sync.SyncAPIResponse, id *string, envs []string, endpoint string, sendType bool) {
 	if len(envs) > 0 {
 		// If the envrionment labels are present, call the controle plane with labels.
 		logger.LoggerUtils.Debugf("Environment labels present: %v", envs)
-		go sync.FetchAPIs(id, envs, c, endpoint, sendType)
+		go sync.FetchAPIs(ctx, id, envs, c, endpoint, sendType)
 	}
 }
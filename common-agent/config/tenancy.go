@@ -0,0 +1,87 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxNamespaceLength is the maximum length of a Kubernetes namespace name (RFC 1123 label)
+	maxNamespaceLength = 63
+	// namespaceHashLength is the length of the organization hash appended to the shortened namespace names
+	namespaceHashLength = 8
+)
+
+var invalidNamespaceChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ServesOrganization checks whether the events and artifacts of the organization are handled by this agent
+func (d dataPlane) ServesOrganization(organization string) bool {
+	if len(d.Organizations) == 0 {
+		return true
+	}
+	return slices.Contains(d.Organizations, organization)
+}
+
+// IsNamespacePerOrganization checks whether the resources of the organizations are spread over namespaces
+func (d dataPlane) IsNamespacePerOrganization() bool {
+	return d.NamespacePerOrganization || len(d.OrganizationNamespaces) > 0
+}
+
+// GetOrganizationNamespace returns the namespace the resources of the organization are deployed to. The
+// organizations which are neither mapped nor isolated share the data plane namespace.
+func (d dataPlane) GetOrganizationNamespace(organization string) string {
+	if namespace, ok := d.OrganizationNamespaces[organization]; ok && namespace != "" {
+		return namespace
+	}
+	if d.NamespacePerOrganization && organization != "" {
+		return toNamespaceName(organization)
+	}
+	return d.Namespace
+}
+
+// GetLookupNamespace returns the namespace to search when the organization of a resource is not known. All the
+// namespaces are searched when the resources of the organizations are spread over namespaces.
+func (d dataPlane) GetLookupNamespace() string {
+	if d.IsNamespacePerOrganization() {
+		return ""
+	}
+	return d.Namespace
+}
+
+// toNamespaceName converts the organization to a valid namespace name. Names that have to be altered are
+// suffixed with a hash of the organization so that two organizations do not end up in the same namespace.
+func toNamespaceName(organization string) string {
+	name := strings.Trim(invalidNamespaceChars.ReplaceAllString(strings.ToLower(organization), "-"), "-")
+	if name == organization && len(name) <= maxNamespaceLength {
+		return name
+	}
+	hash := sha1.Sum([]byte(organization))
+	suffix := hex.EncodeToString(hash[:])[:namespaceHashLength]
+	if maxLength := maxNamespaceLength - namespaceHashLength - 1; len(name) > maxLength {
+		name = strings.TrimRight(name[:maxLength], "-")
+	}
+	if name == "" {
+		return suffix
+	}
+	return name + "-" + suffix
+}
@@ -0,0 +1,56 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServesOrganization(t *testing.T) {
+	assert.True(t, dataPlane{}.ServesOrganization("carbon.super"))
+
+	restricted := dataPlane{Organizations: []string{"carbon.super", "wso2.com"}}
+	assert.True(t, restricted.ServesOrganization("wso2.com"))
+	assert.False(t, restricted.ServesOrganization("example.com"))
+	assert.False(t, restricted.ServesOrganization(""))
+}
+
+func TestGetOrganizationNamespace(t *testing.T) {
+	shared := dataPlane{Namespace: "apk"}
+	assert.Equal(t, "apk", shared.GetOrganizationNamespace("wso2.com"))
+	assert.Equal(t, "apk", shared.GetLookupNamespace())
+
+	mapped := dataPlane{Namespace: "apk", OrganizationNamespaces: map[string]string{"wso2.com": "wso2"}}
+	assert.Equal(t, "wso2", mapped.GetOrganizationNamespace("wso2.com"))
+	assert.Equal(t, "apk", mapped.GetOrganizationNamespace("example.com"))
+	assert.Equal(t, "", mapped.GetLookupNamespace())
+
+	isolated := dataPlane{Namespace: "apk", NamespacePerOrganization: true}
+	assert.Equal(t, "tenant1", isolated.GetOrganizationNamespace("tenant1"))
+	assert.Equal(t, "apk", isolated.GetOrganizationNamespace(""))
+
+	// Organizations that are not valid namespace names are suffixed with a hash to keep them apart
+	assert.Regexp(t, `^wso2-com-[0-9a-f]{8}$`, isolated.GetOrganizationNamespace("wso2.com"))
+	assert.NotEqual(t, isolated.GetOrganizationNamespace("wso2.com"), isolated.GetOrganizationNamespace("WSO2.com"))
+	long := isolated.GetOrganizationNamespace(strings.Repeat("a", 100))
+	assert.LessOrEqual(t, len(long), maxNamespaceLength)
+	assert.Regexp(t, `^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`, long)
+}
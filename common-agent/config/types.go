@@ -78,6 +78,14 @@ type Config struct {
 	Agent        agent        `toml:"agent"`
 	// Metric represents configurations to expose/export go metrics
 	Metrics      metrics      `toml:"metrics"`
+	// Tracing represents configurations to export the traces of the event processing
+	Tracing      tracing      `toml:"tracing"`
+	// HealthProbe represents configurations of the HTTP liveness and readiness endpoints
+	HealthProbe healthProbe `toml:"healthProbe"`
+	// LeaderElection represents configurations to elect a leader among the agent replicas
+	LeaderElection leaderElection `toml:"leaderElection"`
+	// Snapshot represents configurations to persist the in-memory stores on disk
+	Snapshot snapshot `toml:"snapshot"`
 	GatewayAgent gatewayAgent `toml:"gatewayAgent"`
 }
 type agent struct {
@@ -133,6 +141,19 @@ type dataPlane struct {
 	GatewayClassName   string
 	GatewayHTTPSPort   int
 	GatewayHTTPPort    int
+	// FallbackToK8ResourceEndpoint generates the CRs using the K8ResourceEndpoint when the gateway connector
+	// is unable to generate them in-process
+	FallbackToK8ResourceEndpoint bool
+	// SkipSSLVerification skips the certificate validation of the K8ResourceEndpoint
+	SkipSSLVerification bool
+	// Organizations restricts the agent to the events and artifacts of the listed organizations. The agent
+	// serves all the organizations when it is empty
+	Organizations []string
+	// NamespacePerOrganization deploys the resources of each organization to a namespace of its own, named
+	// after the organization unless it is mapped in OrganizationNamespaces
+	NamespacePerOrganization bool
+	// OrganizationNamespaces maps organizations to the namespaces their resources are deployed to
+	OrganizationNamespaces map[string]string
 }
 
 type requestWorkerPool struct {
@@ -141,11 +162,36 @@ type requestWorkerPool struct {
 	QueueSizePerPool int
 	// PauseTimeAfterFailure is the time duration to pause the worker pool after a failure
 	PauseTimeAfterFailure time.Duration
+	// BlockWhenFull makes the requests wait for a free slot when the queue is full instead of being dropped
+	BlockWhenFull bool
+	// EnqueueTimeout is the time duration (in seconds) a request waits for a free slot in the queue. A request
+	// waits until it is cancelled when the timeout is zero.
+	EnqueueTimeout time.Duration
+	// MaxRetries is the number of retries of a request failed due to a transient control plane error
+	MaxRetries int
+	// MaxRetryInterval is the upper bound (in seconds) of the exponential backoff between the retries
+	MaxRetryInterval time.Duration
 }
 type brokerConnectionParameters struct {
+	// BrokerType is the type of the broker the control plane events are consumed from, one of amqp,
+	// kafka or nats
+	BrokerType              string
 	EventListeningEndpoints []string
 	ReconnectInterval       time.Duration
 	ReconnectRetryCount     int
+	// DurableQueues declares named durable queues so that the events published while the agent is
+	// reconnecting or restarting are delivered once it is back
+	DurableQueues bool
+	// QueueNamePrefix identifies the agent instance in the durable queue names and must be unique per
+	// instance, as the instances sharing a queue split its events. It must also be stable across restarts,
+	// hence the STATEFULSET_POD_NAME environment variable is used when empty, and the agent does not consume
+	// the events from durable queues when neither is set.
+	QueueNamePrefix string
+	// MaxRedeliveryCount is the number of times a failed event is redelivered before it is moved to
+	// the dead letter queue
+	MaxRedeliveryCount int
+	// TopicPrefix is prepended to the topic or subject names of the kafka and nats brokers
+	TopicPrefix string
 }
 
 type httpClient struct {
@@ -159,6 +205,46 @@ type metrics struct {
 	Port    int32
 }
 
+// Tracing defines the configuration for exporting the OpenTelemetry traces.
+type tracing struct {
+	Enabled bool
+	// Exporter is the exporter the spans are sent through, one of otlp or stdout
+	Exporter string
+	// Endpoint is the host and port of the OTLP HTTP collector
+	Endpoint string
+	// Insecure sends the spans to the OTLP collector over plain HTTP
+	Insecure    bool
+	ServiceName string
+	// SamplingRatio is the fraction of the traces that are sampled, between 0 and 1
+	SamplingRatio float64
+}
+
+// HealthProbe defines the configuration of the /healthz and /readyz endpoints.
+type healthProbe struct {
+	Enabled bool
+	Port    int32
+}
+
+// LeaderElection defines the configuration for electing a leader among the agent replicas. The replicas which are
+// not the leader are reported as not ready.
+type leaderElection struct {
+	Enabled bool
+	// LeaseName is the name of the Lease resource used for the election
+	LeaseName string
+	// Namespace of the Lease resource. The namespace of the agent is used when it is not set.
+	Namespace string
+}
+
+// Snapshot defines the configuration for persisting the in-memory stores on disk. The stores are restored from the
+// snapshot on startup and reconciled against the control plane once it is reachable.
+type snapshot struct {
+	Enabled bool
+	// Directory is the directory the snapshot files are written to
+	Directory string
+	// Interval is the interval in seconds at which the changed stores are saved
+	Interval time.Duration
+}
+
 // Certificates struct contains the configurations related to the certificates
 type certificates struct {
 	CaCertSecretName string
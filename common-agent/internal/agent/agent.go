@@ -35,9 +35,14 @@ import (
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/loggers"
 	logging "github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/logging"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/cache"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/health"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 	"github.com/wso2/apk/common-go-libs/loggers"
 	"github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
 	"google.golang.org/grpc"
@@ -47,9 +52,11 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
-	healthservice "github.com/wso2/apk/adapter/pkg/health/api/wso2/health/service"
+	healthservice "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 var (
@@ -66,6 +73,7 @@ const (
 	ads                      = "ads"
 	amqpProtocol             = "amqp"
 	grpcMaxConcurrentStreams = 1000000
+	defaultSnapshotInterval  = 30 * time.Second
 )
 
 func init() {
@@ -109,7 +117,17 @@ func Run(conf *config.Config) {
 	logger.LoggerAgent.Info("Starting apim-agent ....")
 	eventHubEnabled := conf.ControlPlane.Enabled
 
-	var probeAddr string
+	shutdownTracer, err := tracing.InitTracer(conf)
+	if err != nil {
+		logger.LoggerAgent.Errorf("Error initializing the tracer, continuing without tracing: %v", err)
+	} else {
+		defer func() {
+			if err := shutdownTracer(context.Background()); err != nil {
+				logger.LoggerAgent.Errorf("Error flushing the traces: %v", err)
+			}
+		}()
+	}
+
 	var scheme = runtime.NewScheme()
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(gwapiv1.Install(scheme))
@@ -119,9 +137,16 @@ func Run(conf *config.Config) {
 	agent.PreRun(conf, scheme)
 	logger.LoggerAgent.Info("PreRunning complete...")
 
+	if conf.Snapshot.Enabled {
+		snapshotSaved := loadSnapshot(ctx, conf)
+		defer func() {
+			cancel()
+			<-snapshotSaved
+		}()
+	}
+
 	options := ctrl.Options{
-		Scheme:                 scheme,
-		HealthProbeBindAddress: probeAddr,
+		Scheme: scheme,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -135,12 +160,24 @@ func Run(conf *config.Config) {
 		// LeaderElectionReleaseOnCancel: true,
 	}
 
+	if conf.HealthProbe.Enabled {
+		options.HealthProbeBindAddress = fmt.Sprintf(":%d", conf.HealthProbe.Port)
+	}
+	if conf.LeaderElection.Enabled {
+		options.LeaderElection = true
+		options.LeaderElectionID = conf.LeaderElection.LeaseName
+		options.LeaderElectionNamespace = conf.LeaderElection.Namespace
+	}
+
 	if conf.Metrics.Enabled {
 		options.Metrics.BindAddress = fmt.Sprintf(":%d", conf.Metrics.Port)
 		// Register the metrics collector
 		if strings.EqualFold(conf.Metrics.Type, metrics.PrometheusMetricType) {
 			loggers.LoggerAPKOperator.Info("Registering Prometheus metrics collector.")
 			metrics.RegisterPrometheusCollector()
+			metrics.RegisterCacheSize("subscriptions", managementserver.GetSubscriptionCount)
+			metrics.RegisterCacheSize("keyManagers", cache.GetKeyManagerCacheInstance().GetKeyManagerCount)
+			options.NewClient = metrics.NewInstrumentedClient
 		}
 	} else {
 		options.Metrics.BindAddress = "0"
@@ -151,11 +188,19 @@ func Run(conf *config.Config) {
 	if err != nil {
 		logger.LoggerAgent.Error("unable to start kubernetes controller manager", err)
 	}
+	if conf.HealthProbe.Enabled {
+		addHealthChecks(conf, mgr)
+	}
 
 	if eventHubEnabled {
 		var connectionURLList = conf.ControlPlane.BrokerConnectionParameters.EventListeningEndpoints
-		if strings.Contains(connectionURLList[0], amqpProtocol) {
+		// The AMQP broker requires AMQP URLs while the Kafka and NATS brokers are listed by their addresses
+		brokerType := strings.ToLower(conf.ControlPlane.BrokerConnectionParameters.BrokerType)
+		if (brokerType != "" && brokerType != msg.AMQPBrokerType) || strings.Contains(connectionURLList[0], amqpProtocol) {
 			go messaging.ProcessEvents(conf, mgr.GetClient(), agent)
+			if conf.HealthProbe.Enabled {
+				addReadyzCheck(mgr, "broker", health.BrokerConnectionService.Checker)
+			}
 		}
 	}
 
@@ -229,7 +274,17 @@ func Run(conf *config.Config) {
 
 	// run agent specific functions
 	logger.LoggerAgent.Info("Running gateway specific agent...")
-	agent.Run(conf, mgr)
+	if err := agent.Run(conf, mgr); err != nil {
+		logger.LoggerAgent.Errorf("Error loading the startup data, the agent is not ready: %v", err)
+	} else {
+		health.StartupDataLoadService.SetStatus(true)
+	}
+	if conf.Snapshot.Enabled {
+		// The stores restored from the snapshot are reconciled against the control plane by the startup data load
+		if err := snapshot.Save(conf.Snapshot.Directory); err != nil {
+			logger.LoggerAgent.Errorf("Error saving the snapshot after loading the startup data: %v", err)
+		}
+	}
 OUTER:
 	for {
 		select {
@@ -250,3 +305,51 @@ OUTER:
 	}
 	logger.LoggerAgent.Info("Bye!")
 }
+
+// loadSnapshot restores the in-memory stores from the snapshot so that they can be served before the control plane
+// is reachable, and saves the changed stores periodically until the context is done. The returned channel is closed
+// once the final snapshot is saved.
+func loadSnapshot(ctx context.Context, conf *config.Config) <-chan struct{} {
+	managementserver.RegisterSnapshotStores()
+	cache.GetKeyManagerCacheInstance().RegisterSnapshotStore()
+	sequencer.RegisterSnapshotStore()
+	restored, err := snapshot.Load(conf.Snapshot.Directory)
+	if err != nil {
+		logger.LoggerAgent.Errorf("Error loading the snapshot from %s: %v", conf.Snapshot.Directory, err)
+	}
+	logger.LoggerAgent.Infof("Restored %d stores from the snapshot in %s: %v", len(restored),
+		conf.Snapshot.Directory, restored)
+
+	interval := conf.Snapshot.Interval * time.Second
+	if interval <= 0 {
+		logger.LoggerAgent.Warnf("Invalid snapshot interval %v, using the default interval", conf.Snapshot.Interval)
+		interval = defaultSnapshotInterval
+	}
+	saved := make(chan struct{})
+	go func() {
+		defer close(saved)
+		snapshot.Run(ctx, conf.Snapshot.Directory, interval)
+	}()
+	return saved
+}
+
+// addHealthChecks registers the checks of the /healthz and /readyz endpoints of the controller manager. The agent
+// is ready once the startup data is loaded from the control plane and the informer cache is synced. When leader
+// election is enabled, only the leader is ready.
+func addHealthChecks(conf *config.Config, mgr manager.Manager) {
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		logger.LoggerAgent.Errorf("Unable to add the liveness check: %v", err)
+	}
+	addReadyzCheck(mgr, "startupData", health.StartupDataLoadService.Checker)
+	addReadyzCheck(mgr, "keyManagers", health.KeyManagerFetchService.Checker)
+	addReadyzCheck(mgr, "informers", health.CacheSyncChecker(mgr.GetCache()))
+	if conf.LeaderElection.Enabled {
+		addReadyzCheck(mgr, "leader", health.LeaderChecker(mgr.Elected()))
+	}
+}
+
+func addReadyzCheck(mgr manager.Manager, name string, check healthz.Checker) {
+	if err := mgr.AddReadyzCheck(name, check); err != nil {
+		logger.LoggerAgent.Errorf("Unable to add the %s readiness check: %v", name, err)
+	}
+}
@@ -38,4 +38,5 @@ const (
 	CommonType = "common"
 
 	DefaultKongAgentName = "Kong"
+	DefaultEGAgentName   = "EnvoyGateway"
 )
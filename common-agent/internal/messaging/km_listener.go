@@ -19,20 +19,31 @@
 package messaging
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
 	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // handleKMConfiguration
 func handleKMConfiguration(c client.Client, agent agent.Agent) {
+	conf, err := config.ReadConfigs()
+	if err != nil {
+		logger.LoggerMessaging.Errorf("Error while reading the configs, filtering the key manager events by the default configs: %v", err)
+	}
 	for d := range msg.KeyManagerChannel {
 		var notification msg.EventKeyManagerNotification
 		var keyManager eventhubTypes.KeyManager
@@ -43,42 +54,84 @@ func handleKMConfiguration(c client.Client, agent agent.Agent) {
 				Severity:  logging.CRITICAL,
 				ErrorCode: 2000,
 			})
-			return
+			d.Nack(false)
+			continue
+		}
+		eventType := notification.Event.PayloadData.EventType
+		ctx := logging.ContextWithFields(context.Background(), logging.Fields{
+			logging.EVENTTYPE:     eventType,
+			logging.CORRELATIONID: uuid.NewString(),
+			logging.ORGANIZATION:  notification.Event.PayloadData.TenantDomain,
+		})
+		logger.LoggerMessaging.FromContext(ctx).Infof("Event %s is received", eventType)
+		metrics.RecordEventReceived(eventType)
+		if tenantDomain := notification.Event.PayloadData.TenantDomain; tenantDomain != "" && !conf.DataPlane.ServesOrganization(tenantDomain) {
+			logger.LoggerMessaging.FromContext(ctx).Debugf("Dropping the key manager event of organization %s as it is not served by this agent", tenantDomain)
+			metrics.RecordEventResult(eventType, nil)
+			d.Ack()
+			continue
+		}
+		sequenceKey := notification.Event.PayloadData.TenantDomain + ":" + notification.Event.PayloadData.Name
+		if !sequencer.Accept(sequencer.KeyManager, sequenceKey, 0, keyManagerEventID(notification)) {
+			logger.LoggerMessaging.FromContext(ctx).Infof("Dropping the redelivered key manager event of %s",
+				notification.Event.PayloadData.Name)
+			metrics.RecordEventResult(eventType, nil)
+			d.Ack()
+			continue
 		}
-		logger.LoggerMessaging.Infof("Event %s is received", notification.Event.PayloadData.EventType)
 
 		var decodedByte, err = base64.StdEncoding.DecodeString(notification.Event.PayloadData.Value)
 
 		if err != nil {
 			if _, ok := err.(base64.CorruptInputError); ok {
-				logger.LoggerMessaging.ErrorC(logging.ErrorDetails{
+				logger.LoggerMessaging.ErrorCContext(ctx, logging.ErrorDetails{
 					Message:   "\nbase64 input is corrupt, check the provided key",
 					Severity:  logging.MINOR,
 					ErrorCode: 2001,
 				})
 			}
-			logger.LoggerMessaging.ErrorC(logging.ErrorDetails{
+			logger.LoggerMessaging.ErrorCContext(ctx, logging.ErrorDetails{
 				Message:   fmt.Sprintf("Error occurred while decoding the notification event %v", err.Error()),
 				Severity:  logging.CRITICAL,
 				ErrorCode: 2002,
 			})
-			return
+			metrics.RecordEventResult(eventType, err)
+			d.Nack(false)
+			continue
 		}
 
 		if decodedByte != nil {
 			kmConfigMapErr := json.Unmarshal([]byte(string(decodedByte)), &keyManager)
 			if kmConfigMapErr != nil {
-				logger.LoggerMessaging.ErrorC(logging.ErrorDetails{
+				logger.LoggerMessaging.ErrorCContext(ctx, logging.ErrorDetails{
 					Message:   fmt.Sprintf("Error occurred while unmarshalling key manager config map %v", kmConfigMapErr),
 					Severity:  logging.CRITICAL,
 					ErrorCode: 2003,
 				})
-				return
+				metrics.RecordEventResult(eventType, kmConfigMapErr)
+				d.Nack(false)
+				continue
 			}
 		}
-		agent.HandleKMConfiguration(&keyManager, notification, c)
-
-		logger.LoggerMessaging.Info("handle: deliveries channel closed")
-		d.Ack(false)
+		err = runEventHandler(func() error {
+			agent.HandleKMConfiguration(&keyManager, notification, c)
+			return nil
+		})
+		metrics.RecordEventResult(eventType, err)
+		if err != nil {
+			logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while handling key manager event %s: %v", eventType, err)
+			sequencer.Forget(sequencer.KeyManager, sequenceKey)
+			d.Nack(true)
+			continue
+		}
+		d.Ack()
 	}
+	logger.LoggerMessaging.Info("handle: deliveries channel closed")
+}
+
+// keyManagerEventID identifies a key manager event by its action and the digest of its configuration, as the key
+// manager events do not carry an event ID or a time stamp
+func keyManagerEventID(notification msg.EventKeyManagerNotification) string {
+	digest := sha256.Sum256([]byte(notification.Event.PayloadData.Value))
+	return notification.Event.PayloadData.Action + ":" + hex.EncodeToString(digest[:])
 }
@@ -29,11 +29,37 @@ import (
 
 // ProcessEvents to pass event consumption
 func ProcessEvents(config *config.Config, c client.Client, agent agent.Agent) {
-	msg.InitiateJMSConnection(config.ControlPlane.BrokerConnectionParameters.EventListeningEndpoints)
+	brokerParams := config.ControlPlane.BrokerConnectionParameters
+	gatewayLabel := ""
+	if len(config.ControlPlane.EnvironmentLabels) > 0 {
+		gatewayLabel = config.ControlPlane.EnvironmentLabels[0]
+	}
+	err := msg.InitiateBrokerConnection(brokerParams.BrokerType, brokerParams.EventListeningEndpoints, msg.QueueOptions{
+		Durable:            brokerParams.DurableQueues,
+		NamePrefix:         brokerParams.QueueNamePrefix,
+		GatewayLabel:       gatewayLabel,
+		MaxRedeliveryCount: brokerParams.MaxRedeliveryCount,
+		TopicPrefix:        brokerParams.TopicPrefix,
+	})
+	if err != nil {
+		logger.LoggerAgent.Errorf("Error while connecting to the %s event broker: %v", brokerParams.BrokerType, err)
+	}
 	go handleNotification(c, agent)
 	go handleKMConfiguration(c, agent)
+	go handleOrganizationPurge(c, agent)
+	go acknowledgeUnhandledEvents("token revocation", msg.RevokedTokenChannel)
+	go acknowledgeUnhandledEvents("throttle data", msg.ThrottleDataChannel)
 
 	// run agent specific event handlers
 	logger.LoggerAgent.Info("Running gateway event handler...")
 	agent.ProcessEvents(config, c)
 }
+
+// acknowledgeUnhandledEvents acknowledges the events of the topics the agent subscribes to but does not act on,
+// so that the brokers do not block while delivering them
+func acknowledgeUnhandledEvents(kind string, eventChannel chan msg.BrokerEvent) {
+	for d := range eventChannel {
+		logger.LoggerMessaging.Debugf("Ignoring the %s event as it is not handled by the agent", kind)
+		d.Ack()
+	}
+}
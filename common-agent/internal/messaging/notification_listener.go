@@ -19,27 +19,32 @@
 package messaging
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // var variables
 var (
 	ScopeList = make([]types.Scope, 0)
-	// timestamps needs to be maintained as it is not guranteed to receive them in order,
-	// hence older events should be discarded
-	apiListTimeStampMap          = make(map[string]int64, 0)
-	subsriptionsListTimeStampMap = make(map[string]int64, 0)
-	applicationListTimeStampMap  = make(map[string]int64, 0)
 )
 
 // handleNotification to process
@@ -49,51 +54,127 @@ func handleNotification(c client.Client, agent agent.Agent) {
 		var notification msg.EventNotification
 		notificationErr := parseNotificationJSONEvent([]byte(string(d.Body)), &notification)
 		if notificationErr != nil {
+			d.Nack(false)
 			continue
 		}
 		logger.LoggerMessaging.Infof("Event %s is received", notification.Event.PayloadData.EventType)
 		logger.LoggerMessaging.Infof("Event %s is received with payload %s", notification.Event.PayloadData.EventType, notification.Event.PayloadData.Event)
 		err := processNotificationEvent(conf, &notification, c, agent)
 		if err != nil {
+			var retriableErr *retriableError
+			d.Nack(errors.As(err, &retriableErr))
 			continue
 		}
-		d.Ack(false)
+		d.Ack()
 	}
 	logger.LoggerMessaging.Infof("handle: deliveries channel closed")
 }
 
-func processNotificationEvent(conf *config.Config, notification *msg.EventNotification, c client.Client, agent agent.Agent) error {
+func processNotificationEvent(conf *config.Config, notification *msg.EventNotification, c client.Client, agent agent.Agent) (err error) {
+	metrics.RecordEventReceived(notification.Event.PayloadData.EventType)
+	ctx, span := tracing.StartSpan(metrics.ContextWithEventTime(context.Background(), time.Now()),
+		"ProcessNotificationEvent", attribute.String("event.type", notification.Event.PayloadData.EventType))
+	defer func() {
+		tracing.EndSpan(span, err)
+		metrics.RecordEventResult(notification.Event.PayloadData.EventType, err)
+	}()
+	ctx = logging.ContextWithFields(ctx, logging.Fields{logging.EVENTTYPE: notification.Event.PayloadData.EventType})
+
 	var eventType string
-	var decodedByte, err = base64.StdEncoding.DecodeString(notification.Event.PayloadData.Event)
+	decodedByte, err := base64.StdEncoding.DecodeString(notification.Event.PayloadData.Event)
 	if err != nil {
 		if _, ok := err.(base64.CorruptInputError); ok {
-			logger.LoggerMessaging.Error("\nbase64 input is corrupt, check the provided key")
+			logger.LoggerMessaging.FromContext(ctx).Error("\nbase64 input is corrupt, check the provided key")
 		}
-		logger.LoggerMessaging.Errorf("Error occurred while decoding the notification event %v. "+
+		logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while decoding the notification event %v. "+
 			"Hence dropping the event", err)
 		return err
 	}
 
 	eventType = notification.Event.PayloadData.EventType
+	var event msg.Event
+	if unmarshalErr := json.Unmarshal(decodedByte, &event); unmarshalErr != nil {
+		event = msg.Event{}
+	}
+	ctx = logging.ContextWithFields(ctx, logging.Fields{
+		logging.CORRELATIONID: correlationID(ctx, event.EventID),
+		logging.ORGANIZATION:  event.TenantDomain,
+	})
+	span.SetAttributes(attribute.String("organization", event.TenantDomain))
+	if !servesEventOrganization(conf, event) {
+		logger.LoggerMessaging.FromContext(ctx).Debugf("Dropping the event %s of organization %s as it is not served "+
+			"by this agent", eventType, event.TenantDomain)
+		return nil
+	}
+	err = runEventHandler(func() error { return dispatchNotificationEvent(ctx, conf, decodedByte, eventType, c, agent) })
+	if err != nil {
+		logger.LoggerMessaging.FromContext(ctx).Errorf("Error processing the event %s: %v", eventType, err)
+	}
+	return err
+}
+
+// dispatchNotificationEvent passes the decoded event to the agent handler of its type
+func dispatchNotificationEvent(ctx context.Context, conf *config.Config, decodedByte []byte, eventType string, c client.Client, agent agent.Agent) error {
 	if strings.Contains(eventType, constants.APILifeCycleChange) {
-		agent.HandleLifeCycleEvents(decodedByte)
+		return agent.HandleLifeCycleEvents(decodedByte, c)
 	} else if strings.Contains(eventType, constants.APIEventType) {
-		agent.HandleAPIEvents(decodedByte, eventType, conf, c)
+		return agent.HandleAPIEvents(ctx, decodedByte, eventType, conf, c)
 	} else if strings.Contains(eventType, constants.ApplicationEventType) {
-		agent.HandleApplicationEvents(decodedByte, eventType, c)
+		return agent.HandleApplicationEvents(ctx, decodedByte, eventType, c)
 	} else if strings.Contains(eventType, constants.SubscriptionEventType) {
-		agent.HandleSubscriptionEvents(decodedByte, eventType, c)
+		return agent.HandleSubscriptionEvents(ctx, decodedByte, eventType, c)
 	} else if strings.Contains(eventType, constants.PolicyEventType) {
-		agent.HandlePolicyEvents(decodedByte, eventType, c)
+		return agent.HandlePolicyEvents(ctx, decodedByte, eventType, c)
 	} else if strings.Contains(eventType, constants.AIProviderEventType) {
-		agent.HandleAIProviderEvents(decodedByte, eventType, c)
+		return agent.HandleAIProviderEvents(decodedByte, eventType, c)
 	} else if strings.Contains(eventType, constants.ScopeEventType) {
-		agent.HandleScopeEvents(decodedByte, eventType, c)
+		return agent.HandleScopeEvents(decodedByte, eventType, c)
 	}
 	// other events will ignore including HEALTH_CHECK event
 	return nil
 }
 
+// servesEventOrganization checks whether the organization of the event is served by this agent. The events that
+// do not carry an organization are left to the handlers.
+func servesEventOrganization(conf *config.Config, event msg.Event) bool {
+	return event.TenantDomain == "" || conf.DataPlane.ServesOrganization(event.TenantDomain)
+}
+
+// correlationID returns the identifier used to correlate the logs of an event. The event ID assigned by the control
+// plane is used when available, followed by the trace ID of the event.
+func correlationID(ctx context.Context, eventID string) string {
+	if eventID != "" {
+		return eventID
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		return spanContext.TraceID().String()
+	}
+	return uuid.NewString()
+}
+
+// retriableError marks the failures of an event that may succeed once the event is redelivered
+type retriableError struct {
+	err error
+}
+
+func (e *retriableError) Error() string {
+	return e.err.Error()
+}
+
+// runEventHandler runs an agent event handler and reports its failure, or a panic within it, as a retriable error
+// so that the event is redelivered instead of being acknowledged or stopping the listener
+func runEventHandler(handler func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &retriableError{err: fmt.Errorf("event handler panicked: %v", r)}
+		}
+	}()
+	if handlerErr := handler(); handlerErr != nil {
+		return &retriableError{err: handlerErr}
+	}
+	return nil
+}
+
 func parseNotificationJSONEvent(data []byte, notification *msg.EventNotification) error {
 	unmarshalErr := json.Unmarshal(data, &notification)
 	if unmarshalErr != nil {
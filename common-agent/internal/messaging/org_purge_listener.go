@@ -0,0 +1,113 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of the cached data removed when an organization is purged
+const (
+	cachedSubscriptionKind       = "CachedSubscription"
+	cachedRateLimitPolicyKind    = "CachedRateLimitPolicy"
+	cachedSubscriptionPolicyKind = "CachedSubscriptionPolicy"
+	cachedApplicationPolicyKind  = "CachedApplicationPolicy"
+)
+
+// handleOrganizationPurge removes the gateway resources and the cached data of the purged organizations
+func handleOrganizationPurge(c client.Client, agent agent.Agent) {
+	for d := range msg.OrganizationPurgeChannel {
+		var purgeEvent msg.EventOrganizationPurge
+		if err := json.Unmarshal(d.Body, &purgeEvent); err != nil {
+			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling organization purge event data %v. "+
+				"Hence dropping the event", err)
+			d.Nack(false)
+			continue
+		}
+		if err := processOrganizationPurgeEvent(&purgeEvent, c, agent); err != nil {
+			logger.LoggerMessaging.Errorf("Error occurred while purging the organizations: %v", err)
+			var retriableErr *retriableError
+			d.Nack(errors.As(err, &retriableErr))
+			continue
+		}
+		d.Ack()
+	}
+	logger.LoggerMessaging.Infof("handle: organization purge channel closed")
+}
+
+// processOrganizationPurgeEvent purges each organization of the event. Purging is idempotent, hence a
+// redelivered event purges the organizations that were already purged again without side effects.
+func processOrganizationPurgeEvent(purgeEvent *msg.EventOrganizationPurge, c client.Client, gatewayAgent agent.Agent) error {
+	conf, _ := config.ReadConfigs()
+	organizations := purgeEvent.Event.PayloadData.OrganizationList
+	logger.LoggerMessaging.Infof("Organization purge event is received for %d organizations", len(organizations))
+	for _, organization := range organizations {
+		identifiers := organization.Identifiers()
+		if len(identifiers) == 0 {
+			logger.LoggerMessaging.Warnf("Skipping the purge of organization %q as it has no identifiers", organization.Name)
+			continue
+		}
+		if !slices.ContainsFunc(identifiers, conf.DataPlane.ServesOrganization) {
+			logger.LoggerMessaging.Debugf("Skipping the purge of organization %s as it is not served by this agent",
+				strings.Join(identifiers, "/"))
+			continue
+		}
+		report := agent.NewPurgeReport(strings.Join(identifiers, "/"))
+		purgeCachedOrganizationData(identifiers, report)
+		if err := runEventHandler(func() error {
+			gatewayAgent.HandleOrganizationPurge(organization, report, c)
+			return nil
+		}); err != nil {
+			return err
+		}
+		logger.LoggerMessaging.Infof("Organization purge completed for %s", report)
+		if report.HasFailures() {
+			return &retriableError{err: fmt.Errorf("some resources of organization %s could not be deleted", report.Organization)}
+		}
+	}
+	return nil
+}
+
+// purgeCachedOrganizationData removes the subscriptions and policies of the organization cached by the agent
+func purgeCachedOrganizationData(identifiers []string, report *agent.PurgeReport) {
+	for _, identifier := range identifiers {
+		for _, uuid := range managementserver.DeleteSubscriptionsByOrganization(identifier) {
+			report.AddDeleted(cachedSubscriptionKind, uuid)
+		}
+		for _, name := range managementserver.DeleteRateLimitPoliciesByTenantDomain(identifier) {
+			report.AddDeleted(cachedRateLimitPolicyKind, name)
+		}
+		for _, name := range managementserver.DeleteSubscriptionPoliciesByTenantDomain(identifier) {
+			report.AddDeleted(cachedSubscriptionPolicyKind, name)
+		}
+		for _, name := range managementserver.DeleteApplicationPoliciesByTenantDomain(identifier) {
+			report.AddDeleted(cachedApplicationPolicyKind, name)
+		}
+	}
+}
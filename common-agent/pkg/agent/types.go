@@ -18,6 +18,12 @@
 package agent
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
@@ -26,28 +32,102 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
-// Agent defines the functions of a pluggable agent
+// Agent defines the functions of a pluggable agent. The notification event handlers return the failures that may
+// succeed once the event is redelivered.
 type Agent interface {
 	// PreRun handles any prerequisites before agent Run
 	PreRun(conf *config.Config, scheme *runtime.Scheme)
-	// Run initiates the gateway specific agent
-	Run(conf *config.Config, manager manager.Manager)
+	// Run initiates the gateway specific agent and returns once the startup data is loaded, failing when it could
+	// not be loaded from the control plane
+	Run(conf *config.Config, manager manager.Manager) error
 	// ProcessEvents handles gateway specific functions need to be triggered on event processing
 	ProcessEvents(conf *config.Config, client client.Client)
 	// HandleLifeCycleEvents handles the events of an api through out the life cycle
-	HandleLifeCycleEvents(data []byte)
-	// HandleAPIEvents to process api related data
-	HandleAPIEvents(data []byte, eventType string, conf *config.Config, client client.Client)
-	// HandleApplicationEvents to process application related events
-	HandleApplicationEvents(data []byte, eventType string, client client.Client)
-	// HandleSubscriptionEvents to process subscription related events
-	HandleSubscriptionEvents(data []byte, eventType string, client client.Client)
-	// HandlePolicyEvents to process policy related events
-	HandlePolicyEvents(data []byte, eventType string, client client.Client)
+	HandleLifeCycleEvents(data []byte, client client.Client) error
+	// HandleAPIEvents to process api related data. The context carries the trace of the notification event
+	HandleAPIEvents(ctx context.Context, data []byte, eventType string, conf *config.Config, client client.Client) error
+	// HandleApplicationEvents to process application related events. The context carries the trace of the notification event
+	HandleApplicationEvents(ctx context.Context, data []byte, eventType string, client client.Client) error
+	// HandleSubscriptionEvents to process subscription related events. The context carries the trace of the notification event
+	HandleSubscriptionEvents(ctx context.Context, data []byte, eventType string, client client.Client) error
+	// HandlePolicyEvents to process policy related events. The context carries the trace of the notification event
+	HandlePolicyEvents(ctx context.Context, data []byte, eventType string, client client.Client) error
 	// HandleAIProviderEvents to process AI Provider related events
-	HandleAIProviderEvents(data []byte, eventType string, client client.Client)
+	HandleAIProviderEvents(data []byte, eventType string, client client.Client) error
 	// HandleScopeEvents to process Scope related events
-	HandleScopeEvents(data []byte, eventType string, client client.Client)
+	HandleScopeEvents(data []byte, eventType string, client client.Client) error
 	// HandleKMConfiguration to handle Key Manager configurations
 	HandleKMConfiguration(keyManager *types.KeyManager, notification msg.EventKeyManagerNotification, client client.Client)
+	// HandleOrganizationPurge removes every gateway resource of a purged organization
+	HandleOrganizationPurge(organization msg.Organization, report *PurgeReport, client client.Client)
+}
+
+// PurgeReport records the resources removed from the gateway when an organization is purged
+type PurgeReport struct {
+	Organization string
+	mutex        sync.Mutex
+	deleted      map[string][]string
+	failed       map[string][]string
+}
+
+// NewPurgeReport creates an empty purge report for the organization
+func NewPurgeReport(organization string) *PurgeReport {
+	return &PurgeReport{
+		Organization: organization,
+		deleted:      make(map[string][]string),
+		failed:       make(map[string][]string),
+	}
+}
+
+// AddDeleted records a resource of the given kind that has been deleted
+func (r *PurgeReport) AddDeleted(kind string, name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deleted[kind] = append(r.deleted[kind], name)
+}
+
+// AddFailed records a resource of the given kind that could not be deleted
+func (r *PurgeReport) AddFailed(kind string, name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.failed[kind] = append(r.failed[kind], name)
+}
+
+// Deleted returns the names of the deleted resources of the given kind
+func (r *PurgeReport) Deleted(kind string) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.deleted[kind]...)
+}
+
+// HasFailures reports whether any resource could not be deleted
+func (r *PurgeReport) HasFailures() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.failed) > 0
+}
+
+// String summarises the deleted and failed resources by kind
+func (r *PurgeReport) String() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "organization %s:", r.Organization)
+	if len(r.deleted) == 0 && len(r.failed) == 0 {
+		builder.WriteString(" nothing to delete")
+	}
+	writeResources(&builder, "deleted", r.deleted)
+	writeResources(&builder, "failed", r.failed)
+	return builder.String()
+}
+
+func writeResources(builder *strings.Builder, state string, resources map[string][]string) {
+	kinds := make([]string, 0, len(resources))
+	for kind := range resources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(builder, " %s %d %s %v;", state, len(resources[kind]), kind, resources[kind])
+	}
 }
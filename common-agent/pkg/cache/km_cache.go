@@ -18,12 +18,14 @@
 package cache
 
 import (
+	"maps"
 	"regexp"
 	"strings"
 	"sync"
 
 	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
 )
 
 // KeyManagerCache singleton instance for managing Key Manager details in-memory
@@ -155,6 +157,41 @@ func (kmc *KeyManagerCache) IsKeyManagerEnabled(kmName string) bool {
 	return false
 }
 
+// RetainKeyManagers removes the Key Managers which are not in the given list from the cache and returns their names
+func (kmc *KeyManagerCache) RetainKeyManagers(keyManagers []eventhubTypes.ResolvedKeyManager) []string {
+	retained := make(map[string]struct{}, len(keyManagers))
+	for _, km := range keyManagers {
+		retained[km.Organization+"-"+SanitizeKeyManagerName(km.Name)] = struct{}{}
+	}
+
+	kmc.mu.Lock()
+	defer kmc.mu.Unlock()
+
+	var removed []string
+	for name := range kmc.keyManagers {
+		if _, exists := retained[name]; !exists {
+			delete(kmc.keyManagers, name)
+			removed = append(removed, name)
+			logger.LoggerCache.Infof("KeyManager '%s' removed from cache as it no longer exists", name)
+		}
+	}
+	return removed
+}
+
+// RegisterSnapshotStore registers the Key Managers in the cache to be persisted in the snapshot
+func (kmc *KeyManagerCache) RegisterSnapshotStore() {
+	snapshot.Register("keyManagers", func() map[string]*KMCacheObject {
+		kmc.mu.RLock()
+		defer kmc.mu.RUnlock()
+		return maps.Clone(kmc.keyManagers)
+	}, func(keyManagers map[string]*KMCacheObject) {
+		kmc.mu.Lock()
+		defer kmc.mu.Unlock()
+		kmc.keyManagers = keyManagers
+		logger.LoggerCache.Infof("Restored %d KeyManagers in cache", len(keyManagers))
+	})
+}
+
 // SanitizeKeyManagerName sanitizes the key manager name by converting it to envoy acceptable format
 func SanitizeKeyManagerName(input string) string {
 	lower := strings.ToLower(input)
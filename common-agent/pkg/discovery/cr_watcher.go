@@ -25,6 +25,9 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/internal/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,6 +39,9 @@ import (
 )
 
 // Define the resources to watch
+// discoveryQueue is the name of the event queue in the metrics
+const discoveryQueue = "discovery"
+
 var (
 	configOnce sync.Once
 	eventQueue chan managementserver.APICPEvent
@@ -172,6 +178,7 @@ func init() {
 		APIMap = make(map[string]managementserver.API)
 		APIHashMap = make(map[string]string)
 		eventQueue = make(chan managementserver.APICPEvent, 100)
+		metrics.RegisterQueueDepth(discoveryQueue, func() int { return len(eventQueue) })
 
 		wg.Add(1)
 		go sendData()
@@ -186,14 +193,18 @@ func sendData() {
 
 	for event := range eventQueue {
 		loggers.LoggerWatcher.Infof("Processing event: %+v", event)
+		ctx, span := tracing.StartSpan(context.Background(), "SendData",
+			attribute.String("event.type", string(event.Event)), attribute.String("api.uuid", event.API.APIUUID))
+		var eventErr error
 
 		for {
 			if event.Event == managementserver.DeleteEvent {
-				managementserver.HandleDeleteEvent(event)
+				eventErr = managementserver.HandleDeleteEvent(ctx, event)
 			} else {
-				id, revisionID, err := managementserver.HandleCreateOrUpdateEvent(event)
+				id, revisionID, err := managementserver.HandleCreateOrUpdateEvent(ctx, event)
 				if err != nil {
 					loggers.LoggerWatcher.Errorf("Event create or update error : %+v", err)
+					eventErr = err
 				} else if id == "" {
 					loggers.LoggerWatcher.Error("Id field not present in response")
 					id = "" // Default to empty string if not found
@@ -204,7 +215,7 @@ func sendData() {
 				loggers.LoggerWatcher.Infof("Adding label update to API Labels: apiUUID: %s, apiID: %s, revisionID: %s",
 					event.API.APIUUID, id, revisionID)
 
-				if event.AgentName == constants.DefaultKongAgentName && id != "" {
+				if (event.AgentName == constants.DefaultKongAgentName || event.AgentName == constants.DefaultEGAgentName) && id != "" {
 					if callback := managementserver.GetAPIImportCallback(); callback != nil && id != "" {
 						callback.OnAPIImportSuccess(event.UUID, id, revisionID, event.Name, event.Namespace, event.AgentName)
 					}
@@ -212,6 +223,7 @@ func sendData() {
 			}
 			break
 		}
+		tracing.EndSpan(span, eventErr)
 	}
 }
 
@@ -230,5 +242,6 @@ func QueueEvent(eventType managementserver.EventType, api managementserver.API,
 		loggers.LoggerWatcher.Infof("Queued %s event for API %s", eventType, api.APIUUID)
 	default:
 		loggers.LoggerWatcher.Warnf("Event queue full, dropping %s event for API %s", eventType, api.APIUUID)
+		metrics.RecordQueueDrop(discoveryQueue)
 	}
 }
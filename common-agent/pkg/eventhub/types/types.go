@@ -83,6 +83,41 @@ type SubscriptionPolicyList struct {
 	List  []SubscriptionPolicy `json:"list"`
 }
 
+// Application for struct application
+type Application struct {
+	UUID         string            `json:"uuid"`
+	ID           int32             `json:"id"`
+	Name         string            `json:"name"`
+	SubName      string            `json:"subName"`
+	Policy       string            `json:"policy"`
+	TokenType    string            `json:"tokenType"`
+	Attributes   map[string]string `json:"attributes"`
+	TenantID     int32             `json:"tenanId,omitempty"`
+	Organization string            `json:"organization,omitempty"`
+	TimeStamp    int64             `json:"timeStamp,omitempty"`
+}
+
+// ApplicationList for struct list of application
+type ApplicationList struct {
+	List []Application `json:"list"`
+}
+
+// ApplicationPolicy for struct ApplicationPolicy
+type ApplicationPolicy struct {
+	TenantID     int32        `json:"tenantId"`
+	TenantDomain string       `json:"tenantDomain,omitempty"`
+	Name         string       `json:"name"`
+	QuotaType    string       `json:"quotaType"`
+	DefaultLimit DefaultLimit `json:"defaultLimit"`
+	TimeStamp    int64        `json:"timeStamp,omitempty"`
+}
+
+// ApplicationPolicyList for struct list of ApplicationPolicy
+type ApplicationPolicyList struct {
+	Count int                 `json:"count"`
+	List  []ApplicationPolicy `json:"list"`
+}
+
 // AIProviderList for struct list of AIProvider
 type AIProviderList struct {
 	AIProviders []AIProvider `json:"llmProviders"`
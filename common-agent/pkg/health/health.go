@@ -19,10 +19,12 @@ package health
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"sync"
 
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
-	healthservice "github.com/wso2/apk/adapter/pkg/health/api/wso2/health/service"
+	healthservice "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 var (
@@ -38,6 +40,9 @@ var (
 const (
 	NotificationListenerService service = "apk.apim.agent.internal.NotificationListenerService"
 	CommonControllerGrpcService service = "apk.apim.agent.internal.CommonControllerGrpcService"
+	BrokerConnectionService     service = "apk.apim.agent.internal.BrokerConnectionService"
+	StartupDataLoadService      service = "apk.apim.agent.internal.StartupDataLoadService"
+	KeyManagerFetchService      service = "apk.apim.agent.internal.KeyManagerFetchService"
 )
 
 type service string
@@ -50,7 +55,19 @@ func (s service) SetStatus(isHealthy bool) {
 	serviceHealthStatus[string(s)] = isHealthy
 }
 
-// Server represents the Health GRPC server
+// Checker reports an error when the service is not healthy or its health status is not set yet. It is used
+// as a check of the HTTP readiness endpoint.
+func (s service) Checker(_ *http.Request) error {
+	mutexForHealthUpdate.Lock()
+	defer mutexForHealthUpdate.Unlock()
+	if isHealthy, ok := serviceHealthStatus[string(s)]; !ok || !isHealthy {
+		return fmt.Errorf("service %q is not healthy", s)
+	}
+	return nil
+}
+
+// Server represents the Health GRPC server. It serves the grpc.health.v1 protos of gRPC, which are the protos of the
+// APK health service as well, hence the health clients of the agent are served as before.
 type Server struct {
 	healthservice.UnimplementedHealthServer
 }
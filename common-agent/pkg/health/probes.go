@@ -0,0 +1,56 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// cacheSyncCheckTimeout bounds the time a readiness check waits for the informers to sync
+const cacheSyncCheckTimeout = time.Second
+
+// CacheSyncChecker reports an error until the informers of the controller-runtime cache are synced
+func CacheSyncChecker(informerCache cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncCheckTimeout)
+		defer cancel()
+		if !informerCache.WaitForCacheSync(ctx) {
+			return errors.New("informer cache is not synced")
+		}
+		return nil
+	}
+}
+
+// LeaderChecker reports an error until the agent is elected as the leader. The elected channel is closed once
+// the agent becomes the leader.
+func LeaderChecker(elected <-chan struct{}) healthz.Checker {
+	return func(_ *http.Request) error {
+		select {
+		case <-elected:
+			return nil
+		default:
+			return errors.New("agent is not elected as the leader")
+		}
+	}
+}
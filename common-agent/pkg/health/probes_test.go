@@ -0,0 +1,85 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package health
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthservice "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+func TestServiceChecker(t *testing.T) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	testService := service("apk.apim.agent.internal.TestService")
+
+	assert.Error(t, testService.Checker(req), "a service without a health status must not be ready")
+	testService.SetStatus(true)
+	assert.NoError(t, testService.Checker(req))
+	testService.SetStatus(false)
+	assert.Error(t, testService.Checker(req))
+}
+
+func TestCacheSyncChecker(t *testing.T) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	synced := false
+	informers := &informertest.FakeInformers{Synced: &synced}
+
+	assert.Error(t, CacheSyncChecker(informers)(req))
+	synced = true
+	assert.NoError(t, CacheSyncChecker(informers)(req))
+}
+
+func TestLeaderChecker(t *testing.T) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	elected := make(chan struct{})
+
+	assert.Error(t, LeaderChecker(elected)(req))
+	close(elected)
+	assert.NoError(t, LeaderChecker(elected)(req))
+}
+
+func TestServerServesHealthCheckMethod(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	healthservice.RegisterHealthServer(grpcServer, &Server{})
+	go func() { _ = grpcServer.Serve(listener) }()
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	testService := service("apk.apim.agent.internal.TestGrpcService")
+	testService.SetStatus(true)
+	// The health clients of the APK health service invoke the same method
+	response := &healthservice.HealthCheckResponse{}
+	require.NoError(t, conn.Invoke(context.Background(), "/grpc.health.v1.Health/Check",
+		&healthservice.HealthCheckRequest{Service: string(testService)}, response))
+	assert.Equal(t, healthservice.HealthCheckResponse_SERVING, response.Status)
+}
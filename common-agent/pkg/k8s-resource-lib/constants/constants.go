@@ -17,6 +17,8 @@
 
 package constants
 
+import "time"
+
 // SandboxType is the type of the sandbox environment
 const SandboxType = "sandbox"
 
@@ -46,3 +48,30 @@ const APITypeWS = "WS"
 
 // APITypeWebSub is the type of the WEBSUB API
 const APITypeWebSub = "WEBSUB"
+
+// HTTPMethodGet is the GET http method
+const HTTPMethodGet = "GET"
+
+// HTTPMethodPost is the POST http method
+const HTTPMethodPost = "POST"
+
+// WebSocketUpgradeType is the http upgrade protocol used by WS APIs
+const WebSocketUpgradeType = "websocket"
+
+// DefaultStreamingIdleTimeout is the idle timeout applied to WS and SSE connections
+const DefaultStreamingIdleTimeout = time.Hour
+
+// DefaultWebSubRequestTimeout is the request timeout applied to WebSub callback deliveries
+const DefaultWebSubRequestTimeout = 60 * time.Second
+
+// GraphQLOperationQuery is the QUERY operation type of GraphQL APIs
+const GraphQLOperationQuery = "QUERY"
+
+// GraphQLOperationMutation is the MUTATION operation type of GraphQL APIs
+const GraphQLOperationMutation = "MUTATION"
+
+// GraphQLOperationSubscription is the SUBSCRIPTION operation type of GraphQL APIs
+const GraphQLOperationSubscription = "SUBSCRIPTION"
+
+// GraphQLOperationTarget is the operation target that resolves to the base path of a GraphQL API
+const GraphQLOperationTarget = "/*"
@@ -0,0 +1,101 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package asyncgenerator
+
+import (
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// IsStreamingAPI returns true if the given api type is served through a long lived or upgraded connection.
+func IsStreamingAPI(apiType string) bool {
+	switch apiType {
+	case constants.APITypeWS, constants.APITypeSSE, constants.APITypeWebSub, constants.APITypeAsync:
+		return true
+	}
+	return false
+}
+
+// collapseOperations merges operations that resolve to the same route match (e.g. PUBLISH and SUBSCRIBE of a WS topic).
+// The merged operation is secured when any of the operations it serves is secured.
+func (g *AsyncRouteGenerator) collapseOperations(operations []types.Operation) []types.Operation {
+	collapsed := make([]types.Operation, 0, len(operations))
+	seen := make(map[string]int)
+	for _, operation := range operations {
+		if index, ok := seen[operation.Target]; ok {
+			collapsed[index].Secured = collapsed[index].Secured || operation.Secured
+			continue
+		}
+		seen[operation.Target] = len(collapsed)
+		collapsed = append(collapsed, operation)
+	}
+	return collapsed
+}
+
+// mapOperationMethod maps the operation verb of a streaming API to the HTTP method the client connects with.
+func (g *AsyncRouteGenerator) mapOperationMethod(apkConf types.APKConf, operation types.Operation) string {
+	switch apkConf.Type {
+	case constants.APITypeWS, constants.APITypeSSE:
+		// websocket handshakes and event streams are both initiated with a GET request
+		return constants.HTTPMethodGet
+	case constants.APITypeWebSub:
+		// hubs deliver content and subscription requests to the callback with POST
+		return constants.HTTPMethodPost
+	}
+	return ""
+}
+
+// retrieveAsyncMatch retrieves the HTTPRouteMatch of a streaming operation.
+func (g *AsyncRouteGenerator) retrieveAsyncMatch(apkConf types.APKConf, operation types.Operation) (gwapiv1.HTTPRouteMatch, error) {
+	pathType := gwapiv1.PathMatchRegularExpression
+	operationTarget := "/*"
+	if operation.Target != "" {
+		operationTarget = operation.Target
+	}
+	basePath := utils.GeneratePath(apkConf.BasePath, apkConf.Version)
+	pathValue := utils.RetrievePathPrefix(operationTarget, basePath)
+	httpRouteMatch := gwapiv1.HTTPRouteMatch{
+		Path: &gwapiv1.HTTPPathMatch{
+			Type:  &pathType,
+			Value: &pathValue,
+		},
+	}
+	if method := g.MapOperationMethod(apkConf, operation); method != "" {
+		httpMethod := gwapiv1.HTTPMethod(method)
+		httpRouteMatch.Method = &httpMethod
+	}
+	return httpRouteMatch, nil
+}
+
+// retrieveStreamingSettings returns the default connection settings for the type of the given API.
+func (g *AsyncRouteGenerator) retrieveStreamingSettings(apkConf types.APKConf) StreamingSettings {
+	settings := StreamingSettings{APIType: apkConf.Type}
+	switch apkConf.Type {
+	case constants.APITypeWS:
+		settings.UpgradeType = constants.WebSocketUpgradeType
+		settings.IdleTimeout = constants.DefaultStreamingIdleTimeout
+	case constants.APITypeSSE:
+		settings.IdleTimeout = constants.DefaultStreamingIdleTimeout
+	case constants.APITypeWebSub:
+		settings.RequestTimeout = constants.DefaultWebSubRequestTimeout
+	}
+	return settings
+}
@@ -0,0 +1,87 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package asyncgenerator
+
+import (
+	"testing"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStreamingAPI(t *testing.T) {
+	assert.True(t, IsStreamingAPI(constants.APITypeWS))
+	assert.True(t, IsStreamingAPI(constants.APITypeSSE))
+	assert.True(t, IsStreamingAPI(constants.APITypeWebSub))
+	assert.False(t, IsStreamingAPI(constants.APITypeRest))
+	assert.False(t, IsStreamingAPI(constants.APITypeGrpc))
+}
+
+func TestCollapseOperations(t *testing.T) {
+	g := Generator()
+	operations := []types.Operation{
+		{Target: "/rooms", Verb: "SUBSCRIBE"},
+		{Target: "/rooms", Verb: "PUBLISH", Secured: true},
+		{Target: "/alerts", Verb: "SUBSCRIBE"},
+	}
+
+	collapsed := g.CollapseOperations(operations)
+
+	assert.Len(t, collapsed, 2)
+	assert.Equal(t, "/rooms", collapsed[0].Target)
+	assert.True(t, collapsed[0].Secured)
+	assert.Equal(t, "/alerts", collapsed[1].Target)
+	assert.False(t, collapsed[1].Secured)
+}
+
+func TestRetrieveAsyncMatch(t *testing.T) {
+	g := Generator()
+	tests := []struct {
+		apiType        string
+		expectedMethod string
+	}{
+		{constants.APITypeWS, constants.HTTPMethodGet},
+		{constants.APITypeSSE, constants.HTTPMethodGet},
+		{constants.APITypeWebSub, constants.HTTPMethodPost},
+	}
+	for _, test := range tests {
+		apkConf := types.APKConf{BasePath: "/events", Version: "1.0", Type: test.apiType}
+		match, err := g.HTTPGenerator.RetrieveHTTPMatch(apkConf, types.Operation{Target: "/orders", Verb: "SUBSCRIBE"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, test.expectedMethod, string(*match.Method))
+		assert.NotNil(t, match.Path.Value)
+	}
+}
+
+func TestRetrieveStreamingSettings(t *testing.T) {
+	g := Generator()
+
+	wsSettings := g.RetrieveStreamingSettings(types.APKConf{Type: constants.APITypeWS})
+	assert.Equal(t, constants.WebSocketUpgradeType, wsSettings.UpgradeType)
+	assert.Equal(t, constants.DefaultStreamingIdleTimeout, wsSettings.IdleTimeout)
+
+	sseSettings := g.RetrieveStreamingSettings(types.APKConf{Type: constants.APITypeSSE})
+	assert.Empty(t, sseSettings.UpgradeType)
+	assert.Equal(t, constants.DefaultStreamingIdleTimeout, sseSettings.IdleTimeout)
+
+	webSubSettings := g.RetrieveStreamingSettings(types.APKConf{Type: constants.APITypeWebSub})
+	assert.Equal(t, constants.DefaultWebSubRequestTimeout, webSubSettings.RequestTimeout)
+}
@@ -0,0 +1,57 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package asyncgenerator
+
+import (
+	"fmt"
+
+	httpgenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/http"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+)
+
+// AsyncRouteGenerator is the interface for the streaming (WS, SSE, WebSub) route generator.
+type AsyncRouteGenerator struct {
+	HTTPGenerator             *httpgenerator.HTTPRouteGenerator
+	CollapseOperations        func(operations []types.Operation) []types.Operation
+	MapOperationMethod        func(apkConf types.APKConf, operation types.Operation) string
+	RetrieveStreamingSettings func(apkConf types.APKConf) StreamingSettings
+}
+
+// Generator creates a new streaming route generator.
+func Generator() *AsyncRouteGenerator {
+	gen := &AsyncRouteGenerator{}
+	gen.HTTPGenerator = httpgenerator.Generator()
+	gen.HTTPGenerator.RetrieveHTTPMatch = gen.retrieveAsyncMatch
+	gen.CollapseOperations = gen.collapseOperations
+	gen.MapOperationMethod = gen.mapOperationMethod
+	gen.RetrieveStreamingSettings = gen.retrieveStreamingSettings
+	return gen
+}
+
+// GenerateAsyncRoute generates the HTTPRoute and the streaming settings of a WS, SSE or WebSub API.
+func (g *AsyncRouteGenerator) GenerateAsyncRoute(apkConf types.APKConf, organization types.Organization, gatewayConfiguration types.GatewayConfigurations, operations []types.Operation, endpoint *[]types.EndpointDetails, endpointType string, uniqueID string, count int) (*K8sArtifacts, error) {
+	if !IsStreamingAPI(apkConf.Type) {
+		return nil, fmt.Errorf("unsupported api type for streaming route generation: %s", apkConf.Type)
+	}
+	collapsedOperations := g.CollapseOperations(operations)
+	httpArtifacts, err := g.HTTPGenerator.GenerateHTTPRoute(apkConf, organization, gatewayConfiguration, collapsedOperations, endpoint, endpointType, uniqueID, count)
+	if err != nil {
+		return nil, err
+	}
+	return &K8sArtifacts{K8sArtifacts: httpArtifacts, Operations: collapsedOperations, StreamingSettings: g.RetrieveStreamingSettings(apkConf)}, nil
+}
@@ -0,0 +1,83 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package asyncgenerator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getStreamingAPKConf(apiType string) types.APKConf {
+	return types.APKConf{
+		Name:     "ChatAPI",
+		Version:  "1.0",
+		BasePath: "/chat",
+		Type:     apiType,
+		EndpointConfigurations: &types.EndpointConfigurations{
+			Production: &[]types.EndpointConfiguration{
+				{
+					Endpoint: types.EndpointURL("http://chat-service:8080"),
+				},
+			},
+		},
+		Operations: &[]types.Operation{
+			{Target: "/rooms", Verb: "SUBSCRIBE", Secured: true, Scopes: []string{}},
+			{Target: "/rooms", Verb: "PUBLISH", Secured: true, Scopes: []string{}},
+			{Target: "/notifications", Verb: "SUBSCRIBE", Secured: true, Scopes: []string{}},
+		},
+	}
+}
+
+// TestGenerateAsyncRoute test for GenerateAsyncRoute
+func TestGenerateAsyncRoute(t *testing.T) {
+	gen := Generator()
+	apkConf := getStreamingAPKConf(constants.APITypeWS)
+	organization := types.Organization{Name: "wso2"}
+	gatewayConfig := types.GatewayConfigurations{Name: "wso2-apim", ListenerName: "wso2-apim-gateway"}
+
+	endpoints := utils.GetEndpoints(apkConf)
+	endpoint := endpoints[constants.ProductionType]
+	artifacts, err := gen.GenerateAsyncRoute(apkConf, organization, gatewayConfig, *apkConf.Operations, &endpoint, constants.ProductionType, "unique-id", 0)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, artifacts.HTTPRoute)
+	assert.Len(t, artifacts.HTTPRoute.Spec.Rules, 2)
+	for _, rule := range artifacts.HTTPRoute.Spec.Rules {
+		assert.Equal(t, constants.HTTPMethodGet, string(*rule.Matches[0].Method))
+	}
+	assert.Equal(t, constants.WebSocketUpgradeType, artifacts.StreamingSettings.UpgradeType)
+	assert.Equal(t, time.Hour, artifacts.StreamingSettings.IdleTimeout)
+}
+
+// TestGenerateAsyncRouteUnsupportedType test for GenerateAsyncRoute with a non streaming API
+func TestGenerateAsyncRouteUnsupportedType(t *testing.T) {
+	gen := Generator()
+	apkConf := getStreamingAPKConf(constants.APITypeRest)
+	endpoint := []types.EndpointDetails{{Name: "chat-service", URL: "http://chat-service:8080"}}
+
+	artifacts, err := gen.GenerateAsyncRoute(apkConf, types.Organization{}, types.GatewayConfigurations{}, *apkConf.Operations, &endpoint, constants.ProductionType, "unique-id", 0)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, artifacts)
+}
@@ -0,0 +1,44 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package asyncgenerator
+
+import (
+	"time"
+
+	httpgenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/http"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+)
+
+// K8sArtifacts represents the Kubernetes artifacts that are generated for a streaming (WS, SSE, WebSub) API
+type K8sArtifacts struct {
+	*httpgenerator.K8sArtifacts
+	// Operations holds the collapsed operations the rules of the route are generated from, in the order of the rules
+	Operations        []types.Operation
+	StreamingSettings StreamingSettings
+}
+
+// StreamingSettings holds the connection level settings a gateway needs to apply for a streaming API
+type StreamingSettings struct {
+	APIType string
+	// UpgradeType is the HTTP upgrade protocol that should be allowed on the route (empty when no upgrade is needed)
+	UpgradeType string
+	// IdleTimeout is the time a connection may stay open without traffic (zero keeps the gateway default)
+	IdleTimeout time.Duration
+	// RequestTimeout is the time allowed for a complete response (zero disables the timeout)
+	RequestTimeout time.Duration
+}
@@ -0,0 +1,85 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package graphqlgenerator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+)
+
+// generateGraphQLOperations generates the HTTP operations every GraphQL operation of the API is served through.
+func (g *GraphQLRouteGenerator) generateGraphQLOperations(apkConf types.APKConf, subscriptionsEnabled bool) []types.Operation {
+	// queries and mutations are all posted to the base path of the api
+	operations := []types.Operation{
+		{Target: constants.GraphQLOperationTarget, Verb: constants.HTTPMethodPost,
+			Secured: isOperationTypeSecured(apkConf, constants.GraphQLOperationQuery, constants.GraphQLOperationMutation)},
+	}
+	if subscriptionsEnabled {
+		// subscriptions are initiated by a websocket handshake on the same path
+		operations = append(operations, types.Operation{Target: constants.GraphQLOperationTarget, Verb: constants.HTTPMethodGet,
+			Secured: isOperationTypeSecured(apkConf, constants.GraphQLOperationSubscription)})
+	}
+	return operations
+}
+
+// retrieveGraphQLOperationPolicies maps the operations of the apk-conf to GraphQL operation policies.
+func (g *GraphQLRouteGenerator) retrieveGraphQLOperationPolicies(apkConf types.APKConf) ([]OperationPolicy, error) {
+	operationPolicies := []OperationPolicy{}
+	if apkConf.Operations == nil {
+		return operationPolicies, nil
+	}
+	for _, operation := range *apkConf.Operations {
+		operationType := strings.ToUpper(operation.Verb)
+		switch operationType {
+		case constants.GraphQLOperationQuery, constants.GraphQLOperationMutation, constants.GraphQLOperationSubscription:
+		default:
+			return nil, fmt.Errorf("invalid graphql operation type %s for operation %s", operation.Verb, operation.Target)
+		}
+		operationPolicies = append(operationPolicies, OperationPolicy{
+			OperationType: operationType,
+			Name:          operation.Target,
+			Secured:       operation.Secured,
+			Scopes:        operation.Scopes,
+			RateLimit:     operation.RateLimit,
+		})
+	}
+	return operationPolicies, nil
+}
+
+// isOperationTypeSecured returns true if any of the operations of the given types requires authentication, as they
+// are all served through the same rule. The rule is secured when the API defines no operations of the given types.
+func isOperationTypeSecured(apkConf types.APKConf, operationTypes ...string) bool {
+	if apkConf.Operations == nil {
+		return true
+	}
+	found := false
+	for _, operation := range *apkConf.Operations {
+		if !slices.Contains(operationTypes, strings.ToUpper(operation.Verb)) {
+			continue
+		}
+		if operation.Secured {
+			return true
+		}
+		found = true
+	}
+	return !found
+}
@@ -0,0 +1,85 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package graphqlgenerator
+
+import (
+	"testing"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGraphQLOperations(t *testing.T) {
+	g := Generator()
+	operations := []types.Operation{{Target: "hero", Verb: "QUERY", Secured: true}}
+	apkConf := types.APKConf{Type: constants.APITypeGraphql, Operations: &operations}
+
+	withoutSubscriptions := g.GenerateGraphQLOperations(apkConf, false)
+	assert.Len(t, withoutSubscriptions, 1)
+	assert.Equal(t, constants.HTTPMethodPost, withoutSubscriptions[0].Verb)
+	assert.True(t, withoutSubscriptions[0].Secured)
+
+	withSubscriptions := g.GenerateGraphQLOperations(apkConf, true)
+	assert.Len(t, withSubscriptions, 2)
+	assert.Equal(t, constants.HTTPMethodGet, withSubscriptions[1].Verb)
+}
+
+func TestGenerateGraphQLOperationsSecuredPerOperationType(t *testing.T) {
+	g := Generator()
+	operations := []types.Operation{
+		{Target: "hero", Verb: "QUERY", Secured: false},
+		{Target: "createReview", Verb: "MUTATION", Secured: true},
+		{Target: "reviewAdded", Verb: "SUBSCRIPTION", Secured: false},
+	}
+	apkConf := types.APKConf{Type: constants.APITypeGraphql, Operations: &operations}
+
+	generated := g.GenerateGraphQLOperations(apkConf, true)
+	assert.True(t, generated[0].Secured)
+	assert.False(t, generated[1].Secured)
+}
+
+func TestRetrieveGraphQLOperationPolicies(t *testing.T) {
+	g := Generator()
+	operations := []types.Operation{
+		{Target: "hero", Verb: "query", Secured: true, Scopes: []string{"read"}},
+		{Target: "createReview", Verb: "MUTATION", Secured: false},
+	}
+	apkConf := types.APKConf{Type: constants.APITypeGraphql, Operations: &operations}
+
+	operationPolicies, err := g.RetrieveGraphQLOperationPolicies(apkConf)
+
+	assert.Nil(t, err)
+	assert.Len(t, operationPolicies, 2)
+	assert.Equal(t, constants.GraphQLOperationQuery, operationPolicies[0].OperationType)
+	assert.Equal(t, "hero", operationPolicies[0].Name)
+	assert.Equal(t, []string{"read"}, operationPolicies[0].Scopes)
+	assert.False(t, operationPolicies[1].Secured)
+}
+
+func TestRetrieveGraphQLOperationPoliciesInvalidVerb(t *testing.T) {
+	g := Generator()
+	operations := []types.Operation{{Target: "/employees", Verb: "GET"}}
+	apkConf := types.APKConf{Type: constants.APITypeGraphql, Operations: &operations}
+
+	operationPolicies, err := g.RetrieveGraphQLOperationPolicies(apkConf)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, operationPolicies)
+}
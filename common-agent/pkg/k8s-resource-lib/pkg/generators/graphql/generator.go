@@ -0,0 +1,67 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package graphqlgenerator
+
+import (
+	"fmt"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	httpgenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/http"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+)
+
+// GraphQLRouteGenerator is the interface for the GraphQL route generator.
+type GraphQLRouteGenerator struct {
+	HTTPGenerator                    *httpgenerator.HTTPRouteGenerator
+	GenerateGraphQLOperations        func(apkConf types.APKConf, subscriptionsEnabled bool) []types.Operation
+	RetrieveGraphQLOperationPolicies func(apkConf types.APKConf) ([]OperationPolicy, error)
+}
+
+// Generator creates a new GraphQL route generator.
+func Generator() *GraphQLRouteGenerator {
+	gen := &GraphQLRouteGenerator{}
+	gen.HTTPGenerator = httpgenerator.Generator()
+	gen.GenerateGraphQLOperations = gen.generateGraphQLOperations
+	gen.RetrieveGraphQLOperationPolicies = gen.retrieveGraphQLOperationPolicies
+	return gen
+}
+
+// GenerateGraphQLRoute generates a single HTTPRoute for a GraphQL API along with the operation level policy metadata.
+func (g *GraphQLRouteGenerator) GenerateGraphQLRoute(apkConf types.APKConf, organization types.Organization, gatewayConfiguration types.GatewayConfigurations, endpoint *[]types.EndpointDetails, endpointType string, uniqueID string) (*K8sArtifacts, error) {
+	if apkConf.Type != constants.APITypeGraphql {
+		return nil, fmt.Errorf("unsupported api type for graphql route generation: %s", apkConf.Type)
+	}
+	operationPolicies, err := g.RetrieveGraphQLOperationPolicies(apkConf)
+	if err != nil {
+		return nil, err
+	}
+	subscriptionsEnabled := false
+	for _, operationPolicy := range operationPolicies {
+		if operationPolicy.OperationType == constants.GraphQLOperationSubscription {
+			subscriptionsEnabled = true
+			break
+		}
+	}
+	operations := g.GenerateGraphQLOperations(apkConf, subscriptionsEnabled)
+	httpArtifacts, err := g.HTTPGenerator.GenerateHTTPRoute(apkConf, organization, gatewayConfiguration, operations, endpoint, endpointType, uniqueID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &K8sArtifacts{K8sArtifacts: httpArtifacts, Operations: operations, OperationPolicies: operationPolicies,
+		SubscriptionsEnabled: subscriptionsEnabled}, nil
+}
@@ -0,0 +1,81 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package graphqlgenerator
+
+import (
+	"testing"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getGraphQLAPKConf(operations []types.Operation) types.APKConf {
+	return types.APKConf{
+		Name:     "StarWarsAPI",
+		Version:  "1.0",
+		BasePath: "/starwars",
+		Type:     constants.APITypeGraphql,
+		EndpointConfigurations: &types.EndpointConfigurations{
+			Production: &[]types.EndpointConfiguration{
+				{
+					Endpoint: types.EndpointURL("http://starwars-service:8080/graphql"),
+				},
+			},
+		},
+		Operations: &operations,
+	}
+}
+
+// TestGenerateGraphQLRoute test for GenerateGraphQLRoute
+func TestGenerateGraphQLRoute(t *testing.T) {
+	gen := Generator()
+	apkConf := getGraphQLAPKConf([]types.Operation{
+		{Target: "hero", Verb: "QUERY", Secured: true, Scopes: []string{"read"}},
+		{Target: "createReview", Verb: "MUTATION", Secured: true, Scopes: []string{"write"}, RateLimit: &types.RateLimit{RequestsPerUnit: 10, Unit: "Minute"}},
+		{Target: "reviewAdded", Verb: "SUBSCRIPTION", Secured: false},
+	})
+	organization := types.Organization{Name: "wso2"}
+	gatewayConfig := types.GatewayConfigurations{Name: "wso2-apim", ListenerName: "wso2-apim-gateway"}
+	endpoint := utils.GetEndpoints(apkConf)[constants.ProductionType]
+
+	artifacts, err := gen.GenerateGraphQLRoute(apkConf, organization, gatewayConfig, &endpoint, constants.ProductionType, "unique-id")
+
+	assert.Nil(t, err)
+	assert.True(t, artifacts.SubscriptionsEnabled)
+	assert.Len(t, artifacts.HTTPRoute.Spec.Rules, 2)
+	assert.Equal(t, "/starwars/1.0", *artifacts.HTTPRoute.Spec.Rules[0].Matches[0].Path.Value)
+	assert.Equal(t, constants.HTTPMethodPost, string(*artifacts.HTTPRoute.Spec.Rules[0].Matches[0].Method))
+	assert.Len(t, artifacts.OperationPolicies, 3)
+	assert.Equal(t, 10, artifacts.OperationPolicies[1].RateLimit.RequestsPerUnit)
+}
+
+// TestGenerateGraphQLRouteInvalidType test for GenerateGraphQLRoute with a non GraphQL API
+func TestGenerateGraphQLRouteInvalidType(t *testing.T) {
+	gen := Generator()
+	apkConf := getGraphQLAPKConf([]types.Operation{{Target: "hero", Verb: "QUERY"}})
+	apkConf.Type = constants.APITypeRest
+	endpoint := utils.GetEndpoints(apkConf)[constants.ProductionType]
+
+	artifacts, err := gen.GenerateGraphQLRoute(apkConf, types.Organization{}, types.GatewayConfigurations{}, &endpoint, constants.ProductionType, "unique-id")
+
+	assert.NotNil(t, err)
+	assert.Nil(t, artifacts)
+}
@@ -0,0 +1,47 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package graphqlgenerator
+
+import (
+	httpgenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/http"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+)
+
+// K8sArtifacts represents the Kubernetes artifacts that are generated for a GraphQL API
+type K8sArtifacts struct {
+	*httpgenerator.K8sArtifacts
+	// Operations holds the HTTP operations the rules of the route are generated from, in the order of the rules
+	Operations []types.Operation
+	// OperationPolicies holds the per operation security and rate limit metadata a gateway
+	// needs to enforce on top of the single GraphQL route
+	OperationPolicies []OperationPolicy
+	// SubscriptionsEnabled is true when the schema exposes subscription operations, which are
+	// served through a websocket upgrade on the same route
+	SubscriptionsEnabled bool
+}
+
+// OperationPolicy represents the security and rate limit metadata of a single GraphQL operation
+type OperationPolicy struct {
+	// OperationType is one of QUERY, MUTATION or SUBSCRIPTION
+	OperationType string
+	// Name is the root field name of the operation
+	Name      string
+	Secured   bool
+	Scopes    []string
+	RateLimit *types.RateLimit
+}
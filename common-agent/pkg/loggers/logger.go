@@ -41,6 +41,9 @@ const (
 	pkgSync        = "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/synchronizer"
 	pkgWatcher     = "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/discovery"
 	pkgCache       = "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/cache"
+	pkgTracing     = "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
+	pkgSnapshot    = "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
+	pkgSequencer   = "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
 )
 
 // logger package references
@@ -54,6 +57,9 @@ var (
 	LoggerSync        logging.Log
 	LoggerWatcher     logging.Log
 	LoggerCache       logging.Log
+	LoggerTracing     logging.Log
+	LoggerSnapshot    logging.Log
+	LoggerSequencer   logging.Log
 )
 
 func init() {
@@ -71,5 +77,8 @@ func UpdateLoggers() {
 	LoggerSync = logging.InitPackageLogger(pkgSync)
 	LoggerWatcher = logging.InitPackageLogger(pkgWatcher)
 	LoggerCache = logging.InitPackageLogger(pkgCache)
+	LoggerTracing = logging.InitPackageLogger(pkgTracing)
+	LoggerSnapshot = logging.InitPackageLogger(pkgSnapshot)
+	LoggerSequencer = logging.InitPackageLogger(pkgSequencer)
 	logrus.Info("Updated loggers")
 }
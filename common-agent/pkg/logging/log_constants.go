@@ -41,3 +41,12 @@ const (
 	SEVERITY  = "severity"
 	ERRORCODE = "error_code"
 )
+
+// Structured log attribute name constants
+const (
+	APIUUID       = "api_uuid"
+	REVISIONID    = "revision_id"
+	ORGANIZATION  = "organization"
+	EVENTTYPE     = "event_type"
+	CORRELATIONID = "correlation_id"
+)
@@ -0,0 +1,68 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields is the set of structured attributes attached to the logs
+type Fields = logrus.Fields
+
+// fieldsKey is the context key of the structured log attributes
+type fieldsKey struct{}
+
+// ContextWithFields returns the context carrying the given structured log attributes in addition to the attributes
+// already carried by ctx. Empty values are ignored so that an unknown attribute does not hide a known one.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	merged := Fields{}
+	for key, value := range FieldsFromContext(ctx) {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		if value == nil || value == "" {
+			continue
+		}
+		merged[key] = value
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the structured log attributes carried by the context
+func FieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	return fields
+}
+
+// FromContext returns a log entry with the structured log attributes carried by the context
+func (l *Log) FromContext(ctx context.Context) *logrus.Entry {
+	return l.WithFields(FieldsFromContext(ctx))
+}
+
+// ErrorCContext can be used for formal error logs with the structured log attributes carried by the context
+func (l *Log) ErrorCContext(ctx context.Context, e ErrorDetails) {
+	l.FromContext(ctx).WithFields(logrus.Fields{SEVERITY: e.Severity, ERRORCODE: e.ErrorCode}).Error(e.Message)
+	if e.Severity == BLOCKER {
+		l.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWithFields(t *testing.T) {
+	assert.Empty(t, FieldsFromContext(context.Background()))
+
+	ctx := ContextWithFields(context.Background(), Fields{EVENTTYPE: "API_UPDATE", CORRELATIONID: "1234"})
+	ctx = ContextWithFields(ctx, Fields{APIUUID: "api-1", ORGANIZATION: ""})
+	childCtx := ContextWithFields(ctx, Fields{REVISIONID: "rev-1"})
+
+	assert.Equal(t, Fields{EVENTTYPE: "API_UPDATE", CORRELATIONID: "1234", APIUUID: "api-1"}, FieldsFromContext(ctx),
+		"empty attributes must be ignored and the attributes of the parent context must not be modified")
+	assert.Equal(t, "rev-1", FieldsFromContext(childCtx)[REVISIONID])
+	assert.Equal(t, "api-1", FieldsFromContext(childCtx)[APIUUID])
+}
+
+func TestJSONLogWithContextFields(t *testing.T) {
+	logger := InitPackageLogger("sample.package3")
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	logFormatter.setLogFormat("json")
+	defer logFormatter.setLogFormat(TEXT)
+
+	ctx := ContextWithFields(context.Background(), Fields{APIUUID: "api-1", CORRELATIONID: "1234"})
+	logger.ErrorCContext(ctx, ErrorDetails{Message: "Test error log", Severity: MAJOR, ErrorCode: 1100})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry), "the log format must be switched to JSON")
+	assert.Equal(t, "Test error log", entry["msg"])
+	assert.Equal(t, "api-1", entry[APIUUID])
+	assert.Equal(t, "1234", entry[CORRELATIONID])
+	assert.Equal(t, MAJOR, entry[SEVERITY])
+	assert.Equal(t, float64(1100), entry[ERRORCODE])
+	assert.Contains(t, entry["file"], "log_context_test.go")
+
+	buf.Reset()
+	logFormatter.setLogFormat(TEXT)
+	logger.FromContext(ctx).Info("Test info log")
+	assert.Contains(t, buf.String(), "Test info log [api_uuid=api-1 correlation_id=1234]")
+}
@@ -22,10 +22,11 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
+	"sync/atomic"
 
 	logrus "github.com/sirupsen/logrus"
-	"github.com/wso2/apk/adapter/pkg/config"
 )
 
 type plainFormatter struct {
@@ -37,36 +38,55 @@ type plainFormatter struct {
 type errorHook struct {
 }
 
-// logFormatter is to keep the relevant log formatter
+// switchableFormatter delegates to the formatter of the log format read from the log configs. It is shared by all
+// the loggers so that a log format change in the reloaded log configs applies to every logger.
+type switchableFormatter struct {
+	formatter atomic.Pointer[logrus.Formatter]
+}
+
+// function names of the formal error log methods, which are skipped when reporting the caller
+var (
+	errorCFunc        = runtime.FuncForPC(reflect.ValueOf((*Log).ErrorC).Pointer()).Name()
+	errorCContextFunc = runtime.FuncForPC(reflect.ValueOf((*Log).ErrorCContext).Pointer()).Name()
+)
+
+// logFormatter is to keep the relevant log formatter. The log format is decided by the configs (LogFormalization)
+// whenever a logger is initialized.
 var (
-	logFormatter logrus.Formatter
+	logFormatter = &switchableFormatter{}
 )
 
-// init will read the configs (LogFormalization) and decide which formatter to be used for logging, when initializing
-func init() {
-	logConf := config.ReadLogConfigs()
-	switch logConf.LogFormat {
+// setLogFormat switches the formatter to the given log format. The TEXT format is used when the format is unknown.
+func (f *switchableFormatter) setLogFormat(logFormat string) {
+	var formatter logrus.Formatter
+	switch strings.ToUpper(logFormat) {
 	case JSON:
-		formatter := new(logrus.JSONFormatter)
-		formatter.TimestampFormat = "2006-01-02 15:04:05"
-		formatter.CallerPrettyfier = func(frame *runtime.Frame) (function string, file string) {
+		jsonFormatter := new(logrus.JSONFormatter)
+		jsonFormatter.TimestampFormat = "2006-01-02 15:04:05"
+		jsonFormatter.CallerPrettyfier = func(frame *runtime.Frame) (function string, file string) {
 			fileArr := strings.Split(frame.File, "/")
 			return formatFilePath(frame.Function), fileArr[len(fileArr)-1] + ":" + fmt.Sprintf("%d", frame.Line)
 		}
-		logFormatter = formatter
+		formatter = jsonFormatter
 
-	case TEXT:
-		formatter := new(plainFormatter)
-		formatter.TimestampFormat = "2006-01-02 15:04:05"
-		formatter.LevelDesc = []string{
+	default:
+		textFormatter := new(plainFormatter)
+		textFormatter.TimestampFormat = "2006-01-02 15:04:05"
+		textFormatter.LevelDesc = []string{
 			panicLevel,
 			fatalLevel,
 			errorLevel,
 			warnLevel,
 			infoLevel,
 			debugLevel}
-		logFormatter = formatter
+		formatter = textFormatter
 	}
+	f.formatter.Store(&formatter)
+}
+
+// Format formats the entry with the formatter of the current log format.
+func (f *switchableFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return (*f.formatter.Load()).Format(entry)
 }
 
 // Format sets a custom format for loggers.
@@ -83,9 +103,14 @@ func (f *plainFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 }
 
 func createKeyValuePairs(m logrus.Fields) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 	b := new(bytes.Buffer)
-	for key, value := range m {
-		fmt.Fprintf(b, "%s=%v ", key, value)
+	for _, key := range keys {
+		fmt.Fprintf(b, "%s=%v ", key, m[key])
 	}
 	return strings.TrimSpace(b.String())
 }
@@ -110,18 +135,20 @@ func (h *errorHook) Fire(e *logrus.Entry) error {
 	if _, ok := e.Data[ERRORCODE]; !ok {
 		e.Data[ERRORCODE] = 0
 	}
-	pcLogPkg, _, _, _ := runtime.Caller(7)
-	detailsLogPkgFunc := runtime.FuncForPC(pcLogPkg)
-	if strings.Contains(detailsLogPkgFunc.Name(), runtime.FuncForPC(reflect.ValueOf((*Log).ErrorC).Pointer()).Name()) {
-		pc, filename, line, _ := runtime.Caller(8)
-		details := runtime.FuncForPC(pc)
-		e.Caller = &runtime.Frame{
-			PC:       pc,
-			Func:     details,
-			Function: details.Name(),
-			File:     filename,
-			Line:     line,
-			Entry:    0,
+	// report the caller of ErrorC instead of ErrorC itself as the caller of the formal error logs
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if frame.Function == errorCFunc || frame.Function == errorCContextFunc {
+			if more {
+				caller, _ := frames.Next()
+				e.Caller = &caller
+			}
+			break
+		}
+		if !more {
+			break
 		}
 	}
 	return nil
@@ -76,12 +76,11 @@ func InitPackageLogger(pkgName string) Log {
 	logger := Log{logrus.New()}
 	logger.SetReportCaller(true)
 
-	formatter := logFormatter
-	logger.SetFormatter(formatter)
-
 	logger.AddHook(&errorHook{})
 
 	logConf := config.ReadLogConfigs()
+	logFormatter.setLogFormat(logConf.LogFormat)
+	logger.SetFormatter(logFormatter)
 
 	// Create the log file if doesn't exist. And append to it if it already exists.
 	_, err := os.OpenFile(logConf.Logfile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
@@ -43,9 +43,10 @@ func initGlobalLogger(filename string) error {
 	// Create the log file if doesn't exist. And append to it if it already exists.
 	_, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
 
+	logConf := config.ReadLogConfigs()
 	logrus.SetReportCaller(true)
-	formatter := logFormatter
-	logrus.SetFormatter(formatter)
+	logFormatter.setLogFormat(logConf.LogFormat)
+	logrus.SetFormatter(logFormatter)
 	logrus.AddHook(&errorHook{})
 
 	if err != nil {
@@ -59,7 +60,6 @@ func initGlobalLogger(filename string) error {
 		logrus.SetOutput(multiWriter)
 	}
 
-	logConf := config.ReadLogConfigs()
 	logrus.SetLevel(logLevelMapper(logConf.LogLevel))
 	return err
 }
@@ -18,6 +18,7 @@ package managementserver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -28,7 +29,7 @@ import (
 )
 
 // HandleDeleteEvent processes a delete event and returns an error if it fails
-func HandleDeleteEvent(event APICPEvent) error {
+func HandleDeleteEvent(ctx context.Context, event APICPEvent) error {
 	cpConfig, err := config.ReadConfigs()
 	envLabel := []string{"Default"}
 	if err == nil {
@@ -52,7 +53,7 @@ func HandleDeleteEvent(event APICPEvent) error {
 	}
 
 	// Delete the API revision
-	if err := utils.DeleteAPIRevision(event.API.APIUUID, event.API.RevisionID, string(jsonPayload)); err != nil {
+	if err := utils.DeleteAPIRevision(ctx, event.API.APIUUID, event.API.RevisionID, string(jsonPayload)); err != nil {
 		logger.LoggerMgtServer.Errorf("Error while undeploying api revision. RevisionId: %s, API ID: %s", event.API.RevisionID, event.API.APIUUID)
 		return fmt.Errorf("failed to undeploy revision: %v", err)
 	}
@@ -60,7 +61,7 @@ func HandleDeleteEvent(event APICPEvent) error {
 }
 
 // HandleCreateOrUpdateEvent processes create or update events and returns id, revisionID, and error
-func HandleCreateOrUpdateEvent(event APICPEvent) (string, string, error) {
+func HandleCreateOrUpdateEvent(ctx context.Context, event APICPEvent) (string, string, error) {
 	// Set default OpenAPI definition for REST APIs if missing
 	if strings.EqualFold(event.API.APIType, "rest") && event.API.Definition == "" {
 		event.API.Definition = utils.OpenAPIDefaultYaml
@@ -126,7 +127,7 @@ func HandleCreateOrUpdateEvent(event APICPEvent) (string, string, error) {
 	}
 
 	// Import API
-	id, revisionID, err := utils.ImportAPI(fmt.Sprintf("admin-%s-%s.zip", event.API.APIName, event.API.APIVersion), &buf)
+	id, revisionID, err := utils.ImportAPI(ctx, fmt.Sprintf("admin-%s-%s.zip", event.API.APIName, event.API.APIVersion), &buf)
 	if err != nil {
 		logger.LoggerMgtServer.Errorf("Error while importing API.")
 		return "", "", fmt.Errorf("failed to import API: %v", err)
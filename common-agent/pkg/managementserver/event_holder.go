@@ -17,14 +17,24 @@
 package managementserver
 
 import (
+	"maps"
+	"sync"
+
 	eventHub "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
 )
 
 var (
-	rateLimitPolicyMap    map[string]eventHub.RateLimitPolicy
-	aiProviderMap         map[string]eventHub.AIProvider
-	subscriptionMap       map[string]Subscription
-	subscriptionPolicyMap map[string]eventHub.SubscriptionPolicy
+	rateLimitPolicyMap      map[string]eventHub.RateLimitPolicy
+	aiProviderMap           map[string]eventHub.AIProvider
+	subscriptionMap         map[string]Subscription
+	subscriptionPolicyMap   map[string]eventHub.SubscriptionPolicy
+	applicationPolicyMap    map[string]eventHub.ApplicationPolicy
+	rateLimitPolicyMutex    sync.RWMutex // Mutex for rateLimitPolicyMap operations
+	aiProviderMutex         sync.RWMutex // Mutex for aiProviderMap operations
+	subscriptionMutex       sync.RWMutex // Mutex for subscriptionMap operations
+	subscriptionPolicyMutex sync.RWMutex // Mutex for subscriptionPolicyMap operations
+	applicationPolicyMutex  sync.RWMutex // Mutex for applicationPolicyMap operations
 )
 
 func init() {
@@ -32,25 +42,34 @@ func init() {
 	aiProviderMap = make(map[string]eventHub.AIProvider)
 	subscriptionMap = make(map[string]Subscription)
 	subscriptionPolicyMap = make(map[string]eventHub.SubscriptionPolicy)
+	applicationPolicyMap = make(map[string]eventHub.ApplicationPolicy)
 }
 
 // AddAIProvider adds an AI provider to the aiProviderMap
 func AddAIProvider(aiProvider eventHub.AIProvider) {
+	aiProviderMutex.Lock()
+	defer aiProviderMutex.Unlock()
 	aiProviderMap[aiProvider.ID] = aiProvider
 }
 
 // GetAIProvider returns an AI provider from the aiProviderMap
 func GetAIProvider(id string) eventHub.AIProvider {
+	aiProviderMutex.RLock()
+	defer aiProviderMutex.RUnlock()
 	return aiProviderMap[id]
 }
 
 // DeleteAIProvider deletes an AI provider from the aiProviderMap
 func DeleteAIProvider(id string) {
+	aiProviderMutex.Lock()
+	defer aiProviderMutex.Unlock()
 	delete(aiProviderMap, id)
 }
 
 // GetAllAIProviders returns all the AI providers in the aiProviderMap
 func GetAllAIProviders() []eventHub.AIProvider {
+	aiProviderMutex.RLock()
+	defer aiProviderMutex.RUnlock()
 	var aiProviders []eventHub.AIProvider
 	for _, aiProvider := range aiProviderMap {
 		aiProviders = append(aiProviders, aiProvider)
@@ -60,31 +79,71 @@ func GetAllAIProviders() []eventHub.AIProvider {
 
 // AddRateLimitPolicy adds a rate limit policy to the rateLimitPolicyMap
 func AddRateLimitPolicy(rateLimitPolicy eventHub.RateLimitPolicy) {
+	rateLimitPolicyMutex.Lock()
+	defer rateLimitPolicyMutex.Unlock()
 	rateLimitPolicyMap[rateLimitPolicy.Name+rateLimitPolicy.TenantDomain] = rateLimitPolicy
 }
 
 // AddSubscriptionPolicy adds a rate limit policy to the subscriptionPolicyMap
 func AddSubscriptionPolicy(rateLimitPolicy eventHub.SubscriptionPolicy) {
+	subscriptionPolicyMutex.Lock()
+	defer subscriptionPolicyMutex.Unlock()
 	subscriptionPolicyMap[rateLimitPolicy.Name+rateLimitPolicy.TenantDomain] = rateLimitPolicy
 }
 
 // GetSubscriptionPolicy returns a subscription policy from the subscriptionPolicyMap
 func GetSubscriptionPolicy(name string, tenantDomain string) eventHub.SubscriptionPolicy {
+	subscriptionPolicyMutex.RLock()
+	defer subscriptionPolicyMutex.RUnlock()
 	return subscriptionPolicyMap[name+tenantDomain]
 }
 
-// GetSubscriptionPolicies return the subscription policy map
+// GetSubscriptionPolicies return a copy of the subscription policy map
 func GetSubscriptionPolicies() map[string]eventHub.SubscriptionPolicy {
-	return subscriptionPolicyMap
+	subscriptionPolicyMutex.RLock()
+	defer subscriptionPolicyMutex.RUnlock()
+	return maps.Clone(subscriptionPolicyMap)
+}
+
+// AddApplicationPolicy adds an application policy to the applicationPolicyMap
+func AddApplicationPolicy(applicationPolicy eventHub.ApplicationPolicy) {
+	applicationPolicyMutex.Lock()
+	defer applicationPolicyMutex.Unlock()
+	applicationPolicyMap[applicationPolicy.Name+applicationPolicy.TenantDomain] = applicationPolicy
+}
+
+// GetApplicationPolicy returns an application policy from the applicationPolicyMap
+func GetApplicationPolicy(name string, tenantDomain string) eventHub.ApplicationPolicy {
+	applicationPolicyMutex.RLock()
+	defer applicationPolicyMutex.RUnlock()
+	return applicationPolicyMap[name+tenantDomain]
+}
+
+// GetApplicationPolicies returns a copy of the application policy map
+func GetApplicationPolicies() map[string]eventHub.ApplicationPolicy {
+	applicationPolicyMutex.RLock()
+	defer applicationPolicyMutex.RUnlock()
+	return maps.Clone(applicationPolicyMap)
+}
+
+// DeleteApplicationPolicy deletes an application policy from the applicationPolicyMap
+func DeleteApplicationPolicy(name string, tenantDomain string) {
+	applicationPolicyMutex.Lock()
+	defer applicationPolicyMutex.Unlock()
+	delete(applicationPolicyMap, name+tenantDomain)
 }
 
 // GetRateLimitPolicy returns a rate limit policy from the rateLimitPolicyMap
 func GetRateLimitPolicy(name string, tenantDomain string) eventHub.RateLimitPolicy {
+	rateLimitPolicyMutex.RLock()
+	defer rateLimitPolicyMutex.RUnlock()
 	return rateLimitPolicyMap[name+tenantDomain]
 }
 
 // GetAllRateLimitPolicies returns all the rate limit policies in the rateLimitPolicyMap
 func GetAllRateLimitPolicies() []eventHub.RateLimitPolicy {
+	rateLimitPolicyMutex.RLock()
+	defer rateLimitPolicyMutex.RUnlock()
 	var rateLimitPolicies []eventHub.RateLimitPolicy
 	for _, rateLimitPolicy := range rateLimitPolicyMap {
 		rateLimitPolicies = append(rateLimitPolicies, rateLimitPolicy)
@@ -94,26 +153,36 @@ func GetAllRateLimitPolicies() []eventHub.RateLimitPolicy {
 
 // DeleteRateLimitPolicy deletes a rate limit policy from the rateLimitPolicyMap
 func DeleteRateLimitPolicy(name string, tenantDomain string) {
+	rateLimitPolicyMutex.Lock()
+	defer rateLimitPolicyMutex.Unlock()
 	delete(rateLimitPolicyMap, name+tenantDomain)
 }
 
 // DeleteSubscriptionPolicy deletes a subscription policy from the subscriptionPolicyMap
 func DeleteSubscriptionPolicy(name string, tenantDomain string) {
+	subscriptionPolicyMutex.Lock()
+	defer subscriptionPolicyMutex.Unlock()
 	delete(subscriptionPolicyMap, name+tenantDomain)
 }
 
 // UpdateRateLimitPolicy updates a rate limit policy in the rateLimitPolicyMap
 func UpdateRateLimitPolicy(name string, tenantDomain string, rateLimitPolicy eventHub.RateLimitPolicy) {
+	rateLimitPolicyMutex.Lock()
+	defer rateLimitPolicyMutex.Unlock()
 	rateLimitPolicyMap[name+tenantDomain] = rateLimitPolicy
 }
 
 // AddSubscription adds a subscription to the subscriptionMap
 func AddSubscription(subscription Subscription) {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
 	subscriptionMap[subscription.UUID] = subscription
 }
 
 // GetAllSubscriptions returns all the subscriptions in the subscriptionMap
 func GetAllSubscriptions() []Subscription {
+	subscriptionMutex.RLock()
+	defer subscriptionMutex.RUnlock()
 	var subscriptions []Subscription
 	for _, subscription := range subscriptionMap {
 		subscriptions = append(subscriptions, subscription)
@@ -121,36 +190,230 @@ func GetAllSubscriptions() []Subscription {
 	return subscriptions
 }
 
+// GetSubscriptionCount returns the number of subscriptions in the subscriptionMap
+func GetSubscriptionCount() int {
+	subscriptionMutex.RLock()
+	defer subscriptionMutex.RUnlock()
+	return len(subscriptionMap)
+}
+
 // GetSubscription returns a subscription from the subscriptionMap
 func GetSubscription(uuid string) Subscription {
+	subscriptionMutex.RLock()
+	defer subscriptionMutex.RUnlock()
 	return subscriptionMap[uuid]
 }
 
 // DeleteSubscription deletes a subscription from the subscriptionMap
 func DeleteSubscription(uuid string) {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
 	delete(subscriptionMap, uuid)
 }
 
 // UpdateSubscription updates a subscription in the subscriptionMap
 func UpdateSubscription(uuid string, subscription Subscription) {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
 	subscriptionMap[uuid] = subscription
 }
 
 // DeleteAllSubscriptions deletes all the subscriptions in the subscriptionMap
 func DeleteAllSubscriptions() {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
 	subscriptionMap = make(map[string]Subscription)
 }
 
 // AddAllSubscriptions adds all the subscriptions in the subscriptionMap
 func AddAllSubscriptions(subscriptionMapTemp map[string]Subscription) {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
 	subscriptionMap = subscriptionMapTemp
 }
 
 // DeleteAllSubscriptionsByApplicationsUUID deletes all the subscriptions in the subscriptionMap
 func DeleteAllSubscriptionsByApplicationsUUID(uuid string) {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
 	for _, subscription := range subscriptionMap {
 		if subscription.Organization == uuid {
 			delete(subscriptionMap, subscription.UUID)
 		}
 	}
 }
+
+// DeleteSubscriptionsByOrganization deletes the subscriptions of the organization from the subscriptionMap
+// and returns their UUIDs
+func DeleteSubscriptionsByOrganization(organization string) []string {
+	subscriptionMutex.Lock()
+	defer subscriptionMutex.Unlock()
+	var deleted []string
+	for uuid, subscription := range subscriptionMap {
+		if subscription.Organization == organization {
+			delete(subscriptionMap, uuid)
+			deleted = append(deleted, uuid)
+		}
+	}
+	return deleted
+}
+
+// DeleteRateLimitPoliciesByTenantDomain deletes the rate limit policies of the tenant domain from the
+// rateLimitPolicyMap and returns their names
+func DeleteRateLimitPoliciesByTenantDomain(tenantDomain string) []string {
+	rateLimitPolicyMutex.Lock()
+	defer rateLimitPolicyMutex.Unlock()
+	var deleted []string
+	for key, rateLimitPolicy := range rateLimitPolicyMap {
+		if rateLimitPolicy.TenantDomain == tenantDomain {
+			delete(rateLimitPolicyMap, key)
+			deleted = append(deleted, rateLimitPolicy.Name)
+		}
+	}
+	return deleted
+}
+
+// DeleteSubscriptionPoliciesByTenantDomain deletes the subscription policies of the tenant domain from the
+// subscriptionPolicyMap and returns their names
+func DeleteSubscriptionPoliciesByTenantDomain(tenantDomain string) []string {
+	subscriptionPolicyMutex.Lock()
+	defer subscriptionPolicyMutex.Unlock()
+	var deleted []string
+	for key, subscriptionPolicy := range subscriptionPolicyMap {
+		if subscriptionPolicy.TenantDomain == tenantDomain {
+			delete(subscriptionPolicyMap, key)
+			deleted = append(deleted, subscriptionPolicy.Name)
+		}
+	}
+	return deleted
+}
+
+// DeleteApplicationPoliciesByTenantDomain deletes the application policies of the tenant domain from the
+// applicationPolicyMap and returns their names
+func DeleteApplicationPoliciesByTenantDomain(tenantDomain string) []string {
+	applicationPolicyMutex.Lock()
+	defer applicationPolicyMutex.Unlock()
+	var deleted []string
+	for key, applicationPolicy := range applicationPolicyMap {
+		if applicationPolicy.TenantDomain == tenantDomain {
+			delete(applicationPolicyMap, key)
+			deleted = append(deleted, applicationPolicy.Name)
+		}
+	}
+	return deleted
+}
+
+// RetainRateLimitPolicies deletes the rate limit policies which are not in the given list from the
+// rateLimitPolicyMap and returns their names
+func RetainRateLimitPolicies(rateLimitPolicies []eventHub.RateLimitPolicy) []string {
+	retained := make(map[string]struct{}, len(rateLimitPolicies))
+	for _, rateLimitPolicy := range rateLimitPolicies {
+		retained[rateLimitPolicy.Name+rateLimitPolicy.TenantDomain] = struct{}{}
+	}
+	rateLimitPolicyMutex.Lock()
+	defer rateLimitPolicyMutex.Unlock()
+	var deleted []string
+	for key, rateLimitPolicy := range rateLimitPolicyMap {
+		if _, exists := retained[key]; !exists {
+			delete(rateLimitPolicyMap, key)
+			deleted = append(deleted, rateLimitPolicy.Name)
+		}
+	}
+	return deleted
+}
+
+// RetainSubscriptionPolicies deletes the subscription policies which are not in the given list from the
+// subscriptionPolicyMap and returns their names
+func RetainSubscriptionPolicies(subscriptionPolicies []eventHub.SubscriptionPolicy) []string {
+	retained := make(map[string]struct{}, len(subscriptionPolicies))
+	for _, subscriptionPolicy := range subscriptionPolicies {
+		retained[subscriptionPolicy.Name+subscriptionPolicy.TenantDomain] = struct{}{}
+	}
+	subscriptionPolicyMutex.Lock()
+	defer subscriptionPolicyMutex.Unlock()
+	var deleted []string
+	for key, subscriptionPolicy := range subscriptionPolicyMap {
+		if _, exists := retained[key]; !exists {
+			delete(subscriptionPolicyMap, key)
+			deleted = append(deleted, subscriptionPolicy.Name)
+		}
+	}
+	return deleted
+}
+
+// RetainApplicationPolicies deletes the application policies which are not in the given list from the
+// applicationPolicyMap and returns their names
+func RetainApplicationPolicies(applicationPolicies []eventHub.ApplicationPolicy) []string {
+	retained := make(map[string]struct{}, len(applicationPolicies))
+	for _, applicationPolicy := range applicationPolicies {
+		retained[applicationPolicy.Name+applicationPolicy.TenantDomain] = struct{}{}
+	}
+	applicationPolicyMutex.Lock()
+	defer applicationPolicyMutex.Unlock()
+	var deleted []string
+	for key, applicationPolicy := range applicationPolicyMap {
+		if _, exists := retained[key]; !exists {
+			delete(applicationPolicyMap, key)
+			deleted = append(deleted, applicationPolicy.Name)
+		}
+	}
+	return deleted
+}
+
+// RetainAIProviders deletes the AI providers which are not in the given list from the aiProviderMap and returns
+// their IDs
+func RetainAIProviders(aiProviders []eventHub.AIProvider) []string {
+	retained := make(map[string]struct{}, len(aiProviders))
+	for _, aiProvider := range aiProviders {
+		retained[aiProvider.ID] = struct{}{}
+	}
+	aiProviderMutex.Lock()
+	defer aiProviderMutex.Unlock()
+	var deleted []string
+	for id := range aiProviderMap {
+		if _, exists := retained[id]; !exists {
+			delete(aiProviderMap, id)
+			deleted = append(deleted, id)
+		}
+	}
+	return deleted
+}
+
+// RegisterSnapshotStores registers the maps of the management server to be persisted in the snapshot
+func RegisterSnapshotStores() {
+	snapshot.Register("subscriptions", func() map[string]Subscription {
+		subscriptionMutex.RLock()
+		defer subscriptionMutex.RUnlock()
+		return maps.Clone(subscriptionMap)
+	}, AddAllSubscriptions)
+	snapshot.Register("rateLimitPolicies", func() map[string]eventHub.RateLimitPolicy {
+		rateLimitPolicyMutex.RLock()
+		defer rateLimitPolicyMutex.RUnlock()
+		return maps.Clone(rateLimitPolicyMap)
+	}, func(rateLimitPolicies map[string]eventHub.RateLimitPolicy) {
+		rateLimitPolicyMutex.Lock()
+		defer rateLimitPolicyMutex.Unlock()
+		rateLimitPolicyMap = rateLimitPolicies
+	})
+	snapshot.Register("subscriptionPolicies", GetSubscriptionPolicies,
+		func(subscriptionPolicies map[string]eventHub.SubscriptionPolicy) {
+			subscriptionPolicyMutex.Lock()
+			defer subscriptionPolicyMutex.Unlock()
+			subscriptionPolicyMap = subscriptionPolicies
+		})
+	snapshot.Register("applicationPolicies", GetApplicationPolicies,
+		func(applicationPolicies map[string]eventHub.ApplicationPolicy) {
+			applicationPolicyMutex.Lock()
+			defer applicationPolicyMutex.Unlock()
+			applicationPolicyMap = applicationPolicies
+		})
+	snapshot.Register("aiProviders", func() map[string]eventHub.AIProvider {
+		aiProviderMutex.RLock()
+		defer aiProviderMutex.RUnlock()
+		return maps.Clone(aiProviderMap)
+	}, func(aiProviders map[string]eventHub.AIProvider) {
+		aiProviderMutex.Lock()
+		defer aiProviderMutex.Unlock()
+		aiProviderMap = aiProviders
+	})
+}
@@ -18,8 +18,10 @@ package managementserver
 
 import (
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/utils"
 	apkmgt "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -39,8 +41,11 @@ func (s EventServer) StreamEvents(req *apkmgt.Request, srv apkmgt.EventStreamSer
 	}
 	commonControllerID := md.Get("common-controller-uuid")
 	logger.LoggerMgtServer.Debugf("Enforcer ID : %v", commonControllerID[0])
+	_, span := tracing.StartSpan(tracing.ExtractFromGRPCMetadata(srv.Context()), "EventStreamService/StreamEvents",
+		attribute.String("client.id", commonControllerID[0]))
 	utils.AddClientConnection(commonControllerID[0], srv)
 	utils.SendInitialEvent(srv)
+	span.End()
 	<-srv.Context().Done()
 	logger.LoggerMgtServer.Infof("Connection closed by the client : %v", commonControllerID[0])
 	utils.DeleteClientConnection(commonControllerID[0])
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	eventHub "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 )
 
 func TestAddSubscription(t *testing.T) {
@@ -99,3 +100,35 @@ func TestDeleteAllSubscriptionsByApplicationsUUID(t *testing.T) {
 		assert.NotEqual(t, uuid, sub.Organization)
 	}
 }
+
+func TestDeleteSubscriptionsByOrganization(t *testing.T) {
+	subscriptionMap = map[string]Subscription{
+		"sub1": {UUID: "sub1", Organization: "Org1"},
+		"sub2": {UUID: "sub2", Organization: "Org2"},
+	}
+	assert.Equal(t, []string{"sub1"}, DeleteSubscriptionsByOrganization("Org1"))
+	assert.Len(t, subscriptionMap, 1)
+	assert.Empty(t, DeleteSubscriptionsByOrganization("Org1"))
+}
+
+func TestRetainApplicationPolicies(t *testing.T) {
+	applicationPolicyMap = map[string]eventHub.ApplicationPolicy{}
+	AddApplicationPolicy(eventHub.ApplicationPolicy{Name: "10PerMin", TenantDomain: "Org1"})
+	AddApplicationPolicy(eventHub.ApplicationPolicy{Name: "20PerMin", TenantDomain: "Org1"})
+	AddApplicationPolicy(eventHub.ApplicationPolicy{Name: "10PerMin", TenantDomain: "Org2"})
+
+	deleted := RetainApplicationPolicies([]eventHub.ApplicationPolicy{{Name: "10PerMin", TenantDomain: "Org1"}})
+	assert.ElementsMatch(t, []string{"20PerMin", "10PerMin"}, deleted)
+	assert.Equal(t, "10PerMin", GetApplicationPolicy("10PerMin", "Org1").Name)
+	assert.Len(t, GetApplicationPolicies(), 1)
+}
+
+func TestDeleteApplicationPoliciesByTenantDomain(t *testing.T) {
+	applicationPolicyMap = map[string]eventHub.ApplicationPolicy{}
+	AddApplicationPolicy(eventHub.ApplicationPolicy{Name: "10PerMin", TenantDomain: "Org1"})
+	AddApplicationPolicy(eventHub.ApplicationPolicy{Name: "10PerMin", TenantDomain: "Org2"})
+
+	assert.Equal(t, []string{"10PerMin"}, DeleteApplicationPoliciesByTenantDomain("Org1"))
+	assert.Empty(t, GetApplicationPolicy("10PerMin", "Org1").Name)
+	assert.Equal(t, "10PerMin", GetApplicationPolicy("10PerMin", "Org2").Name)
+}
@@ -0,0 +1,154 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+)
+
+// Supported broker types
+const (
+	AMQPBrokerType  = "amqp"
+	KafkaBrokerType = "kafka"
+	NATSBrokerType  = "nats"
+)
+
+// eventTopics holds the topics the agent subscribes to
+var eventTopics = []string{notification, keymanager, tokenRevocation, throttleData, organizationPurge}
+
+// Broker defines the functions of a pluggable event broker the control plane events are consumed from
+type Broker interface {
+	// Connect connects to the first reachable endpoint, failing over to the other endpoints. The broker
+	// reconnects on its own if the connection drops afterwards.
+	Connect(endpoints []string, options QueueOptions) error
+	// Subscribe consumes the events of the topic and passes them to the event channel of the topic
+	Subscribe(topic string) error
+	// Close closes the connection to the broker
+	Close() error
+}
+
+// BrokerEvent is a control plane event received from a broker. Every event must be either acknowledged or
+// negatively acknowledged once it is processed.
+type BrokerEvent struct {
+	Body []byte
+	ack  func()
+	nack func(retriable bool)
+	once *sync.Once
+}
+
+// newEvent creates an event which runs only the first of its acknowledgements
+func newEvent(body []byte, ack func(), nack func(retriable bool)) BrokerEvent {
+	return BrokerEvent{Body: body, ack: ack, nack: nack, once: &sync.Once{}}
+}
+
+// Ack acknowledges the event once it has been processed successfully
+func (e BrokerEvent) Ack() {
+	if e.once != nil && e.ack != nil {
+		e.once.Do(e.ack)
+	}
+}
+
+// Nack negatively acknowledges an event that could not be processed. Retriable events are redelivered
+// until the redelivery limit is reached, after which they are moved to the dead letter queue.
+func (e BrokerEvent) Nack(retriable bool) {
+	if e.once != nil && e.nack != nil {
+		e.once.Do(func() { e.nack(retriable) })
+	}
+}
+
+// newBroker returns the broker implementation of the broker type
+func newBroker(brokerType string) (Broker, error) {
+	switch strings.ToLower(brokerType) {
+	case "", AMQPBrokerType:
+		return &amqpBroker{}, nil
+	case KafkaBrokerType:
+		return &kafkaBroker{}, nil
+	case NATSBrokerType:
+		return &natsBroker{}, nil
+	}
+	return nil, fmt.Errorf("unsupported broker type %q", brokerType)
+}
+
+// getEventChannel returns the channel the events of the topic are passed to
+func getEventChannel(topic string) chan BrokerEvent {
+	switch {
+	case strings.EqualFold(topic, notification):
+		return NotificationChannel
+	case strings.EqualFold(topic, keymanager):
+		return KeyManagerChannel
+	case strings.EqualFold(topic, tokenRevocation):
+		return RevokedTokenChannel
+	case strings.EqualFold(topic, throttleData):
+		return ThrottleDataChannel
+	case strings.EqualFold(topic, organizationPurge):
+		return OrganizationPurgeChannel
+	}
+	return nil
+}
+
+// InitiateBrokerConnection connects to the broker of the given type and subscribes to the control plane event topics
+func InitiateBrokerConnection(brokerType string, eventListeningEndpoints []string, options QueueOptions) error {
+	broker, err := newBroker(brokerType)
+	if err != nil {
+		return err
+	}
+	if options.NamePrefix, err = resolveNamePrefix(options.NamePrefix, options.Durable); err != nil {
+		return err
+	}
+	queueOptions = options
+	logger.LoggerMsg.Infof("Connecting to the %s event broker", brokerType)
+	if err = broker.Connect(eventListeningEndpoints, options); err != nil {
+		return err
+	}
+	for i, topic := range eventTopics {
+		logger.LoggerMsg.Infof("Establishing consumer index %v for key %s ", i, topic)
+		if err := broker.Subscribe(topic); err != nil {
+			logger.LoggerMsg.Errorf("Error while subscribing to %s events: %v", topic, err)
+		}
+	}
+	return nil
+}
+
+// amqpBroker consumes the events from an AMQP broker such as the one embedded in API Manager
+type amqpBroker struct{}
+
+func (b *amqpBroker) Connect(endpoints []string, options QueueOptions) error {
+	var err error
+	EventListeningEndpoints = endpoints
+	RabbitConn, err = connectToRabbitMQ()
+	return err
+}
+
+func (b *amqpBroker) Subscribe(topic string) error {
+	go func(key string) {
+		startConsumer(key)
+		select {}
+	}(topic)
+	return nil
+}
+
+func (b *amqpBroker) Close() error {
+	if RabbitConn == nil || RabbitConn.IsClosed() {
+		return nil
+	}
+	return RabbitConn.Close()
+}
@@ -27,25 +27,30 @@ import (
 	"time"
 
 	"github.com/streadway/amqp"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/health"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 )
 
 var (
 	// NotificationChannel stores the Events for notifications
-	NotificationChannel chan amqp.Delivery
+	NotificationChannel chan BrokerEvent
 	// KeyManagerChannel stores the key manager eventsv
-	KeyManagerChannel chan amqp.Delivery
+	KeyManagerChannel chan BrokerEvent
 	// RevokedTokenChannel stores the revoked token events
-	RevokedTokenChannel chan amqp.Delivery
+	RevokedTokenChannel chan BrokerEvent
 	// ThrottleDataChannel stores the throttling related events
-	ThrottleDataChannel chan amqp.Delivery
+	ThrottleDataChannel chan BrokerEvent
+	// OrganizationPurgeChannel stores the organization purge events
+	OrganizationPurgeChannel chan BrokerEvent
 )
 
 func init() {
-	NotificationChannel = make(chan amqp.Delivery)
-	KeyManagerChannel = make(chan amqp.Delivery)
-	RevokedTokenChannel = make(chan amqp.Delivery)
-	ThrottleDataChannel = make(chan amqp.Delivery)
+	NotificationChannel = make(chan BrokerEvent)
+	KeyManagerChannel = make(chan BrokerEvent)
+	RevokedTokenChannel = make(chan BrokerEvent)
+	ThrottleDataChannel = make(chan BrokerEvent)
+	OrganizationPurgeChannel = make(chan BrokerEvent)
 }
 
 // EventListeningEndpoints represents the list of endpoints
@@ -61,6 +66,7 @@ func connectToRabbitMQ() (*amqp.Connection, error) {
 		conn, err = amqp.Dial(url.URL)
 		if err == nil {
 			logger.LoggerMsg.Infof("Successfully established the AMQP connection on URI [%d], %q", index, maskURL(url.URL)+"/")
+			health.BrokerConnectionService.SetStatus(true)
 			return conn, nil
 		}
 	}
@@ -93,6 +99,8 @@ func (c *Consumer) reconnect(key string) {
 	}
 
 	if shouldReconnect {
+		metrics.RecordAMQPReconnect()
+		health.BrokerConnectionService.SetStatus(false)
 		c.Conn.Close()
 		c, RabbitConn, err = connectionRetry(key)
 		if err != nil {
@@ -128,6 +136,7 @@ func connectionRetry(key string) (*Consumer, *amqp.Connection, error) {
 			RabbitConn, err = amqp.Dial(amqpURIArray[j].URL + "/")
 			if err == nil {
 				logger.LoggerMsg.Infof("Successfully connected to %s (URI %d) after %d attempts", maskURL(amqpURIArray[j].URL), j, i)
+				health.BrokerConnectionService.SetStatus(true)
 				if key != "" && len(key) > 0 {
 					logger.LoggerMsg.Infof("Reconnected to topic %s", key)
 					// startup pull
@@ -226,18 +235,17 @@ func handleEvent(c *Consumer, key string) error {
 		return fmt.Errorf("Exchange Declare: %s", err)
 	}
 
-	logger.LoggerMsg.Infof("declared Exchange, declaring Queue %q", key+"queue")
-	queue, err := c.Channel.QueueDeclare(
-		"",    // name of the queue
-		false, // durable
-		true,  // delete when usused
-		false, // exclusive
-		false, // noWait
-		nil,   // arguments
-	)
+	logger.LoggerMsg.Infof("declared Exchange, declaring Queue for %q", key)
+	queue, err := declareQueue(c, key)
 	if err != nil {
-		return fmt.Errorf("Error while declaring queue: %s", err)
+		return err
+	}
+	if c.Durable {
+		if err = c.Channel.Qos(prefetchCount, 0, false); err != nil {
+			return fmt.Errorf("Channel Qos: %s", err)
+		}
 	}
+	registerConsumer(c)
 
 	logger.LoggerMsg.Debugf("Binding to Exchange (key %q) after declaring the Queue (%q %d messages, %d consumers)",
 		key, queue.Name, queue.Messages, queue.Consumers)
@@ -261,41 +269,20 @@ func handleEvent(c *Consumer, key string) error {
 		false,      // noWait
 		nil,        // arguments
 	)
-	if strings.EqualFold(key, notification) {
-		for event := range deliveries {
-			NotificationChannel <- event
-		}
-	} else if strings.EqualFold(key, keymanager) {
-		for event := range deliveries {
-			KeyManagerChannel <- event
-		}
-	} else if strings.EqualFold(key, tokenRevocation) {
-		for event := range deliveries {
-			RevokedTokenChannel <- event
-		}
-	} else if strings.EqualFold(key, throttleData) {
-		for event := range deliveries {
-			ThrottleDataChannel <- event
-		}
+	if err != nil {
+		return fmt.Errorf("Queue Consume: %s", err)
+	}
+	eventChannel := getEventChannel(key)
+	if eventChannel == nil {
+		return fmt.Errorf("No event channel for the key %s", key)
+	}
+	for delivery := range deliveries {
+		eventChannel <- newAMQPEvent(delivery)
 	}
 	return nil
 }
 
 // InitiateJMSConnection to pass event consumption
-func InitiateJMSConnection(eventListeningEndpoints []string) error {
-	var err error
-	EventListeningEndpoints = eventListeningEndpoints
-	bindingKeys := []string{notification, keymanager, tokenRevocation, throttleData}
-	RabbitConn, err = connectToRabbitMQ()
-
-	if err == nil {
-		for i, key := range bindingKeys {
-			logger.LoggerMsg.Infof("Establishing consumer index %v for key %s ", i, key)
-			go func(key string) {
-				startConsumer(key)
-				select {}
-			}(key)
-		}
-	}
-	return err
+func InitiateJMSConnection(eventListeningEndpoints []string, options QueueOptions) error {
+	return InitiateBrokerConnection(AMQPBrokerType, eventListeningEndpoints, options)
 }
@@ -0,0 +1,245 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/streadway/amqp"
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+)
+
+// QueueOptions holds the options used to declare the queues the events are consumed from
+type QueueOptions struct {
+	// Durable declares named durable queues along with a dead letter queue
+	Durable bool
+	// NamePrefix identifies the agent instance in the queue names and must be unique per instance, as the
+	// instances sharing a queue split its events. The name of the StatefulSet pod is used for the durable queues
+	// when empty, and the name of the pod, or the hostname, for the others.
+	NamePrefix string
+	// GatewayLabel identifies the gateway environment in the queue names
+	GatewayLabel string
+	// MaxRedeliveryCount is the number of redeliveries of a failed event before it is dead lettered
+	MaxRedeliveryCount int
+	// TopicPrefix is prepended to the topic or subject names of the Kafka and NATS brokers
+	TopicPrefix string
+}
+
+// podNameEnv is the environment variable holding the name of the pod of the agent instance
+const podNameEnv = "POD_NAME"
+
+// statefulSetPodNameEnv is the environment variable holding the name of the StatefulSet pod of the agent instance,
+// which is kept when the pod is replaced
+const statefulSetPodNameEnv = "STATEFULSET_POD_NAME"
+
+var (
+	queueOptions    QueueOptions
+	consumers       = make(map[string]*Consumer)
+	consumersMutex  sync.RWMutex
+	invalidQueueChr = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+	// durableKeys holds the binding keys whose events are processed by the agent and hence kept in durable queues
+	durableKeys = map[string]bool{notification: true, keymanager: true, organizationPurge: true}
+)
+
+// resolveNamePrefix returns the configured queue name prefix. As the durable queues outlive the agent instance, they
+// are named after the StatefulSet pod of the instance when no prefix is configured, and a stable name is required. The
+// other queues are named after the pod, or the host, of the instance so that every instance consumes all the events.
+func resolveNamePrefix(prefix string, durable bool) (string, error) {
+	if prefix != "" {
+		return prefix, nil
+	}
+	if durable {
+		if podName := os.Getenv(statefulSetPodNameEnv); podName != "" {
+			return podName, nil
+		}
+		return "", fmt.Errorf("durable queues require a stable queue name prefix per agent instance, configure the " +
+			"queueNamePrefix or run the agent as a StatefulSet exposing its pod name in " + statefulSetPodNameEnv)
+	}
+	if podName := os.Getenv(podNameEnv); podName != "" {
+		return podName, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.LoggerMsg.Warnf("Unable to resolve the hostname to name the queues of the agent instance: %v", err)
+		return "", nil
+	}
+	return hostname, nil
+}
+
+// getQueueName returns the name of the queue for the binding key. An empty name lets the broker generate the
+// name of a non durable queue.
+func getQueueName(key string) string {
+	if !queueOptions.Durable || !durableKeys[key] {
+		return ""
+	}
+	return getConsumerName(key)
+}
+
+// getConsumerName returns the name identifying the consumer of the binding key of this agent instance
+func getConsumerName(key string) string {
+	name := key
+	if queueOptions.GatewayLabel != "" {
+		name = queueOptions.GatewayLabel + "-" + name
+	}
+	if queueOptions.NamePrefix != "" {
+		name = queueOptions.NamePrefix + "-" + name
+	}
+	return invalidQueueChr.ReplaceAllString(name, "_")
+}
+
+// getDeadLetterQueueName returns the name of the dead letter queue of a durable queue
+func getDeadLetterQueueName(queueName string) string {
+	return queueName + deadLetterQueueSuffix
+}
+
+// registerConsumer keeps track of the consumer so that its deliveries can be redelivered or dead lettered
+func registerConsumer(c *Consumer) {
+	consumersMutex.Lock()
+	defer consumersMutex.Unlock()
+	consumers[c.Tag] = c
+}
+
+func getConsumer(tag string) *Consumer {
+	consumersMutex.RLock()
+	defer consumersMutex.RUnlock()
+	return consumers[tag]
+}
+
+// getRedeliveryCount returns how many times the event has been redelivered by the agent
+func getRedeliveryCount(d amqp.Delivery) int {
+	switch count := d.Headers[redeliveryCountHeader].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	}
+	return 0
+}
+
+// ackDelivery acknowledges an event once it has been processed successfully
+func ackDelivery(d amqp.Delivery) {
+	if err := d.Ack(false); err != nil {
+		logger.LoggerMsg.Errorf("Error while acknowledging the event with delivery tag %d: %v", d.DeliveryTag, err)
+	}
+}
+
+// nackDelivery negatively acknowledges an event that could not be processed. Retriable events are published
+// back to the queue until the redelivery limit is reached. Events that are not retriable or have exhausted
+// their redeliveries are moved to the dead letter queue, or dropped when the queue is not durable.
+func nackDelivery(d amqp.Delivery, retriable bool) {
+	consumer := getConsumer(d.ConsumerTag)
+	if consumer == nil || consumer.QueueName == "" {
+		logger.LoggerMsg.Warnf("Dropping the failed event with delivery tag %d as its queue is unknown", d.DeliveryTag)
+		rejectDelivery(d, false)
+		return
+	}
+
+	redeliveryCount := getRedeliveryCount(d)
+	if retriable && redeliveryCount < queueOptions.MaxRedeliveryCount {
+		logger.LoggerMsg.Infof("Redelivering the failed event to queue %s, attempt %d of %d",
+			consumer.QueueName, redeliveryCount+1, queueOptions.MaxRedeliveryCount)
+		republish(consumer, d, consumer.QueueName, redeliveryCount+1)
+		return
+	}
+	if !consumer.Durable {
+		logger.LoggerMsg.Warnf("Dropping the failed event from queue %s after %d redeliveries", consumer.QueueName, redeliveryCount)
+		rejectDelivery(d, false)
+		return
+	}
+	deadLetterQueue := getDeadLetterQueueName(consumer.QueueName)
+	logger.LoggerMsg.Warnf("Moving the failed event to the dead letter queue %s after %d redeliveries", deadLetterQueue, redeliveryCount)
+	republish(consumer, d, deadLetterQueue, redeliveryCount)
+}
+
+// republish publishes a copy of the delivery to the given queue through the default exchange and acknowledges
+// the original. The original is requeued by the broker if the copy cannot be published.
+func republish(c *Consumer, d amqp.Delivery, queueName string, redeliveryCount int) {
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[redeliveryCountHeader] = int32(redeliveryCount)
+
+	err := c.Channel.Publish("", queueName, false, false, amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		CorrelationId:   d.CorrelationId,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		Body:            d.Body,
+	})
+	if err != nil {
+		logger.LoggerMsg.Errorf("Error while publishing the failed event to queue %s, requeueing it: %v", queueName, err)
+		rejectDelivery(d, true)
+		return
+	}
+	ackDelivery(d)
+}
+
+func rejectDelivery(d amqp.Delivery, requeue bool) {
+	if err := d.Nack(false, requeue); err != nil {
+		logger.LoggerMsg.Errorf("Error while rejecting the event with delivery tag %d: %v", d.DeliveryTag, err)
+	}
+}
+
+// newAMQPEvent wraps an AMQP delivery as an event acknowledged on the channel it was delivered from
+func newAMQPEvent(d amqp.Delivery) BrokerEvent {
+	return newEvent(d.Body, func() { ackDelivery(d) }, func(retriable bool) { nackDelivery(d, retriable) })
+}
+
+// declareQueue declares the queue for the binding key along with its dead letter queue when durable
+func declareQueue(c *Consumer, key string) (amqp.Queue, error) {
+	queueName := getQueueName(key)
+	c.Durable = queueName != ""
+	if c.Durable {
+		deadLetterQueue := getDeadLetterQueueName(queueName)
+		logger.LoggerMsg.Infof("Declaring dead letter queue %q", deadLetterQueue)
+		if _, err := c.Channel.QueueDeclare(
+			deadLetterQueue, // name of the queue
+			true,            // durable
+			false,           // delete when unused
+			false,           // exclusive
+			false,           // noWait
+			nil,             // arguments
+		); err != nil {
+			return amqp.Queue{}, fmt.Errorf("Error while declaring dead letter queue: %s", err)
+		}
+	}
+
+	queue, err := c.Channel.QueueDeclare(
+		queueName,  // name of the queue
+		c.Durable,  // durable
+		!c.Durable, // delete when unused
+		false,      // exclusive
+		false,      // noWait
+		nil,        // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("Error while declaring queue: %s", err)
+	}
+	c.QueueName = queue.Name
+	return queue, nil
+}
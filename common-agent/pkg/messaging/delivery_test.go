@@ -0,0 +1,106 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"os"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetQueueName(t *testing.T) {
+	queueOptions = QueueOptions{Durable: true, NamePrefix: "agent-1", GatewayLabel: "Default Env"}
+	assert.Equal(t, "agent-1-Default_Env-notification", getQueueName(notification))
+	assert.Equal(t, "agent-1-Default_Env-notification.dlq", getDeadLetterQueueName(getQueueName(notification)))
+	// Events that are not processed by the agent are not kept in durable queues
+	assert.Equal(t, "", getQueueName(throttleData))
+
+	queueOptions = QueueOptions{Durable: false, NamePrefix: "agent-1"}
+	assert.Equal(t, "", getQueueName(notification))
+}
+
+func TestGetConsumerNameIsUniquePerInstance(t *testing.T) {
+	first, _ := resolveNamePrefix("agent-1", true)
+	queueOptions = QueueOptions{NamePrefix: first, GatewayLabel: "Default"}
+	firstName := getConsumerName(notification)
+	second, _ := resolveNamePrefix("agent-2", true)
+	queueOptions = QueueOptions{NamePrefix: second, GatewayLabel: "Default"}
+	assert.NotEqual(t, firstName, getConsumerName(notification))
+}
+
+func TestResolveNamePrefix(t *testing.T) {
+	prefix, err := resolveNamePrefix("agent-1", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "agent-1", prefix)
+
+	t.Setenv(podNameEnv, "agent-7d9c-x2k")
+	t.Setenv(statefulSetPodNameEnv, "")
+	prefix, err = resolveNamePrefix("", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "agent-7d9c-x2k", prefix)
+	// The name of a pod which is not run by a StatefulSet changes when the pod is replaced
+	_, err = resolveNamePrefix("", true)
+	assert.Error(t, err)
+
+	t.Setenv(statefulSetPodNameEnv, "agent-0")
+	prefix, err = resolveNamePrefix("", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "agent-0", prefix)
+
+	t.Setenv(podNameEnv, "")
+	hostname, _ := os.Hostname()
+	prefix, err = resolveNamePrefix("", false)
+	assert.NoError(t, err)
+	assert.Equal(t, hostname, prefix)
+}
+
+func TestGetRedeliveryCount(t *testing.T) {
+	assert.Equal(t, 0, getRedeliveryCount(amqp.Delivery{}))
+	assert.Equal(t, 2, getRedeliveryCount(amqp.Delivery{Headers: amqp.Table{redeliveryCountHeader: int32(2)}}))
+	assert.Equal(t, 3, getRedeliveryCount(amqp.Delivery{Headers: amqp.Table{redeliveryCountHeader: int64(3)}}))
+}
+
+func TestBrokerEventIsAcknowledgedOnce(t *testing.T) {
+	acks, nacks := 0, 0
+	event := newEvent([]byte("{}"), func() { acks++ }, func(bool) { nacks++ })
+	event.Nack(true)
+	event.Ack()
+	event.Nack(false)
+	assert.Equal(t, 0, acks)
+	assert.Equal(t, 1, nacks)
+
+	// Events without acknowledgements are ignored
+	BrokerEvent{}.Ack()
+}
+
+func TestNewBroker(t *testing.T) {
+	broker, err := newBroker("")
+	assert.NoError(t, err)
+	assert.IsType(t, &amqpBroker{}, broker)
+	broker, err = newBroker("Kafka")
+	assert.NoError(t, err)
+	assert.IsType(t, &kafkaBroker{}, broker)
+	broker, err = newBroker(NATSBrokerType)
+	assert.NoError(t, err)
+	assert.IsType(t, &natsBroker{}, broker)
+	_, err = newBroker("mqtt")
+	assert.Error(t, err)
+	assert.Nil(t, getEventChannel("unknown"))
+}
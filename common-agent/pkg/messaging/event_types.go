@@ -18,6 +18,8 @@
 // Package messaging holds the implementation for event listeners functions
 package messaging
 
+import "slices"
+
 // EventNotification for struct event notifications
 type EventNotification struct {
 	Event struct {
@@ -77,6 +79,17 @@ type Organization struct {
 	Name   string `json:"name"`
 }
 
+// Identifiers returns the distinct identifiers the resources of the organization may be labelled with
+func (o Organization) Identifiers() []string {
+	identifiers := make([]string, 0, 3)
+	for _, identifier := range []string{o.Handle, o.ID, o.UUID} {
+		if identifier != "" && !slices.Contains(identifiers, identifier) {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return identifiers
+}
+
 // EventKeyManagerNotification for struct
 type EventKeyManagerNotification struct {
 	Event struct {
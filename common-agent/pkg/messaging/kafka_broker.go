@@ -0,0 +1,213 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/health"
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+)
+
+const (
+	kafkaScheme              string        = "kafka://"
+	kafkaDialTimeout         time.Duration = 10 * time.Second
+	kafkaHealthCheckInterval time.Duration = 30 * time.Second
+)
+
+// kafkaBroker consumes the events from Kafka topics. Each topic is consumed by a consumer group of the agent
+// instance so that its offsets survive restarts, and the failed events are written to a dead letter topic.
+type kafkaBroker struct {
+	brokers []string
+	options QueueOptions
+	writer  *kafka.Writer
+	readers []*kafka.Reader
+	done    chan struct{}
+}
+
+func (b *kafkaBroker) Connect(endpoints []string, options QueueOptions) error {
+	b.options = options
+	b.brokers = make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		for _, address := range strings.Split(endpoint, ",") {
+			address = strings.TrimPrefix(strings.TrimSpace(address), kafkaScheme)
+			if address != "" {
+				b.brokers = append(b.brokers, address)
+			}
+		}
+	}
+	if len(b.brokers) == 0 {
+		return fmt.Errorf("no Kafka broker endpoints are configured")
+	}
+
+	if err := b.dial(); err != nil {
+		return fmt.Errorf("unable to connect to any of the Kafka brokers: %v", err)
+	}
+	health.BrokerConnectionService.SetStatus(true)
+	// The readers reconnect to the brokers on their own, hence the connectivity is checked separately
+	b.done = make(chan struct{})
+	go b.monitorConnection()
+	b.writer = &kafka.Writer{
+		Addr:                   kafka.TCP(b.brokers...),
+		Balancer:               &kafka.LeastBytes{},
+		AllowAutoTopicCreation: true,
+	}
+	return nil
+}
+
+// dial connects to the first reachable broker and closes the connection
+func (b *kafkaBroker) dial() error {
+	var err error
+	for index, address := range b.brokers {
+		logger.LoggerMsg.Debugf("Dialing Kafka broker [%d] %q", index, address)
+		ctx, cancel := context.WithTimeout(context.Background(), kafkaDialTimeout)
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", address)
+		cancel()
+		if err == nil {
+			conn.Close()
+			logger.LoggerMsg.Debugf("Successfully connected to the Kafka broker [%d] %q", index, address)
+			return nil
+		}
+		logger.LoggerMsg.Warnf("Unable to connect to the Kafka broker [%d] %q: %v", index, address, err)
+	}
+	return err
+}
+
+// monitorConnection reports the broker connection status periodically until the broker is closed
+func (b *kafkaBroker) monitorConnection() {
+	ticker := time.NewTicker(kafkaHealthCheckInterval)
+	defer ticker.Stop()
+	connected := true
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			err := b.dial()
+			if (err == nil) != connected {
+				connected = err == nil
+				if connected {
+					logger.LoggerMsg.Info("Reconnected to the Kafka brokers")
+				} else {
+					logger.LoggerMsg.Errorf("Lost the connection to the Kafka brokers: %v", err)
+				}
+			}
+			health.BrokerConnectionService.SetStatus(connected)
+		}
+	}
+}
+
+func (b *kafkaBroker) Subscribe(topic string) error {
+	eventChannel := getEventChannel(topic)
+	if eventChannel == nil {
+		return fmt.Errorf("no event channel for the topic %s", topic)
+	}
+	startOffset := kafka.LastOffset
+	if b.options.Durable {
+		startOffset = kafka.FirstOffset
+	}
+	// The reader retries on its own against the other brokers when the connection drops. The consumer group is
+	// named after the agent instance so that every instance receives every event instead of sharing them.
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     b.brokers,
+		GroupID:     getConsumerName(topic),
+		Topic:       b.options.TopicPrefix + topic,
+		StartOffset: startOffset,
+		MaxWait:     time.Second,
+	})
+	b.readers = append(b.readers, reader)
+	go b.consume(reader, eventChannel)
+	return nil
+}
+
+// consume passes the messages to the event channel one at a time and commits the offset of a message only
+// after it has been acknowledged, redelivered up to the redelivery limit or dead lettered
+func (b *kafkaBroker) consume(reader *kafka.Reader, eventChannel chan BrokerEvent) {
+	topic := reader.Config().Topic
+	for {
+		message, err := reader.FetchMessage(context.Background())
+		if err != nil {
+			logger.LoggerMsg.Errorf("Error while fetching the events from the Kafka topic %s, stopping the consumer: %v", topic, err)
+			return
+		}
+		for redeliveryCount := 0; ; redeliveryCount++ {
+			result := make(chan bool, 1)
+			eventChannel <- newEvent(message.Value, func() { result <- true }, func(retriable bool) {
+				if retriable && redeliveryCount < b.options.MaxRedeliveryCount {
+					logger.LoggerMsg.Infof("Redelivering the failed event of the Kafka topic %s, attempt %d of %d",
+						topic, redeliveryCount+1, b.options.MaxRedeliveryCount)
+					result <- false
+					return
+				}
+				b.deadLetter(message, redeliveryCount)
+				result <- true
+			})
+			if <-result {
+				break
+			}
+		}
+		if err := reader.CommitMessages(context.Background(), message); err != nil {
+			logger.LoggerMsg.Errorf("Error while committing the offset %d of the Kafka topic %s: %v", message.Offset, topic, err)
+		}
+	}
+}
+
+// deadLetter writes the failed message to the dead letter topic of its topic
+func (b *kafkaBroker) deadLetter(message kafka.Message, redeliveryCount int) {
+	if !b.options.Durable {
+		logger.LoggerMsg.Warnf("Dropping the failed event of the Kafka topic %s after %d redeliveries", message.Topic, redeliveryCount)
+		return
+	}
+	deadLetterTopic := message.Topic + deadLetterQueueSuffix
+	logger.LoggerMsg.Warnf("Moving the failed event to the dead letter topic %s after %d redeliveries", deadLetterTopic, redeliveryCount)
+	headers := append(message.Headers, kafka.Header{Key: redeliveryCountHeader, Value: []byte(strconv.Itoa(redeliveryCount))})
+	err := b.writer.WriteMessages(context.Background(), kafka.Message{
+		Topic:   deadLetterTopic,
+		Key:     message.Key,
+		Value:   message.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		logger.LoggerMsg.Errorf("Error while writing the failed event to the dead letter topic %s: %v", deadLetterTopic, err)
+	}
+}
+
+func (b *kafkaBroker) Close() error {
+	if b.done != nil {
+		close(b.done)
+		b.done = nil
+	}
+	var err error
+	for _, reader := range b.readers {
+		if closeErr := reader.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	if b.writer != nil {
+		if closeErr := b.writer.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	return err
+}
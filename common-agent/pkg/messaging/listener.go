@@ -36,6 +36,10 @@ var (
 
 const (
 	consumerTag string = "jms-consumer"
+	// redeliveryCountHeader holds the number of times the agent redelivered a failed event
+	redeliveryCountHeader string = "x-agent-redelivery-count"
+	deadLetterQueueSuffix string = ".dlq"
+	prefetchCount         int    = 10
 )
 
 const (
@@ -55,7 +59,7 @@ func startConsumer(key string) *Consumer {
 	c := &Consumer{
 		Conn:    nil,
 		Channel: nil,
-		Tag:     consumerTag + "-key",
+		Tag:     consumerTag + "-" + key,
 		Done:    make(chan error),
 	}
 
@@ -100,8 +104,10 @@ func (c *Consumer) Shutdown() error {
 
 // Consumer struct represents the structure of a consumer object
 type Consumer struct {
-	Conn    *amqp.Connection
-	Channel *amqp.Channel
-	Tag     string
-	Done    chan error
+	Conn      *amqp.Connection
+	Channel   *amqp.Channel
+	Tag       string
+	Done      chan error
+	QueueName string
+	Durable   bool
 }
@@ -0,0 +1,147 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/health"
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+)
+
+const natsReconnectWait time.Duration = 5 * time.Second
+
+// natsBroker consumes the events from NATS subjects. Durable consumers are created on JetStream streams,
+// which must already exist for the subjects, while non durable subscriptions use core NATS.
+type natsBroker struct {
+	conn          *nats.Conn
+	js            nats.JetStreamContext
+	options       QueueOptions
+	subscriptions []*nats.Subscription
+}
+
+func (b *natsBroker) Connect(endpoints []string, options QueueOptions) error {
+	var err error
+	b.options = options
+	// The client fails over between the servers and keeps reconnecting when the connection drops
+	b.conn, err = nats.Connect(strings.Join(endpoints, ","),
+		nats.Name(consumerTag),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(natsReconnectWait),
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			logger.LoggerMsg.Warnf("Disconnected from the NATS server: %v", err)
+			health.BrokerConnectionService.SetStatus(false)
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.LoggerMsg.Infof("Reconnected to the NATS server %q", maskURL(conn.ConnectedUrl()))
+			health.BrokerConnectionService.SetStatus(true)
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the NATS servers: %v", err)
+	}
+	logger.LoggerMsg.Infof("Successfully connected to the NATS server %q", maskURL(b.conn.ConnectedUrl()))
+	health.BrokerConnectionService.SetStatus(true)
+
+	if options.Durable {
+		if b.js, err = b.conn.JetStream(); err != nil {
+			return fmt.Errorf("unable to create the JetStream context: %v", err)
+		}
+	}
+	return nil
+}
+
+func (b *natsBroker) Subscribe(topic string) error {
+	eventChannel := getEventChannel(topic)
+	if eventChannel == nil {
+		return fmt.Errorf("no event channel for the topic %s", topic)
+	}
+	subject := b.options.TopicPrefix + topic
+
+	var subscription *nats.Subscription
+	var err error
+	if b.js == nil {
+		subscription, err = b.conn.Subscribe(subject, func(msg *nats.Msg) {
+			eventChannel <- newEvent(msg.Data, func() {}, func(bool) {
+				logger.LoggerMsg.Warnf("Dropping the failed event of the NATS subject %s", subject)
+			})
+		})
+	} else {
+		// The durable consumer is named after the agent instance so that every instance receives every event
+		subscription, err = b.js.Subscribe(subject, func(msg *nats.Msg) {
+			eventChannel <- b.newJetStreamEvent(msg)
+		},
+			nats.Durable(strings.ReplaceAll(getConsumerName(topic), ".", "_")),
+			nats.ManualAck(),
+			nats.AckExplicit(),
+			nats.DeliverAll(),
+			nats.MaxDeliver(b.options.MaxRedeliveryCount+1),
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to the NATS subject %s: %v", subject, err)
+	}
+	b.subscriptions = append(b.subscriptions, subscription)
+	return nil
+}
+
+// newJetStreamEvent wraps a JetStream message as an event. Failed events are redelivered by the server until
+// the redelivery limit is reached and are then published to the dead letter subject.
+func (b *natsBroker) newJetStreamEvent(msg *nats.Msg) BrokerEvent {
+	return newEvent(msg.Data, func() {
+		if err := msg.Ack(); err != nil {
+			logger.LoggerMsg.Errorf("Error while acknowledging the event of the NATS subject %s: %v", msg.Subject, err)
+		}
+	}, func(retriable bool) {
+		redeliveryCount := 0
+		if metadata, err := msg.Metadata(); err == nil {
+			redeliveryCount = int(metadata.NumDelivered) - 1
+		}
+		if retriable && redeliveryCount < b.options.MaxRedeliveryCount {
+			logger.LoggerMsg.Infof("Redelivering the failed event of the NATS subject %s, attempt %d of %d",
+				msg.Subject, redeliveryCount+1, b.options.MaxRedeliveryCount)
+			if err := msg.Nak(); err != nil {
+				logger.LoggerMsg.Errorf("Error while rejecting the event of the NATS subject %s: %v", msg.Subject, err)
+			}
+			return
+		}
+		deadLetterSubject := msg.Subject + deadLetterQueueSuffix
+		logger.LoggerMsg.Warnf("Moving the failed event to the dead letter subject %s after %d redeliveries", deadLetterSubject, redeliveryCount)
+		if err := b.conn.Publish(deadLetterSubject, msg.Data); err != nil {
+			logger.LoggerMsg.Errorf("Error while publishing the failed event to the dead letter subject %s: %v", deadLetterSubject, err)
+		}
+		if err := msg.Term(); err != nil {
+			logger.LoggerMsg.Errorf("Error while terminating the event of the NATS subject %s: %v", msg.Subject, err)
+		}
+	})
+}
+
+func (b *natsBroker) Close() error {
+	for _, subscription := range b.subscriptions {
+		if err := subscription.Unsubscribe(); err != nil {
+			logger.LoggerMsg.Errorf("Error while unsubscribing from the NATS subject %s: %v", subscription.Subject, err)
+		}
+	}
+	if b.conn != nil {
+		b.conn.Close()
+	}
+	return nil
+}
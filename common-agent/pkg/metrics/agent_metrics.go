@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	metricsNamespace = "apim_gw_agent"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	eventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "events_received_total",
+		Help:      "Number of control plane events received by the agent",
+	}, []string{"event_type"})
+	eventsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "events_processed_total",
+		Help:      "Number of control plane events processed successfully",
+	}, []string{"event_type"})
+	eventsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "events_failed_total",
+		Help:      "Number of control plane events which failed to be processed",
+	}, []string{"event_type"})
+	deploymentLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "api_deployment_duration_seconds",
+		Help:      "Time taken from receiving an API deploy event until the CRs of the API are applied",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	})
+	queueDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "queue_dropped_total",
+		Help:      "Number of items dropped as the queue was full",
+	}, []string{"queue"})
+	amqpReconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "amqp_reconnects_total",
+		Help:      "Number of reconnections to the AMQP broker",
+	})
+	crApplies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "cr_applies_total",
+		Help:      "Number of CR creations, updates and patches sent to the Kubernetes API server",
+	}, []string{"kind", "operation", "result"})
+	queueDepths = newGaugeFuncs(prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "queue_depth"),
+		"Number of items waiting in the queue", []string{"queue"}, nil))
+	cacheSizes = newGaugeFuncs(prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "cache_size"),
+		"Number of entries in the cache", []string{"cache"}, nil))
+
+	agentCollectors = []prometheus.Collector{eventsReceived, eventsProcessed, eventsFailed, deploymentLatency,
+		queueDrops, amqpReconnects, crApplies, queueDepths, cacheSizes}
+)
+
+// eventTimeKey is the context key of the time an event was received
+type eventTimeKey struct{}
+
+// gaugeFuncs reports the values returned by the registered functions as a gauge labelled by their names
+type gaugeFuncs struct {
+	desc  *prometheus.Desc
+	mutex sync.RWMutex
+	funcs map[string]func() int
+}
+
+func newGaugeFuncs(desc *prometheus.Desc) *gaugeFuncs {
+	return &gaugeFuncs{desc: desc, funcs: make(map[string]func() int)}
+}
+
+func (g *gaugeFuncs) register(name string, value func() int) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.funcs[name] = value
+}
+
+// Describe sends the descriptor of the gauge
+func (g *gaugeFuncs) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+// Collect sends the current value of each registered function
+func (g *gaugeFuncs) Collect(ch chan<- prometheus.Metric) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	for name, value := range g.funcs {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, float64(value()), name)
+	}
+}
+
+// RecordEventReceived counts an event received from the control plane
+func RecordEventReceived(eventType string) {
+	eventsReceived.WithLabelValues(eventType).Inc()
+}
+
+// RecordEventResult counts an event as processed or as failed when an error is given
+func RecordEventResult(eventType string, err error) {
+	if err != nil {
+		eventsFailed.WithLabelValues(eventType).Inc()
+		return
+	}
+	eventsProcessed.WithLabelValues(eventType).Inc()
+}
+
+// ContextWithEventTime returns the context carrying the time the event being processed was received
+func ContextWithEventTime(ctx context.Context, received time.Time) context.Context {
+	return context.WithValue(ctx, eventTimeKey{}, received)
+}
+
+// ObserveDeploymentLatency records the time elapsed since the event carried by the context was received.
+// Nothing is recorded for the deployments which are not triggered by an event, such as the startup load.
+func ObserveDeploymentLatency(ctx context.Context) {
+	if received, ok := ctx.Value(eventTimeKey{}).(time.Time); ok {
+		deploymentLatency.Observe(time.Since(received).Seconds())
+	}
+}
+
+// RegisterQueueDepth reports the number of items returned by depth as the depth of the queue
+func RegisterQueueDepth(queue string, depth func() int) {
+	queueDepths.register(queue, depth)
+}
+
+// RecordQueueDrop counts an item dropped as the queue was full
+func RecordQueueDrop(queue string) {
+	queueDrops.WithLabelValues(queue).Inc()
+}
+
+// RecordAMQPReconnect counts a reconnection to the AMQP broker
+func RecordAMQPReconnect() {
+	amqpReconnects.Inc()
+}
+
+// RegisterCacheSize reports the number of entries returned by size as the size of the cache
+func RegisterCacheSize(cache string, size func() int) {
+	cacheSizes.register(cache, size)
+}
+
+// NewInstrumentedClient creates the Kubernetes client of the controller manager which counts the results of the
+// CR creations, updates and patches by the kind of the CR
+func NewInstrumentedClient(config *rest.Config, options client.Options) (client.Client, error) {
+	k8sClient, err := client.NewWithWatch(config, options)
+	if err != nil {
+		return nil, err
+	}
+	return interceptor.NewClient(k8sClient, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			err := c.Create(ctx, obj, opts...)
+			recordCRApply(c, obj, "create", err)
+			return err
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			err := c.Update(ctx, obj, opts...)
+			recordCRApply(c, obj, "update", err)
+			return err
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			err := c.Patch(ctx, obj, patch, opts...)
+			recordCRApply(c, obj, "patch", err)
+			return err
+		},
+	}), nil
+}
+
+func recordCRApply(c client.Client, obj client.Object, operation string, err error) {
+	kind := "Unknown"
+	if gvk, gvkErr := c.GroupVersionKindFor(obj); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+	crApplies.WithLabelValues(kind, operation, result).Inc()
+}
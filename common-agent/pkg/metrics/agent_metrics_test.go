@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2025, WSO2 LLC. (https://www.wso2.com)
+ *
+ * WSO2 LLC. licenses this file to you under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordEventResult(t *testing.T) {
+	eventType := "API_UPDATE_TEST"
+	RecordEventReceived(eventType)
+	RecordEventReceived(eventType)
+	RecordEventResult(eventType, nil)
+	RecordEventResult(eventType, errors.New("failed to fetch the API"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(eventsReceived.WithLabelValues(eventType)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(eventsProcessed.WithLabelValues(eventType)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(eventsFailed.WithLabelValues(eventType)))
+}
+
+func TestObserveDeploymentLatency(t *testing.T) {
+	ObserveDeploymentLatency(context.Background())
+	ObserveDeploymentLatency(ContextWithEventTime(context.Background(), time.Now().Add(-time.Second)))
+
+	expected := `
+# HELP apim_gw_agent_api_deployment_duration_seconds Time taken from receiving an API deploy event until the CRs of the API are applied
+# TYPE apim_gw_agent_api_deployment_duration_seconds histogram
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="0.1"} 0
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="0.2"} 0
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="0.4"} 0
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="0.8"} 0
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="1.6"} 1
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="3.2"} 1
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="6.4"} 1
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="12.8"} 1
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="25.6"} 1
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="51.2"} 1
+apim_gw_agent_api_deployment_duration_seconds_bucket{le="+Inf"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(deploymentLatency, strings.NewReader(expected),
+		"apim_gw_agent_api_deployment_duration_seconds_bucket"))
+}
+
+func TestQueueAndCacheGauges(t *testing.T) {
+	queue := make(chan int, 2)
+	queue <- 1
+	RegisterQueueDepth("test", func() int { return len(queue) })
+	RegisterCacheSize("test", func() int { return 3 })
+	RecordQueueDrop("test")
+
+	expected := `
+# HELP apim_gw_agent_queue_depth Number of items waiting in the queue
+# TYPE apim_gw_agent_queue_depth gauge
+apim_gw_agent_queue_depth{queue="test"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(queueDepths, strings.NewReader(expected)))
+	queue <- 2
+	assert.NoError(t, testutil.CollectAndCompare(queueDepths, strings.NewReader(strings.Replace(expected, "} 1", "} 2", 1))))
+
+	expected = `
+# HELP apim_gw_agent_cache_size Number of entries in the cache
+# TYPE apim_gw_agent_cache_size gauge
+apim_gw_agent_cache_size{cache="test"} 3
+`
+	assert.NoError(t, testutil.CollectAndCompare(cacheSizes, strings.NewReader(expected)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(queueDrops.WithLabelValues("test")))
+}
+
+func TestRecordCRApply(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "apk"}}
+
+	require.NoError(t, k8sClient.Create(context.Background(), secret))
+	recordCRApply(k8sClient, secret, "create", nil)
+	recordCRApply(k8sClient, secret, "update", errors.New("conflict"))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(crApplies.WithLabelValues("Secret", "create", resultSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(crApplies.WithLabelValues("Secret", "update", resultFailure)))
+}
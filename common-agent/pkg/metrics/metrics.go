@@ -24,8 +24,9 @@ import (
 	k8smetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
-// RegisterPrometheusCollector registers the Prometheus collector for metrics.
+// RegisterPrometheusCollector registers the Prometheus collector for metrics along with the metrics of the agent.
 func RegisterPrometheusCollector() {
 	collector := metrics.CustomMetricsCollector()
 	k8smetrics.Registry.MustRegister(collector)
+	k8smetrics.Registry.MustRegister(agentCollectors...)
 }
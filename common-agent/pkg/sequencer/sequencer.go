@@ -0,0 +1,251 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package sequencer keeps track of the latest event processed for each entity received from the control plane, so
+// that the events which are delivered out of order or redelivered by the broker are not applied again. The
+// processed events are kept in memory and are retained across restarts with the snapshot, which is enabled by
+// default (snapshot.enabled). The snapshot is kept only as long as its directory, hence the redeliveries received
+// after the snapshot is lost, or while the snapshot is disabled, are processed again.
+package sequencer
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
+)
+
+// Kind is the kind of the entity an event belongs to
+type Kind string
+
+// Kinds of the sequenced entities
+const (
+	API          Kind = "api"
+	Application  Kind = "application"
+	Subscription Kind = "subscription"
+	Policy       Kind = "policy"
+	KeyManager   Kind = "keyManager"
+)
+
+// retention is the period for which a processed event is remembered. The events older than the retention are not
+// expected to be delivered again.
+const retention = 24 * time.Hour
+
+// Entry is the latest event processed for an entity
+type Entry struct {
+	// TimeStamp is the time stamp of the latest event in milliseconds since the epoch
+	TimeStamp int64 `json:"timeStamp"`
+	// EventIDs are the identifiers of the events processed with the time stamp
+	EventIDs []string `json:"eventIds"`
+	// ProcessedAt is the time the latest event was processed in milliseconds since the epoch
+	ProcessedAt int64 `json:"processedAt"`
+}
+
+// replacedEntry is the entry of an entity which an accepted event replaced. It is restored when the event fails, as
+// the event is recorded before it is processed.
+type replacedEntry struct {
+	entry  Entry
+	exists bool
+}
+
+var (
+	entriesMutex sync.Mutex
+	entries      = make(map[Kind]map[string]Entry)
+	// replaced holds the entries replaced by the accepted events until the events are processed. The events of an
+	// entity are processed one at a time, hence only the entry replaced by its latest event is held.
+	replaced = make(map[Kind]map[string]replacedEntry)
+)
+
+// Accept records an event of an entity and reports whether it should be processed. An event is discarded when a
+// later event of the entity was already processed, or when it is a redelivery of a processed event. The events
+// without a time stamp cannot be ordered, hence only a redelivery of the latest of them is discarded.
+func Accept(kind Kind, key string, timeStamp int64, eventID string) bool {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+	entities, exists := entries[kind]
+	if !exists {
+		entities = make(map[string]Entry)
+		entries[kind] = entities
+	}
+	entry, exists := entities[key]
+	if exists {
+		if timeStamp < entry.TimeStamp {
+			logger.LoggerSequencer.Debugf("Discarding the stale event %s of the %s %s as an event at %d was already "+
+				"processed", eventID, kind, key, entry.TimeStamp)
+			return false
+		}
+		if timeStamp == entry.TimeStamp && slices.Contains(entry.EventIDs, eventID) {
+			logger.LoggerSequencer.Debugf("Discarding the redelivered event %s of the %s %s", eventID, kind, key)
+			return false
+		}
+	}
+	if replaced[kind] == nil {
+		replaced[kind] = make(map[string]replacedEntry)
+	}
+	replaced[kind][key] = replacedEntry{entry: entry, exists: exists}
+	if exists && timeStamp == entry.TimeStamp && timeStamp != 0 {
+		entry.EventIDs = append(slices.Clone(entry.EventIDs), eventID)
+	} else {
+		entry = Entry{TimeStamp: timeStamp, EventIDs: []string{eventID}}
+	}
+	entry.ProcessedAt = time.Now().UnixMilli()
+	entities[key] = entry
+	return true
+}
+
+// AcceptEvent records an event received from the control plane and reports whether it should be processed. The event
+// is identified by its event ID, or by its type when the event ID is not set.
+func AcceptEvent(kind Kind, key string, event msg.Event) bool {
+	eventID := event.EventID
+	if eventID == "" {
+		eventID = event.Type
+	}
+	return Accept(kind, key, event.TimeStamp, eventID)
+}
+
+// AcceptAPIEvent records an API event against each gateway label of it, and returns the keys the event was accepted
+// with. No keys are returned when a later or the same event was already processed for all the gateway labels.
+func AcceptAPIEvent(apiEvent msg.APIEvent) []string {
+	if len(apiEvent.GatewayLabels) == 0 {
+		if AcceptEvent(API, apiEvent.UUID, apiEvent.Event) {
+			return []string{apiEvent.UUID}
+		}
+		return nil
+	}
+	var keys []string
+	for _, env := range apiEvent.GatewayLabels {
+		key := apiEvent.UUID + ":" + env
+		if AcceptEvent(API, key, apiEvent.Event) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// PolicyKey returns the key the events of a throttling policy are sequenced with
+func PolicyKey(policyEvent msg.PolicyInfo) string {
+	return policyEvent.PolicyType + ":" + policyEvent.TenantDomain + ":" + policyEvent.PolicyName
+}
+
+// GetEntry returns the latest event processed for an entity
+func GetEntry(kind Kind, key string) (Entry, bool) {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+	entry, exists := entries[kind][key]
+	return entry, exists
+}
+
+// Forget forgets the latest accepted events of the entities, so that their redeliveries are processed again. The
+// entries the events replaced are restored, so that the events older than the last processed event of an entity are
+// still discarded.
+func Forget(kind Kind, keys ...string) {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+	for _, key := range keys {
+		previous, held := replaced[kind][key]
+		delete(replaced[kind], key)
+		if held && previous.exists {
+			if entries[kind] == nil {
+				entries[kind] = make(map[string]Entry)
+			}
+			entries[kind][key] = previous.entry
+		} else {
+			delete(entries[kind], key)
+		}
+	}
+}
+
+// ForgetOnFailure is deferred by the event handlers once an event is accepted, with the error returned by the
+// handler. As the event is recorded before it is processed, the event is forgotten when the handler fails or panics
+// so that its redelivery is not discarded. The error is nil for the handlers which fail only by panicking.
+func ForgetOnFailure(kind Kind, err *error, keys ...string) {
+	if r := recover(); r != nil {
+		Forget(kind, keys...)
+		panic(r)
+	}
+	if err != nil && *err != nil {
+		Forget(kind, keys...)
+		return
+	}
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+	for _, key := range keys {
+		delete(replaced[kind], key)
+	}
+}
+
+// Prune removes the events processed before the given time and returns the number of removed events
+func Prune(before time.Time) int {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+	return prune(before.UnixMilli())
+}
+
+func prune(before int64) int {
+	pruned := 0
+	for kind, entities := range entries {
+		for key, entry := range entities {
+			if entry.ProcessedAt < before {
+				delete(entities, key)
+				delete(replaced[kind], key)
+				pruned++
+			}
+		}
+		if len(entities) == 0 {
+			delete(entries, kind)
+		}
+		if len(replaced[kind]) == 0 {
+			delete(replaced, kind)
+		}
+	}
+	return pruned
+}
+
+// Reset removes all the processed events
+func Reset() {
+	entriesMutex.Lock()
+	defer entriesMutex.Unlock()
+	entries = make(map[Kind]map[string]Entry)
+	replaced = make(map[Kind]map[string]replacedEntry)
+}
+
+// RegisterSnapshotStore registers the processed events to be persisted in the snapshot. The events processed before
+// the retention period are removed when the snapshot is saved.
+func RegisterSnapshotStore() {
+	snapshot.Register("eventSequence", func() map[Kind]map[string]Entry {
+		entriesMutex.Lock()
+		defer entriesMutex.Unlock()
+		if pruned := prune(time.Now().Add(-retention).UnixMilli()); pruned > 0 {
+			logger.LoggerSequencer.Debugf("Removed %d processed events older than %v", pruned, retention)
+		}
+		content := make(map[Kind]map[string]Entry, len(entries))
+		for kind, entities := range entries {
+			content[kind] = maps.Clone(entities)
+		}
+		return content
+	}, func(content map[Kind]map[string]Entry) {
+		entriesMutex.Lock()
+		defer entriesMutex.Unlock()
+		entries = content
+		replaced = make(map[Kind]map[string]replacedEntry)
+		logger.LoggerSequencer.Infof("Restored the processed events of %d kinds of entities", len(content))
+	})
+}
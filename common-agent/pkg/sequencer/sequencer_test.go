@@ -0,0 +1,171 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package sequencer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
+)
+
+func TestAcceptDiscardsStaleEvents(t *testing.T) {
+	t.Cleanup(Reset)
+
+	assert.True(t, Accept(API, "api1:Default", 200, "DEPLOY_API_IN_GATEWAY"))
+	assert.False(t, Accept(API, "api1:Default", 100, "REMOVE_API_FROM_GATEWAY"))
+	assert.True(t, Accept(API, "api1:Default", 300, "REMOVE_API_FROM_GATEWAY"))
+	// The time stamps of different entities are tracked separately
+	assert.True(t, Accept(API, "api2:Default", 100, "DEPLOY_API_IN_GATEWAY"))
+	assert.True(t, Accept(Application, "api1:Default", 100, "APPLICATION_CREATE"))
+
+	entry, exists := GetEntry(API, "api1:Default")
+	require.True(t, exists)
+	assert.Equal(t, int64(300), entry.TimeStamp)
+}
+
+func TestAcceptDiscardsRedeliveredEvents(t *testing.T) {
+	t.Cleanup(Reset)
+
+	event := msg.Event{EventID: "event1", TimeStamp: 100, Type: "SUBSCRIPTIONS_CREATE"}
+	assert.True(t, AcceptEvent(Subscription, "1", event))
+	assert.False(t, AcceptEvent(Subscription, "1", event))
+	// A different event with the same time stamp is not a redelivery
+	assert.True(t, AcceptEvent(Subscription, "1", msg.Event{EventID: "event2", TimeStamp: 100,
+		Type: "SUBSCRIPTIONS_UPDATE"}))
+	assert.False(t, AcceptEvent(Subscription, "1", event))
+	// The type identifies the events without an event ID
+	assert.True(t, AcceptEvent(Subscription, "2", msg.Event{TimeStamp: 100, Type: "SUBSCRIPTIONS_CREATE"}))
+	assert.False(t, AcceptEvent(Subscription, "2", msg.Event{TimeStamp: 100, Type: "SUBSCRIPTIONS_CREATE"}))
+}
+
+func TestAcceptEventsWithoutTimeStamp(t *testing.T) {
+	t.Cleanup(Reset)
+
+	assert.True(t, Accept(KeyManager, "km1", 0, "digest1"))
+	assert.False(t, Accept(KeyManager, "km1", 0, "digest1"))
+	assert.True(t, Accept(KeyManager, "km1", 0, "digest2"))
+	// Only the latest event without a time stamp is remembered
+	assert.True(t, Accept(KeyManager, "km1", 0, "digest1"))
+}
+
+func TestPrune(t *testing.T) {
+	t.Cleanup(Reset)
+
+	Accept(Policy, "SUBSCRIPTION:carbon.super:Gold", 100, "POLICY_CREATE")
+	assert.Equal(t, 0, Prune(time.Now().Add(-time.Hour)))
+	assert.Equal(t, 1, Prune(time.Now().Add(time.Second)))
+	_, exists := GetEntry(Policy, "SUBSCRIPTION:carbon.super:Gold")
+	assert.False(t, exists)
+}
+
+func TestAcceptAPIEvent(t *testing.T) {
+	t.Cleanup(Reset)
+
+	event := msg.APIEvent{UUID: "api1", GatewayLabels: []string{"Default", "Production"},
+		Event: msg.Event{EventID: "event1", TimeStamp: 100, Type: "DEPLOY_API_IN_GATEWAY"}}
+	assert.Equal(t, []string{"api1:Default", "api1:Production"}, AcceptAPIEvent(event))
+	assert.Empty(t, AcceptAPIEvent(event))
+	// The event is accepted only for the gateway labels which have not processed it
+	Forget(API, "api1:Production")
+	assert.Equal(t, []string{"api1:Production"}, AcceptAPIEvent(event))
+
+	withoutLabels := msg.APIEvent{UUID: "api2", Event: msg.Event{EventID: "event2", TimeStamp: 100}}
+	assert.Equal(t, []string{"api2"}, AcceptAPIEvent(withoutLabels))
+	assert.Empty(t, AcceptAPIEvent(withoutLabels))
+}
+
+func TestPolicyKey(t *testing.T) {
+	assert.Equal(t, "SUBSCRIPTION:carbon.super:Gold", PolicyKey(msg.PolicyInfo{PolicyType: "SUBSCRIPTION",
+		PolicyName: "Gold", Event: msg.Event{TenantDomain: "carbon.super"}}))
+}
+
+func TestForgetOnFailure(t *testing.T) {
+	t.Cleanup(Reset)
+
+	handle := func(key string, handlerErr error) (err error) {
+		if !Accept(Application, key, 100, "APPLICATION_CREATE") {
+			return nil
+		}
+		defer ForgetOnFailure(Application, &err, key)
+		return handlerErr
+	}
+	require.NoError(t, handle("1", nil))
+	_, exists := GetEntry(Application, "1")
+	assert.True(t, exists)
+	require.Error(t, handle("2", assert.AnError))
+	_, exists = GetEntry(Application, "2")
+	assert.False(t, exists, "a failed event should be processed again on redelivery")
+
+	assert.Panics(t, func() {
+		var err error
+		Accept(Application, "3", 100, "APPLICATION_CREATE")
+		defer ForgetOnFailure(Application, &err, "3")
+		panic("handler failed")
+	})
+	_, exists = GetEntry(Application, "3")
+	assert.False(t, exists)
+}
+
+func TestForgetRestoresReplacedEntry(t *testing.T) {
+	t.Cleanup(Reset)
+
+	handle := func(timeStamp int64, eventType string, handlerErr error) (err error) {
+		if !Accept(API, "api1:Default", timeStamp, eventType) {
+			return nil
+		}
+		defer ForgetOnFailure(API, &err, "api1:Default")
+		return handlerErr
+	}
+	require.NoError(t, handle(100, "DEPLOY_API_IN_GATEWAY", nil))
+	require.NoError(t, handle(200, "REMOVE_API_FROM_GATEWAY", nil))
+	require.Error(t, handle(300, "DEPLOY_API_IN_GATEWAY", assert.AnError))
+
+	// The last processed event is retained, hence the stale events are still discarded
+	entry, exists := GetEntry(API, "api1:Default")
+	require.True(t, exists)
+	assert.Equal(t, int64(200), entry.TimeStamp)
+	assert.False(t, Accept(API, "api1:Default", 100, "DEPLOY_API_IN_GATEWAY"))
+	assert.True(t, Accept(API, "api1:Default", 300, "DEPLOY_API_IN_GATEWAY"))
+
+	// An event with the time stamp of the processed event is forgotten without forgetting the processed event
+	Accept(Application, "1", 100, "APPLICATION_CREATE")
+	Accept(Application, "1", 100, "APPLICATION_UPDATE")
+	Forget(Application, "1")
+	assert.False(t, Accept(Application, "1", 100, "APPLICATION_CREATE"))
+	assert.True(t, Accept(Application, "1", 100, "APPLICATION_UPDATE"))
+}
+
+func TestSnapshotRestoresProcessedEvents(t *testing.T) {
+	t.Cleanup(Reset)
+	directory := t.TempDir()
+	RegisterSnapshotStore()
+
+	Accept(API, "api1:Default", 200, "DEPLOY_API_IN_GATEWAY")
+	require.NoError(t, snapshot.Save(directory))
+	Reset()
+
+	restored, err := snapshot.Load(directory)
+	require.NoError(t, err)
+	assert.Contains(t, restored, "eventSequence")
+	assert.False(t, Accept(API, "api1:Default", 100, "REMOVE_API_FROM_GATEWAY"))
+	assert.False(t, Accept(API, "api1:Default", 200, "DEPLOY_API_IN_GATEWAY"))
+}
@@ -0,0 +1,178 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package snapshot persists the in-memory stores of the agent on disk, so that the stores can be restored on
+// startup when the control plane is not reachable. Each store is written as a JSON file in the snapshot directory.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+)
+
+const fileExtension = ".json"
+
+type store struct {
+	export  func() interface{}
+	restore func(data []byte) error
+	// saved is the content of the store which was last written to or read from the snapshot
+	saved []byte
+}
+
+var (
+	storesMutex sync.Mutex
+	stores      = make(map[string]*store)
+)
+
+// Register registers a store to be persisted in the snapshot under the given name. export returns a copy of the
+// content of the store and restore replaces the content of the store with the content read from the snapshot. A
+// store which was saved as null is not restored.
+func Register[T any](name string, export func() T, restore func(T)) {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+	stores[name] = &store{
+		export: func() interface{} {
+			return export()
+		},
+		restore: func(data []byte) error {
+			if bytes.Equal(data, []byte("null")) {
+				return nil
+			}
+			var content T
+			if err := json.Unmarshal(data, &content); err != nil {
+				return err
+			}
+			restore(content)
+			return nil
+		},
+	}
+	logger.LoggerSnapshot.Debugf("Registered the store %s in the snapshot", name)
+}
+
+// Load restores the registered stores from the snapshot in the directory and returns the names of the restored
+// stores. The stores which are not in the snapshot are left as they are.
+func Load(directory string) ([]string, error) {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+	var restored []string
+	var errs []error
+	for _, name := range sortedNames() {
+		data, err := os.ReadFile(filePath(directory, name))
+		if errors.Is(err, os.ErrNotExist) {
+			logger.LoggerSnapshot.Debugf("The store %s is not in the snapshot", name)
+			continue
+		}
+		if err == nil {
+			err = stores[name].restore(data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error restoring the store %s: %w", name, err))
+			continue
+		}
+		stores[name].saved = data
+		restored = append(restored, name)
+	}
+	return restored, errors.Join(errs...)
+}
+
+// Save writes the registered stores which changed since they were last saved or loaded to the snapshot in the
+// directory. Each file is replaced atomically, so that a crash while saving does not leave a partial snapshot.
+func Save(directory string) error {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return fmt.Errorf("error creating the snapshot directory %s: %w", directory, err)
+	}
+	var errs []error
+	for _, name := range sortedNames() {
+		data, err := json.Marshal(stores[name].export())
+		if err == nil && bytes.Equal(data, stores[name].saved) {
+			continue
+		}
+		if err == nil {
+			err = writeFile(filePath(directory, name), data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error saving the store %s: %w", name, err))
+			continue
+		}
+		stores[name].saved = data
+		logger.LoggerSnapshot.Debugf("Saved the store %s in the snapshot", name)
+	}
+	return errors.Join(errs...)
+}
+
+// Run saves the snapshot in the directory every interval until the context is done, and saves it once more
+// before returning.
+func Run(ctx context.Context, directory string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := Save(directory); err != nil {
+				logger.LoggerSnapshot.Errorf("Error saving the snapshot: %v", err)
+			}
+		case <-ctx.Done():
+			if err := Save(directory); err != nil {
+				logger.LoggerSnapshot.Errorf("Error saving the snapshot: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func writeFile(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func filePath(directory string, name string) string {
+	return filepath.Join(directory, name+fileExtension)
+}
+
+func sortedNames() []string {
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
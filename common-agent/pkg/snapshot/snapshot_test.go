@@ -0,0 +1,106 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registerTestStore(t *testing.T, content *map[string]string) {
+	t.Cleanup(func() {
+		stores = make(map[string]*store)
+	})
+	Register("test", func() map[string]string {
+		return *content
+	}, func(restored map[string]string) {
+		*content = restored
+	})
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "snapshot")
+	content := map[string]string{"sub1": "Org1", "sub2": "Org2"}
+	registerTestStore(t, &content)
+
+	require.NoError(t, Save(directory))
+	assert.FileExists(t, filepath.Join(directory, "test.json"))
+
+	content = nil
+	restored, err := Load(directory)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test"}, restored)
+	assert.Equal(t, map[string]string{"sub1": "Org1", "sub2": "Org2"}, content)
+}
+
+func TestSaveWritesChangedStores(t *testing.T) {
+	directory := t.TempDir()
+	content := map[string]string{"sub1": "Org1"}
+	registerTestStore(t, &content)
+	require.NoError(t, Save(directory))
+
+	// The unchanged store is not written again
+	require.NoError(t, os.Remove(filepath.Join(directory, "test.json")))
+	require.NoError(t, Save(directory))
+	assert.NoFileExists(t, filepath.Join(directory, "test.json"))
+
+	content = map[string]string{"sub2": "Org2"}
+	require.NoError(t, Save(directory))
+	data, err := os.ReadFile(filepath.Join(directory, "test.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub2":"Org2"}`, string(data))
+}
+
+func TestLoadSkipsMissingAndCorruptStores(t *testing.T) {
+	directory := t.TempDir()
+	content := map[string]string{"sub1": "Org1"}
+	registerTestStore(t, &content)
+
+	restored, err := Load(directory)
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+	assert.Equal(t, map[string]string{"sub1": "Org1"}, content)
+
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "test.json"), []byte("{"), 0600))
+	restored, err = Load(directory)
+	assert.Error(t, err)
+	assert.Empty(t, restored)
+	assert.Equal(t, map[string]string{"sub1": "Org1"}, content)
+}
+
+func TestRunSavesOnShutdown(t *testing.T) {
+	directory := t.TempDir()
+	content := map[string]string{"sub1": "Org1"}
+	registerTestStore(t, &content)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, directory, time.Hour)
+	}()
+	cancel()
+	<-done
+	assert.FileExists(t, filepath.Join(directory, "test.json"))
+}
@@ -26,6 +26,7 @@ package synchronizer
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -40,7 +41,9 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/auth"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -65,11 +68,33 @@ func init() {
 	InitializeWorkerPool(conf.ControlPlane.RequestWorkerPool.PoolSize, conf.ControlPlane.RequestWorkerPool.QueueSizePerPool,
 		conf.ControlPlane.RequestWorkerPool.PauseTimeAfterFailure, conf.Agent.TrustStore.Location,
 		conf.ControlPlane.SkipSSLVerification, conf.ControlPlane.HTTPClient.RequestTimeOut, conf.ControlPlane.RetryInterval,
-		conf.ControlPlane.ServiceURL, conf.ControlPlane.Username, conf.ControlPlane.Password)
+		conf.ControlPlane.ServiceURL, conf.ControlPlane.Username, conf.ControlPlane.Password,
+		WorkerPoolOptionsFromConfig(conf))
+}
+
+// WorkerPoolOptionsFromConfig returns the worker pool options configured for the control plane requests
+func WorkerPoolOptionsFromConfig(conf *config.Config) WorkerPoolOptions {
+	poolConfig := conf.ControlPlane.RequestWorkerPool
+	return WorkerPoolOptions{
+		BlockWhenFull:    poolConfig.BlockWhenFull,
+		EnqueueTimeout:   poolConfig.EnqueueTimeout,
+		MaxRetries:       poolConfig.MaxRetries,
+		MaxRetryInterval: poolConfig.MaxRetryInterval,
+	}
 }
 
 // FetchAPIsOnEvent  will fetch API from control plane during the API Notification Event
-func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Client) (*FetchAPIsConf, error) {
+func FetchAPIsOnEvent(ctx context.Context, conf *config.Config, apiUUID *string, k8sClient client.Client) (_ *FetchAPIsConf, err error) {
+	ctx, span := tracing.StartSpan(ctx, "FetchAPIsOnEvent")
+	if apiUUID != nil {
+		span.SetAttributes(attribute.String("api.uuid", *apiUUID))
+		ctx = logging.ContextWithFields(ctx, logging.Fields{logging.APIUUID: *apiUUID})
+	}
+	defer func() { tracing.EndSpan(span, err) }()
+	// the pending control plane requests of this fetch are cancelled once it returns
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Populate data from config.
 	fetchAPIsConf := FetchAPIsConf{}
 	apis := make([]string, 0)
@@ -81,14 +106,28 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 	// Create a channel for the byte slice (response from the APIs from control plane)
 	c := make(chan SyncAPIResponse)
 
-	//Get API details.
-	if apiUUID != nil {
-		GetAPI(c, apiUUID, envs, RuntimeArtifactEndpoint, true)
-	} else {
-		GetAPI(c, nil, envs, RuntimeArtifactEndpoint, true)
+	//Get API details, retrying with an exponential backoff on transient failures.
+	var data SyncAPIResponse
+	for attempt := 0; ; attempt++ {
+		GetAPI(ctx, c, apiUUID, envs, RuntimeArtifactEndpoint, true)
+		select {
+		case data = <-c:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !isTransientFailure(data) || attempt >= workerPool.options.MaxRetries {
+			break
+		}
+		backoff := workerPool.retryBackoff(attempt)
+		logger.LoggerUtils.FromContext(ctx).Warnf("Error occurred while fetching data from control plane: %v. "+
+			"Retrying in %v (retry %d of %d)", data.Err, backoff, attempt+1, workerPool.options.MaxRetries)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
-	data := <-c
-	logger.LoggerUtils.Debugf("Receiving data for an API: %v", apiUUID)
+	logger.LoggerUtils.FromContext(ctx).Debugf("Receiving data for an API: %v", apiUUID)
 	if data.Resp != nil {
 		if data.Found {
 			// Reading the root zip
@@ -99,42 +138,46 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 			// Read the .zip files within the root apis.zip and add apis to apiFiles array.
 			for _, file := range zipReader.File {
 				apiFiles[file.Name] = file
-				logger.LoggerUtils.Debug("API file found: " + file.Name)
+				logger.LoggerUtils.FromContext(ctx).Debug("API file found: " + file.Name)
 				// Todo: Read the apis.zip and extract the api.zip,deployments.json
 			}
 			if err != nil {
-				logger.LoggerUtils.Errorf("Error while reading zip: %v", err)
+				logger.LoggerUtils.FromContext(ctx).Errorf("Error while reading zip: %v", err)
 				return nil, err
 			}
 			deploymentJSON, exists := apiFiles["deployments.json"]
 			if !exists {
-				logger.LoggerUtils.Errorf("deployments.json not found")
+				logger.LoggerUtils.FromContext(ctx).Errorf("deployments.json not found")
 				return nil, err
 			}
 			deploymentJSONBytes, err := transformer.ReadContent(deploymentJSON)
 			if err != nil {
-				logger.LoggerUtils.Errorf("Error while decoding the API Project Artifact: %v", err)
+				logger.LoggerUtils.FromContext(ctx).Errorf("Error while decoding the API Project Artifact: %v", err)
 				return nil, err
 			}
 			deploymentDescriptor, err := transformer.ProcessDeploymentDescriptor(deploymentJSONBytes)
 			if err != nil {
-				logger.LoggerUtils.Errorf("Error while decoding the API Project Artifact: %v", err)
+				logger.LoggerUtils.FromContext(ctx).Errorf("Error while decoding the API Project Artifact: %v", err)
 				return nil, err
 			}
 			apiDeployments := deploymentDescriptor.Data.Deployments
+			if apiDeployments != nil {
+				*apiDeployments = filterServedOrganizations(conf, "API deployment", *apiDeployments,
+					func(deployment transformer.Deployment) string { return deployment.OrganizationID })
+			}
 			fetchAPIsConf.APIDeployments = apiDeployments
 			fetchAPIsConf.APIFiles = apiFiles
 			return &fetchAPIsConf, nil
 		}
 
-		logger.LoggerUtils.Info("API not found.")
+		logger.LoggerUtils.FromContext(ctx).Info("API not found.")
 		return &fetchAPIsConf, nil
 	} else if data.ErrorCode == 204 {
-		logger.LoggerUtils.Infof("No API Artifacts are available in the control plane for the envionments :%s",
+		logger.LoggerUtils.FromContext(ctx).Infof("No API Artifacts are available in the control plane for the envionments :%s",
 			strings.Join(envs, ", "))
 		return &FetchAPIsConf{}, nil
 	} else if data.ErrorCode >= 400 && data.ErrorCode < 500 {
-		logger.LoggerUtils.ErrorC(logging.ErrorDetails{
+		logger.LoggerUtils.ErrorCContext(ctx, logging.ErrorDetails{
 			Message:   fmt.Sprintf("Error occurred when retrieving APIs from control plane(unrecoverable error): %v", data.Err.Error()),
 			Severity:  logging.CRITICAL,
 			ErrorCode: 1106,
@@ -142,51 +185,68 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 		return nil, data.Err
 	}
 
-	// Keep the iteration still until all the envrionment response properly.
-	logger.LoggerUtils.ErrorC(logging.ErrorDetails{
-		Message:   fmt.Sprintf("Error occurred while fetching data from control plane: %v ..retrying..", data.Err),
+	logger.LoggerUtils.ErrorCContext(ctx, logging.ErrorDetails{
+		Message: fmt.Sprintf("Error occurred while fetching data from control plane after %d retries: %v",
+			workerPool.options.MaxRetries, data.Err),
 		Severity:  logging.MINOR,
 		ErrorCode: 1107,
 	})
 	//health.SetControlPlaneRestAPIStatus(false)
-	RetryFetchingAPIs(c, data, RuntimeArtifactEndpoint, true)
-	logger.LoggerUtils.Info("Fetching API for an event is completed...")
-	return nil, nil
+	return nil, data.Err
+}
+
+// isTransientFailure checks whether the control plane request failed due to an error which may not occur when the
+// request is retried, such as a connection failure, a full request queue or a server side error
+func isTransientFailure(data SyncAPIResponse) bool {
+	if data.Err == nil {
+		return false
+	}
+	return data.ErrorCode == 0 || data.ErrorCode == http.StatusTooManyRequests ||
+		data.ErrorCode >= http.StatusInternalServerError
 }
 
 // GetAPI function calls the FetchAPIs() with relevant environment labels defined in the config.
-func GetAPI(c chan SyncAPIResponse, id *string, envs []string, endpoint string, sendType bool) {
+func GetAPI(ctx context.Context, c chan SyncAPIResponse, id *string, envs []string, endpoint string, sendType bool) {
 	if len(envs) > 0 {
 		// If the envrionment labels are present, call the controle plane with labels.
 		logger.LoggerUtils.Debugf("Environment labels present: %v", envs)
-		go FetchAPIs(id, envs, c, endpoint, sendType)
+		go FetchAPIs(ctx, id, envs, c, endpoint, sendType)
 	}
 }
 
 // FetchAPIs submits the control plane http request to the thread pool. The thread pool would process it and return
-// the http response to the channel which contains a zip file.
-func FetchAPIs(id *string, gwLabel []string, c chan SyncAPIResponse, resourceEndpoint string, sendType bool) {
+// the http response to the channel which contains a zip file. The request carries the trace context of ctx.
+func FetchAPIs(ctx context.Context, id *string, gwLabel []string, c chan SyncAPIResponse, resourceEndpoint string, sendType bool) {
 	if id != nil {
 		logger.LoggerSync.Infof("Fetching API from Control Plane for Id %q.", *id)
 	} else {
 		logger.LoggerSync.Info("Fetching APIs from Control Plane")
 	}
 
+	if workerPool == nil {
+		logger.LoggerSync.Fatal("WorkerPool is not inititated due to an internal error.")
+	}
 	req := ConstructControlPlaneRequest(id, gwLabel, workerPool.controlPlaneParams, resourceEndpoint, sendType)
 	workerReq := workerRequest{
-		Req:                *req,
-		APIUUID:            id,
-		SyncAPIRespChannel: c,
+		Req:     *req,
+		APIUUID: id,
 	}
 	if gwLabel != nil {
 		workerReq.labels = gwLabel
 	}
 
-	if workerPool == nil {
-		logger.LoggerSync.Fatal("WorkerPool is not inititated due to an internal error.")
+	// The caller is notified with an error instead of waiting for a response that is never sent.
+	if !workerPool.Enqueue(ctx, workerReq, c) {
+		logger.LoggerSync.Warnf("Control plane request queue is full, dropping the request to %s", resourceEndpoint)
+		respSyncAPI := SyncAPIResponse{Err: errQueueFull, GatewayLabels: gwLabel}
+		if id != nil {
+			respSyncAPI.APIUUID = *id
+		}
+		select {
+		case c <- respSyncAPI:
+		case <-ctx.Done():
+		}
 	}
-	// If adding task to the pool cannot be done, the whole thread hangs here.
-	workerPool.Enqueue(workerReq)
 }
 
 // SendRequestToControlPlane is the function triggered to send the request to the control plane.
@@ -322,23 +382,6 @@ func ConstructControlPlaneRequest(id *string, gwLabel []string, controlPlanePara
 	return req
 }
 
-// RetryFetchingAPIs function keeps retrying to fetch APIs from runtime-artifact endpoint.
-func RetryFetchingAPIs(c chan SyncAPIResponse, data SyncAPIResponse, endpoint string, sendType bool) {
-	retryInterval := workerPool.controlPlaneParams.retryInterval
-
-	// Retry fetching from control plane after a configured time interval
-	if retryInterval == 0 {
-		// Assign default retry interval
-		retryInterval = 5
-	}
-	logger.LoggerSync.Debugf("Time Duration for retrying: %v", retryInterval*time.Second)
-	time.Sleep(retryInterval * time.Second)
-	logger.LoggerSync.Infof("Retrying to fetch API data from control plane for the API %q.", data.APIUUID)
-	channelFillPercentage := float64(len(workerPool.internalQueue)) / float64(cap(workerPool.internalQueue)) * 100
-	logger.LoggerSync.Infof("Workerpool channel size as a percentage is : %f", channelFillPercentage)
-	FetchAPIs(&data.APIUUID, data.GatewayLabels, c, endpoint, sendType)
-}
-
 // ReadRootFiles function reads following files inside the root zip
 // deployment.json
 // env_properties.json
@@ -124,7 +124,8 @@ func FetchApplicationKeyMappings(organization string) ([]eventhub.ApplicationKey
 		}
 		logger.LoggerSync.Debugf("Application Key Mappings successfully parsed - URL: %s, Organization: %s, Total Mappings: %d, Mappings: %+v",
 			ehURL, organization, len(applicationKeyMappingList.List), applicationKeyMappingList.List)
-		return applicationKeyMappingList.List, ""
+		return filterServedOrganizations(conf, "application key mapping", applicationKeyMappingList.List,
+			func(keyMapping eventhub.ApplicationKeyMapping) string { return keyMapping.TenantDomain }), ""
 	}
 
 	errorMsg = "Failed to fetch data! " + applicationsEndpoint + " responded with " + strconv.Itoa(resp.StatusCode)
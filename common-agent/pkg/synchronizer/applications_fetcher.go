@@ -0,0 +1,98 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	pkgAuth "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/auth"
+	eventhub "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tlsutils"
+)
+
+const (
+	applicationListEndpoint string = "internal/data/v1/applications"
+)
+
+// FetchApplications fetches the applications of the organization, or of all organizations when the organization is
+// empty, from the control plane
+func FetchApplications(organization string) ([]eventhub.Application, string) {
+	logger.LoggerSync.Infof("Starting Applications fetch Organization: %s", organization)
+
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		logger.LoggerSync.Errorf("Error reading configs for Applications fetch Organization: %s, Error: %v", organization, errReadConfig)
+	}
+
+	ehConfigs := conf.ControlPlane
+	ehURL := ehConfigs.ServiceURL
+	if !strings.HasSuffix(ehURL, "/") {
+		ehURL += "/"
+	}
+	ehURL += applicationListEndpoint
+
+	req, err := http.NewRequest("GET", ehURL, nil)
+	if err != nil {
+		logger.LoggerSync.Errorf("HTTP request creation failed Organization: %s, URL: %s, Error: %v", organization, ehURL, err)
+		return make([]eventhub.Application, 0), "Error occurred while creating the request for: " + applicationListEndpoint
+	}
+	req.Header.Set(Authorization, "Basic "+pkgAuth.GetBasicAuth(ehConfigs.Username, ehConfigs.Password))
+	if organization != "" {
+		req.Header.Set("xWSO2Tenant", organization)
+	} else {
+		req.Header.Set("xWSO2Tenant", "ALL")
+	}
+
+	logger.LoggerSync.Debugf("Sending control plane request - URL: %s, Organization: %s", ehURL, organization)
+	resp, err := tlsutils.InvokeControlPlane(req, ehConfigs.SkipSSLVerification)
+	if err != nil {
+		logger.LoggerSync.Errorf("Control plane request failed - URL: %s, Organization: %s, Error: %v", ehURL, organization, err)
+		return make([]eventhub.Application, 0), "Error occurred while calling the REST API: " + applicationListEndpoint
+	}
+	defer resp.Body.Close()
+
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.LoggerSync.Errorf("Response body read failed - URL: %s, Organization: %s, Error: %v", ehURL, organization, err)
+		return make([]eventhub.Application, 0), "Error occurred while reading the response received for: " + applicationListEndpoint
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		logger.LoggerSync.Errorf("Control plane request failed with non-200 status - URL: %s, Organization: %s, Status Code: %d",
+			ehURL, organization, resp.StatusCode)
+		return make([]eventhub.Application, 0), "Failed to fetch data! " + applicationListEndpoint + " responded with " +
+			strconv.Itoa(resp.StatusCode)
+	}
+
+	var applicationList eventhub.ApplicationList
+	if err := json.Unmarshal(responseBytes, &applicationList); err != nil {
+		logger.LoggerSync.Errorf("JSON unmarshaling failed - URL: %s, Organization: %s, Error: %v", ehURL, organization, err)
+		return make([]eventhub.Application, 0), "Error occurred while JSON unmarshaling the response received for: " +
+			applicationListEndpoint
+	}
+	logger.LoggerSync.Debugf("Applications successfully parsed - Organization: %s, Total Applications: %d",
+		organization, len(applicationList.List))
+	return filterServedOrganizations(conf, "application", applicationList.List,
+		func(application eventhub.Application) string { return application.Organization }), ""
+}
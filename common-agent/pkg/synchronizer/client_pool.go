@@ -18,36 +18,73 @@
 package synchronizer
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tlsutils"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 )
 
+// controlPlaneRequestQueue is the name of the worker pool queue in the metrics
+const controlPlaneRequestQueue = "controlPlaneRequests"
+
+// errQueueFull is reported to the callers when their request cannot be added to the worker pool queue
+var errQueueFull = errors.New("control plane request queue is full")
+
 type worker struct {
 	id              int
-	internalQueue   <-chan workerRequest
+	pool            *pool
+	internalQueue   <-chan *workerRequest
 	processFunc     processHTTPRequest
 	delayAfterFault time.Duration
 }
 
 // workerRequest is the task which can be submitted to the pool.
 type workerRequest struct {
-	Req                http.Request
-	APIUUID            *string
-	labels             []string
+	Req     http.Request
+	APIUUID *string
+	labels  []string
+	// waiters are the callers waiting for the response of the request. The requests for the same artifacts
+	// which are submitted while the request is waiting in the queue are collapsed into it as waiters.
+	waiters []requestWaiter
+}
+
+// requestWaiter is a caller waiting for the response of a control plane request
+type requestWaiter struct {
+	ctx                context.Context
 	SyncAPIRespChannel chan SyncAPIResponse
 }
 
+// WorkerPoolOptions configures the behaviour of the worker pool when its queue is full and the retries of the
+// failed control plane requests.
+type WorkerPoolOptions struct {
+	// BlockWhenFull makes the callers wait for a free slot in the queue instead of dropping their requests
+	BlockWhenFull bool
+	// EnqueueTimeout is the time (in seconds) a caller waits for a free slot in the queue. The caller waits until
+	// its request is cancelled when the timeout is zero.
+	EnqueueTimeout time.Duration
+	// MaxRetries is the number of times a request failed due to a transient error is retried
+	MaxRetries int
+	// MaxRetryInterval is the upper bound (in seconds) of the exponential backoff between the retries
+	MaxRetryInterval time.Duration
+}
+
 // pool is the worker pool which is handling
 type pool struct {
-	internalQueue      chan workerRequest
+	internalQueue      chan *workerRequest
 	workers            []*worker
 	client             http.Client
 	controlPlaneParams controlPlaneParameters
+	options            WorkerPoolOptions
+	// pendingMutex guards the requests waiting in the queue, keyed by their URL
+	pendingMutex sync.Mutex
+	pending      map[string]*workerRequest
 }
 
 type controlPlaneParameters struct {
@@ -61,14 +98,42 @@ type processHTTPRequest func(*http.Request, *string, []string, chan SyncAPIRespo
 
 func (w *worker) ProcessFunction() {
 	for workerReq := range w.internalQueue {
-		responseReceived := w.processFunc(&workerReq.Req, workerReq.APIUUID, workerReq.labels, workerReq.SyncAPIRespChannel,
-			&workerPool.client)
+		waiters := w.pool.dequeue(workerReq)
+		req := activeRequest(workerReq, waiters)
+		if req == nil {
+			loggers.LoggerSync.Debugf("Skipping the control plane request %s as it is cancelled", workerReq.Req.URL)
+			continue
+		}
+		respChannel := make(chan SyncAPIResponse, 1)
+		responseReceived := w.processFunc(req, workerReq.APIUUID, workerReq.labels, respChannel, &w.pool.client)
+		respondToWaiters(waiters, <-respChannel)
 		if !responseReceived {
 			time.Sleep(w.delayAfterFault)
 		}
 	}
 }
 
+// activeRequest returns the request bound to the context of a waiter which is not cancelled, or nil when all the
+// waiters are cancelled
+func activeRequest(workerReq *workerRequest, waiters []requestWaiter) *http.Request {
+	for _, waiter := range waiters {
+		if waiter.ctx.Err() == nil {
+			return workerReq.Req.WithContext(waiter.ctx)
+		}
+	}
+	return nil
+}
+
+// respondToWaiters sends the response to each waiter unless the waiter is cancelled in the meantime
+func respondToWaiters(waiters []requestWaiter, resp SyncAPIResponse) {
+	for _, waiter := range waiters {
+		select {
+		case waiter.SyncAPIRespChannel <- resp:
+		case <-waiter.ctx.Done():
+		}
+	}
+}
+
 var (
 	// WorkerPool is the thread pool responsible for sending the control plane request to fetch APIs
 	workerPool        *pool
@@ -79,10 +144,12 @@ var (
 // maxWorkers indicate the maximum number of parallel workers sending requests to the control plane.
 // jobQueueCapacity indicate the maximum number of requests can kept inside a single worker's queue.
 // delayForFaultRequests indicate the delay a worker enforce (in seconds) when a fault response is received.
+// options decide how the callers are handled when the queue is full and how the failed requests are retried.
 func InitializeWorkerPool(maxWorkers, jobQueueCapacity int, delayForFaultRequests time.Duration, trustStoreLocation string,
-	skipSSL bool, requestTimeout, retryInterval time.Duration, serviceURL, username, password string) {
+	skipSSL bool, requestTimeout, retryInterval time.Duration, serviceURL, username, password string,
+	options WorkerPoolOptions) {
 	oncePoolInitiated.Do(func() {
-		workerPool = newWorkerPool(maxWorkers, jobQueueCapacity, delayForFaultRequests)
+		workerPool = newWorkerPool(maxWorkers, jobQueueCapacity, delayForFaultRequests, options)
 		workerPool.controlPlaneParams = controlPlaneParameters{
 			serviceURL:    serviceURL,
 			username:      username,
@@ -105,43 +172,126 @@ func InitializeWorkerPool(maxWorkers, jobQueueCapacity int, delayForFaultRequest
 		tr.MaxIdleConns = maxWorkers * 2
 		tr.MaxIdleConnsPerHost = maxWorkers * 2
 		workerPool.client = http.Client{
-			Transport: tr,
+			Transport: tracing.NewTransport(tr),
 			Timeout:   requestTimeout * time.Second,
 		}
 	})
 }
 
-func newWorkerPool(maxWorkers, jobQueueCapacity int, delayForFaultRequests time.Duration) *pool {
+func newWorkerPool(maxWorkers, jobQueueCapacity int, delayForFaultRequests time.Duration,
+	options WorkerPoolOptions) *pool {
 	if jobQueueCapacity <= 0 {
 		jobQueueCapacity = 100
 	}
-	requestChannel := make(chan workerRequest, jobQueueCapacity)
-	workers := make([]*worker, maxWorkers)
+	requestChannel := make(chan *workerRequest, jobQueueCapacity)
+	workerPool := &pool{
+		internalQueue: requestChannel,
+		workers:       make([]*worker, maxWorkers),
+		options:       options,
+		pending:       make(map[string]*workerRequest),
+	}
 
 	// create workers
 	for i := 0; i < maxWorkers; i++ {
-		workers[i] = &worker{
+		workerPool.workers[i] = &worker{
 			id:              i,
+			pool:            workerPool,
 			internalQueue:   requestChannel,
 			processFunc:     SendRequestToControlPlane,
 			delayAfterFault: delayForFaultRequests,
 		}
-		go workers[i].ProcessFunction()
+		go workerPool.workers[i].ProcessFunction()
 		loggers.LoggerSync.Infof("ControlPlane processing worker %d spawned.", i)
 	}
 
-	return &pool{
-		internalQueue: requestChannel,
-		workers:       workers,
+	metrics.RegisterQueueDepth(controlPlaneRequestQueue, func() int { return len(requestChannel) })
+
+	return workerPool
+}
+
+// Enqueue adds the request to the queue unless a request for the same URL is already waiting in the queue, in
+// which case the caller waits for the response of that request instead. When the queue is full, the caller waits
+// for a free slot as configured in the pool options. It returns false if the request could not be queued.
+func (q *pool) Enqueue(ctx context.Context, req workerRequest, respChannel chan SyncAPIResponse) bool {
+	waiter := requestWaiter{ctx: ctx, SyncAPIRespChannel: respChannel}
+	key := req.Req.URL.String()
+	q.pendingMutex.Lock()
+	if pendingReq, exists := q.pending[key]; exists {
+		pendingReq.waiters = append(pendingReq.waiters, waiter)
+		q.pendingMutex.Unlock()
+		loggers.LoggerSync.Debugf("Control plane request %s is already queued, waiting for its response", key)
+		return true
+	}
+	req.waiters = []requestWaiter{waiter}
+	q.pending[key] = &req
+	q.pendingMutex.Unlock()
+
+	if q.push(ctx, &req) {
+		return true
 	}
+	metrics.RecordQueueDrop(controlPlaneRequestQueue)
+	// the callers collapsed into the request in the meantime are notified as the request is not sent
+	q.pendingMutex.Lock()
+	delete(q.pending, key)
+	waiters := req.waiters[1:]
+	q.pendingMutex.Unlock()
+	respondToWaiters(waiters, SyncAPIResponse{Err: errQueueFull})
+	return false
 }
 
-// Enqueue Tries to enqueue but fails if queue is full
-func (q *pool) Enqueue(req workerRequest) bool {
+// push adds the request to the queue, waiting for a free slot if the pool is configured to block when full
+func (q *pool) push(ctx context.Context, req *workerRequest) bool {
 	select {
 	case q.internalQueue <- req:
 		return true
 	default:
+		if !q.options.BlockWhenFull {
+			return false
+		}
+	}
+	var timeout <-chan time.Time
+	if q.options.EnqueueTimeout > 0 {
+		timer := time.NewTimer(q.options.EnqueueTimeout * time.Second)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case q.internalQueue <- req:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-timeout:
 		return false
 	}
 }
+
+// dequeue removes the request from the pending requests so that the later requests for the same URL are queued
+// again, and returns the callers waiting for its response
+func (q *pool) dequeue(req *workerRequest) []requestWaiter {
+	q.pendingMutex.Lock()
+	defer q.pendingMutex.Unlock()
+	key := req.Req.URL.String()
+	if q.pending[key] == req {
+		delete(q.pending, key)
+	}
+	return req.waiters
+}
+
+// retryBackoff returns the delay before the given retry attempt (starting from zero). The delay starts from the
+// retry interval of the control plane and doubles on each attempt up to the maximum retry interval.
+func (q *pool) retryBackoff(attempt int) time.Duration {
+	retryInterval := q.controlPlaneParams.retryInterval
+	if retryInterval <= 0 {
+		// Assign default retry interval
+		retryInterval = 5
+	}
+	backoff := retryInterval * time.Second
+	maxBackoff := q.options.MaxRetryInterval * time.Second
+	for i := 0; i < attempt && (maxBackoff <= 0 || backoff < maxBackoff); i++ {
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
@@ -35,6 +35,7 @@ import (
 	pkgAuth "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/auth"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub"
 	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/health"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tlsutils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -118,7 +119,10 @@ func FetchKeyManagersOnStartUp(c client.Client) ([]eventhubTypes.ResolvedKeyMana
 			return nil, errorMsg
 		}
 		logger.LoggerSync.Debugf("Key Managers received: %+v", keyManagers)
+		keyManagers = filterServedOrganizations(conf, "key manager", keyManagers,
+			func(keyManager eventhubTypes.KeyManager) string { return keyManager.Organization })
 		resolvedKeyManagers := eventhub.MarshalKeyManagers(&keyManagers)
+		health.KeyManagerFetchService.SetStatus(true)
 		return resolvedKeyManagers, ""
 	}
 
@@ -43,6 +43,8 @@ const (
 	policiesByNameEndpoint              string = "internal/data/v1/api-policies?policyName="
 	subscriptionsPoliciesEndpoint       string = "internal/data/v1/subscription-policies"
 	subscriptionsPoliciesByNameEndpoint string = "internal/data/v1/subscription-policies?policyName="
+	applicationPoliciesEndpoint         string = "internal/data/v1/application-policies"
+	applicationPoliciesByNameEndpoint   string = "internal/data/v1/application-policies?policyName="
 )
 
 // FetchRateLimitPoliciesOnEvent fetches the policies from the control plane on the start up and notification event updates
@@ -133,7 +135,8 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string) ([
 			return nil, ""
 		}
 		logger.LoggerSync.Debugf("Ratelimit Policies received: %+v", rateLimitPolicyList.List)
-		var rateLimitPolicies []eventhub.RateLimitPolicy = rateLimitPolicyList.List
+		rateLimitPolicies := filterServedOrganizations(conf, "rate limit policy", rateLimitPolicyList.List,
+			func(policy eventhub.RateLimitPolicy) string { return policy.TenantDomain })
 		for _, policy := range rateLimitPolicies {
 			if policy.DefaultLimit.RequestCount.TimeUnit == "min" {
 				policy.DefaultLimit.RequestCount.TimeUnit = "Minute"
@@ -144,6 +147,13 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string) ([
 			}
 			managementserver.AddRateLimitPolicy(policy)
 		}
+		if ratelimitName == "" && organization == "" {
+			// All the policies are fetched, so the ones which are no longer in the control plane, such as the
+			// policies restored from the snapshot, are removed
+			for _, name := range managementserver.RetainRateLimitPolicies(rateLimitPolicies) {
+				logger.LoggerSync.Infof("Removed the rate limit policy %s which is not in the control plane", name)
+			}
+		}
 		return rateLimitPolicies, ""
 	}
 
@@ -229,7 +239,8 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 			return nil, ""
 		}
 		logger.LoggerSync.Debugf("Subscription Ratelimit Policies received: %+v", rateLimitPolicyList.List)
-		var rateLimitPolicies []eventhub.SubscriptionPolicy = rateLimitPolicyList.List
+		rateLimitPolicies := filterServedOrganizations(conf, "subscription policy", rateLimitPolicyList.List,
+			func(policy eventhub.SubscriptionPolicy) string { return policy.TenantDomain })
 		for _, policy := range rateLimitPolicies {
 			if policy.QuotaType == "aiApiQuota" {
 				if policy.DefaultLimit.AiAPIQuota != nil {
@@ -266,6 +277,11 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 				managementserver.AddSubscriptionPolicy(policy)
 			}
 		}
+		if ratelimitName == "" && organization == "" {
+			for _, name := range managementserver.RetainSubscriptionPolicies(rateLimitPolicies) {
+				logger.LoggerSync.Infof("Removed the subscription policy %s which is not in the control plane", name)
+			}
+		}
 		return rateLimitPolicies, ""
 	}
 
@@ -273,3 +289,106 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 		strconv.Itoa(resp.StatusCode)
 	return make([]eventhub.SubscriptionPolicy, 0), errorMsg
 }
+
+// FetchApplicationRateLimitPoliciesOnEvent fetches the application policies from the control plane on the start up
+// and notification event updates
+func FetchApplicationRateLimitPoliciesOnEvent(ratelimitName string, organization string) ([]eventhub.ApplicationPolicy, string) {
+	logger.LoggerSync.Info("Fetching Application RateLimit Policies from Control Plane.")
+
+	// Read configurations and derive the eventHub details
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		logger.LoggerSync.Errorf("Error reading configs: %v", errReadConfig)
+	}
+	// Populate data from the config
+	ehConfigs := conf.ControlPlane
+	ehURL := ehConfigs.ServiceURL
+	// If the eventHub URL is configured with trailing slash
+	if !strings.HasSuffix(ehURL, "/") {
+		ehURL += "/"
+	}
+	if ratelimitName != "" {
+		ehURL += applicationPoliciesByNameEndpoint + ratelimitName
+	} else {
+		ehURL += applicationPoliciesEndpoint
+	}
+
+	logger.LoggerSync.Infof("Fetching Application RateLimit Policies from the URL %v: ", ehURL)
+
+	basicAuth := "Basic " + pkgAuth.GetBasicAuth(ehConfigs.Username, ehConfigs.Password)
+
+	// Create a HTTP request
+	req, err := http.NewRequest("GET", ehURL, nil)
+	if err != nil {
+		logger.LoggerSync.Errorf("Error while creating http request for Application RateLimit Policies Endpoint : %v", err)
+		return make([]eventhub.ApplicationPolicy, 0), "Error occurred while creating the request for: " + applicationPoliciesEndpoint
+	}
+
+	// Setting authorization header
+	req.Header.Set(Authorization, basicAuth)
+
+	if organization != "" {
+		logger.LoggerSync.Debugf("Setting the organization header for the request: %v", organization)
+		req.Header.Set("xWSO2Tenant", organization)
+	} else {
+		logger.LoggerSync.Debugf("Setting the organization header for the request: %v", "ALL")
+		req.Header.Set("xWSO2Tenant", "ALL")
+	}
+
+	// Make the request
+	logger.LoggerSync.Debug("Sending the control plane request")
+	resp, err := tlsutils.InvokeControlPlane(req, ehConfigs.SkipSSLVerification)
+	var errorMsg string
+	if err != nil {
+		errorMsg = "Error occurred while calling the REST API: " + applicationPoliciesEndpoint
+		return make([]eventhub.ApplicationPolicy, 0), errorMsg
+	}
+	responseBytes, err := ioutil.ReadAll(resp.Body)
+	logger.LoggerSync.Debugf("Response String received for Application Policies: %v", string(responseBytes))
+
+	if err != nil {
+		errorMsg = "Error occurred while reading the response received for: " + applicationPoliciesEndpoint
+		return make([]eventhub.ApplicationPolicy, 0), errorMsg
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		var applicationPolicyList eventhub.ApplicationPolicyList
+		err := json.Unmarshal(responseBytes, &applicationPolicyList)
+		if err != nil {
+			logger.LoggerSync.Errorf("Error occurred while unmarshelling Application RateLimit Policies event data %v", err)
+			return nil, ""
+		}
+		logger.LoggerSync.Debugf("Application Ratelimit Policies received: %+v", applicationPolicyList.List)
+		applicationPolicies := filterServedOrganizations(conf, "application policy", applicationPolicyList.List,
+			func(policy eventhub.ApplicationPolicy) string { return policy.TenantDomain })
+		for _, policy := range applicationPolicies {
+			policy.DefaultLimit.RequestCount.TimeUnit = getNormalizedTimeUnit(policy.DefaultLimit.RequestCount.TimeUnit)
+			policy.DefaultLimit.EventCount.TimeUnit = getNormalizedTimeUnit(policy.DefaultLimit.EventCount.TimeUnit)
+			managementserver.AddApplicationPolicy(policy)
+		}
+		if ratelimitName == "" && organization == "" {
+			for _, name := range managementserver.RetainApplicationPolicies(applicationPolicies) {
+				logger.LoggerSync.Infof("Removed the application policy %s which is not in the control plane", name)
+			}
+		}
+		return applicationPolicies, ""
+	}
+
+	errorMsg = "Failed to fetch data! " + applicationPoliciesEndpoint + " responded with " +
+		strconv.Itoa(resp.StatusCode)
+	return make([]eventhub.ApplicationPolicy, 0), errorMsg
+}
+
+// getNormalizedTimeUnit returns the time unit of the control plane policy in the format stored in the internal maps
+func getNormalizedTimeUnit(timeUnit string) string {
+	switch timeUnit {
+	case "min":
+		return "Minute"
+	case "hours":
+		return "Hour"
+	case "days":
+		return "Day"
+	default:
+		return timeUnit
+	}
+}
@@ -130,7 +130,8 @@ func FetchSubscriptions(apiUUID string) ([]eventhub.Subscription, string) {
 		logger.LoggerSync.Debugf("Subscriptions successfully parsed - URL: %s, Total Subscriptions: %d, Subscriptions: %+v",
 			ehURL, len(subscriptionList.List), subscriptionList.List)
 
-		return subscriptionList.List, ""
+		return filterServedOrganizations(conf, "subscription", subscriptionList.List,
+			func(subscription eventhub.Subscription) string { return subscription.ApplicationOrganization }), ""
 	}
 
 	errorMsg = "Failed to fetch data! " + SubscriptionsEndpoint + " responded with " + strconv.Itoa(resp.StatusCode)
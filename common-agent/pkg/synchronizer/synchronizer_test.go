@@ -19,6 +19,9 @@ package synchronizer
 import (
 	"archive/zip"
 	"bytes"
+	"context"
+	"errors"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
@@ -110,7 +113,7 @@ func TestNewWorkerPool(t *testing.T) {
 	jobQueueCapacity := 10
 	delayForFaultRequests := time.Second * 2
 
-	workerPool := newWorkerPool(maxWorkers, jobQueueCapacity, delayForFaultRequests)
+	workerPool := newWorkerPool(maxWorkers, jobQueueCapacity, delayForFaultRequests, WorkerPoolOptions{})
 	if cap(workerPool.internalQueue) != jobQueueCapacity {
 		t.Errorf("Unexpected internal queue capacity. Got: %d, Expected: %d", cap(workerPool.internalQueue), jobQueueCapacity)
 	}
@@ -118,3 +121,93 @@ func TestNewWorkerPool(t *testing.T) {
 		t.Errorf("Unexpected number of workers. Got: %d, Expected: %d", len(workerPool.workers), maxWorkers)
 	}
 }
+
+func newTestWorkerRequest(t *testing.T, id string) workerRequest {
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/"+RuntimeArtifactEndpoint+"?apiId="+id, nil)
+	assert.NoError(t, err)
+	return workerRequest{Req: *req, APIUUID: &id}
+}
+
+func TestEnqueueCollapsesQueuedRequests(t *testing.T) {
+	testPool := newWorkerPool(0, 2, 0, WorkerPoolOptions{})
+	first := make(chan SyncAPIResponse, 1)
+	second := make(chan SyncAPIResponse, 1)
+	other := make(chan SyncAPIResponse, 1)
+
+	assert.True(t, testPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api1"), first))
+	assert.True(t, testPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api1"), second))
+	assert.True(t, testPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api2"), other))
+	assert.Len(t, testPool.internalQueue, 2, "requests for the same URL must be collapsed while queued")
+
+	requests := 0
+	testWorker := &worker{pool: testPool, internalQueue: testPool.internalQueue,
+		processFunc: func(req *http.Request, apiID *string, _ []string, c chan SyncAPIResponse, _ *http.Client) bool {
+			requests++
+			c <- SyncAPIResponse{APIUUID: *apiID, Found: true}
+			return true
+		}}
+	close(testPool.internalQueue)
+	testWorker.ProcessFunction()
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, "api1", (<-first).APIUUID)
+	assert.Equal(t, "api1", (<-second).APIUUID)
+	assert.Equal(t, "api2", (<-other).APIUUID)
+	assert.Empty(t, testPool.pending)
+}
+
+func TestEnqueueWhenQueueIsFull(t *testing.T) {
+	dropPool := newWorkerPool(0, 1, 0, WorkerPoolOptions{})
+	assert.True(t, dropPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api1"), make(chan SyncAPIResponse, 1)))
+	assert.False(t, dropPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api2"), make(chan SyncAPIResponse, 1)),
+		"the request must be dropped without waiting")
+	assert.NotContains(t, dropPool.pending, newTestWorkerRequest(t, "api2").Req.URL.String())
+
+	blockPool := newWorkerPool(0, 1, 0, WorkerPoolOptions{BlockWhenFull: true})
+	assert.True(t, blockPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api1"), make(chan SyncAPIResponse, 1)))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.False(t, blockPool.Enqueue(ctx, newTestWorkerRequest(t, "api2"), make(chan SyncAPIResponse, 1)))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "the request must wait until it is cancelled")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-blockPool.internalQueue
+	}()
+	assert.True(t, blockPool.Enqueue(context.Background(), newTestWorkerRequest(t, "api3"), make(chan SyncAPIResponse, 1)),
+		"the request must be queued once a slot is freed")
+}
+
+func TestWorkerSkipsCancelledRequests(t *testing.T) {
+	testPool := newWorkerPool(0, 1, 0, WorkerPoolOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.True(t, testPool.Enqueue(ctx, newTestWorkerRequest(t, "api1"), make(chan SyncAPIResponse)))
+	cancel()
+
+	testWorker := &worker{pool: testPool, internalQueue: testPool.internalQueue,
+		processFunc: func(*http.Request, *string, []string, chan SyncAPIResponse, *http.Client) bool {
+			t.Error("a cancelled request must not be sent to the control plane")
+			return true
+		}}
+	close(testPool.internalQueue)
+	testWorker.ProcessFunction()
+}
+
+func TestRetryBackoff(t *testing.T) {
+	testPool := newWorkerPool(0, 1, 0, WorkerPoolOptions{MaxRetryInterval: 20})
+	testPool.controlPlaneParams.retryInterval = 3
+	assert.Equal(t, 3*time.Second, testPool.retryBackoff(0))
+	assert.Equal(t, 6*time.Second, testPool.retryBackoff(1))
+	assert.Equal(t, 12*time.Second, testPool.retryBackoff(2))
+	assert.Equal(t, 20*time.Second, testPool.retryBackoff(3))
+	assert.Equal(t, 20*time.Second, testPool.retryBackoff(40))
+}
+
+func TestIsTransientFailure(t *testing.T) {
+	assert.False(t, isTransientFailure(SyncAPIResponse{Found: true}))
+	assert.True(t, isTransientFailure(SyncAPIResponse{Err: errQueueFull}))
+	assert.True(t, isTransientFailure(SyncAPIResponse{Err: errors.New("bad gateway"), ErrorCode: http.StatusBadGateway}))
+	assert.True(t, isTransientFailure(SyncAPIResponse{Err: errors.New("throttled"), ErrorCode: http.StatusTooManyRequests}))
+	assert.False(t, isTransientFailure(SyncAPIResponse{Err: errors.New("unauthorized"), ErrorCode: http.StatusUnauthorized}))
+}
@@ -0,0 +1,40 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"slices"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+)
+
+// filterServedOrganizations drops the artifacts of the organizations that are not served by this agent
+func filterServedOrganizations[T any](conf *config.Config, kind string, artifacts []T, organizationOf func(T) string) []T {
+	if len(conf.DataPlane.Organizations) == 0 {
+		return artifacts
+	}
+	return slices.DeleteFunc(artifacts, func(artifact T) bool {
+		organization := organizationOf(artifact)
+		if conf.DataPlane.ServesOrganization(organization) {
+			return false
+		}
+		logger.LoggerSync.Debugf("Skipping the %s of organization %s as it is not served by this agent", kind, organization)
+		return true
+	})
+}
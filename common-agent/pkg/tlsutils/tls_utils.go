@@ -30,6 +30,7 @@ import (
 
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	logger "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 )
 
 var (
@@ -117,7 +118,7 @@ func InvokeControlPlane(req *http.Request, skipSSL bool) (*http.Response, error)
 
 	// Configuring the http client
 	client := &http.Client{
-		Transport: tr,
+		Transport: tracing.NewTransport(tr),
 	}
 	return client.Do(req)
 }
@@ -0,0 +1,162 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestInitTracer(t *testing.T) {
+	conf := &config.Config{}
+	shutdown, err := InitTracer(conf)
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	conf.Tracing.Enabled = true
+	conf.Tracing.Exporter = "stdout"
+	conf.Tracing.ServiceName = "apim-gw-agent"
+	conf.Tracing.SamplingRatio = 1
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	shutdown, err = InitTracer(conf)
+	require.NoError(t, err)
+	_, span := StartSpan(context.Background(), "test")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.NoError(t, shutdown(context.Background()))
+
+	conf.Tracing.Exporter = "zipkin"
+	_, err = InitTracer(conf)
+	assert.Error(t, err)
+}
+
+func TestOTLPExporterUploadsTraces(t *testing.T) {
+	received := make(chan *coltracepb.ExportTraceServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		request := &coltracepb.ExportTraceServiceRequest{}
+		assert.NoError(t, proto.Unmarshal(body, request))
+		received <- request
+	}))
+	defer server.Close()
+
+	conf := &config.Config{}
+	conf.Tracing.Exporter = "otlp"
+	conf.Tracing.Endpoint = strings.TrimPrefix(server.URL, "http://")
+	conf.Tracing.Insecure = true
+	exporter, err := newExporter(conf)
+	require.NoError(t, err)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	_, span := provider.Tracer(tracerName).Start(context.Background(), "FetchAPIsOnEvent")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	request := <-received
+	require.Len(t, request.ResourceSpans, 1)
+	require.Len(t, request.ResourceSpans[0].ScopeSpans, 1)
+	assert.Equal(t, "FetchAPIsOnEvent", request.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+}
+
+func TestEndSpan(t *testing.T) {
+	recorder := useSpanRecorder(t)
+
+	_, span := StartSpan(context.Background(), "succeeded")
+	EndSpan(span, nil)
+	_, span = StartSpan(context.Background(), "failed")
+	EndSpan(span, errors.New("failed to fetch"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Len(t, spans[1].Events(), 1)
+}
+
+func TestTransportPropagatesTraceContext(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	_, err := InitTracer(&config.Config{})
+	require.NoError(t, err)
+
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, parent := StartSpan(context.Background(), "FetchAPIsOnEvent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/internal/data/v1/runtime-artifacts", nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: NewTransport(nil)}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	clientSpan := spans[0]
+	assert.Equal(t, "HTTP GET", clientSpan.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, clientSpan.Status().Code)
+	assert.Contains(t, traceParent, clientSpan.SpanContext().TraceID().String())
+	assert.Contains(t, traceParent, clientSpan.SpanContext().SpanID().String())
+	assert.Empty(t, req.Header.Get("traceparent"), "the request of the caller must not be modified")
+}
+
+func TestExtractFromGRPCMetadata(t *testing.T) {
+	_, err := InitTracer(&config.Config{})
+	require.NoError(t, err)
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceParent))
+	spanContext := trace.SpanContextFromContext(ExtractFromGRPCMetadata(ctx))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+	assert.True(t, spanContext.IsRemote())
+
+	assert.False(t, trace.SpanContextFromContext(ExtractFromGRPCMetadata(context.Background())).IsValid())
+}
@@ -17,7 +17,11 @@
 
 package transformer
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
 
 // CustomParams holds the custom parameter values that has been enabled for the selected security mode
 type CustomParams struct {
@@ -48,7 +52,76 @@ type EndpointSecurityConfig struct {
 
 // EndpointDetails represents the details of an endpoint, containing its URL.
 type EndpointDetails struct {
-	URL string `json:"url" yaml:"url"`
+	URL    string                  `json:"url" yaml:"url"`
+	Config *AdvancedEndpointConfig `json:"config" yaml:"config"`
+	// LoadBalanced holds all the endpoints of a load balanced endpoint list, of which the first one is the endpoint
+	LoadBalanced []EndpointDetails `json:"-" yaml:"-"`
+}
+
+// endpointDetails is the EndpointDetails decoded without the handling of the endpoint lists
+type endpointDetails EndpointDetails
+
+// UnmarshalJSON reads an endpoint or a load balanced endpoint list
+func (details *EndpointDetails) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '[' {
+		return json.Unmarshal(data, (*endpointDetails)(details))
+	}
+	var endpoints []endpointDetails
+	if err := json.Unmarshal(data, &endpoints); err != nil {
+		return err
+	}
+	details.setLoadBalanced(endpoints)
+	return nil
+}
+
+// UnmarshalYAML reads an endpoint or a load balanced endpoint list
+func (details *EndpointDetails) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var endpoints []endpointDetails
+	if err := unmarshal(&endpoints); err != nil {
+		return unmarshal((*endpointDetails)(details))
+	}
+	details.setLoadBalanced(endpoints)
+	return nil
+}
+
+// setLoadBalanced sets the endpoints of a load balanced endpoint list, of which the first one becomes the endpoint
+func (details *EndpointDetails) setLoadBalanced(endpoints []endpointDetails) {
+	if len(endpoints) == 0 {
+		return
+	}
+	*details = EndpointDetails(endpoints[0])
+	details.LoadBalanced = make([]EndpointDetails, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		details.LoadBalanced = append(details.LoadBalanced, EndpointDetails(endpoint))
+	}
+}
+
+// AdvancedEndpointConfig holds the timeout, retry and suspension settings of an endpoint. The durations are in
+// milliseconds and the error codes are the transport error codes of the gateway.
+type AdvancedEndpointConfig struct {
+	ActionDuration     EndpointConfigValue   `json:"actionDuration" yaml:"actionDuration"`
+	RetryTimeOut       EndpointConfigValue   `json:"retryTimeOut" yaml:"retryTimeOut"`
+	RetryDelay         EndpointConfigValue   `json:"retryDelay" yaml:"retryDelay"`
+	SuspendErrorCodes  []EndpointConfigValue `json:"suspendErrorCode" yaml:"suspendErrorCode"`
+	SuspendDuration    EndpointConfigValue   `json:"suspendDuration" yaml:"suspendDuration"`
+	SuspendMaxDuration EndpointConfigValue   `json:"suspendMaxDuration" yaml:"suspendMaxDuration"`
+	Factor             EndpointConfigValue   `json:"factor" yaml:"factor"`
+}
+
+// EndpointConfigValue is a value of the advanced endpoint configuration, which is sent either as a string or as a
+// number
+type EndpointConfigValue string
+
+// UnmarshalJSON reads the endpoint configuration value from a JSON string or number
+func (value *EndpointConfigValue) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw != nil {
+		*value = EndpointConfigValue(fmt.Sprint(raw))
+	}
+	return nil
 }
 
 // EndpointConfig represents the configuration of an endpoint, including its type, sandbox, and production details.
@@ -56,6 +129,8 @@ type EndpointConfig struct {
 	EndpointType        string                 `json:"endpoint_type" yaml:"endpoint_type"`
 	SandboxEndpoints    EndpointDetails        `json:"sandbox_endpoints" yaml:"sandbox_endpoints"`
 	ProductionEndpoints EndpointDetails        `json:"production_endpoints" yaml:"production_endpoints"`
+	SandboxFailovers    []EndpointDetails      `json:"sandbox_failovers" yaml:"sandbox_failovers"`
+	ProductionFailovers []EndpointDetails      `json:"production_failovers" yaml:"production_failovers"`
 	EndpointSecurity    EndpointSecurityConfig `json:"endpoint_security" yaml:"endpoint_security"`
 }
 
@@ -47,6 +47,36 @@ type EndpointConfiguration struct {
 	EndCertificate EndpointCertificate `yaml:"certificate,omitempty"`
 	EndSecurity    EndpointSecurity    `yaml:"endpointSecurity,omitempty"`
 	AIRatelimit    AIRatelimit         `yaml:"aiRatelimit,omitempty"`
+	// Resiliency, LoadBalanceEndpoints and FailoverEndpoints are not part of the apk-conf, the gateways read them
+	// from the API
+	Resiliency *Resiliency `yaml:"-"`
+	// LoadBalanceEndpoints are the URLs of the other endpoints the requests are balanced with
+	LoadBalanceEndpoints []string `yaml:"-"`
+	// FailoverEndpoints are the URLs of the endpoints the requests fail over to
+	FailoverEndpoints []string `yaml:"-"`
+}
+
+// Resiliency holds the timeout and circuit breaking configurations of an endpoint
+type Resiliency struct {
+	Timeout        *Timeout
+	CircuitBreaker *CircuitBreaker
+}
+
+// Timeout defines the time to wait for the response of an endpoint
+type Timeout struct {
+	UpstreamResponseTimeoutMillis int
+}
+
+// CircuitBreaker defines when an endpoint is suspended and for how long. The error codes are the transport error
+// codes of the gateway on which the endpoint is suspended. A failing endpoint is retried the given number of times,
+// with the given delay between the retries, before it is suspended.
+type CircuitBreaker struct {
+	RetriesBeforeSuspension  int
+	RetryDelayMillis         int
+	SuspendErrorCodes        []string
+	SuspendDurationMillis    int
+	MaxSuspendDurationMillis int
+	SuspendFactor            float64
 }
 
 // AIRatelimit defines the configuration for AI rate limiting,
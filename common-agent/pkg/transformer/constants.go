@@ -108,4 +108,8 @@ const (
 	// Version constants
 	v1 = "v1"
 	v2 = "v2"
+
+	// Endpoint types of the load balanced and failover endpoints
+	loadBalanceEndpointType = "load_balance"
+	failoverEndpointType    = "failover"
 )
@@ -104,7 +104,8 @@ func GenerateConf(APIJson string, certArtifact CertificateArtifact, endpoints st
 		Name: "Primary Production Endpoint",
 		EndpointConfig: EndpointConfig{
 			ProductionEndpoints: EndpointDetails{
-				URL: prodURL,
+				URL:    prodURL,
+				Config: apiYamlData.EndpointConfig.ProductionEndpoints.Config,
 			},
 			EndpointType:     apiYamlData.EndpointConfig.EndpointType,
 			EndpointSecurity: apiYamlData.EndpointConfig.EndpointSecurity,
@@ -117,7 +118,8 @@ func GenerateConf(APIJson string, certArtifact CertificateArtifact, endpoints st
 		Name: "Primary Sandbox Endpoint",
 		EndpointConfig: EndpointConfig{
 			SandboxEndpoints: EndpointDetails{
-				URL: sandboxURL,
+				URL:    sandboxURL,
+				Config: apiYamlData.EndpointConfig.SandboxEndpoints.Config,
 			},
 			EndpointType:     apiYamlData.EndpointConfig.EndpointType,
 			EndpointSecurity: apiYamlData.EndpointConfig.EndpointSecurity,
@@ -234,7 +236,12 @@ func GenerateConf(APIJson string, certArtifact CertificateArtifact, endpoints st
 	logger.LoggerTransformer.Debugf("EndpointList len: %d", len(endpointList))
 	if len(endpointList) == 0 {
 		endpointSecurityData := apiYamlData.EndpointConfig.EndpointSecurity
-		endpointRes, endpointSecurityData = getEndpointConfigs(sandboxURL, prodURL, endCertAvailable, endpointCertList, endpointSecurityData, apiUniqueID, prodAIRatelimit, sandAIRatelimit)
+		endpointRes, endpointSecurityData = getEndpointConfigs(sandboxURL, prodURL, endCertAvailable, endpointCertList, endpointSecurityData, apiUniqueID, prodAIRatelimit, sandAIRatelimit,
+			getEndpointResiliency(apiYamlData.EndpointConfig.ProductionEndpoints), getEndpointResiliency(apiYamlData.EndpointConfig.SandboxEndpoints))
+		addEndpointGroups(endpointRes.Production, apiYamlData.EndpointConfig.EndpointType,
+			apiYamlData.EndpointConfig.ProductionEndpoints, apiYamlData.EndpointConfig.ProductionFailovers)
+		addEndpointGroups(endpointRes.Sandbox, apiYamlData.EndpointConfig.EndpointType,
+			apiYamlData.EndpointConfig.SandboxEndpoints, apiYamlData.EndpointConfig.SandboxFailovers)
 		apk.EndpointConfigurations = &endpointRes
 		endpointSecurityDataList = append(endpointSecurityDataList, endpointSecurityData)
 	} else {
@@ -704,7 +711,7 @@ func prepareAIRatelimit(maxTps *MaxTps) (*AIRatelimit, *AIRatelimit) {
 // getEndpointConfigs will map the endpoints and there security configurations and returns them
 // TODO: Currently the APK-Conf does not support giving multiple certs for a particular endpoint.
 // After fixing this, the following logic should be changed to map multiple cert configs
-func getEndpointConfigs(sandboxURL string, prodURL string, endCertAvailable bool, endpointCertList EndpointCertDescriptor, endpointSecurityData EndpointSecurityConfig, apiUniqueID string, prodAIRatelimit *AIRatelimit, sandAIRatelimit *AIRatelimit) (EndpointConfigurations, EndpointSecurityConfig) {
+func getEndpointConfigs(sandboxURL string, prodURL string, endCertAvailable bool, endpointCertList EndpointCertDescriptor, endpointSecurityData EndpointSecurityConfig, apiUniqueID string, prodAIRatelimit *AIRatelimit, sandAIRatelimit *AIRatelimit, prodResiliency *Resiliency, sandResiliency *Resiliency) (EndpointConfigurations, EndpointSecurityConfig) {
 	var sandboxEndpointConf, prodEndpointConf EndpointConfiguration
 	var sandBoxEndpointEnabled = false
 	var prodEndpointEnabled = false
@@ -720,6 +727,8 @@ func getEndpointConfigs(sandboxURL string, prodURL string, endCertAvailable bool
 	if sandAIRatelimit != nil {
 		sandboxEndpointConf.AIRatelimit = *sandAIRatelimit
 	}
+	sandboxEndpointConf.Resiliency = sandResiliency
+	prodEndpointConf.Resiliency = prodResiliency
 	sandboxEndpointConf.Endpoint = sandboxURL
 	prodEndpointConf.Endpoint = prodURL
 	if endCertAvailable {
@@ -831,6 +840,8 @@ func getMultiEndpointConfigs(endpointList []Endpoint, primaryProdEndpointID stri
 		if sandAIRatelimit != nil {
 			sandboxEndpointConf.AIRatelimit = *sandAIRatelimit
 		}
+		sandboxEndpointConf.Resiliency = getEndpointResiliency(endpoint.EndpointConfig.SandboxEndpoints)
+		prodEndpointConf.Resiliency = getEndpointResiliency(endpoint.EndpointConfig.ProductionEndpoints)
 		sandboxEndpointConf.Endpoint = sandboxURL
 		prodEndpointConf.Endpoint = prodURL
 		if endCertAvailable {
@@ -909,6 +920,83 @@ func getMultiEndpointConfigs(endpointList []Endpoint, primaryProdEndpointID stri
 	return epconfigs, endpointSecurityConfigs
 }
 
+// addEndpointGroups adds the other endpoints of a load balanced endpoint list or the failover endpoints of an
+// environment to the configuration of its endpoint
+func addEndpointGroups(endpointConfigs *[]EndpointConfiguration, endpointType string, endpoints EndpointDetails, failovers []EndpointDetails) {
+	if endpointConfigs == nil || len(*endpointConfigs) == 0 {
+		return
+	}
+	endpointConf := &(*endpointConfigs)[0]
+	switch endpointType {
+	case loadBalanceEndpointType:
+		for i, endpoint := range endpoints.LoadBalanced {
+			if i > 0 && endpoint.URL != "" {
+				endpointConf.LoadBalanceEndpoints = append(endpointConf.LoadBalanceEndpoints, endpoint.URL)
+			}
+		}
+	case failoverEndpointType:
+		for _, endpoint := range failovers {
+			if endpoint.URL != "" {
+				endpointConf.FailoverEndpoints = append(endpointConf.FailoverEndpoints, endpoint.URL)
+			}
+		}
+	}
+}
+
+// getEndpointResiliency maps the advanced configuration of an endpoint to its resiliency configuration. Nil is
+// returned when the endpoint has no timeout, retry or suspension settings.
+func getEndpointResiliency(endpointDetails EndpointDetails) *Resiliency {
+	endpointConfig := endpointDetails.Config
+	if endpointConfig == nil {
+		return nil
+	}
+	resiliency := Resiliency{}
+	if actionDuration := int(parseEndpointConfigValue(endpointConfig.ActionDuration, "actionDuration")); actionDuration > 0 {
+		resiliency.Timeout = &Timeout{UpstreamResponseTimeoutMillis: actionDuration}
+	}
+	suspendErrorCodes := []string{}
+	for _, errorCode := range endpointConfig.SuspendErrorCodes {
+		if code := strings.TrimSpace(string(errorCode)); code != "" {
+			suspendErrorCodes = append(suspendErrorCodes, code)
+		}
+	}
+	suspendDuration := int(parseEndpointConfigValue(endpointConfig.SuspendDuration, "suspendDuration"))
+	// The retry timeout of an endpoint is the number of retries before it is suspended
+	retriesBeforeSuspension := int(parseEndpointConfigValue(endpointConfig.RetryTimeOut, "retryTimeOut"))
+	if suspendDuration > 0 || len(suspendErrorCodes) > 0 || retriesBeforeSuspension > 0 {
+		resiliency.CircuitBreaker = &CircuitBreaker{
+			RetriesBeforeSuspension:  retriesBeforeSuspension,
+			RetryDelayMillis:         int(parseEndpointConfigValue(endpointConfig.RetryDelay, "retryDelay")),
+			SuspendErrorCodes:        suspendErrorCodes,
+			SuspendDurationMillis:    suspendDuration,
+			MaxSuspendDurationMillis: int(parseEndpointConfigValue(endpointConfig.SuspendMaxDuration, "suspendMaxDuration")),
+			SuspendFactor:            parseEndpointConfigValue(endpointConfig.Factor, "factor"),
+		}
+	}
+	if resiliency.Timeout == nil && resiliency.CircuitBreaker == nil {
+		return nil
+	}
+	return &resiliency
+}
+
+// parseEndpointConfigValue returns the numeric value of an advanced endpoint configuration. Zero is returned for
+// the unset, invalid and negative values, which leave the setting disabled.
+func parseEndpointConfigValue(value EndpointConfigValue, name string) float64 {
+	trimmedValue := strings.TrimSpace(string(value))
+	if trimmedValue == "" {
+		return 0
+	}
+	number, err := strconv.ParseFloat(trimmedValue, 64)
+	if err != nil {
+		logger.LoggerTransformer.Warnf("Ignoring the invalid endpoint %s %q: %v", name, trimmedValue, err)
+		return 0
+	}
+	if number < 0 {
+		return 0
+	}
+	return number
+}
+
 // generateSHA1Hash returns the SHA1 hash for the given string
 func generateSHA1Hash(input string) string {
 	h := sha1.New() /* #nosec */
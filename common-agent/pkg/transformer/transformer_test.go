@@ -20,11 +20,13 @@ package transformer
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
 )
 
 // Define testResourcesDir
@@ -154,3 +156,77 @@ func TestAPKConfGeneration(t *testing.T) {
 		}
 	}
 }
+
+func TestEndpointResiliencyMapping(t *testing.T) {
+	jsonConfig := `{"url": "https://backend.example.com", "config": {"actionDuration": "30000", "actionSelect": "fault",
+		"retryTimeOut": 2, "retryDelay": "500", "suspendErrorCode": ["101504", "101505"], "suspendDuration": 60000,
+		"suspendMaxDuration": "-1", "factor": ""}}`
+	yamlConfig := `
+url: https://backend.example.com
+config:
+  actionDuration: 30000
+  retryTimeOut: "2"
+  retryDelay: 500
+  suspendErrorCode: ["101504", "101505"]
+  suspendDuration: "60000"
+  suspendMaxDuration: -1
+`
+	expected := &Resiliency{
+		Timeout: &Timeout{UpstreamResponseTimeoutMillis: 30000},
+		CircuitBreaker: &CircuitBreaker{
+			RetriesBeforeSuspension: 2,
+			RetryDelayMillis:        500,
+			SuspendErrorCodes:       []string{"101504", "101505"},
+			SuspendDurationMillis:   60000,
+		},
+	}
+
+	var jsonEndpoint, yamlEndpoint EndpointDetails
+	assert.NoError(t, json.Unmarshal([]byte(jsonConfig), &jsonEndpoint))
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlConfig), &yamlEndpoint))
+	assert.Equal(t, expected, getEndpointResiliency(jsonEndpoint))
+	assert.Equal(t, expected, getEndpointResiliency(yamlEndpoint))
+
+	// The resiliency configuration is not part of the apk-conf
+	apkConf, err := yaml.Marshal(EndpointConfiguration{Endpoint: "https://backend.example.com", Resiliency: expected})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(apkConf), "resiliency")
+
+	emptyConfig := `{"url": "https://backend.example.com", "config": {"actionDuration": "", "retryTimeOut": "",
+		"suspendErrorCode": [], "suspendDuration": ""}}`
+	var emptyEndpoint EndpointDetails
+	assert.NoError(t, json.Unmarshal([]byte(emptyConfig), &emptyEndpoint))
+	assert.Nil(t, getEndpointResiliency(emptyEndpoint))
+	assert.Nil(t, getEndpointResiliency(EndpointDetails{URL: "https://backend.example.com"}))
+}
+
+func TestEndpointGroupsMapping(t *testing.T) {
+	loadBalanceConfig := `{"endpoint_type": "load_balance",
+		"production_endpoints": [{"url": "https://prod1.example.com"}, {"url": "https://prod2.example.com"}],
+		"sandbox_endpoints": {"url": "https://sandbox.example.com"}}`
+	failoverConfig := `
+endpoint_type: failover
+production_endpoints:
+  url: https://prod1.example.com
+production_failovers:
+  - url: https://prod2.example.com
+  - url: https://prod3.example.com
+`
+	var loadBalanceEndpoints, failoverEndpoints EndpointConfig
+	assert.NoError(t, json.Unmarshal([]byte(loadBalanceConfig), &loadBalanceEndpoints))
+	assert.NoError(t, yaml.Unmarshal([]byte(failoverConfig), &failoverEndpoints))
+	assert.Equal(t, "https://prod1.example.com", loadBalanceEndpoints.ProductionEndpoints.URL)
+	assert.Equal(t, "https://sandbox.example.com", loadBalanceEndpoints.SandboxEndpoints.URL)
+
+	production := []EndpointConfiguration{{Endpoint: "https://prod1.example.com"}}
+	sandbox := []EndpointConfiguration{{Endpoint: "https://sandbox.example.com"}}
+	addEndpointGroups(&production, loadBalanceEndpoints.EndpointType, loadBalanceEndpoints.ProductionEndpoints, loadBalanceEndpoints.ProductionFailovers)
+	addEndpointGroups(&sandbox, loadBalanceEndpoints.EndpointType, loadBalanceEndpoints.SandboxEndpoints, loadBalanceEndpoints.SandboxFailovers)
+	assert.Equal(t, []string{"https://prod2.example.com"}, production[0].LoadBalanceEndpoints)
+	assert.Empty(t, sandbox[0].LoadBalanceEndpoints)
+
+	production = []EndpointConfiguration{{Endpoint: "https://prod1.example.com"}}
+	addEndpointGroups(&production, failoverEndpoints.EndpointType, failoverEndpoints.ProductionEndpoints, failoverEndpoints.ProductionFailovers)
+	assert.Equal(t, []string{"https://prod2.example.com", "https://prod3.example.com"}, production[0].FailoverEndpoints)
+	assert.Empty(t, production[0].LoadBalanceEndpoints)
+}
@@ -207,6 +207,10 @@ func getAPIType(protocolType string) string {
 		apiType = "REST"
 	case "GRAPHQL":
 		apiType = "GRAPHQL"
+	case "GRPC":
+		apiType = "GRPC"
+	case "WS", "SSE", "WEBSUB", "ASYNC":
+		apiType = protocolType
 	}
 	return apiType
 }
@@ -1,16 +1,19 @@
 package utils
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 	"github.com/wso2/apk/common-go-libs/constants"
 	"github.com/wso2/apk/common-go-libs/loggers"
 	apkmgt "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/service/apkmgt"
 	"github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var clientConnections = make(map[string]apkmgt.EventStreamService_StreamEventsServer)
@@ -63,9 +66,13 @@ func SendInitialEvent(srv apkmgt.EventStreamService_StreamEventsServer) {
 // SendEvent sends event to the common-controllers
 func SendEvent(event *subscription.Event) {
 	loggers.LoggerAPKOperator.Infof("Sending event to all clients: %v", event)
+	_, span := tracing.StartSpan(context.Background(), "EventStreamService/SendEvent",
+		attribute.String("event.type", event.Type), attribute.String("event.uuid", event.Uuid))
+	defer span.End()
 	for clientID, stream := range GetAllClientConnections() {
 		err := stream.Send(event)
 		if err != nil {
+			span.RecordError(err)
 			loggers.LoggerAPKOperator.Errorf("Error sending event to client %s: %v", clientID, err)
 		} else {
 			loggers.LoggerAPKOperator.Debugf("Event sent to client %s", clientID)
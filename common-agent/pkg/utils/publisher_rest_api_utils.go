@@ -18,6 +18,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -215,7 +216,7 @@ func GetSuitableAuthHeadervalue(scopes []string) (string, error) {
 }
 
 // ImportAPI imports an API from a zip file, returning the ID of the imported API.
-func ImportAPI(apiZipName string, zipFileBytes *bytes.Buffer) (string, string, error) {
+func ImportAPI(ctx context.Context, apiZipName string, zipFileBytes *bytes.Buffer) (string, string, error) {
 	authHeaderVal, err := GetSuitableAuthHeadervalue([]string{string(AdminScope), string(ImportExportScope)})
 	if err != nil {
 		return "", "", err
@@ -230,7 +231,7 @@ func ImportAPI(apiZipName string, zipFileBytes *bytes.Buffer) (string, string, e
 		return "", "", err
 	}
 	writer.Close()
-	req, err := http.NewRequest("POST", apiImportURL, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", apiImportURL, body)
 	if err != nil {
 		return "", "", err
 	}
@@ -265,13 +266,13 @@ func ImportAPI(apiZipName string, zipFileBytes *bytes.Buffer) (string, string, e
 }
 
 // DeleteAPIRevision deletes an API given its UUID.
-func DeleteAPIRevision(apiUUID string, revisionID string, body string) error {
+func DeleteAPIRevision(ctx context.Context, apiUUID string, revisionID string, body string) error {
 	deleteURL := apiDeleteURL + apiUUID + "/undeploy-revision?revisionId=" + revisionID
 	authheaderval, err := GetSuitableAuthHeadervalue([]string{string(AdminScope), string(ImportExportScope)})
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest("POST", deleteURL, bytes.NewBuffer([]byte(body)))
+	req, err := http.NewRequestWithContext(ctx, "POST", deleteURL, bytes.NewBuffer([]byte(body)))
 	if err != nil {
 		return err
 	}
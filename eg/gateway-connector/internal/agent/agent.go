@@ -21,6 +21,7 @@ package agent
 import (
 	"flag"
 
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/discovery"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/eventhub"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/synchronizer"
@@ -28,6 +29,7 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/utils"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	commonMgmt "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 
 	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	cpv1alpha2 "github.com/wso2/apk/common-go-libs/apis/cp/v1alpha2"
@@ -56,6 +58,7 @@ func PreRun(conf *config.Config, scheme *runtime.Scheme) {
 	utilruntime.Must(gwapiv1.Install(scheme))
 	utilruntime.Must(gwapiv1a2.Install(scheme))
 	utilruntime.Must(gwapiv1a3.Install(scheme))
+	managementserver.RegisterSnapshotStores()
 }
 
 // initializeAPKIntegrations sets up APK-specific integrations with the common agent
@@ -63,14 +66,34 @@ func initializeAPKIntegrations() {
 	loggers.LoggerAgent.Info("Starting APK integrations initialization")
 	apkAPIYamlCreator := utils.NewAPKAPIYamlCreator()
 	commonMgmt.SetAPIYamlCreator(apkAPIYamlCreator)
+
+	egCallback := &discovery.EGAPIImportCallback{}
+	commonMgmt.RegisterAPIImportCallback(egCallback)
+	loggers.LoggerAgent.Debugf("Successfully registered Envoy Gateway API import callback for discovery mode")
+}
+
+// startDiscovery publishes the APIs created directly in the data plane to the control plane
+func startDiscovery(conf *config.Config) {
+	loggers.LoggerAgent.Info("Initializing Envoy Gateway CR Watcher")
+	if err := discovery.CRWatcher.Initialize(); err != nil {
+		loggers.LoggerAgent.Errorf("Failed to initialize Envoy Gateway CR Watcher: %v", err)
+		return
+	}
+
+	loggers.LoggerAgent.Info("Initializing HTTPRoutes state")
+	discovery.InitializeHTTPRoutesState(conf.DataPlane.Namespace)
+
+	loggers.LoggerAgent.Info("Starting Envoy Gateway CR Discovery")
+	go discovery.CRWatcher.Watch()
 }
 
 // Run starts the GRPC server and Rest API server.
-func Run(conf *config.Config, mgr manager.Manager) {
+func Run(conf *config.Config, mgr manager.Manager) error {
 	loggers.LoggerAgent.Info("Starting APK Gateway Connector Agent...")
 	AgentMode := conf.Agent.Mode
 	loggers.LoggerAgent.Infof("Agent Mode: %s", AgentMode)
 	initializeAPKIntegrations()
+	metrics.RegisterCacheSize("applications", managementserver.GetApplicationCount)
 
 	go managementserver.StartInternalServer(restPort)
 
@@ -86,6 +109,15 @@ func Run(conf *config.Config, mgr manager.Manager) {
 	// Load initial AI Provider data from control plane
 	synchronizer.FetchAIProvidersOnEvent("", "", "", mgr.GetClient(), true)
 
+	if AgentMode == "DPtoCP" {
+		startDiscovery(conf)
+	}
+
 	// Load initial data from control plane
-	eventhub.LoadInitialData(conf, mgr.GetClient())
+	if err := eventhub.LoadInitialData(conf, mgr.GetClient()); err != nil {
+		return err
+	}
+	// Load initial Application Rate Limit data from control plane once the applications and their keys are loaded
+	synchronizer.FetchApplicationRateLimitPoliciesOnEvent("", "", mgr.GetClient())
+	return nil
 }
@@ -17,6 +17,8 @@
 
 package constants
 
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
 // Gateway related constants
 const (
 	GatewayName  = "wso2-apk-default"
@@ -33,3 +35,74 @@ const (
 	InternalKeySecretKey       = "wso2.crt"
 	InternalKeySuffix          = "-internal-key-issuer"
 )
+
+// Discovery related constants
+const (
+	DefaultEGAgentName = "EnvoyGateway"
+
+	HTTPRouteKind            = "HTTPRoute"
+	SecurityPolicyKind       = "SecurityPolicy"
+	BackendTrafficPolicyKind = "BackendTrafficPolicy"
+	BackendKind              = "Backend"
+	ServiceKind              = "Service"
+	RouteMetadataKind        = "RouteMetadata"
+
+	APIUUIDLabel          = "apiUUID"
+	EGAPIUUIDLabel        = "egAPIUUID"
+	APIVersionLabel       = "apiVersion"
+	APINameLabel          = "apiName"
+	RevisionIDLabel       = "revisionID"
+	ShowInCPLabel         = "showInCP"
+	OrganizationLabel     = "kgw.wso2.com/organization"
+	K8sInitiatedFromField = "InitiateFrom"
+	ControlPlaneOrigin    = "CP"
+	DataPlaneOrigin       = "DP"
+	EnvTypeAnnotation     = "gateway.envoyproxy.io/kgw-envtype"
+	ProductionEnvType     = "PRODUCTION"
+	SandboxEnvType        = "SANDBOX"
+
+	DefaultAPIVersion    = "v1"
+	DefaultAPIType       = "rest"
+	DefaultBasePath      = "/"
+	DefaultHTTPMethod    = "GET"
+	DefaultAuthHeader    = "Authorization"
+	DefaultShowInCPFalse = "false"
+	OAuth2SecurityScheme = "oauth2"
+	ServiceDNSTemplate   = "%s.%s.svc.cluster.local"
+
+	MaxRetries           = 3
+	RetryDelayMultiplier = 100
+)
+
+var (
+	// HTTPRouteGVR defines the HTTPRoute GroupVersionResource
+	HTTPRouteGVR = schema.GroupVersionResource{
+		Group:    "gateway.networking.k8s.io",
+		Version:  "v1",
+		Resource: "httproutes",
+	}
+	// SecurityPolicyGVR defines the SecurityPolicy GroupVersionResource
+	SecurityPolicyGVR = schema.GroupVersionResource{
+		Group:    "gateway.envoyproxy.io",
+		Version:  "v1alpha1",
+		Resource: "securitypolicies",
+	}
+	// BackendTrafficPolicyGVR defines the BackendTrafficPolicy GroupVersionResource
+	BackendTrafficPolicyGVR = schema.GroupVersionResource{
+		Group:    "gateway.envoyproxy.io",
+		Version:  "v1alpha1",
+		Resource: "backendtrafficpolicies",
+	}
+	// BackendGVR defines the Backend GroupVersionResource
+	BackendGVR = schema.GroupVersionResource{
+		Group:    "gateway.envoyproxy.io",
+		Version:  "v1alpha1",
+		Resource: "backends",
+	}
+	// DiscoveryGVRs defines the resources watched for DP to CP discovery
+	DiscoveryGVRs = []schema.GroupVersionResource{
+		HTTPRouteGVR,
+		SecurityPolicyGVR,
+		BackendTrafficPolicyGVR,
+	}
+)
@@ -0,0 +1,72 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package discovery
+
+import (
+	"context"
+
+	discoverPkg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/discovery"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EGAPIImportCallback implements the APIImportCallback interface for the Envoy Gateway connector
+type EGAPIImportCallback struct{}
+
+// OnAPIImportSuccess is called when an API has been successfully imported to the control plane.
+// It updates the HTTPRoute CRs with the apiID label so that later updates refer to the imported API.
+func (e *EGAPIImportCallback) OnAPIImportSuccess(egAPIUUID, apiID, revisionID, crName, crNamespace, agentName string) {
+	loggers.LoggerDiscovery.Infof("%s API import callback triggered - egAPIUUID: %s, apiID: %s, revisionID: %s, CR: %s/%s",
+		agentName, egAPIUUID, apiID, revisionID, crNamespace, crName)
+
+	if CRWatcher == nil || CRWatcher.DynamicClient == nil {
+		loggers.LoggerDiscovery.Error("CRWatcher or DynamicClient is not initialized")
+		return
+	}
+
+	httpRouteList, err := CRWatcher.DynamicClient.Resource(constants.HTTPRouteGVR).Namespace(crNamespace).List(
+		context.Background(),
+		metav1.ListOptions{
+			LabelSelector: constants.EGAPIUUIDLabel + "=" + egAPIUUID,
+		},
+	)
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Failed to list HTTPRoutes with apiID for API %s: %v", egAPIUUID, err)
+	} else {
+		for i := range httpRouteList.Items {
+			updateHTTPRouteLabels(&httpRouteList.Items[i], map[string]string{
+				constants.RevisionIDLabel: revisionID,
+				constants.APIUUIDLabel:    apiID,
+			})
+		}
+	}
+
+	apiMutex.Lock()
+	defer apiMutex.Unlock()
+	api, ok := discoverPkg.APIMap[egAPIUUID]
+	if !ok {
+		loggers.LoggerDiscovery.Errorf("API not found in APIMap: %s", egAPIUUID)
+		return
+	}
+	api.APIUUID = apiID
+	api.RevisionID = revisionID
+	discoverPkg.APIMap[egAPIUUID] = api
+
+	loggers.LoggerDiscovery.Infof("Successfully updated API in APIMap - originalKey: %s, newAPIUUID: %s", egAPIUUID, apiID)
+}
@@ -0,0 +1,72 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package discovery
+
+// Field names used while reading the unstructured Envoy Gateway resources
+const (
+	specField        = "spec"
+	hostnamesField   = "hostnames"
+	rulesField       = "rules"
+	matchesField     = "matches"
+	pathField        = "path"
+	valueField       = "value"
+	methodField      = "method"
+	filtersField     = "filters"
+	extensionRef     = "extensionRef"
+	backendRefsField = "backendRefs"
+	kindField        = "kind"
+	nameField        = "name"
+	namespaceField   = "namespace"
+	portField        = "port"
+	targetRefField   = "targetRef"
+	targetRefsField  = "targetRefs"
+	sectionNameField = "sectionName"
+	endpointsField   = "endpoints"
+	fqdnField        = "fqdn"
+	ipField          = "ip"
+	hostnameField    = "hostname"
+	addressField     = "address"
+	tlsField         = "tls"
+
+	corsField             = "cors"
+	allowOriginsField     = "allowOrigins"
+	allowMethodsField     = "allowMethods"
+	allowHeadersField     = "allowHeaders"
+	exposeHeadersField    = "exposeHeaders"
+	maxAgeField           = "maxAge"
+	allowCredentialsField = "allowCredentials"
+
+	jwtField          = "jwt"
+	providersField    = "providers"
+	extractFromField  = "extractFrom"
+	headersField      = "headers"
+	rateLimitField    = "rateLimit"
+	globalField       = "global"
+	localField        = "local"
+	limitField        = "limit"
+	requestsField     = "requests"
+	unitField         = "unit"
+	metadataField     = "metadata"
+	labelsField       = "labels"
+	objectModifiedErr = "the object has been modified"
+
+	httpProtocol  = "http"
+	httpsProtocol = "https"
+	httpPort      = 80
+	httpsPort     = 443
+)
@@ -0,0 +1,113 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+// Package discovery publishes the APIs created directly in the Envoy Gateway data plane to the control plane
+package discovery
+
+import (
+	"sync"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	discoveryPkg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/discovery"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	configOnce sync.Once
+	apiMutex   sync.RWMutex
+)
+
+// IsControlPlaneInitiated checks if the resource was initiated from control plane. Routes deployed
+// through the control plane always refer to a RouteMetadata, so they are treated as control plane
+// initiated even when the origin label is missing.
+func IsControlPlaneInitiated(u *unstructured.Unstructured) bool {
+	if origin, exists := u.GetLabels()[constants.K8sInitiatedFromField]; exists {
+		return origin == constants.ControlPlaneOrigin
+	}
+	if u.GetKind() == constants.HTTPRouteKind {
+		return hasRouteMetadataRef(u)
+	}
+	return false
+}
+
+// addResource handles the addition of a resource
+func addResource(u *unstructured.Unstructured) {
+	loggers.LoggerDiscovery.Debugf("Resource Added: %s/%s (Kind: %s), APIVersion: %s, Labels: %v",
+		u.GetNamespace(), u.GetName(), u.GetKind(), u.GetAPIVersion(), u.GetLabels())
+	if IsControlPlaneInitiated(u) {
+		return
+	}
+	switch u.GetKind() {
+	case constants.HTTPRouteKind:
+		handleAddHTTPRouteResource(u)
+	case constants.SecurityPolicyKind, constants.BackendTrafficPolicyKind:
+		handlePolicyResource(nil, u)
+	}
+}
+
+// updateResource handles the update of a resource
+func updateResource(oldU, newU *unstructured.Unstructured) {
+	if oldU.GetResourceVersion() == newU.GetResourceVersion() {
+		loggers.LoggerDiscovery.Debugf("Skipping resync event for %s/%s", newU.GetNamespace(), newU.GetName())
+		return
+	}
+	loggers.LoggerDiscovery.Debugf("Resource Updated: %s/%s (Kind: %s), APIVersion: %s, Generation: %d -> %d",
+		newU.GetNamespace(), newU.GetName(), newU.GetKind(), newU.GetAPIVersion(), oldU.GetGeneration(), newU.GetGeneration())
+	if IsControlPlaneInitiated(newU) {
+		return
+	}
+	switch newU.GetKind() {
+	case constants.HTTPRouteKind:
+		handleUpdateHTTPRouteResource(oldU, newU)
+	case constants.SecurityPolicyKind, constants.BackendTrafficPolicyKind:
+		handlePolicyResource(oldU, newU)
+	}
+}
+
+// deleteResource handles the deletion of a resource
+func deleteResource(u *unstructured.Unstructured) {
+	loggers.LoggerDiscovery.Debugf("Resource Deleted: %s/%s (Kind: %s), APIVersion: %s, UID: %s",
+		u.GetNamespace(), u.GetName(), u.GetKind(), u.GetAPIVersion(), u.GetUID())
+	if IsControlPlaneInitiated(u) {
+		return
+	}
+	switch u.GetKind() {
+	case constants.HTTPRouteKind:
+		handleDeleteHTTPRouteResource(u)
+	case constants.SecurityPolicyKind, constants.BackendTrafficPolicyKind:
+		handlePolicyResource(u, nil)
+	}
+}
+
+// CRWatcher watches the Envoy Gateway resources used to discover data plane APIs
+var CRWatcher *discoveryPkg.CRWatcher
+
+func init() {
+	configOnce.Do(func() {
+		conf, _ := config.ReadConfigs()
+
+		CRWatcher = &discoveryPkg.CRWatcher{
+			Namespace:     conf.DataPlane.Namespace,
+			GroupVersions: constants.DiscoveryGVRs,
+			AddFunc:       addResource,
+			UpdateFunc:    updateResource,
+			DeleteFunc:    deleteResource,
+		}
+	})
+}
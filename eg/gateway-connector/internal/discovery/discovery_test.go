@@ -0,0 +1,149 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newHTTPRoute(filters []interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":      "pizza-route",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"hostnames": []interface{}{"pizza.example.com"},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/pizza/menu"}, "method": "GET"},
+						map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/pizza/order"}, "method": "POST"},
+						map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/pizza/menu"}, "method": "GET"},
+					},
+					"filters":     filters,
+					"backendRefs": []interface{}{map[string]interface{}{"name": "pizza-svc", "port": int64(8080)}},
+				},
+			},
+		},
+	}}
+}
+
+func TestExtractOperationsAndBasePath(t *testing.T) {
+	route := newHTTPRoute(nil)
+	operations := extractOperations(route)
+	assert.Len(t, operations, 2)
+	assert.Equal(t, "POST", operations[1].Verb)
+	assert.Equal(t, "/pizza", extractBasePath(operations))
+	assert.Equal(t, "/", extractBasePath(nil))
+}
+
+func TestIsControlPlaneInitiated(t *testing.T) {
+	assert.False(t, IsControlPlaneInitiated(newHTTPRoute(nil)))
+
+	route := newHTTPRoute([]interface{}{map[string]interface{}{
+		"type":         "ExtensionRef",
+		"extensionRef": map[string]interface{}{"group": "dp.wso2.com", "kind": "RouteMetadata", "name": "pizza"},
+	}})
+	assert.True(t, IsControlPlaneInitiated(route))
+
+	route.SetLabels(map[string]string{"InitiateFrom": "DP"})
+	assert.False(t, IsControlPlaneInitiated(route))
+}
+
+func TestExtractEndpointFromService(t *testing.T) {
+	protocol, endpoint := extractEndpoint(newHTTPRoute(nil))
+	assert.Equal(t, "http", protocol)
+	assert.Equal(t, "pizza-svc.default.svc.cluster.local:8080", endpoint)
+}
+
+func TestGetBackendEndpoint(t *testing.T) {
+	backend := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"endpoints": []interface{}{map[string]interface{}{"fqdn": map[string]interface{}{"hostname": "pizza.com", "port": int64(443)}}},
+			"tls":       map[string]interface{}{"wellKnownCACertificates": "System"},
+		},
+	}}
+	protocol, endpoint := getBackendEndpoint(backend)
+	assert.Equal(t, "https", protocol)
+	assert.Equal(t, "pizza.com", endpoint)
+}
+
+func TestExtractSecurityPolicy(t *testing.T) {
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "SecurityPolicy",
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{
+				map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": "pizza-route"},
+				map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "default"},
+			},
+			"cors": map[string]interface{}{
+				"allowOrigins":     []interface{}{"*"},
+				"allowMethods":     []interface{}{"GET", "POST"},
+				"allowCredentials": true,
+				"maxAge":           "10m",
+			},
+			"jwt": map[string]interface{}{
+				"providers": []interface{}{map[string]interface{}{
+					"name":        "km",
+					"extractFrom": map[string]interface{}{"headers": []interface{}{map[string]interface{}{"name": "X-JWT"}}},
+				}},
+			},
+		},
+	}}
+	assert.Equal(t, []string{"pizza-route"}, getTargetHTTPRouteNames(policy))
+	assert.True(t, isAPILevelPolicy(policy, "pizza-route"))
+
+	cors := extractCORSPolicy(policy)
+	assert.NotNil(t, cors)
+	assert.Equal(t, []string{"*"}, cors.AccessControlAllowOrigins)
+	assert.Equal(t, []string{"GET", "POST"}, cors.AccessControlAllowMethods)
+	assert.True(t, cors.AccessControlAllowCredentials)
+	assert.Equal(t, 600, *cors.AccessControlMaxAge)
+
+	authHeader, found := extractAuthHeader(policy)
+	assert.True(t, found)
+	assert.Equal(t, "X-JWT", authHeader)
+}
+
+func TestExtractRateLimit(t *testing.T) {
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "BackendTrafficPolicy",
+		"spec": map[string]interface{}{
+			"targetRefs": []interface{}{
+				map[string]interface{}{"kind": "HTTPRoute", "name": "pizza-route", "sectionName": "operation-0"},
+			},
+			"rateLimit": map[string]interface{}{
+				"type": "Global",
+				"global": map[string]interface{}{
+					"rules": []interface{}{map[string]interface{}{"limit": map[string]interface{}{"requests": int64(10), "unit": "Minute"}}},
+				},
+			},
+		},
+	}}
+	assert.False(t, isAPILevelPolicy(policy, "pizza-route"))
+	rateLimit := extractRateLimit(policy)
+	assert.NotNil(t, rateLimit)
+	assert.Equal(t, uint32(10), *rateLimit.RequestCount)
+	assert.Equal(t, "Minute", rateLimit.TimeUnit)
+}
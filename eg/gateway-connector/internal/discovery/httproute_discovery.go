@@ -0,0 +1,505 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	discoverPkg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/discovery"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
+)
+
+// ReconcileAPI is the single point of logic for creating/updating the API of an HTTPRoute in the control plane.
+func ReconcileAPI(namespace, routeName string) {
+	loggers.LoggerDiscovery.Infof("Reconciling API for HTTPRoute %s/%s", namespace, routeName)
+
+	apiMutex.Lock()
+	defer apiMutex.Unlock()
+
+	route := FetchHTTPRoute(namespace, routeName)
+	if route == nil {
+		loggers.LoggerDiscovery.Warnf("Cannot reconcile API for HTTPRoute %s/%s, as it could not be fetched.", namespace, routeName)
+		return
+	}
+	if IsControlPlaneInitiated(route) {
+		loggers.LoggerDiscovery.Debugf("Skipping reconciliation for control plane initiated HTTPRoute %s/%s", namespace, routeName)
+		return
+	}
+	if shouldSkipResource(route) {
+		loggers.LoggerDiscovery.Infof("Skipping reconciliation for HTTPRoute %s/%s as it's marked to be ignored.", namespace, routeName)
+		return
+	}
+
+	egAPIUUID := getOrGenerateEGAPIUUID(route)
+	desiredAPI := buildAPIFromHTTPRoute(route, egAPIUUID)
+	newHash := computeAPIHash(desiredAPI)
+
+	currentHash, apiExists := discoverPkg.APIHashMap[egAPIUUID]
+	loggers.LoggerDiscovery.Debugf("API %s has current hash: %s old hash: %s.", egAPIUUID, newHash, currentHash)
+	if apiExists && currentHash == newHash {
+		loggers.LoggerDiscovery.Infof("API %s is unchanged (hash match). Skipping update.", egAPIUUID)
+		return
+	}
+
+	loggers.LoggerDiscovery.Infof("API %s has changed (new hash: %s). Updating...", egAPIUUID, newHash)
+	revisionID := generateRevisionID()
+	desiredAPI.RevisionID = revisionID
+	if existingAPI, exists := discoverPkg.APIMap[egAPIUUID]; exists && existingAPI.APIUUID != egAPIUUID {
+		// The API was already imported, keep the control plane API ID so that the update targets the same API
+		desiredAPI.APIUUID = existingAPI.APIUUID
+	}
+
+	updateHTTPRouteLabels(route, map[string]string{
+		constants.RevisionIDLabel:       revisionID,
+		constants.EGAPIUUIDLabel:        egAPIUUID,
+		constants.K8sInitiatedFromField: constants.DataPlaneOrigin,
+	})
+
+	discoverPkg.APIHashMap[egAPIUUID] = newHash
+	discoverPkg.APIMap[egAPIUUID] = desiredAPI
+	discoverPkg.QueueEvent(managementserver.CreateEvent, desiredAPI, routeName, namespace, constants.DefaultEGAgentName, egAPIUUID)
+
+	loggers.LoggerDiscovery.Infof("Successfully reconciled and updated API %s for HTTPRoute %s/%s", egAPIUUID, namespace, routeName)
+}
+
+// InitializeHTTPRoutesState fetches all existing HTTPRoutes and reconciles the data plane initiated ones
+func InitializeHTTPRoutesState(namespace string) {
+	loggers.LoggerDiscovery.Infof("Starting HTTPRoutes state initialization")
+
+	if namespace == "" {
+		loggers.LoggerDiscovery.Error("Namespace cannot be empty for HTTPRoutes state initialization")
+		return
+	}
+	if CRWatcher == nil || CRWatcher.DynamicClient == nil {
+		loggers.LoggerDiscovery.Error("CRWatcher or DynamicClient is not initialized")
+		return
+	}
+
+	routeList, err := CRWatcher.DynamicClient.Resource(constants.HTTPRouteGVR).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Failed to list HTTPRoutes in namespace %s: %v", namespace, err)
+		return
+	}
+
+	for i := range routeList.Items {
+		if !IsControlPlaneInitiated(&routeList.Items[i]) {
+			ReconcileAPI(namespace, routeList.Items[i].GetName())
+		}
+	}
+	loggers.LoggerDiscovery.Infof("Completed HTTPRoutes state initialization for namespace %s, processed %d routes", namespace, len(routeList.Items))
+}
+
+// handleAddHTTPRouteResource handles the addition of an HTTPRoute
+func handleAddHTTPRouteResource(route *unstructured.Unstructured) {
+	loggers.LoggerDiscovery.Infof("Processing new HTTPRoute addition: %s/%s (Generation: %d, ResourceVersion: %s)",
+		route.GetNamespace(), route.GetName(), route.GetGeneration(), route.GetResourceVersion())
+	ReconcileAPI(route.GetNamespace(), route.GetName())
+}
+
+// handleUpdateHTTPRouteResource handles the update of an HTTPRoute
+func handleUpdateHTTPRouteResource(oldRoute, route *unstructured.Unstructured) {
+	loggers.LoggerDiscovery.Infof("Processing HTTPRoute modification: %s/%s (Generation: %d, ResourceVersion: %s)",
+		route.GetNamespace(), route.GetName(), route.GetGeneration(), route.GetResourceVersion())
+	if generateResourceHash(oldRoute) == generateResourceHash(route) {
+		loggers.LoggerDiscovery.Debugf("HTTPRoute %s/%s hash unchanged - skipping reconciliation", route.GetNamespace(), route.GetName())
+		return
+	}
+	ReconcileAPI(route.GetNamespace(), route.GetName())
+}
+
+// handleDeleteHTTPRouteResource handles the deletion of an HTTPRoute
+func handleDeleteHTTPRouteResource(route *unstructured.Unstructured) {
+	loggers.LoggerDiscovery.Infof("Processing HTTPRoute deletion: %s/%s (Generation: %d, ResourceVersion: %s)",
+		route.GetNamespace(), route.GetName(), route.GetGeneration(), route.GetResourceVersion())
+
+	egAPIUUID, hasEGAPIUUID := route.GetLabels()[constants.EGAPIUUIDLabel]
+	if !hasEGAPIUUID {
+		loggers.LoggerDiscovery.Warnf("Deleted HTTPRoute %s/%s has no egAPIUUID label, cannot delete corresponding API.",
+			route.GetNamespace(), route.GetName())
+		return
+	}
+
+	apiMutex.Lock()
+	defer apiMutex.Unlock()
+
+	if api, exists := discoverPkg.APIMap[egAPIUUID]; exists {
+		delete(discoverPkg.APIMap, egAPIUUID)
+		delete(discoverPkg.APIHashMap, egAPIUUID)
+		discoverPkg.QueueEvent(managementserver.DeleteEvent, api, route.GetName(), route.GetNamespace(), constants.DefaultEGAgentName, egAPIUUID)
+		loggers.LoggerDiscovery.Infof("Successfully processed %s/%s HTTPRoute deletion - API %s removed from system",
+			route.GetNamespace(), route.GetName(), egAPIUUID)
+	}
+}
+
+// FetchHTTPRoute retrieves an HTTPRoute CR by name
+func FetchHTTPRoute(namespace, name string) *unstructured.Unstructured {
+	route, err := CRWatcher.DynamicClient.Resource(constants.HTTPRouteGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Error fetching HTTPRoute %s/%s: %v", namespace, name, err)
+		return nil
+	}
+	return route
+}
+
+// buildAPIFromHTTPRoute constructs a managementserver.API from an HTTPRoute and the policies attached to it
+func buildAPIFromHTTPRoute(route *unstructured.Unstructured, egAPIUUID string) managementserver.API {
+	loggers.LoggerDiscovery.Debugf("Building API from HTTPRoute %s/%s - UUID: %s", route.GetNamespace(), route.GetName(), egAPIUUID)
+
+	labels := route.GetLabels()
+	api := managementserver.API{
+		APIUUID:          egAPIUUID,
+		APIName:          route.GetName(),
+		APIVersion:       constants.DefaultAPIVersion,
+		IsDefaultVersion: true,
+		APIType:          constants.DefaultAPIType,
+		Organization:     labels[constants.OrganizationLabel],
+	}
+	if apiName, found := labels[constants.APINameLabel]; found && apiName != "" {
+		api.APIName = apiName
+	}
+	if apiVersion, found := labels[constants.APIVersionLabel]; found && apiVersion != "" {
+		api.APIVersion = apiVersion
+	}
+
+	sandbox := isSandboxRoute(route)
+	if hostnames, found, _ := unstructured.NestedStringSlice(route.Object, specField, hostnamesField); found && len(hostnames) > 0 {
+		if sandbox {
+			api.SandVhost = hostnames[0]
+		} else {
+			api.Vhost = hostnames[0]
+		}
+	}
+
+	api.Operations = extractOperations(route)
+	api.BasePath = extractBasePath(api.Operations)
+	generateAndAttachAPIDefinition(&api, route, egAPIUUID)
+
+	if protocol, endpoint := extractEndpoint(route); endpoint != "" {
+		api.EndpointProtocol = protocol
+		if sandbox {
+			api.SandEndpoint = endpoint
+		} else {
+			api.ProdEndpoint = endpoint
+		}
+	}
+
+	applySecurityPolicies(&api, route)
+	applyBackendTrafficPolicies(&api, route, sandbox)
+	return api
+}
+
+// generateAndAttachAPIDefinition generates the OpenAPI definition for an API and attaches it
+func generateAndAttachAPIDefinition(api *managementserver.API, route *unstructured.Unstructured, egAPIUUID string) {
+	conf, _ := config.ReadConfigs()
+	apiDefinition, err := discoverPkg.GenerateOpenAPIDefinition([]*unstructured.Unstructured{route}, egAPIUUID, conf)
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Failed to generate OpenAPI definition for API %s: %v", egAPIUUID, err)
+		return
+	}
+
+	data, err := json.Marshal(apiDefinition)
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Failed to convert API definition to JSON bytes for API %s: %v", egAPIUUID, err)
+		return
+	}
+	api.Definition = string(data)
+}
+
+// extractOperations pulls operations from HTTPRoute rules
+func extractOperations(route *unstructured.Unstructured) []managementserver.OperationFromDP {
+	rules, found, err := unstructured.NestedSlice(route.Object, specField, rulesField)
+	if err != nil || !found {
+		return nil
+	}
+
+	var operations []managementserver.OperationFromDP
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches, found, err := unstructured.NestedSlice(ruleMap, matchesField)
+		if err != nil || !found {
+			continue
+		}
+		for _, match := range matches {
+			matchMap, ok := match.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			path, _, _ := unstructured.NestedString(matchMap, pathField, valueField)
+			if path == "" {
+				path = constants.DefaultBasePath
+			}
+			verb, _, _ := unstructured.NestedString(matchMap, methodField)
+			if verb == "" {
+				verb = constants.DefaultHTTPMethod
+			}
+			operation := managementserver.OperationFromDP{
+				Path:   path,
+				Verb:   verb,
+				Scopes: []string{},
+			}
+			if !operationExists(operations, operation) {
+				operations = append(operations, operation)
+			}
+		}
+	}
+	return operations
+}
+
+// operationExists checks if an operation is already in the list
+func operationExists(ops []managementserver.OperationFromDP, newOp managementserver.OperationFromDP) bool {
+	for _, op := range ops {
+		if op.Path == newOp.Path && op.Verb == newOp.Verb {
+			return true
+		}
+	}
+	return false
+}
+
+// extractBasePath finds the first path segment shared by all operation paths
+func extractBasePath(operations []managementserver.OperationFromDP) string {
+	if len(operations) == 0 {
+		return constants.DefaultBasePath
+	}
+	prefix := operations[0].Path
+	for _, op := range operations[1:] {
+		for !strings.HasPrefix(op.Path, prefix) {
+			prefix = prefix[:len(prefix)-1]
+			if prefix == "" {
+				return constants.DefaultBasePath
+			}
+		}
+	}
+	parts := strings.Split(prefix, "/")
+	if len(parts) > 1 && parts[1] != "" {
+		return "/" + parts[1]
+	}
+	return constants.DefaultBasePath
+}
+
+// extractEndpoint resolves the protocol and host:port of the first backend referred by the HTTPRoute
+func extractEndpoint(route *unstructured.Unstructured) (string, string) {
+	rules, found, _ := unstructured.NestedSlice(route.Object, specField, rulesField)
+	if !found {
+		return "", ""
+	}
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		backendRefs, found, _ := unstructured.NestedSlice(ruleMap, backendRefsField)
+		if !found || len(backendRefs) == 0 {
+			continue
+		}
+		backendRef, ok := backendRefs[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(backendRef, kindField)
+		name, _, _ := unstructured.NestedString(backendRef, nameField)
+		namespace, _, _ := unstructured.NestedString(backendRef, namespaceField)
+		if namespace == "" {
+			namespace = route.GetNamespace()
+		}
+		port, _, _ := unstructured.NestedInt64(backendRef, portField)
+		if kind == constants.BackendKind {
+			return resolveBackendEndpoint(namespace, name)
+		}
+		if kind == "" || kind == constants.ServiceKind {
+			protocol := httpProtocol
+			if port == httpsPort {
+				protocol = httpsProtocol
+			}
+			return protocol, formatHostPort(fmt.Sprintf(constants.ServiceDNSTemplate, name, namespace), protocol, port)
+		}
+	}
+	return "", ""
+}
+
+// resolveBackendEndpoint reads the first endpoint of an Envoy Gateway Backend CR
+func resolveBackendEndpoint(namespace, name string) (string, string) {
+	backend, err := CRWatcher.DynamicClient.Resource(constants.BackendGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Error fetching Backend %s/%s: %v", namespace, name, err)
+		return "", ""
+	}
+	return getBackendEndpoint(backend)
+}
+
+// getBackendEndpoint returns the protocol and host:port of the first FQDN or IP endpoint of a Backend
+func getBackendEndpoint(backend *unstructured.Unstructured) (string, string) {
+	endpoints, found, _ := unstructured.NestedSlice(backend.Object, specField, endpointsField)
+	if !found || len(endpoints) == 0 {
+		return "", ""
+	}
+	endpoint, ok := endpoints[0].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	host, _, _ := unstructured.NestedString(endpoint, fqdnField, hostnameField)
+	port, _, _ := unstructured.NestedInt64(endpoint, fqdnField, portField)
+	if host == "" {
+		host, _, _ = unstructured.NestedString(endpoint, ipField, addressField)
+		port, _, _ = unstructured.NestedInt64(endpoint, ipField, portField)
+	}
+	if host == "" {
+		return "", ""
+	}
+	protocol := httpProtocol
+	if _, hasTLS, _ := unstructured.NestedMap(backend.Object, specField, tlsField); hasTLS || port == httpsPort {
+		protocol = httpsProtocol
+	}
+	return protocol, formatHostPort(host, protocol, port)
+}
+
+// formatHostPort omits the port when it is the default port of the protocol
+func formatHostPort(host, protocol string, port int64) string {
+	if port <= 0 || (protocol == httpProtocol && port == httpPort) || (protocol == httpsProtocol && port == httpsPort) {
+		return host
+	}
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// hasRouteMetadataRef checks whether any rule of the HTTPRoute refers to a RouteMetadata
+func hasRouteMetadataRef(route *unstructured.Unstructured) bool {
+	rules, found, _ := unstructured.NestedSlice(route.Object, specField, rulesField)
+	if !found {
+		return false
+	}
+	for _, rule := range rules {
+		ruleMap, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filters, _, _ := unstructured.NestedSlice(ruleMap, filtersField)
+		for _, filter := range filters {
+			filterMap, ok := filter.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _, _ := unstructured.NestedString(filterMap, extensionRef, kindField); kind == constants.RouteMetadataKind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSandboxRoute checks whether the HTTPRoute serves the sandbox environment
+func isSandboxRoute(route *unstructured.Unstructured) bool {
+	return strings.EqualFold(route.GetAnnotations()[constants.EnvTypeAnnotation], constants.SandboxEnvType)
+}
+
+// shouldSkipResource checks if a resource should be skipped based on labels
+func shouldSkipResource(resource *unstructured.Unstructured) bool {
+	showInCP, hasShowInCP := resource.GetLabels()[constants.ShowInCPLabel]
+	return hasShowInCP && showInCP == constants.DefaultShowInCPFalse
+}
+
+// getOrGenerateEGAPIUUID gets the existing API UUID from the HTTPRoute or generates a new one
+func getOrGenerateEGAPIUUID(route *unstructured.Unstructured) string {
+	egAPIUUID, hasEGAPIUUID := route.GetLabels()[constants.EGAPIUUIDLabel]
+	if !hasEGAPIUUID {
+		egAPIUUID = uuid.New().String()
+		loggers.LoggerDiscovery.Infof("Generated egAPIUUID %s for HTTPRoute %s/%s", egAPIUUID, route.GetNamespace(), route.GetName())
+	}
+	return egAPIUUID
+}
+
+// computeAPIHash generates a hash of the fields of the API that are published to the control plane
+func computeAPIHash(api managementserver.API) string {
+	data, _ := json.Marshal([]interface{}{api.APIName, api.APIVersion, api.Organization, api.Vhost, api.SandVhost,
+		api.Operations, api.ProdEndpoint, api.SandEndpoint, api.EndpointProtocol, api.CORSPolicy, api.AuthHeader,
+		api.SecurityScheme, api.ProdAIRL, api.SandAIRL})
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// generateResourceHash hashes the spec, labels and annotations of a resource to detect meaningful changes
+func generateResourceHash(resource *unstructured.Unstructured) string {
+	if resource == nil {
+		return ""
+	}
+	labels := maps.Clone(resource.GetLabels())
+	// Labels written back by the discovery itself must not trigger another reconciliation
+	delete(labels, constants.RevisionIDLabel)
+	delete(labels, constants.APIUUIDLabel)
+	delete(labels, constants.EGAPIUUIDLabel)
+	delete(labels, constants.K8sInitiatedFromField)
+	data, err := json.Marshal([]interface{}{resource.Object[specField], labels, resource.GetAnnotations()})
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Failed to marshal resource data for hashing: %v", err)
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// generateRevisionID creates a unique revision ID
+func generateRevisionID() string {
+	return fmt.Sprintf("%d", time.Now().UnixNano())
+}
+
+func updateHTTPRouteLabels(route *unstructured.Unstructured, labelsToSet map[string]string) {
+	for attempt := 0; attempt < constants.MaxRetries; attempt++ {
+		latest, err := CRWatcher.DynamicClient.Resource(constants.HTTPRouteGVR).Namespace(route.GetNamespace()).Get(context.Background(), route.GetName(), metav1.GetOptions{})
+		if err != nil {
+			loggers.LoggerDiscovery.Errorf("Failed to fetch latest HTTPRoute %s/%s on attempt %d: %v", route.GetNamespace(), route.GetName(), attempt+1, err)
+			time.Sleep(time.Duration((attempt+1)*constants.RetryDelayMultiplier) * time.Millisecond)
+			continue
+		}
+
+		labels, _, _ := unstructured.NestedStringMap(latest.Object, metadataField, labelsField)
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		maps.Copy(labels, labelsToSet)
+		unstructured.SetNestedStringMap(latest.Object, labels, metadataField, labelsField)
+
+		_, err = CRWatcher.DynamicClient.Resource(constants.HTTPRouteGVR).Namespace(latest.GetNamespace()).Update(context.Background(), latest, metav1.UpdateOptions{})
+		if err == nil {
+			loggers.LoggerDiscovery.Infof("Successfully updated labels on HTTPRoute %s/%s", latest.GetNamespace(), latest.GetName())
+			return
+		}
+		if !strings.Contains(err.Error(), objectModifiedErr) {
+			loggers.LoggerDiscovery.Errorf("Failed to update HTTPRoute %s/%s (non-conflict error): %v", latest.GetNamespace(), latest.GetName(), err)
+			return
+		}
+		loggers.LoggerDiscovery.Warnf("HTTPRoute %s/%s was modified during update, retrying attempt %d: %v", latest.GetNamespace(), latest.GetName(), attempt+1, err)
+		time.Sleep(time.Duration((attempt+1)*constants.RetryDelayMultiplier) * time.Millisecond)
+	}
+	loggers.LoggerDiscovery.Errorf("Failed to update HTTPRoute %s/%s after %d retry attempts",
+		route.GetNamespace(), route.GetName(), constants.MaxRetries)
+}
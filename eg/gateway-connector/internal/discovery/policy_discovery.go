@@ -0,0 +1,250 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package discovery
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
+)
+
+// handlePolicyResource reconciles the APIs of every HTTPRoute targeted by the old or the new version of a
+// SecurityPolicy or BackendTrafficPolicy. Either of the versions is nil for additions and deletions.
+func handlePolicyResource(oldPolicy, policy *unstructured.Unstructured) {
+	if oldPolicy != nil && policy != nil && generateResourceHash(oldPolicy) == generateResourceHash(policy) {
+		loggers.LoggerDiscovery.Debugf("%s %s/%s hash unchanged - skipping reconciliation",
+			policy.GetKind(), policy.GetNamespace(), policy.GetName())
+		return
+	}
+
+	routeNames := make(map[string]string)
+	for _, p := range []*unstructured.Unstructured{oldPolicy, policy} {
+		if p == nil {
+			continue
+		}
+		loggers.LoggerDiscovery.Infof("Processing %s %s/%s (Generation: %d, ResourceVersion: %s)",
+			p.GetKind(), p.GetNamespace(), p.GetName(), p.GetGeneration(), p.GetResourceVersion())
+		for _, routeName := range getTargetHTTPRouteNames(p) {
+			routeNames[routeName] = p.GetNamespace()
+		}
+	}
+	for routeName, namespace := range routeNames {
+		ReconcileAPI(namespace, routeName)
+	}
+}
+
+// getTargetHTTPRouteNames returns the names of the HTTPRoutes the policy is attached to
+func getTargetHTTPRouteNames(policy *unstructured.Unstructured) []string {
+	var targetRefs []interface{}
+	if refs, found, _ := unstructured.NestedSlice(policy.Object, specField, targetRefsField); found {
+		targetRefs = append(targetRefs, refs...)
+	}
+	if ref, found, _ := unstructured.NestedMap(policy.Object, specField, targetRefField); found {
+		targetRefs = append(targetRefs, ref)
+	}
+
+	var routeNames []string
+	for _, ref := range targetRefs {
+		refMap, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(refMap, kindField)
+		name, _, _ := unstructured.NestedString(refMap, nameField)
+		if kind == constants.HTTPRouteKind && name != "" {
+			routeNames = append(routeNames, name)
+		}
+	}
+	sort.Strings(routeNames)
+	return routeNames
+}
+
+// isAPILevelPolicy checks whether the policy applies to the whole HTTPRoute rather than some of its rules
+func isAPILevelPolicy(policy *unstructured.Unstructured, routeName string) bool {
+	refs, _, _ := unstructured.NestedSlice(policy.Object, specField, targetRefsField)
+	if ref, found, _ := unstructured.NestedMap(policy.Object, specField, targetRefField); found {
+		refs = append(refs, ref)
+	}
+	for _, ref := range refs {
+		refMap, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(refMap, nameField)
+		sectionName, _, _ := unstructured.NestedString(refMap, sectionNameField)
+		if name == routeName && sectionName == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchPoliciesForHTTPRoute lists the policies of the given resource that target the HTTPRoute
+func fetchPoliciesForHTTPRoute(gvr schema.GroupVersionResource, route *unstructured.Unstructured) []*unstructured.Unstructured {
+	policyList, err := CRWatcher.DynamicClient.Resource(gvr).Namespace(route.GetNamespace()).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		loggers.LoggerDiscovery.Errorf("Failed to list %s in namespace %s: %v", gvr.Resource, route.GetNamespace(), err)
+		return nil
+	}
+
+	var policies []*unstructured.Unstructured
+	for i := range policyList.Items {
+		for _, routeName := range getTargetHTTPRouteNames(&policyList.Items[i]) {
+			if routeName == route.GetName() {
+				policies = append(policies, &policyList.Items[i])
+				break
+			}
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].GetName() < policies[j].GetName()
+	})
+	return policies
+}
+
+// applySecurityPolicies merges the CORS and JWT configuration of the SecurityPolicies attached to the HTTPRoute
+func applySecurityPolicies(api *managementserver.API, route *unstructured.Unstructured) {
+	for _, policy := range fetchPoliciesForHTTPRoute(constants.SecurityPolicyGVR, route) {
+		if cors := extractCORSPolicy(policy); cors != nil {
+			api.CORSPolicy = cors
+		}
+		if authHeader, found := extractAuthHeader(policy); found {
+			api.AuthHeader = authHeader
+			api.SecurityScheme = []string{constants.OAuth2SecurityScheme}
+		}
+	}
+}
+
+// applyBackendTrafficPolicies merges the API level rate limits of the BackendTrafficPolicies attached to the HTTPRoute
+func applyBackendTrafficPolicies(api *managementserver.API, route *unstructured.Unstructured, sandbox bool) {
+	for _, policy := range fetchPoliciesForHTTPRoute(constants.BackendTrafficPolicyGVR, route) {
+		if !isAPILevelPolicy(policy, route.GetName()) {
+			loggers.LoggerDiscovery.Debugf("Skipping rule level BackendTrafficPolicy %s/%s for API %s",
+				policy.GetNamespace(), policy.GetName(), api.APIUUID)
+			continue
+		}
+		rateLimit := extractRateLimit(policy)
+		if rateLimit == nil {
+			continue
+		}
+		if sandbox {
+			api.SandAIRL = rateLimit
+		} else {
+			api.ProdAIRL = rateLimit
+		}
+	}
+}
+
+// extractCORSPolicy pulls the CORS details from a SecurityPolicy
+func extractCORSPolicy(policy *unstructured.Unstructured) *managementserver.CORSPolicy {
+	cors, found, _ := unstructured.NestedMap(policy.Object, specField, corsField)
+	if !found {
+		return nil
+	}
+	loggers.LoggerDiscovery.Debugf("Extracting CORS policy from SecurityPolicy: %s/%s", policy.GetNamespace(), policy.GetName())
+
+	corsPolicy := &managementserver.CORSPolicy{
+		AccessControlAllowOrigins:  []string{},
+		AccessControlAllowMethods:  []string{},
+		AccessControlAllowHeaders:  []string{},
+		AccessControlExposeHeaders: []string{},
+	}
+	if origins, found, _ := unstructured.NestedStringSlice(cors, allowOriginsField); found {
+		corsPolicy.AccessControlAllowOrigins = origins
+	}
+	if methods, found, _ := unstructured.NestedStringSlice(cors, allowMethodsField); found {
+		corsPolicy.AccessControlAllowMethods = methods
+	}
+	if headers, found, _ := unstructured.NestedStringSlice(cors, allowHeadersField); found {
+		corsPolicy.AccessControlAllowHeaders = headers
+	}
+	if headers, found, _ := unstructured.NestedStringSlice(cors, exposeHeadersField); found {
+		corsPolicy.AccessControlExposeHeaders = headers
+	}
+	if credentials, found, _ := unstructured.NestedBool(cors, allowCredentialsField); found {
+		corsPolicy.AccessControlAllowCredentials = credentials
+	}
+	if maxAge, found, _ := unstructured.NestedString(cors, maxAgeField); found {
+		if duration, err := time.ParseDuration(maxAge); err == nil {
+			seconds := int(duration.Seconds())
+			corsPolicy.AccessControlMaxAge = &seconds
+		}
+	}
+	return corsPolicy
+}
+
+// extractAuthHeader returns the header the JWT is read from if the SecurityPolicy enables JWT authentication
+func extractAuthHeader(policy *unstructured.Unstructured) (string, bool) {
+	providers, found, _ := unstructured.NestedSlice(policy.Object, specField, jwtField, providersField)
+	if !found || len(providers) == 0 {
+		return "", false
+	}
+	loggers.LoggerDiscovery.Debugf("Extracting JWT policy from SecurityPolicy: %s/%s", policy.GetNamespace(), policy.GetName())
+
+	for _, provider := range providers {
+		providerMap, ok := provider.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		headers, found, _ := unstructured.NestedSlice(providerMap, extractFromField, headersField)
+		if !found || len(headers) == 0 {
+			continue
+		}
+		if headerMap, ok := headers[0].(map[string]interface{}); ok {
+			if name, _, _ := unstructured.NestedString(headerMap, nameField); name != "" {
+				return name, true
+			}
+		}
+	}
+	return constants.DefaultAuthHeader, true
+}
+
+// extractRateLimit pulls the first global or local rate limit rule from a BackendTrafficPolicy
+func extractRateLimit(policy *unstructured.Unstructured) *managementserver.AIRL {
+	for _, rateLimitType := range []string{globalField, localField} {
+		rules, found, _ := unstructured.NestedSlice(policy.Object, specField, rateLimitField, rateLimitType, rulesField)
+		if !found || len(rules) == 0 {
+			continue
+		}
+		ruleMap, ok := rules[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		requests, found, _ := unstructured.NestedInt64(ruleMap, limitField, requestsField)
+		if !found {
+			continue
+		}
+		unit, _, _ := unstructured.NestedString(ruleMap, limitField, unitField)
+		requestCount := uint32(requests)
+		loggers.LoggerDiscovery.Debugf("Extracted %s rate limit %d per %s from BackendTrafficPolicy %s/%s",
+			rateLimitType, requests, unit, policy.GetNamespace(), policy.GetName())
+		return &managementserver.AIRL{
+			RequestCount: &requestCount,
+			TimeUnit:     unit,
+		}
+	}
+	return nil
+}
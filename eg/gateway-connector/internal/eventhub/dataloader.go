@@ -18,6 +18,7 @@
 package eventhub
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -92,7 +93,7 @@ type resource struct {
 }
 
 // LoadInitialData loads subscription/application and keymapping data from control-plane
-func LoadInitialData(configFile *config.Config, client client.Client) {
+func LoadInitialData(configFile *config.Config, client client.Client) error {
 	conf = configFile
 	accessToken = pkgAuth.GetBasicAuth(configFile.ControlPlane.Username, configFile.ControlPlane.Password)
 	var responseChannel = make(chan response)
@@ -132,9 +133,12 @@ func LoadInitialData(configFile *config.Config, client client.Client) {
 	}
 	AgentMode := conf.Agent.Mode
 	if AgentMode == "CPtoDP" {
-		FetchAPIsOnStartUp(conf, client)
+		if err := FetchAPIsOnStartUp(conf, client); err != nil {
+			return err
+		}
 	}
 	go utils.SendInitialEventToAllConnectedClients()
+	return nil
 }
 
 // InvokeService invokes the internal data resource
@@ -230,28 +234,36 @@ func retrieveDataFromResponseChannel(response response) {
 	}
 }
 
+// fetchAPIs deploys the APIs of the control plane and returns their UUIDs
+var fetchAPIs = synchronizer.FetchAPIsOnEvent
+
 // FetchAPIsOnStartUp APIs from control plane during the server start up and push them
 // to the router and enforcer components.
-func FetchAPIsOnStartUp(conf *config.Config, k8sClient client.Client) {
+func FetchAPIsOnStartUp(conf *config.Config, k8sClient client.Client) error {
 	k8sRouteMetas, _, err := internalk8sClient.RetrieveAllRouteMetasFromK8s(k8sClient, "")
 	if err != nil {
 		logger.LoggerEventhub.Errorf("Error occurred while fetching RouteMetadata from K8s %v", err)
 	}
-	apis, err := synchronizer.FetchAPIsOnEvent(conf, nil, k8sClient)
+	apis, err := fetchAPIs(context.Background(), conf, nil, k8sClient)
+	if err == nil && apis == nil {
+		err = errors.New("no API list is received from the control plane")
+	}
 	if err != nil {
-		logger.LoggerEventhub.Errorf("Error occurred while fetching APIs from control plane %v", err)
+		// The deployed APIs are kept as they are, as the APIs removed from the control plane are unknown. This holds
+		// even when the stores are restored from the snapshot, as the snapshot does not record the deployed APIs.
+		logger.LoggerEventhub.Errorf("Error occurred while fetching APIs from control plane, skipping the removal of "+
+			"the undeployed APIs: %v", err)
+		return err
 	}
 	removeRouteMetas := make([]dpv2alpha1.RouteMetadata, 0)
 	for _, k8sRouteMeta := range k8sRouteMetas {
 		found := false
-		if apis != nil {
-			for _, api := range *apis {
-				apiUUID, exist := k8sRouteMeta.ObjectMeta.Labels["apiUUID"]
-				if exist {
-					if apiUUID == api {
-						found = true
-						break
-					}
+		for _, api := range *apis {
+			apiUUID, exist := k8sRouteMeta.ObjectMeta.Labels["apiUUID"]
+			if exist {
+				if apiUUID == api {
+					found = true
+					break
 				}
 			}
 		}
@@ -267,4 +279,5 @@ func FetchAPIsOnStartUp(conf *config.Config, k8sClient client.Client) {
 			internalk8sClient.UndeployK8sRouteMetadataCRs(k8sClient, removeRouteMeta)
 		}
 	}
+	return nil
 }
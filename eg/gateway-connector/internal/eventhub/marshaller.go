@@ -88,6 +88,7 @@ func MarshalApplication(appInternal *Application) managementserver.Application {
 		Owner:        appInternal.SubName,
 		Organization: appInternal.Organization,
 		Attributes:   appInternal.Attributes,
+		Policy:       appInternal.Policy,
 		TimeStamp:    appInternal.TimeStamp,
 	}
 	return app
@@ -19,24 +19,26 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
-	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/eventhub"
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/k8sClient"
 	k8sclient "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/synchronizer"
 	internalutils "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/utils"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/transformer"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	eventConstants "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
 	mgtServer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/utils"
 	"github.com/wso2/apk/common-go-libs/constants"
 	event "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
@@ -46,57 +48,65 @@ import (
 // var variables
 var (
 	ScopeList = make([]types.Scope, 0)
-	// timestamps needs to be maintained as it is not guranteed to receive them in order,
-	// hence older events should be discarded
-	apiListTimeStampMap          = make(map[string]int64, 0)
-	subsriptionsListTimeStampMap = make(map[string]int64, 0)
-	applicationListTimeStampMap  = make(map[string]int64, 0)
 )
 
-// HandleLifeCycleEvents handles the events of an api through out the life cycle
-func HandleLifeCycleEvents(data []byte) {
+// lifeCycleSequenceKeySuffix separates the life cycle events of an API from its deployment events when sequencing
+const lifeCycleSequenceKeySuffix = "lifecycle"
+
+// HandleLifeCycleEvents handles the events of an api through out the life cycle. A blocked API responds with 503,
+// a deprecated API advertises the deprecation in the response headers and a retired API is undeployed.
+func HandleLifeCycleEvents(data []byte, c client.Client) (err error) {
 	var apiEvent msg.APIEvent
 	apiLCEventErr := json.Unmarshal([]byte(string(data)), &apiEvent)
 	if apiLCEventErr != nil {
 		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Lifecycle event data %v", apiLCEventErr)
-		return
+		return nil
 	}
 	if !belongsToTenant(apiEvent.TenantDomain) {
 		logger.LoggerMessaging.Debugf("API Lifecycle event for the API %s:%s is dropped due to having non related tenantDomain : %s",
 			apiEvent.APIName, apiEvent.APIVersion, apiEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	apiEventObj := types.API{UUID: apiEvent.UUID, APIID: apiEvent.APIID, Name: apiEvent.APIName,
-		Context: apiEvent.APIContext, Version: apiEvent.APIVersion, Provider: apiEvent.APIProvider}
+		Context: apiEvent.APIContext, Version: apiEvent.APIVersion, Provider: apiEvent.APIProvider, APIStatus: apiEvent.APIStatus}
 
 	logger.LoggerMessaging.Infof("API event data %+v", apiEventObj)
 
-	conf, _ := config.ReadConfigs()
-	configuredEnvs := conf.ControlPlane.EnvironmentLabels
-	logger.LoggerMessaging.Debugf("%s : %s API life cycle state change event triggered", apiEvent.APIName, apiEvent.APIVersion)
-	if len(configuredEnvs) == 0 {
-		configuredEnvs = append(configuredEnvs, config.DefaultGatewayName)
+	if !sequencer.AcceptEvent(sequencer.API, apiEvent.UUID+":"+lifeCycleSequenceKeySuffix, apiEvent.Event) {
+		logger.LoggerMessaging.Infof("Discarding the life cycle state change of the API %s to %s as a later or the "+
+			"same event was already processed", apiEvent.UUID, apiEvent.APIStatus)
+		return nil
+	}
+	defer sequencer.ForgetOnFailure(sequencer.API, &err, apiEvent.UUID+":"+lifeCycleSequenceKeySuffix)
+	logger.LoggerMessaging.Debugf("%s : %s API life cycle state changed to %s", apiEvent.APIName, apiEvent.APIVersion, apiEvent.APIStatus)
+	if strings.EqualFold(apiEvent.APIStatus, transformer.RetiredLifeCycleState) {
+		internalk8sClient.UndeployRouteMetadataCRs(apiEvent.UUID, c)
+		return nil
+	}
+	lifeCycle := transformer.NewLifeCycle(apiEvent.APIStatus, apiEvent.TimeStamp)
+	if err := internalk8sClient.UpdateAPILifeCycleState(apiEvent.UUID, lifeCycle, c); err != nil {
+		return fmt.Errorf("error applying the life cycle state %s to the API %s: %w", apiEvent.APIStatus,
+			apiEvent.UUID, err)
 	}
+	return nil
 }
 
 // HandleAPIEvents to process api related data
 // !!!TODO: Need to change this becuase now we use RouteMetadata CRs instead of API CRs
-func HandleAPIEvents(data []byte, eventType string, conf *config.Config, c client.Client) {
-	var (
-		apiEvent         msg.APIEvent
-		currentTimeStamp int64 = apiEvent.Event.TimeStamp
-	)
+func HandleAPIEvents(ctx context.Context, data []byte, eventType string, conf *config.Config, c client.Client) (err error) {
+	var apiEvent msg.APIEvent
 
 	apiEventErr := json.Unmarshal([]byte(string(data)), &apiEvent)
 	if apiEventErr != nil {
-		logger.LoggerMessaging.ErrorC(logging.ErrorDetails{
+		logger.LoggerMessaging.ErrorCContext(ctx, logging.ErrorDetails{
 			Message:   fmt.Sprintf("Error occurred while unmarshalling API event data %v", apiEventErr),
 			Severity:  logging.MAJOR,
 			ErrorCode: 2004,
 		})
-		return
+		return nil
 	}
+	ctx = logging.ContextWithFields(ctx, logging.Fields{logging.APIUUID: apiEvent.UUID})
 
 	if !belongsToTenant(apiEvent.TenantDomain) {
 		apiName := apiEvent.APIName
@@ -107,25 +117,33 @@ func HandleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 		if apiEvent.Version == "" {
 			apiVersion = apiEvent.Version
 		}
-		logger.LoggerMessaging.Debugf("API event for the API %s:%s is dropped due to having non related tenantDomain : %s",
+		logger.LoggerMessaging.FromContext(ctx).Debugf("API event for the API %s:%s is dropped due to having non related tenantDomain : %s",
 			apiName, apiVersion, apiEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	apiEventObj := types.API{UUID: apiEvent.UUID, APIID: apiEvent.APIID, Name: apiEvent.APIName,
 		Context: apiEvent.APIContext, Version: apiEvent.APIVersion, Provider: apiEvent.APIProvider}
 
-	logger.LoggerMessaging.Infof("API event data %+v", apiEventObj)
+	logger.LoggerMessaging.FromContext(ctx).Infof("API event data %+v", apiEventObj)
+
+	// The events are not guaranteed to be received in order, hence the older and redelivered events are discarded
+	acceptedKeys := sequencer.AcceptAPIEvent(apiEvent)
+	if len(acceptedKeys) == 0 {
+		logger.LoggerMessaging.FromContext(ctx).Infof("Discarding the %s event of the API as a later or the same "+
+			"event was already processed", apiEvent.Event.Type)
+		return nil
+	}
+	defer sequencer.ForgetOnFailure(sequencer.API, &err, acceptedKeys...)
 
 	//Per each revision, synchronization should happen.
 	if strings.EqualFold(eventConstants.DeployAPIToGateway, apiEvent.Event.Type) {
-		go synchronizer.FetchAPIsOnEvent(conf, &apiEvent.UUID, c)
+		if _, err := synchronizer.FetchAPIsOnEvent(ctx, conf, &apiEvent.UUID, c); err != nil {
+			return fmt.Errorf("error deploying the API %s: %w", apiEvent.UUID, err)
+		}
 	}
 
-	for _, env := range apiEvent.GatewayLabels {
-		if isLaterEvent(apiListTimeStampMap, apiEvent.UUID+":"+env, currentTimeStamp) {
-			break
-		}
+	for range apiEvent.GatewayLabels {
 		// removeFromGateway event with multiple labels could only appear when the API is subjected
 		// to delete. Hence we could simply delete after checking against just one iteration.
 		if strings.EqualFold(eventConstants.RemoveAPIFromGateway, apiEvent.Event.Type) {
@@ -155,26 +173,27 @@ func HandleAPIEvents(data []byte, eventType string, conf *config.Config, c clien
 			// 	}
 		}
 	}
+	return nil
 }
 
 // HandleApplicationEvents to process application related events
-func HandleApplicationEvents(data []byte, eventType string, c client.Client) {
+func HandleApplicationEvents(ctx context.Context, data []byte, eventType string, c client.Client) (err error) {
 	if strings.EqualFold(eventConstants.ApplicationRegistration, eventType) ||
 		strings.EqualFold(eventConstants.RemoveApplicationKeyMapping, eventType) {
 		var applicationRegistrationEvent msg.ApplicationRegistrationEvent
 		appRegEventErr := json.Unmarshal([]byte(string(data)), &applicationRegistrationEvent)
 		if appRegEventErr != nil {
-			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Application Registration event data %v", appRegEventErr)
-			return
+			logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while unmarshalling Application Registration event data %v", appRegEventErr)
+			return nil
 		}
 
 		if !belongsToTenant(applicationRegistrationEvent.TenantDomain) {
-			logger.LoggerMessaging.Debugf("Application Registration event for the Consumer Key : %s is dropped due to having non related tenantDomain : %s",
+			logger.LoggerMessaging.FromContext(ctx).Debugf("Application Registration event for the Consumer Key : %s is dropped due to having non related tenantDomain : %s",
 				applicationRegistrationEvent.ConsumerKey, applicationRegistrationEvent.TenantDomain)
-			return
+			return nil
 		}
 		envID := internalutils.GetEnvLabel()
-		logger.LoggerMessaging.Infof("EnvID from handler.go: %s", envID)
+		logger.LoggerMessaging.FromContext(ctx).Infof("EnvID from handler.go: %s", envID)
 		applicationKeyMappingEvent := event.ApplicationKeyMapping{ApplicationUUID: applicationRegistrationEvent.ApplicationUUID,
 			SecurityScheme:        "OAuth2",
 			ApplicationIdentifier: applicationRegistrationEvent.ConsumerKey,
@@ -197,29 +216,36 @@ func HandleApplicationEvents(data []byte, eventType string, c client.Client) {
 				ApplicationKeyMapping: &applicationKeyMappingEvent,
 			}
 			uuid := utils.GetUniqueIDOfApplicationKeyMapping(applicationKeyMappingEvent.ApplicationUUID, applicationKeyMappingEvent.KeyType, applicationKeyMappingEvent.SecurityScheme, applicationKeyMappingEvent.EnvID, applicationKeyMappingEvent.Organization)
-			logger.LoggerMessaging.Infof("Application Key Mapping event data %v", uuid)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Application Key Mapping event data %v", uuid)
 			managementserver.DeleteApplicationKeyMapping(uuid)
 			go utils.SendEvent(&event)
 		}
+		if err := synchronizer.SyncApplicationRateLimitRules(applicationRegistrationEvent.TenantDomain, c); err != nil {
+			return fmt.Errorf("error updating the application rate limit rules of organization %s: %w",
+				applicationRegistrationEvent.TenantDomain, err)
+		}
 	} else {
 		var applicationEvent msg.ApplicationEvent
 		appEventErr := json.Unmarshal([]byte(string(data)), &applicationEvent)
 		if appEventErr != nil {
-			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Application event data %v", appEventErr)
-			return
+			logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while unmarshalling Application event data %v", appEventErr)
+			return nil
 		}
 
 		if !belongsToTenant(applicationEvent.TenantDomain) {
-			logger.LoggerMessaging.Debugf("Application event for the Application : %s (with uuid %s) is dropped due to having non related tenantDomain : %s",
+			logger.LoggerMessaging.FromContext(ctx).Debugf("Application event for the Application : %s (with uuid %s) is dropped due to having non related tenantDomain : %s",
 				applicationEvent.ApplicationName, applicationEvent.UUID, applicationEvent.TenantDomain)
-			return
+			return nil
 		}
 
-		logger.LoggerMessaging.Infof("Application event data %v", applicationEvent)
+		logger.LoggerMessaging.FromContext(ctx).Infof("Application event data %v", applicationEvent)
 
-		if isLaterEvent(applicationListTimeStampMap, fmt.Sprint(applicationEvent.ApplicationID), applicationEvent.TimeStamp) {
-			return
+		if !sequencer.AcceptEvent(sequencer.Application, fmt.Sprint(applicationEvent.ApplicationID), applicationEvent.Event) {
+			logger.LoggerMessaging.FromContext(ctx).Infof("Discarding the %s event of the application %s as a later or the same event "+
+				"was already processed", applicationEvent.Event.Type, applicationEvent.UUID)
+			return nil
 		}
+		defer sequencer.ForgetOnFailure(sequencer.Application, &err, fmt.Sprint(applicationEvent.ApplicationID))
 
 		applicationGrpcEvent := event.Application{Uuid: applicationEvent.UUID,
 			Name:         applicationEvent.ApplicationName,
@@ -229,41 +255,49 @@ func HandleApplicationEvents(data []byte, eventType string, c client.Client) {
 		}
 		if applicationEvent.Event.Type == eventConstants.ApplicationCreate {
 			event := event.Event{Type: constants.ApplicationCreated, Uuid: uuid.New().String(), TimeStamp: applicationEvent.TimeStamp, Application: &applicationGrpcEvent}
-			managementserver.AddApplication(managementserver.Application{UUID: applicationGrpcEvent.Uuid, Name: applicationGrpcEvent.Name, Owner: applicationGrpcEvent.Owner, Organization: applicationGrpcEvent.Organization, Attributes: applicationGrpcEvent.Attributes})
+			managementserver.AddApplication(managementserver.Application{UUID: applicationGrpcEvent.Uuid, Name: applicationGrpcEvent.Name, Owner: applicationGrpcEvent.Owner, Organization: applicationGrpcEvent.Organization, Attributes: applicationGrpcEvent.Attributes, Policy: applicationEvent.ApplicationPolicy})
 			utils.SendEvent(&event)
 		} else if applicationEvent.Event.Type == eventConstants.ApplicationUpdate {
 			event := event.Event{Type: constants.ApplicationUpdated, Uuid: uuid.New().String(), TimeStamp: applicationEvent.TimeStamp, Application: &applicationGrpcEvent}
-			managementserver.UpdateApplication(applicationGrpcEvent.Uuid, managementserver.Application{UUID: applicationGrpcEvent.Uuid, Name: applicationGrpcEvent.Name, Owner: applicationGrpcEvent.Owner, Organization: applicationGrpcEvent.Organization, Attributes: applicationGrpcEvent.Attributes})
+			managementserver.UpdateApplication(applicationGrpcEvent.Uuid, managementserver.Application{UUID: applicationGrpcEvent.Uuid, Name: applicationGrpcEvent.Name, Owner: applicationGrpcEvent.Owner, Organization: applicationGrpcEvent.Organization, Attributes: applicationGrpcEvent.Attributes, Policy: applicationEvent.ApplicationPolicy})
 			utils.SendEvent(&event)
 		} else if applicationEvent.Event.Type == eventConstants.ApplicationDelete {
 			event := event.Event{Type: constants.ApplicationDeleted, Uuid: uuid.New().String(), TimeStamp: applicationEvent.TimeStamp, Application: &applicationGrpcEvent}
 			managementserver.DeleteApplication(applicationGrpcEvent.Uuid)
 			utils.SendEvent(&event)
 		} else {
-			logger.LoggerMessaging.Warnf("Application Event Type is not recognized for the Event under "+
+			logger.LoggerMessaging.FromContext(ctx).Warnf("Application Event Type is not recognized for the Event under "+
 				"Application UUID %s", applicationEvent.UUID)
-			return
+			return nil
+		}
+		if err := synchronizer.SyncApplicationRateLimitRules(applicationEvent.TenantDomain, c); err != nil {
+			return fmt.Errorf("error updating the application rate limit rules of organization %s: %w",
+				applicationEvent.TenantDomain, err)
 		}
 	}
+	return nil
 }
 
 // HandleSubscriptionEvents to process subscription related events
-func HandleSubscriptionEvents(data []byte, eventType string, c client.Client) {
+func HandleSubscriptionEvents(ctx context.Context, data []byte, eventType string, c client.Client) (err error) {
 	var subscriptionEvent msg.SubscriptionEvent
 	subEventErr := json.Unmarshal([]byte(string(data)), &subscriptionEvent)
 	if subEventErr != nil {
-		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Subscription event data %v", subEventErr)
-		return
+		logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while unmarshalling Subscription event data %v", subEventErr)
+		return nil
 	}
 	if !belongsToTenant(subscriptionEvent.TenantDomain) {
-		logger.LoggerMessaging.Debugf("Subscription event for the Application : %s and API %s is dropped due to having non related tenantDomain : %s",
+		logger.LoggerMessaging.FromContext(ctx).Debugf("Subscription event for the Application : %s and API %s is dropped due to having non related tenantDomain : %s",
 			subscriptionEvent.ApplicationUUID, subscriptionEvent.APIUUID, subscriptionEvent.TenantDomain)
-		return
+		return nil
 	}
 
-	if isLaterEvent(subsriptionsListTimeStampMap, fmt.Sprint(subscriptionEvent.SubscriptionID), subscriptionEvent.TimeStamp) {
-		return
+	if !sequencer.AcceptEvent(sequencer.Subscription, fmt.Sprint(subscriptionEvent.SubscriptionID), subscriptionEvent.Event) {
+		logger.LoggerMessaging.FromContext(ctx).Infof("Discarding the %s event of the subscription %s as a later or the same event "+
+			"was already processed", subscriptionEvent.Event.Type, subscriptionEvent.SubscriptionUUID)
+		return nil
 	}
+	defer sequencer.ForgetOnFailure(sequencer.Subscription, &err, fmt.Sprint(subscriptionEvent.SubscriptionID))
 
 	subscription := event.Subscription{Uuid: subscriptionEvent.SubscriptionUUID,
 		SubStatus:     subscriptionEvent.SubscriptionState,
@@ -295,87 +329,83 @@ func HandleSubscriptionEvents(data []byte, eventType string, c client.Client) {
 		managementserver.DeleteApplicationMapping(applicationMappingEvent.Uuid)
 		go utils.SendEvent(&applicationMappingEvent)
 	}
+	return nil
 }
 
 // HandlePolicyEvents to process policy related events
-func HandlePolicyEvents(data []byte, eventType string, c client.Client) {
+func HandlePolicyEvents(ctx context.Context, data []byte, eventType string, c client.Client) (err error) {
 	var policyEvent msg.PolicyInfo
 	policyEventErr := json.Unmarshal([]byte(string(data)), &policyEvent)
 	if policyEventErr != nil {
-		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Throttling Policy event data %v", policyEventErr)
-		return
+		logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while unmarshalling Throttling Policy event data %v", policyEventErr)
+		return nil
 	}
+	if !sequencer.AcceptEvent(sequencer.Policy, sequencer.PolicyKey(policyEvent), policyEvent.Event) {
+		logger.LoggerMessaging.FromContext(ctx).Infof("Discarding the %s event of the policy %s as a later or the same event was "+
+			"already processed", eventType, policyEvent.PolicyName)
+		return nil
+	}
+	defer sequencer.ForgetOnFailure(sequencer.Policy, &err, sequencer.PolicyKey(policyEvent))
 	// TODO: Handle policy events
 	// !!! Subscription -> Resource Level
 	// !!! API -> API Level
 	if strings.EqualFold(eventType, eventConstants.PolicyCreate) {
 		if strings.EqualFold(policyEvent.PolicyType, "API") {
-			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s for tenant: %s", policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s for tenant: %s", policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
 			synchronizer.FetchRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c)
 			ratelimitPolicies := mgtServer.GetAllRateLimitPolicies()
-			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
 		} else if strings.EqualFold(policyEvent.PolicyType, "SUBSCRIPTION") {
-			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
 			synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c, false)
 			ratelimitPolicies := mgtServer.GetAllRateLimitPolicies()
-			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
 		}
 	} else if strings.EqualFold(eventType, eventConstants.PolicyUpdate) {
 		if strings.EqualFold(policyEvent.PolicyType, "API") {
-			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s for tenant: %s", policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s for tenant: %s", policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
 			synchronizer.FetchRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c)
 			ratelimitPolicies := mgtServer.GetAllRateLimitPolicies()
-			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
 		} else if strings.EqualFold(policyEvent.PolicyType, "SUBSCRIPTION") {
-			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
 			synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c, false)
 			ratelimitPolicies := mgtServer.GetAllRateLimitPolicies()
-			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
 		}
 	} else if strings.EqualFold(eventType, eventConstants.PolicyDelete) {
 		if strings.EqualFold(policyEvent.PolicyType, "API") {
-			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
 			mgtServer.DeleteRateLimitPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
 			ratelimitPolicies := mgtServer.GetAllRateLimitPolicies()
-			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
 		} else if strings.EqualFold(policyEvent.PolicyType, "SUBSCRIPTION") {
-			logger.LoggerMessaging.Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
-			mgtServer.DeleteSubscriptionPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
-			crName := k8sclient.PrepareSubscritionPolicyCRName(policyEvent.PolicyName, policyEvent.TenantDomain)
-			// !!!TODO: NEED TO ADD THE LOGIC
-			logger.LoggerMessaging.Debugf("Deleting Subscription Rate Limit Policy: %s", crName)
-			// k8sclient.UnDeploySubscriptionRateLimitPolicyCR(crName, c)
-			// k8sclient.UndeploySubscriptionAIRateLimitPolicyCR(crName, c)
-			ratelimitPolicies := mgtServer.GetAllRateLimitPolicies()
-			logger.LoggerMessaging.Infof("Rate Limit Policies Internal Map: %v", ratelimitPolicies)
+			logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s", policyEvent.PolicyName, policyEvent.PolicyType)
+			report := deleteSubscriptionPolicy(policyEvent.PolicyName, policyEvent.TenantDomain, c)
+			if len(report.Failures) > 0 {
+				return fmt.Errorf("subscription policy deletion is incomplete: %s", report)
+			}
+			logger.LoggerMessaging.FromContext(ctx).Infof("Subscription policy deleted: %s", report)
 		}
 	}
 
 	if strings.EqualFold(eventConstants.ApplicationEventType, policyEvent.PolicyType) {
-		applicationPolicy := eventhub.ApplicationPolicy{ID: policyEvent.PolicyID, TenantID: policyEvent.Event.TenantID,
-			Name: policyEvent.PolicyName, QuotaType: policyEvent.QuotaType}
-
-		logger.LoggerMessaging.Infof("ApplicationPolicy event data %v", applicationPolicy)
-		// var applicationPolicyList *subscription.ApplicationPolicyList
-		// if policyEvent.Event.Type == policyCreate {
-		// 	applicationPolicyList = xds.MarshalApplicationPolicyEventAndReturnList(&applicationPolicy, xds.CreateEvent)
-		// } else if policyEvent.Event.Type == policyUpdate {
-		// 	applicationPolicyList = xds.MarshalApplicationPolicyEventAndReturnList(&applicationPolicy, xds.UpdateEvent)
-		// } else if policyEvent.Event.Type == policyDelete {
-		// 	applicationPolicyList = xds.MarshalApplicationPolicyEventAndReturnList(&applicationPolicy, xds.DeleteEvent)
-		// } else {
-		// 	logger.LoggerInternalMsg.Warnf("ApplicationPolicy Event Type is not recognized for the Event under "+
-		// 		" policy name %s", policyEvent.PolicyName)
-		// 	return
-		// }
-		// xds.UpdateEnforcerApplicationPolicies(applicationPolicyList)
-
+		logger.LoggerMessaging.FromContext(ctx).Infof("Policy: %s for policy type: %s for tenant: %s", policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
+		if strings.EqualFold(eventType, eventConstants.PolicyCreate) || strings.EqualFold(eventType, eventConstants.PolicyUpdate) {
+			synchronizer.FetchApplicationRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c)
+		} else if strings.EqualFold(eventType, eventConstants.PolicyDelete) {
+			mgtServer.DeleteApplicationPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
+			if err := synchronizer.SyncApplicationRateLimitRules(policyEvent.TenantDomain, c); err != nil {
+				return fmt.Errorf("error removing the application policy %s of organization %s: %w",
+					policyEvent.PolicyName, policyEvent.TenantDomain, err)
+			}
+		}
 	} else if strings.EqualFold(eventConstants.SubscriptionEventType, policyEvent.PolicyType) {
 		var subscriptionPolicyEvent msg.SubscriptionPolicyEvent
 		subPolicyErr := json.Unmarshal([]byte(string(data)), &subscriptionPolicyEvent)
 		if subPolicyErr != nil {
-			logger.LoggerMessaging.Errorf("Error occurred while unmarshalling Subscription Policy event data %v", subPolicyErr)
-			return
+			logger.LoggerMessaging.FromContext(ctx).Errorf("Error occurred while unmarshalling Subscription Policy event data %v", subPolicyErr)
+			return nil
 		}
 
 		// subscriptionPolicy := types.SubscriptionPolicy{ID: subscriptionPolicyEvent.PolicyID, TenantID: -1,
@@ -385,7 +415,7 @@ func HandlePolicyEvents(data []byte, eventType string, c client.Client) {
 		// 	RateLimitTimeUnit: subscriptionPolicyEvent.RateLimitTimeUnit, StopOnQuotaReach: subscriptionPolicyEvent.StopOnQuotaReach,
 		// 	TenantDomain: subscriptionPolicyEvent.TenantDomain, TimeStamp: subscriptionPolicyEvent.TimeStamp}
 
-		// logger.LoggerMessaging.Debugf("SubscriptionPolicy event data %v", subscriptionPolicy)
+		// logger.LoggerMessaging.FromContext(ctx).Debugf("SubscriptionPolicy event data %v", subscriptionPolicy)
 
 		// var subscriptionPolicyList *subscription.SubscriptionPolicyList
 		// if subscriptionPolicyEvent.Event.Type == policyCreate {
@@ -395,22 +425,23 @@ func HandlePolicyEvents(data []byte, eventType string, c client.Client) {
 		// } else if subscriptionPolicyEvent.Event.Type == policyDelete {
 		// 	subscriptionPolicyList = xds.MarshalSubscriptionPolicyEventAndReturnList(&subscriptionPolicy, xds.DeleteEvent)
 		// } else {
-		// 	logger.LoggerInternalMsg.Warnf("SubscriptionPolicy Event Type is not recognized for the Event under "+
+		// 	logger.LoggerInternalMsg.FromContext(ctx).Warnf("SubscriptionPolicy Event Type is not recognized for the Event under "+
 		// 		" policy name %s", policyEvent.PolicyName)
 		// 	return
 		// }
 		// xds.UpdateEnforcerSubscriptionPolicies(subscriptionPolicyList)
 	}
+	return nil
 }
 
 // HandleAIProviderEvents to process AI Provider related events
 // !!!TODO: Need to change this becuase now we use RoutePolicy instead of AIProvider CR
-func HandleAIProviderEvents(data []byte, eventType string, c client.Client) {
+func HandleAIProviderEvents(data []byte, eventType string, c client.Client) error {
 	var aiProviderEvent msg.AIProviderEvent
 	aiProviderEventErr := json.Unmarshal([]byte(string(data)), &aiProviderEvent)
 	if aiProviderEventErr != nil {
 		logger.LoggerMessaging.Errorf("Error occurred while unmarshalling AI Provider event data %v", aiProviderEventErr)
-		return
+		return nil
 	}
 
 	if strings.EqualFold(eventConstants.AIProviderCreate, eventType) { // Creating AI Provider CR
@@ -432,22 +463,17 @@ func HandleAIProviderEvents(data []byte, eventType string, c client.Client) {
 		aiProviders := mgtServer.GetAllAIProviders()
 		logger.LoggerMessaging.Infof("AI Providers Internal Map: %v", aiProviders)
 	}
+	return nil
 }
 
+// belongsToTenant checks if the tenant domain belongs to an organization served by this agent
 func belongsToTenant(tenantDomain string) bool {
-	// TODO : enable this once the events are fixed in apim
-	// return config.GetControlPlaneConnectedTenantDomain() == tenantDomain
-	return true
-}
-
-func isLaterEvent(timeStampMap map[string]int64, mapKey string, currentTimeStamp int64) bool {
-	if timeStamp, ok := timeStampMap[mapKey]; ok {
-		if timeStamp > currentTimeStamp {
-			return true
-		}
+	conf, err := config.ReadConfigs()
+	if err != nil {
+		logger.LoggerMessaging.Errorf("Error while reading the configs, checking the organization %s against the default configs: %v",
+			tenantDomain, err)
 	}
-	timeStampMap[mapKey] = currentTimeStamp
-	return false
+	return conf.DataPlane.ServesOrganization(tenantDomain)
 }
 
 func marshalAppAttributes(attributes interface{}) map[string]string {
@@ -0,0 +1,154 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	mgtServer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/utils"
+	k8sclient "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/k8sClient"
+	logger "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
+	internalutils "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/utils"
+	"github.com/wso2/apk/common-go-libs/constants"
+	event "github.com/wso2/apk/common-go-libs/pkg/discovery/api/wso2/discovery/subscription"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// subscriptionFallbackPolicyConfig is the gatewayAgent configuration of the policy the subscriptions of a deleted
+	// subscription policy are moved to
+	subscriptionFallbackPolicyConfig  = "subscriptionFallbackPolicy"
+	defaultSubscriptionFallbackPolicy = "Unlimited"
+	aiAPIQuotaType                    = "aiApiQuota"
+)
+
+// subscriptionPolicyDeletionReport records the changes made to the gateway when a subscription policy is deleted
+type subscriptionPolicyDeletionReport struct {
+	PolicyName     string
+	Organization   string
+	FallbackPolicy string
+	// RemovedCRs are the BackendTrafficPolicy CRs and shared rules removed for the policy
+	RemovedCRs []string
+	// ReboundSubscriptions are the UUIDs of the subscriptions moved to the fallback policy
+	ReboundSubscriptions []string
+	Failures             []string
+}
+
+// String summarises the changes made for the deleted subscription policy
+func (r *subscriptionPolicyDeletionReport) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "subscription policy %s of organization %s: removed %d CRs %v; moved %d subscriptions %v to %s",
+		r.PolicyName, r.Organization, len(r.RemovedCRs), r.RemovedCRs, len(r.ReboundSubscriptions),
+		r.ReboundSubscriptions, r.FallbackPolicy)
+	if len(r.Failures) > 0 {
+		fmt.Fprintf(&builder, "; failed %d %v", len(r.Failures), r.Failures)
+	}
+	return builder.String()
+}
+
+// deleteSubscriptionPolicy removes a deleted subscription policy from the internal map and the gateway, and moves
+// the subscriptions throttled by it to the fallback policy, so that the deleted policy no longer throttles traffic
+func deleteSubscriptionPolicy(policyName string, tenantDomain string, c client.Client) *subscriptionPolicyDeletionReport {
+	report := &subscriptionPolicyDeletionReport{
+		PolicyName:     policyName,
+		Organization:   tenantDomain,
+		FallbackPolicy: getSubscriptionFallbackPolicy(),
+	}
+	aiPolicy := strings.EqualFold(mgtServer.GetSubscriptionPolicy(policyName, tenantDomain).QuotaType, aiAPIQuotaType)
+	mgtServer.DeleteSubscriptionPolicy(policyName, tenantDomain)
+
+	sharedPolicyName := internalutils.CreateSubscriptionPolicyName(k8sclient.SharedRateLimitPolicyName, tenantDomain)
+	if removed, err := k8sclient.UnDeploySharedSubscriptionRateLimitPolicyCR(policyName, tenantDomain, c, aiPolicy); err != nil {
+		report.Failures = append(report.Failures, sharedPolicyName)
+	} else if removed {
+		report.RemovedCRs = append(report.RemovedCRs, sharedPolicyName)
+	}
+	crName := internalutils.CreateSubscriptionPolicyName(policyName, tenantDomain)
+	if removed, err := k8sclient.UnDeploySubscriptionRateLimitPolicyCR(crName, c); err != nil {
+		report.Failures = append(report.Failures, crName)
+	} else if removed {
+		report.RemovedCRs = append(report.RemovedCRs, crName)
+	}
+	aiCRName := k8sclient.PrepareSubscritionPolicyCRName(policyName, tenantDomain)
+	if removed, err := k8sclient.UndeploySubscriptionAIRateLimitPolicyCR(aiCRName, c); err != nil {
+		report.Failures = append(report.Failures, aiCRName)
+	} else if removed {
+		report.RemovedCRs = append(report.RemovedCRs, aiCRName)
+	}
+
+	report.ReboundSubscriptions = rebindSubscriptions(policyName, tenantDomain, report.FallbackPolicy)
+	return report
+}
+
+// rebindSubscriptions moves the subscriptions of the organization throttled by the policy to the fallback policy,
+// notifies the enforcer and returns the UUIDs of the moved subscriptions
+func rebindSubscriptions(policyName string, tenantDomain string, fallbackPolicy string) []string {
+	if strings.EqualFold(policyName, fallbackPolicy) {
+		logger.LoggerMessaging.Warnf("The deleted subscription policy %s is the fallback policy, the subscriptions are not moved",
+			policyName)
+		return nil
+	}
+	// The subscriptions fetched on startup refer to the policy by name while the ones received as events refer to
+	// it by its hashed CR name
+	hashedPolicyName := k8sclient.PrepareSubscritionPolicyCRName(policyName, tenantDomain)
+	timeStamp := time.Now().UnixMilli()
+	var rebound []string
+	for _, subscription := range mgtServer.GetAllSubscriptions() {
+		if subscription.Organization != tenantDomain {
+			continue
+		}
+		switch subscription.RateLimit {
+		case policyName:
+			subscription.RateLimit = fallbackPolicy
+		case hashedPolicyName:
+			subscription.RateLimit = k8sclient.PrepareSubscritionPolicyCRName(fallbackPolicy, tenantDomain)
+		default:
+			continue
+		}
+		mgtServer.UpdateSubscription(subscription.UUID, subscription)
+		rebound = append(rebound, subscription.UUID)
+		grpcSubscription := &event.Subscription{Uuid: subscription.UUID, SubStatus: subscription.SubStatus,
+			Organization: subscription.Organization, RatelimitTier: subscription.RateLimit}
+		if subscription.SubscribedAPI != nil {
+			grpcSubscription.SubscribedApi = &event.SubscribedAPI{Name: subscription.SubscribedAPI.Name,
+				Version: subscription.SubscribedAPI.Version}
+		}
+		go utils.SendEvent(&event.Event{Uuid: uuid.New().String(), Type: constants.SubscriptionUpdated,
+			TimeStamp: timeStamp, Subscription: grpcSubscription})
+	}
+	return rebound
+}
+
+// getSubscriptionFallbackPolicy returns the configured policy the subscriptions of a deleted subscription policy
+// are moved to
+func getSubscriptionFallbackPolicy() string {
+	conf, _ := config.ReadConfigs()
+	if conf != nil {
+		if value := conf.GatewayAgent.Get(subscriptionFallbackPolicyConfig); value != nil {
+			if fallbackPolicy := fmt.Sprint(value); fallbackPolicy != "" {
+				return fallbackPolicy
+			}
+		}
+	}
+	return defaultSubscriptionFallbackPolicy
+}
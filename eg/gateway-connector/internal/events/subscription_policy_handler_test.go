@@ -0,0 +1,64 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	mgtServer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	k8sclient "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/k8sClient"
+)
+
+func TestRebindSubscriptions(t *testing.T) {
+	mgtServer.AddAllSubscriptions(map[string]mgtServer.Subscription{
+		"sub1": {UUID: "sub1", Organization: "org1", RateLimit: "Gold"},
+		"sub2": {UUID: "sub2", Organization: "org1", RateLimit: k8sclient.PrepareSubscritionPolicyCRName("Gold", "org1")},
+		"sub3": {UUID: "sub3", Organization: "org1", RateLimit: "Silver"},
+		"sub4": {UUID: "sub4", Organization: "org2", RateLimit: "Gold"},
+	})
+	t.Cleanup(mgtServer.DeleteAllSubscriptions)
+
+	rebound := rebindSubscriptions("Gold", "org1", "Unlimited")
+
+	assert.ElementsMatch(t, []string{"sub1", "sub2"}, rebound)
+	assert.Equal(t, "Unlimited", mgtServer.GetSubscription("sub1").RateLimit)
+	assert.Equal(t, k8sclient.PrepareSubscritionPolicyCRName("Unlimited", "org1"), mgtServer.GetSubscription("sub2").RateLimit)
+	assert.Equal(t, "Silver", mgtServer.GetSubscription("sub3").RateLimit)
+	assert.Equal(t, "Gold", mgtServer.GetSubscription("sub4").RateLimit)
+}
+
+func TestRebindSubscriptionsOfFallbackPolicy(t *testing.T) {
+	mgtServer.AddAllSubscriptions(map[string]mgtServer.Subscription{
+		"sub1": {UUID: "sub1", Organization: "org1", RateLimit: "Unlimited"},
+	})
+	t.Cleanup(mgtServer.DeleteAllSubscriptions)
+
+	assert.Empty(t, rebindSubscriptions("Unlimited", "org1", "Unlimited"))
+	assert.Equal(t, "Unlimited", mgtServer.GetSubscription("sub1").RateLimit)
+}
+
+func TestSubscriptionPolicyDeletionReportString(t *testing.T) {
+	report := &subscriptionPolicyDeletionReport{PolicyName: "Gold", Organization: "org1", FallbackPolicy: "Unlimited",
+		RemovedCRs: []string{"subscription-abc"}, ReboundSubscriptions: []string{"sub1"}}
+	assert.Equal(t, "subscription policy Gold of organization org1: removed 1 CRs [subscription-abc]; moved 1 subscriptions [sub1] to Unlimited",
+		report.String())
+
+	report.Failures = []string{"subscription-def"}
+	assert.Contains(t, report.String(), "; failed 1 [subscription-def]")
+}
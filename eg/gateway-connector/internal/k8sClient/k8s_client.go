@@ -22,6 +22,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"regexp"
 	"strings"
 
 	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
@@ -29,14 +31,18 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/logging"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/transformer"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/cache"
 	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,7 +55,7 @@ import (
 // !!! ======== NEW ========
 
 const (
-	// Shared BackendTrafficPolicy name for all subscription rate limit policies
+	// Shared BackendTrafficPolicy name for all subscription and application rate limit policies
 	SharedRateLimitPolicyName = "kgw-shared-subscription-rl-policy"
 )
 
@@ -60,7 +66,7 @@ func UndeployRouteMetadataCRs(apiID string, k8sClient client.Client) {
 		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
 	}
 	routeMetadataList := &dpv2alpha1.RouteMetadataList{}
-	err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{"apiUUID": apiID})})
+	err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{"apiUUID": apiID})})
 	// Retrieve all API CRs from the Kubernetes cluster
 	if err != nil {
 		loggers.LoggerK8sClient.Errorf("Unable to list RouteMetadata CRs: %v", err)
@@ -84,6 +90,119 @@ func UndeployK8sRouteMetadataCRs(k8sClient client.Client, k8sRouteMetadata dpv2a
 	return nil
 }
 
+// UpdateAPILifeCycleState applies the lifecycle state of an API to the HTTPRoutes and GRPCRoutes of its RouteMetadata CRs. The
+// state is recorded in the RouteMetadata CRs so that it is applied again when a new revision of the API is deployed.
+func UpdateAPILifeCycleState(apiID string, lifeCycle transformer.LifeCycle, k8sClient client.Client) error {
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		return fmt.Errorf("error reading configurations: %w", errReadConfig)
+	}
+	routeMetadataList := &dpv2alpha1.RouteMetadataList{}
+	if err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(),
+		LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}); err != nil {
+		return fmt.Errorf("unable to list RouteMetadata CRs of API %s: %w", apiID, err)
+	}
+	var errs []error
+	for i := range routeMetadataList.Items {
+		if err := updateRouteMetadataLifeCycleState(&routeMetadataList.Items[i], lifeCycle, k8sClient); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// updateRouteMetadataLifeCycleState applies the lifecycle state of an API to the HTTPRoutes and GRPCRoutes owned by
+// one of its RouteMetadata CRs
+func updateRouteMetadataLifeCycleState(routeMetadata *dpv2alpha1.RouteMetadata, lifeCycle transformer.LifeCycle, k8sClient client.Client) error {
+	previous := transformer.LifeCycleFromAnnotations(routeMetadata.Annotations)
+	if routeMetadata.Annotations == nil {
+		routeMetadata.Annotations = make(map[string]string)
+	}
+	delete(routeMetadata.Annotations, transformer.DeprecatedAtAnnotation)
+	maps.Copy(routeMetadata.Annotations, lifeCycle.Annotations())
+	if err := k8sClient.Update(context.Background(), routeMetadata); err != nil {
+		return fmt.Errorf("unable to update RouteMetadata CR %s: %w", routeMetadata.Name, err)
+	}
+
+	blockedFilterName := transformer.BlockedHTTPRouteFilterName(routeMetadata.Name)
+	if lifeCycle.State == transformer.BlockedLifeCycleState {
+		ownerRef := &metav1.OwnerReference{
+			APIVersion: "dp.wso2.com/v2alpha1",
+			Kind:       constants.RouteMetadataKind,
+			Name:       routeMetadata.Name,
+			UID:        routeMetadata.UID,
+		}
+		DeployHTTPRouteFilterCR(transformer.GenerateBlockedHTTPRouteFilter(routeMetadata.Name, routeMetadata.Namespace,
+			routeMetadata.Labels), ownerRef, k8sClient)
+	}
+
+	httpRouteList := &gwapiv1.HTTPRouteList{}
+	if err := k8sClient.List(context.Background(), httpRouteList, &client.ListOptions{Namespace: routeMetadata.Namespace}); err != nil {
+		return fmt.Errorf("unable to list HTTPRoute CRs of RouteMetadata %s: %w", routeMetadata.Name, err)
+	}
+	var errs []error
+	for i := range httpRouteList.Items {
+		httpRoute := &httpRouteList.Items[i]
+		if !isOwnedBy(httpRoute.OwnerReferences, routeMetadata.UID) {
+			continue
+		}
+		transformer.ApplyLifeCycleStateToHTTPRoute(httpRoute, previous, lifeCycle, blockedFilterName)
+		if err := k8sClient.Update(context.Background(), httpRoute); err != nil {
+			errs = append(errs, fmt.Errorf("unable to update HTTPRoute CR %s: %w", httpRoute.Name, err))
+			continue
+		}
+		loggers.LoggerK8sClient.Infof("Applied the lifecycle state %s to HTTPRoute CR: %s", lifeCycle.State, httpRoute.Name)
+	}
+
+	grpcRouteList := &gwapiv1.GRPCRouteList{}
+	if err := k8sClient.List(context.Background(), grpcRouteList, &client.ListOptions{Namespace: routeMetadata.Namespace}); err != nil {
+		return fmt.Errorf("unable to list GRPCRoute CRs of RouteMetadata %s: %w", routeMetadata.Name, err)
+	}
+	for i := range grpcRouteList.Items {
+		grpcRoute := &grpcRouteList.Items[i]
+		if !isOwnedBy(grpcRoute.OwnerReferences, routeMetadata.UID) {
+			continue
+		}
+		transformer.ApplyLifeCycleStateToGRPCRoute(grpcRoute, previous, lifeCycle, blockedFilterName)
+		if err := k8sClient.Update(context.Background(), grpcRoute); err != nil {
+			errs = append(errs, fmt.Errorf("unable to update GRPCRoute CR %s: %w", grpcRoute.Name, err))
+			continue
+		}
+		loggers.LoggerK8sClient.Infof("Applied the lifecycle state %s to GRPCRoute CR: %s", lifeCycle.State, grpcRoute.Name)
+	}
+
+	if lifeCycle.State != transformer.BlockedLifeCycleState {
+		blockedFilter := &gatewayv1alpha1.HTTPRouteFilter{ObjectMeta: metav1.ObjectMeta{Name: blockedFilterName,
+			Namespace: routeMetadata.Namespace}}
+		if err := k8sClient.Delete(context.Background(), blockedFilter); err != nil && !k8error.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("unable to delete HTTPRouteFilter CR %s: %w", blockedFilterName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RetrieveAPILifeCycle returns the lifecycle of an API recorded in its RouteMetadata CR
+func RetrieveAPILifeCycle(namespace string, routeMetadataName string, k8sClient client.Client) transformer.LifeCycle {
+	routeMetadata := &dpv2alpha1.RouteMetadata{}
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: routeMetadataName}, routeMetadata); err != nil {
+		if !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Errorf("Unable to get RouteMetadata CR %s: %v", routeMetadataName, err)
+		}
+		return transformer.LifeCycle{}
+	}
+	return transformer.LifeCycleFromAnnotations(routeMetadata.Annotations)
+}
+
+// isOwnedBy checks whether the owner references contain the owner with the given UID
+func isOwnedBy(ownerReferences []metav1.OwnerReference, uid types.UID) bool {
+	for _, ownerReference := range ownerReferences {
+		if ownerReference.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
 // DeployRouteMetadataCR applies the given RouteMetadata struct to the Kubernetes cluster.
 func DeployRouteMetadataCR(routeMetadata *dpv2alpha1.RouteMetadata, k8sClient client.Client) (types.UID, error) {
 	crRouteMetadata := &dpv2alpha1.RouteMetadata{}
@@ -486,7 +605,7 @@ func UpdateRateLimitPolicyCR(policy eventhubTypes.RateLimitPolicy, k8sClient cli
 	labelMap := map[string]string{"kgw.wso2.com/cpInitiated": "true", "kgw.wso2.com/organization": policy.TenantDomain}
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(policy.TenantDomain),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	err := k8sClient.List(context.Background(), rlBackendTrafficPolicyList, listOption)
@@ -737,31 +856,47 @@ func DeployAIRateLimitPolicyFromCPPolicy(policy eventhubTypes.SubscriptionPolicy
 
 // !!!TODO: Might be possible to use single method for both SubscriptionRL and SubscriptionAIRL(because both use BackendTrafficPolicy CR)
 // UnDeploySubscriptionRateLimitPolicyCR deletes the given RateLimit BackendTrafficPolicy struct from the Kubernetes cluster.
-func UnDeploySubscriptionRateLimitPolicyCR(crName string, k8sClient client.Client) {
+// It reports whether the CR existed and was deleted.
+func UnDeploySubscriptionRateLimitPolicyCR(crName string, k8sClient client.Client) (bool, error) {
 	conf, _ := config.ReadConfigs()
 	crRLBackendTrafficPPolicies := &gatewayv1alpha1.BackendTrafficPolicy{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: crName}, crRLBackendTrafficPPolicies); err != nil {
+		if k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Debugf("RateLimit BackendTrafficPolicy CR %s not found, nothing to delete", crName)
+			return false, nil
+		}
 		loggers.LoggerK8sClient.Error("Unable to get RateLimit BackendTrafficPolicies CR: " + err.Error())
+		return false, err
 	}
 	err := k8sClient.Delete(context.Background(), crRLBackendTrafficPPolicies, &client.DeleteOptions{})
 	if err != nil {
 		loggers.LoggerK8sClient.Error("Unable to delete RateLimit BackendTrafficPolicy CR: " + err.Error())
+		return false, err
 	}
 	loggers.LoggerK8sClient.Debug("RateLimit BackendTrafficPolicy CR deleted: " + crRLBackendTrafficPPolicies.Name)
+	return true, nil
 }
 
 // UndeploySubscriptionAIRateLimitPolicyCR deletes the given AIRateLimit BackendTrafficPolicy struct from the Kubernetes cluster.
-func UndeploySubscriptionAIRateLimitPolicyCR(crName string, k8sClient client.Client) {
+// It reports whether the CR existed and was deleted.
+func UndeploySubscriptionAIRateLimitPolicyCR(crName string, k8sClient client.Client) (bool, error) {
 	conf, _ := config.ReadConfigs()
 	crAIRLBackendTrafficPolicies := &gatewayv1alpha1.BackendTrafficPolicy{}
 	if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: crName}, crAIRLBackendTrafficPolicies); err != nil {
+		if k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Debugf("Subscription AI RateLimit BackendTrafficPolicy CR %s not found, nothing to delete", crName)
+			return false, nil
+		}
 		loggers.LoggerK8sClient.Error("Unable to get Subscription AI RateLimit BackendTrafficPolicy CR: " + err.Error())
+		return false, err
 	}
 	err := k8sClient.Delete(context.Background(), crAIRLBackendTrafficPolicies, &client.DeleteOptions{})
 	if err != nil {
 		loggers.LoggerK8sClient.Error("Unable to delete Subscription AI RateLimit BackendTrafficPolicy CR: " + err.Error())
+		return false, err
 	}
 	loggers.LoggerK8sClient.Debug("Subscription AI RateLimit BackendTrafficPolicy CR deleted: " + crAIRLBackendTrafficPolicies.Name)
+	return true, nil
 }
 
 // ^^^^^^^^^^^^^ OLD RL POLICY CODE ^^^^^^^^^^^^^
@@ -918,7 +1053,7 @@ func UpdateSecurityPolicyCRs(keymanagerName string, tenantDomain string, k8sClie
 	// SecurityPolicy CRs are filtered by organization
 	labelMap := map[string]string{"kgw.wso2.com/organization": tenantDomain}
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(tenantDomain),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 
@@ -972,6 +1107,10 @@ func UpdateSecurityPolicyCRs(keymanagerName string, tenantDomain string, k8sClie
 							Claim:  claim.RemoteClaim,
 						})
 					}
+					mappedClaims = append(mappedClaims, gatewayv1alpha1.ClaimToHeader{
+						Header: transformer.ApplicationClientIDHeader,
+						Claim:  transformer.ClientIDClaim,
+					})
 					securitypolicy.Spec.JWT.Providers[i].ClaimToHeaders = mappedClaims
 					updated = true
 				}
@@ -1056,7 +1195,7 @@ func UpdateSecurityPolicyCR(keyManager eventhubTypes.ResolvedKeyManager, k8sClie
 
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(keyManager.Organization),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 
@@ -1198,7 +1337,7 @@ func RetrieveAllRouteMetasFromK8s(k8sClient client.Client, nextToken string) ([]
 	conf, _ := config.ReadConfigs()
 	routeMetaList := dpv2alpha1.RouteMetadataList{}
 	err := k8sClient.List(context.Background(), &routeMetaList, &client.ListOptions{
-		Namespace: conf.DataPlane.Namespace,
+		Namespace: conf.DataPlane.GetLookupNamespace(),
 	})
 
 	if err != nil {
@@ -1236,7 +1375,7 @@ func RetrieveAllRatelimitPoliciesSFromK8s(ratelimitName string, organization str
 	// !!! Might need to change this later
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(organization),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	var err error
@@ -1260,7 +1399,7 @@ func RetrieveAllAIRatelimitPoliciesSFromK8s(aiRatelimitName string, organization
 	// !!! Might need to change this later
 	// Create a list option with the label selector
 	listOption := &client.ListOptions{
-		Namespace:     conf.DataPlane.Namespace,
+		Namespace:     conf.DataPlane.GetOrganizationNamespace(organization),
 		LabelSelector: labels.SelectorFromSet(labelMap),
 	}
 	var err error
@@ -1303,27 +1442,32 @@ func getGatewayNameFromK8s(k8sClient client.Client) (string, error) {
 
 // getRateLimitPolicyContents returns the time unit and count for the given policy based on the quota type
 func getRateLimitPolicyContents(policy eventhubTypes.SubscriptionPolicy) (gatewayv1alpha1.RateLimitUnit, uint) {
+	return getDefaultLimitContents(policy.QuotaType, policy.DefaultLimit)
+}
+
+// getDefaultLimitContents returns the time unit and count of the default limit of a policy based on the quota type
+func getDefaultLimitContents(quotaType string, defaultLimit eventhubTypes.DefaultLimit) (gatewayv1alpha1.RateLimitUnit, uint) {
 	var timeUnit gatewayv1alpha1.RateLimitUnit
 	var count, unitTime int
-	switch policy.QuotaType {
+	switch quotaType {
 	case "aiApiQuota":
 		// For AI RL, the time unit is already properly formatted
-		unitTime = int(policy.DefaultLimit.AiAPIQuota.UnitTime)
-		loggers.LoggerK8sClient.Infof("Formatted Time Unit(AIAPIQuota): %s", policy.DefaultLimit.AiAPIQuota.TimeUnit)
-		timeUnit = gatewayv1alpha1.RateLimitUnit(policy.DefaultLimit.AiAPIQuota.TimeUnit)
-		count = int(*policy.DefaultLimit.AiAPIQuota.RequestCount) / unitTime
+		unitTime = int(defaultLimit.AiAPIQuota.UnitTime)
+		loggers.LoggerK8sClient.Infof("Formatted Time Unit(AIAPIQuota): %s", defaultLimit.AiAPIQuota.TimeUnit)
+		timeUnit = gatewayv1alpha1.RateLimitUnit(defaultLimit.AiAPIQuota.TimeUnit)
+		count = int(*defaultLimit.AiAPIQuota.RequestCount) / unitTime
 	case "eventCount":
-		unitTime = int(policy.DefaultLimit.EventCount.UnitTime)
-		loggers.LoggerK8sClient.Infof("Formatted Time Unit(EventCount): %s", getFormattedTimeUnit(policy.DefaultLimit.EventCount.TimeUnit))
-		timeUnit = gatewayv1alpha1.RateLimitUnit(getFormattedTimeUnit(policy.DefaultLimit.EventCount.TimeUnit))
-		count = int(policy.DefaultLimit.EventCount.EventCount) / unitTime
+		unitTime = int(defaultLimit.EventCount.UnitTime)
+		loggers.LoggerK8sClient.Infof("Formatted Time Unit(EventCount): %s", getFormattedTimeUnit(defaultLimit.EventCount.TimeUnit))
+		timeUnit = gatewayv1alpha1.RateLimitUnit(getFormattedTimeUnit(defaultLimit.EventCount.TimeUnit))
+		count = int(defaultLimit.EventCount.EventCount) / unitTime
 	case "requestCount":
-		unitTime = int(policy.DefaultLimit.RequestCount.UnitTime)
-		loggers.LoggerK8sClient.Infof("Formatted Time Unit(RequestCount): %s", getFormattedTimeUnit(policy.DefaultLimit.EventCount.TimeUnit))
-		timeUnit = gatewayv1alpha1.RateLimitUnit(getFormattedTimeUnit(policy.DefaultLimit.RequestCount.TimeUnit))
-		count = int(policy.DefaultLimit.RequestCount.RequestCount) / unitTime
+		unitTime = int(defaultLimit.RequestCount.UnitTime)
+		loggers.LoggerK8sClient.Infof("Formatted Time Unit(RequestCount): %s", getFormattedTimeUnit(defaultLimit.EventCount.TimeUnit))
+		timeUnit = gatewayv1alpha1.RateLimitUnit(getFormattedTimeUnit(defaultLimit.RequestCount.TimeUnit))
+		count = int(defaultLimit.RequestCount.RequestCount) / unitTime
 	default:
-		loggers.LoggerK8sClient.Errorf("Unexpected quota type %s", policy.QuotaType)
+		loggers.LoggerK8sClient.Errorf("Unexpected quota type %s", quotaType)
 		return "", 0
 	}
 	return timeUnit, uint(count)
@@ -1691,8 +1835,9 @@ func DeploySharedSubscriptionRateLimitPolicyCR(policy eventhubTypes.Subscription
 	}
 }
 
-// UnDeploySubscriptionRateLimitPolicyCR removes the rate limit rule for the given policy from the shared BackendTrafficPolicy.
-func UnDeploySharedSubscriptionRateLimitPolicyCR(policyName string, tenantDomain string, k8sClient client.Client, aiPolicy bool) {
+// UnDeploySharedSubscriptionRateLimitPolicyCR removes the rate limit rule for the given policy from the shared
+// BackendTrafficPolicy. It reports whether a rule of the policy was removed.
+func UnDeploySharedSubscriptionRateLimitPolicyCR(policyName string, tenantDomain string, k8sClient client.Client, aiPolicy bool) (bool, error) {
 	conf, _ := config.ReadConfigs()
 	sharedPolicyName := utils.CreateSubscriptionPolicyName(SharedRateLimitPolicyName, tenantDomain)
 	// Get the shared BackendTrafficPolicy
@@ -1705,10 +1850,10 @@ func UnDeploySharedSubscriptionRateLimitPolicyCR(policyName string, tenantDomain
 	if err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Infof("Shared RateLimit BackendTrafficPolicy not found, nothing to remove for policy: %s", policyName)
-		} else {
-			loggers.LoggerK8sClient.Errorf("Unable to get shared RateLimit BackendTrafficPolicy CR: %v", err)
+			return false, nil
 		}
-		return
+		loggers.LoggerK8sClient.Errorf("Unable to get shared RateLimit BackendTrafficPolicy CR: %v", err)
+		return false, err
 	}
 
 	// Remove the rule for this policy
@@ -1736,7 +1881,7 @@ func UnDeploySharedSubscriptionRateLimitPolicyCR(policyName string, tenantDomain
 
 	if !ruleFound {
 		loggers.LoggerK8sClient.Infof("Rule for policy '%s' not found in shared BackendTrafficPolicy", policyName)
-		return
+		return false, nil
 	}
 
 	// Update or delete the policy based on remaining rules
@@ -1744,16 +1889,243 @@ func UnDeploySharedSubscriptionRateLimitPolicyCR(policyName string, tenantDomain
 		// No rules left, delete the entire BackendTrafficPolicy
 		if err := k8sClient.Delete(context.Background(), existingPolicy); err != nil {
 			loggers.LoggerK8sClient.Errorf("Unable to delete shared RateLimit BackendTrafficPolicy CR: %v", err)
-		} else {
-			loggers.LoggerK8sClient.Infof("Shared RateLimit BackendTrafficPolicy CR deleted as no rules remain")
+			return false, err
 		}
+		loggers.LoggerK8sClient.Infof("Shared RateLimit BackendTrafficPolicy CR deleted as no rules remain")
 	} else {
 		// Update with remaining rules
 		existingPolicy.Spec.RateLimit.Global.Rules = updatedRules
 		if err := k8sClient.Update(context.Background(), existingPolicy); err != nil {
 			loggers.LoggerK8sClient.Errorf("Unable to update shared RateLimit BackendTrafficPolicy CR after removing rule: %v", err)
-		} else {
-			loggers.LoggerK8sClient.Infof("Rule for policy '%s' removed from shared BackendTrafficPolicy", policyName)
+			return false, err
+		}
+		loggers.LoggerK8sClient.Infof("Rule for policy '%s' removed from shared BackendTrafficPolicy", policyName)
+	}
+	return true, nil
+}
+
+// GenerateApplicationRateLimitRule creates the rule of an application in the shared BackendTrafficPolicy. The rule
+// selects the requests by the client IDs of the keys of the application and is shared across all the routes, so that
+// the application gets the limit of its application policy across all the APIs it subscribes to.
+func GenerateApplicationRateLimitRule(policy eventhubTypes.ApplicationPolicy, clientIDs []string) gatewayv1alpha1.RateLimitRule {
+	unit, requestsPerUnit := getDefaultLimitContents(policy.QuotaType, policy.DefaultLimit)
+	quotedClientIDs := make([]string, 0, len(clientIDs))
+	for _, clientID := range clientIDs {
+		quotedClientIDs = append(quotedClientIDs, regexp.QuoteMeta(clientID))
+	}
+	return gatewayv1alpha1.RateLimitRule{
+		ClientSelectors: []gatewayv1alpha1.RateLimitSelectCondition{
+			{
+				Headers: []gatewayv1alpha1.HeaderMatch{
+					{
+						Type:   ptr.To(gatewayv1alpha1.HeaderMatchRegularExpression),
+						Name:   transformer.ApplicationClientIDHeader,
+						Value:  ptr.To("^(" + strings.Join(quotedClientIDs, "|") + ")$"),
+						Invert: ptr.To(false),
+					},
+				},
+			},
+		},
+		Limit: gatewayv1alpha1.RateLimitValue{
+			Requests: requestsPerUnit,
+			Unit:     unit,
+		},
+		Shared: ptr.To(true),
+	}
+}
+
+// isApplicationRateLimitRule reports whether a rule of the shared BackendTrafficPolicy is the rule of an application
+func isApplicationRateLimitRule(rule gatewayv1alpha1.RateLimitRule) bool {
+	for _, selector := range rule.ClientSelectors {
+		for _, header := range selector.Headers {
+			if header.Name == transformer.ApplicationClientIDHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DeploySharedApplicationRateLimitRules replaces the application rules of the shared BackendTrafficPolicy of the
+// organization with the given rules. The subscription rules of the policy are kept, and the policy is created when
+// it does not exist and deleted when no rules remain.
+func DeploySharedApplicationRateLimitRules(organization string, rules []gatewayv1alpha1.RateLimitRule, k8sClient client.Client) error {
+	conf, _ := config.ReadConfigs()
+	sharedPolicyName := utils.CreateSubscriptionPolicyName(SharedRateLimitPolicyName, organization)
+
+	existingPolicy := &gatewayv1alpha1.BackendTrafficPolicy{}
+	err := k8sClient.Get(context.Background(), client.ObjectKey{
+		Namespace: conf.DataPlane.Namespace,
+		Name:      sharedPolicyName,
+	}, existingPolicy)
+	if err != nil {
+		if !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Errorf("Unable to get shared RateLimit BackendTrafficPolicy CR: %v", err)
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		gatewayName, _ := getGatewayNameFromK8s(k8sClient)
+		if gatewayName == "" {
+			gatewayName = "wso2-kgw-default"
+		}
+		sharedPolicy := &gatewayv1alpha1.BackendTrafficPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sharedPolicyName,
+				Namespace: conf.DataPlane.Namespace,
+				Labels: map[string]string{
+					"kgw.wso2.com/cpInitiated":  "true",
+					"kgw.wso2.com/type":         "subscription-ratelimit",
+					"kgw.wso2.com/organization": organization,
+				},
+			},
+			Spec: gatewayv1alpha1.BackendTrafficPolicySpec{
+				MergeType: ptr.To(gatewayv1alpha1.MergeType("StrategicMerge")),
+				RateLimit: &gatewayv1alpha1.RateLimitSpec{
+					Type: gatewayv1alpha1.RateLimitType("Global"),
+					Global: &gatewayv1alpha1.GlobalRateLimit{
+						Rules: rules,
+					},
+				},
+				PolicyTargetReferences: gatewayv1alpha1.PolicyTargetReferences{
+					TargetRefs: []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName{
+						{
+							LocalPolicyTargetReference: gwapiv1a2.LocalPolicyTargetReference{
+								Group: gwapiv1a2.Group(constants.GatewayGroup),
+								Kind:  gwapiv1a2.Kind("Gateway"),
+								Name:  gwapiv1a2.ObjectName(gatewayName),
+							},
+						},
+					},
+				},
+			},
+		}
+		if err := k8sClient.Create(context.Background(), sharedPolicy); err != nil {
+			loggers.LoggerK8sClient.Errorf("Unable to create the shared RateLimit BackendTrafficPolicy CR: %v", err)
+			return err
+		}
+		loggers.LoggerK8sClient.Infof("Shared RateLimit BackendTrafficPolicy CR created with the application rules: %s", sharedPolicyName)
+		return nil
+	}
+
+	updatedRules := []gatewayv1alpha1.RateLimitRule{}
+	for _, rule := range existingPolicy.Spec.RateLimit.Global.Rules {
+		if !isApplicationRateLimitRule(rule) {
+			updatedRules = append(updatedRules, rule)
+		}
+	}
+	updatedRules = append(updatedRules, rules...)
+	if len(updatedRules) == 0 {
+		if err := k8sClient.Delete(context.Background(), existingPolicy); err != nil && !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Errorf("Unable to delete shared RateLimit BackendTrafficPolicy CR: %v", err)
+			return err
+		}
+		loggers.LoggerK8sClient.Infof("Shared RateLimit BackendTrafficPolicy CR deleted as no rules remain")
+		return nil
+	}
+	existingPolicy.Spec.RateLimit.Global.Rules = updatedRules
+	if err := k8sClient.Update(context.Background(), existingPolicy); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to update shared RateLimit BackendTrafficPolicy CR: %v", err)
+		return err
+	}
+	loggers.LoggerK8sClient.Infof("Shared RateLimit BackendTrafficPolicy CR updated with %d application rules", len(rules))
+	return nil
+}
+
+// RetrieveSharedRateLimitPolicyOrganizations returns the organizations which have a shared rate limit
+// BackendTrafficPolicy deployed
+func RetrieveSharedRateLimitPolicyOrganizations(k8sClient client.Client) ([]string, error) {
+	conf, _ := config.ReadConfigs()
+	policyList := &gatewayv1alpha1.BackendTrafficPolicyList{}
+	if err := k8sClient.List(context.Background(), policyList, &client.ListOptions{
+		Namespace:     conf.DataPlane.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{"kgw.wso2.com/type": "subscription-ratelimit"}),
+	}); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list shared RateLimit BackendTrafficPolicy CRs: %v", err)
+		return nil, err
+	}
+	organizations := []string{}
+	for _, policy := range policyList.Items {
+		if organization := policy.Labels["kgw.wso2.com/organization"]; organization != "" {
+			organizations = append(organizations, organization)
 		}
 	}
+	return organizations, nil
+}
+
+// UndeployOrganizationCRs removes the Custom Resources of an organization from the Kubernetes cluster and records
+// them in the purge report. Deleting the RouteMetadata CRs of the organization removes the API CRs owned by them,
+// while the policies, backends and secrets created from the control plane are found by the organization label.
+func UndeployOrganizationCRs(organization string, k8sClient client.Client, report *agent.PurgeReport) {
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
+	}
+
+	namespace := conf.DataPlane.GetOrganizationNamespace(organization)
+	routeMetadataList := &dpv2alpha1.RouteMetadataList{}
+	if err := k8sClient.List(context.Background(), routeMetadataList, &client.ListOptions{Namespace: namespace}); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list RouteMetadata CRs: %v", err)
+		report.AddFailed(constants.RouteMetadataKind, organization)
+	} else {
+		for _, routeMetadata := range routeMetadataList.Items {
+			if routeMetadata.Spec.API.Organization != organization {
+				continue
+			}
+			if err := k8sClient.Delete(context.Background(), &routeMetadata, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+				loggers.LoggerK8sClient.Errorf("Unable to delete RouteMetadata CR %s: %v", routeMetadata.Name, err)
+				report.AddFailed(constants.RouteMetadataKind, routeMetadata.Name)
+				continue
+			}
+			loggers.LoggerK8sClient.Infof("Deleted RouteMetadata CR: %s", routeMetadata.Name)
+			report.AddDeleted(constants.RouteMetadataKind, routeMetadata.Name)
+		}
+	}
+
+	selector := labels.SelectorFromSet(map[string]string{
+		constants.OrganizationLabel: organization,
+		"kgw.wso2.com/cpInitiated":  "true",
+	})
+	for _, resourceType := range []struct {
+		list client.ObjectList
+		kind string
+	}{
+		{&gatewayv1alpha1.BackendTrafficPolicyList{}, constants.BackendTrafficPolicyKind},
+		{&gatewayv1alpha1.SecurityPolicyList{}, constants.SecurityPolicyKind},
+		{&gwapiv1a3.BackendTLSPolicyList{}, "BackendTLSPolicy"},
+		{&gatewayv1alpha1.BackendList{}, constants.BackendKind},
+		{&corev1.SecretList{}, "Secret"},
+	} {
+		purgeResources(resourceType.list, resourceType.kind, namespace, selector, k8sClient, report)
+	}
+}
+
+// purgeResources deletes the resources of the given kind in the namespace that match the label selector
+func purgeResources(resourceList client.ObjectList, kind string, namespace string, selector labels.Selector,
+	k8sClient client.Client, report *agent.PurgeReport) {
+	listOpts := &client.ListOptions{Namespace: namespace, LabelSelector: selector}
+	if err := k8sClient.List(context.Background(), resourceList, listOpts); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list %s CRs: %v", kind, err)
+		report.AddFailed(kind, selector.String())
+		return
+	}
+	err := meta.EachListItem(resourceList, func(item runtime.Object) error {
+		resource, ok := item.(client.Object)
+		if !ok {
+			return nil
+		}
+		if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Errorf("Unable to delete %s CR %s: %v", kind, resource.GetName(), err)
+			report.AddFailed(kind, resource.GetName())
+			return nil
+		}
+		loggers.LoggerK8sClient.Infof("Deleted %s CR: %s", kind, resource.GetName())
+		report.AddDeleted(kind, resource.GetName())
+		return nil
+	})
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to read the listed %s CRs: %v", kind, err)
+		report.AddFailed(kind, selector.String())
+	}
 }
@@ -0,0 +1,165 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/transformer"
+	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newApplicationPolicy(name string, requestCount int) eventhubTypes.ApplicationPolicy {
+	policy := eventhubTypes.ApplicationPolicy{Name: name, TenantDomain: "org1", QuotaType: "requestCount"}
+	policy.DefaultLimit.RequestCount.TimeUnit = "min"
+	policy.DefaultLimit.RequestCount.UnitTime = 1
+	policy.DefaultLimit.RequestCount.RequestCount = requestCount
+	return policy
+}
+
+func TestGenerateApplicationRateLimitRule(t *testing.T) {
+	rule := GenerateApplicationRateLimitRule(newApplicationPolicy("10PerMin", 10), []string{"key.1", "key2"})
+
+	assert.Equal(t, gatewayv1alpha1.RateLimitValue{Requests: 10, Unit: "Minute"}, rule.Limit)
+	assert.True(t, *rule.Shared)
+	assert.True(t, isApplicationRateLimitRule(rule))
+	require.Len(t, rule.ClientSelectors[0].Headers, 1)
+	header := rule.ClientSelectors[0].Headers[0]
+	assert.Equal(t, transformer.ApplicationClientIDHeader, header.Name)
+	assert.Equal(t, gatewayv1alpha1.HeaderMatchRegularExpression, *header.Type)
+	assert.Equal(t, `^(key\.1|key2)$`, *header.Value)
+}
+
+func TestDeploySharedApplicationRateLimitRules(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	key := client.ObjectKey{Namespace: conf.DataPlane.Namespace,
+		Name: utils.CreateSubscriptionPolicyName(SharedRateLimitPolicyName, "org1")}
+	subscriptionPolicy := eventhubTypes.SubscriptionPolicy{Name: "Gold", TenantDomain: "org1", QuotaType: "requestCount"}
+	subscriptionPolicy.DefaultLimit.RequestCount.TimeUnit = "Minute"
+	subscriptionPolicy.DefaultLimit.RequestCount.UnitTime = 1
+	subscriptionPolicy.DefaultLimit.RequestCount.RequestCount = 5000
+
+	// The application rules are added to the shared policy holding the subscription rules
+	DeploySharedSubscriptionRateLimitPolicyCR(subscriptionPolicy, k8sClient, false)
+	require.NoError(t, DeploySharedApplicationRateLimitRules("org1", []gatewayv1alpha1.RateLimitRule{
+		GenerateApplicationRateLimitRule(newApplicationPolicy("10PerMin", 10), []string{"key1"}),
+		GenerateApplicationRateLimitRule(newApplicationPolicy("20PerMin", 20), []string{"key2"}),
+	}, k8sClient))
+	sharedPolicy := &gatewayv1alpha1.BackendTrafficPolicy{}
+	require.NoError(t, k8sClient.Get(context.Background(), key, sharedPolicy))
+	assert.Len(t, sharedPolicy.Spec.RateLimit.Global.Rules, 3)
+	organizations, err := RetrieveSharedRateLimitPolicyOrganizations(k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org1"}, organizations)
+
+	// The application rules are replaced while the subscription rules are kept
+	require.NoError(t, DeploySharedApplicationRateLimitRules("org1", []gatewayv1alpha1.RateLimitRule{
+		GenerateApplicationRateLimitRule(newApplicationPolicy("10PerMin", 15), []string{"key1"}),
+	}, k8sClient))
+	require.NoError(t, k8sClient.Get(context.Background(), key, sharedPolicy))
+	require.Len(t, sharedPolicy.Spec.RateLimit.Global.Rules, 2)
+	assert.False(t, isApplicationRateLimitRule(sharedPolicy.Spec.RateLimit.Global.Rules[0]))
+	assert.Equal(t, uint(15), sharedPolicy.Spec.RateLimit.Global.Rules[1].Limit.Requests)
+	policyNames, err := RetrieveSharedSubscriptionRateLimitPolicyFromK8s("org1", k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gold"}, policyNames)
+
+	removed, err := UnDeploySharedSubscriptionRateLimitPolicyCR("Gold", "org1", k8sClient, false)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	require.NoError(t, k8sClient.Get(context.Background(), key, sharedPolicy))
+	require.NoError(t, DeploySharedApplicationRateLimitRules("org1", nil, k8sClient))
+	assert.True(t, k8error.IsNotFound(k8sClient.Get(context.Background(), key, sharedPolicy)))
+	require.NoError(t, DeploySharedApplicationRateLimitRules("org1", nil, k8sClient))
+}
+
+func TestUpdateAPILifeCycleState(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	namespace := conf.DataPlane.Namespace
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	require.NoError(t, dpv2alpha1.AddToScheme(scheme))
+	require.NoError(t, gwapiv1.Install(scheme))
+	routeMetadata := &dpv2alpha1.RouteMetadata{ObjectMeta: metav1.ObjectMeta{Name: "api1", Namespace: namespace,
+		UID: "route-metadata-uid", Labels: map[string]string{constants.APIUUIDLabel: "uuid1"}}}
+	httpRoute := &gwapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route1", Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{{Name: "api1", UID: "route-metadata-uid"}}},
+		Spec: gwapiv1.HTTPRouteSpec{Rules: []gwapiv1.HTTPRouteRule{{}}},
+	}
+	otherHTTPRoute := &gwapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route2", Namespace: namespace},
+		Spec:       gwapiv1.HTTPRouteSpec{Rules: []gwapiv1.HTTPRouteRule{{}}},
+	}
+	grpcRoute := &gwapiv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route3", Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{{Name: "api1", UID: "route-metadata-uid"}}},
+		Spec: gwapiv1.GRPCRouteSpec{Rules: []gwapiv1.GRPCRouteRule{{}}},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(routeMetadata, httpRoute, otherHTTPRoute,
+		grpcRoute).Build()
+	blockedFilterKey := client.ObjectKey{Namespace: namespace, Name: transformer.BlockedHTTPRouteFilterName("api1")}
+
+	require.NoError(t, UpdateAPILifeCycleState("uuid1", transformer.LifeCycle{State: transformer.BlockedLifeCycleState}, k8sClient))
+	require.NoError(t, k8sClient.Get(context.Background(), blockedFilterKey, &gatewayv1alpha1.HTTPRouteFilter{}))
+	updatedRoute := &gwapiv1.HTTPRoute{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(httpRoute), updatedRoute))
+	assert.Len(t, updatedRoute.Spec.Rules[0].Filters, 1)
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(otherHTTPRoute), updatedRoute))
+	assert.Empty(t, updatedRoute.Spec.Rules[0].Filters)
+	updatedGRPCRoute := &gwapiv1.GRPCRoute{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(grpcRoute), updatedGRPCRoute))
+	require.Len(t, updatedGRPCRoute.Spec.Rules[0].Filters, 1)
+	assert.Equal(t, gwapiv1.GRPCRouteFilterExtensionRef, updatedGRPCRoute.Spec.Rules[0].Filters[0].Type)
+	assert.Equal(t, transformer.BlockedLifeCycleState, RetrieveAPILifeCycle(namespace, "api1", k8sClient).State)
+
+	// The deprecation headers are removed once the API is published again
+	deprecated := transformer.LifeCycle{State: transformer.DeprecatedLifeCycleState, DeprecatedAt: time.Unix(1740787200, 0)}
+	require.NoError(t, UpdateAPILifeCycleState("uuid1", deprecated, k8sClient))
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(grpcRoute), updatedGRPCRoute))
+	require.Len(t, updatedGRPCRoute.Spec.Rules[0].Filters, 1)
+	assert.Equal(t, gwapiv1.GRPCRouteFilterResponseHeaderModifier, updatedGRPCRoute.Spec.Rules[0].Filters[0].Type)
+
+	require.NoError(t, UpdateAPILifeCycleState("uuid1", transformer.LifeCycle{State: "PUBLISHED"}, k8sClient))
+	assert.True(t, k8error.IsNotFound(k8sClient.Get(context.Background(), blockedFilterKey, &gatewayv1alpha1.HTTPRouteFilter{})))
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(httpRoute), updatedRoute))
+	assert.Empty(t, updatedRoute.Spec.Rules[0].Filters)
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(grpcRoute), updatedGRPCRoute))
+	assert.Empty(t, updatedGRPCRoute.Spec.Rules[0].Filters)
+	assert.Equal(t, "PUBLISHED", RetrieveAPILifeCycle(namespace, "api1", k8sClient).State)
+}
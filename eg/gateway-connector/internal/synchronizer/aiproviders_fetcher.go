@@ -178,6 +178,13 @@ func FetchAIProvidersOnEvent(aiProviderName string, aiProviderVersion string, or
 			k8sclient.DeployRoutePolicyCR(&crAIProviderRP, nil, c)
 			logger.LoggerSynchronizer.Info("AI Provider RoutePolicy CR Deployed Successfully")
 		}
+		if cleanupDeletedProviders {
+			// Remove the AI providers which are no longer in the control plane, such as the ones restored from
+			// the snapshot
+			for _, id := range managementserver.RetainAIProviders(aiProviders) {
+				logger.LoggerSynchronizer.Infof("Removed the AI Provider %s which is not in the control plane", id)
+			}
+		}
 	} else {
 		errorMsg = "Failed to fetch data! " + aiProviderEndpoint + " responded with " +
 			strconv.Itoa(resp.StatusCode)
@@ -84,6 +84,9 @@ func FetchKeyManagersOnStartUp(c client.Client) {
 			}
 
 			logger.LoggerSynchronizer.Infof("Populated KM cache with %d entries on startup", len(resolvedKeyManagers))
+			// Remove the key managers which are no longer in the control plane, such as the ones restored from the
+			// snapshot
+			kmCache.RetainKeyManagers(resolvedKeyManagers)
 			// applyAllKeyManagerConfiguration(c, resolvedKeyManagers)
 		}
 	}
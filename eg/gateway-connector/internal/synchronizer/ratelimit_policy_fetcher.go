@@ -24,12 +24,18 @@
 package synchronizer
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"time"
 
+	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	k8sclient "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/managementserver"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
-	managementserver "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	mgtServer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
 	sync "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/synchronizer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -184,7 +190,7 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 						logger.LoggerSynchronizer.Infof("AI -> PromptTokenCount: %d", *policy.DefaultLimit.AiAPIQuota.PromptTokenCount)
 						logger.LoggerSynchronizer.Infof("AI -> CompletionTokenCount: %d", *policy.DefaultLimit.AiAPIQuota.CompletionTokenCount)
 						logger.LoggerSynchronizer.Infof("AI -> TotalTokenCount: %d", *policy.DefaultLimit.AiAPIQuota.TotalTokenCount)
-						managementserver.AddSubscriptionPolicy(policy)
+						mgtServer.AddSubscriptionPolicy(policy)
 						logger.LoggerSynchronizer.Infof("AI Subscription RateLimit Policy added to internal map")
 						k8sclient.DeploySharedSubscriptionRateLimitPolicyCR(policy, c, true)
 						logger.LoggerSynchronizer.Infof("AI RateLimit Policy added from CP Policy: %+v \n\n", policy)
@@ -202,7 +208,7 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 					} else if policy.DefaultLimit.RequestCount.TimeUnit == "months" {
 						policy.DefaultLimit.RequestCount.TimeUnit = "Month"
 					}
-					managementserver.AddSubscriptionPolicy(policy)
+					mgtServer.AddSubscriptionPolicy(policy)
 					logger.LoggerSynchronizer.Debug("Normal Subscription RateLimit Policy added to internal map")
 					// Update the exisitng rate limit policies with current policy
 					k8sclient.DeploySharedSubscriptionRateLimitPolicyCR(policy, c, false)
@@ -213,6 +219,96 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 	}
 }
 
+// FetchApplicationRateLimitPoliciesOnEvent fetches the application policies from the control plane on the start up
+// and notification event updates, and deploys the rules of the applications using them
+func FetchApplicationRateLimitPoliciesOnEvent(ratelimitName string, organization string, c client.Client) {
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		logger.LoggerSynchronizer.Errorf("Error reading configs: %v", errReadConfig)
+	}
+	logger.LoggerSynchronizer.Infof("Fetching application rate limit policies on event for organization: %s", organization)
+	logger.LoggerSynchronizer.Debugf("Rate Limit Name: %s | Organization: %s", ratelimitName, organization)
+	applicationPolicies, errorMsg := sync.FetchApplicationRateLimitPoliciesOnEvent(ratelimitName, organization)
+	if applicationPolicies == nil {
+		return
+	}
+	if len(applicationPolicies) == 0 && errorMsg != "" {
+		go retryApplicationRLPFetchData(ratelimitName, organization, conf, errorMsg, c)
+		return
+	}
+	if err := SyncApplicationRateLimitRules(organization, c); err != nil {
+		logger.LoggerSynchronizer.Errorf("Error deploying the application rate limit rules of organization %s: %v", organization, err)
+	}
+}
+
+// SyncApplicationRateLimitRules deploys the rate limit rules of the applications of the organization, or of all
+// organizations when the organization is empty, from the cached applications, their keys and application policies.
+// It has to be called whenever an application, an application key or an application policy changes.
+func SyncApplicationRateLimitRules(organization string, c client.Client) error {
+	applicationPolicies := mgtServer.GetApplicationPolicies()
+	applications := managementserver.GetAllApplications()
+	organizations := map[string]struct{}{organization: {}}
+	if organization == "" {
+		organizations = make(map[string]struct{})
+		for _, policy := range applicationPolicies {
+			organizations[policy.TenantDomain] = struct{}{}
+		}
+		for _, application := range applications {
+			organizations[application.Organization] = struct{}{}
+		}
+		// Organizations whose applications are all gone still have rules to remove
+		deployedOrganizations, err := k8sclient.RetrieveSharedRateLimitPolicyOrganizations(c)
+		if err != nil {
+			return err
+		}
+		for _, deployedOrganization := range deployedOrganizations {
+			organizations[deployedOrganization] = struct{}{}
+		}
+	}
+	sort.Slice(applications, func(i, j int) bool { return applications[i].UUID < applications[j].UUID })
+
+	var errs []error
+	for org := range organizations {
+		rules := []gatewayv1alpha1.RateLimitRule{}
+		for _, application := range applications {
+			if application.Organization != org {
+				continue
+			}
+			policy, exists := applicationPolicies[application.Policy+org]
+			if !exists {
+				continue
+			}
+			clientIDs := applicationClientIDs(application)
+			if len(clientIDs) == 0 {
+				continue
+			}
+			rule := k8sclient.GenerateApplicationRateLimitRule(policy, clientIDs)
+			if rule.Limit.Unit == "" {
+				logger.LoggerSynchronizer.Warnf("Unsupported quota type %s of the application policy %s of the application %s",
+					policy.QuotaType, policy.Name, application.UUID)
+				continue
+			}
+			rules = append(rules, rule)
+		}
+		if err := k8sclient.DeploySharedApplicationRateLimitRules(org, rules, c); err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", org, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applicationClientIDs returns the sorted client IDs of the keys of the application
+func applicationClientIDs(application managementserver.ResolvedApplication) []string {
+	clientIDs := []string{}
+	for _, securityScheme := range application.SecuritySchemes {
+		if securityScheme.ApplicationIdentifier != "" && !slices.Contains(clientIDs, securityScheme.ApplicationIdentifier) {
+			clientIDs = append(clientIDs, securityScheme.ApplicationIdentifier)
+		}
+	}
+	sort.Strings(clientIDs)
+	return clientIDs
+}
+
 func retryRLPFetchData(conf *config.Config, errorMessage string, c client.Client) {
 	logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
 		conf.ControlPlane.RetryInterval*time.Second)
@@ -236,3 +332,24 @@ func retrySubscriptionRLPFetchData(conf *config.Config, errorMessage string, c c
 		return
 	}
 }
+
+// retryApplicationRLPFetchData retries fetching the application policies until it succeeds or the retries run out
+func retryApplicationRLPFetchData(ratelimitName string, organization string, conf *config.Config, errorMessage string, c client.Client) {
+	for attempt := 1; attempt <= retryCount; attempt++ {
+		logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
+			conf.ControlPlane.RetryInterval*time.Second)
+		time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
+		applicationPolicies, errorMsg := sync.FetchApplicationRateLimitPoliciesOnEvent(ratelimitName, organization)
+		if applicationPolicies == nil {
+			return
+		}
+		if len(applicationPolicies) != 0 || errorMsg == "" {
+			if err := SyncApplicationRateLimitRules(organization, c); err != nil {
+				logger.LoggerSynchronizer.Errorf("Error deploying the application rate limit rules of organization %s: %v", organization, err)
+			}
+			return
+		}
+		errorMessage = errorMsg
+	}
+	logger.LoggerSynchronizer.Error(errorMessage)
+}
@@ -0,0 +1,77 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"context"
+	"testing"
+
+	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	eventhubTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	mgtServer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	k8sclient "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/k8sClient"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/managementserver"
+	k8error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSyncApplicationRateLimitRules(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	key := client.ObjectKey{Namespace: conf.DataPlane.Namespace,
+		Name: utils.CreateSubscriptionPolicyName(k8sclient.SharedRateLimitPolicyName, "sync-org")}
+
+	policy := eventhubTypes.ApplicationPolicy{Name: "10PerMin", TenantDomain: "sync-org", QuotaType: "requestCount"}
+	policy.DefaultLimit.RequestCount.TimeUnit = "min"
+	policy.DefaultLimit.RequestCount.UnitTime = 1
+	policy.DefaultLimit.RequestCount.RequestCount = 10
+	mgtServer.AddApplicationPolicy(policy)
+	defer mgtServer.DeleteApplicationPolicy("10PerMin", "sync-org")
+	managementserver.AddApplication(managementserver.Application{UUID: "sync-app1", Organization: "sync-org", Policy: "10PerMin"})
+	defer managementserver.DeleteApplication("sync-app1")
+	// An application without keys cannot be identified and gets no rule
+	managementserver.AddApplication(managementserver.Application{UUID: "sync-app2", Organization: "sync-org", Policy: "10PerMin"})
+	defer managementserver.DeleteApplication("sync-app2")
+	for _, keyType := range []string{"PRODUCTION", "SANDBOX"} {
+		managementserver.AddApplicationKeyMapping(managementserver.ApplicationKeyMapping{ApplicationUUID: "sync-app1",
+			SecurityScheme: "OAuth2", ApplicationIdentifier: "key-" + keyType, KeyType: keyType, Organization: "sync-org"})
+	}
+	defer managementserver.DeleteApplicationKeyMappingsByOrganization("sync-org")
+
+	require.NoError(t, SyncApplicationRateLimitRules("sync-org", k8sClient))
+	sharedPolicy := &gatewayv1alpha1.BackendTrafficPolicy{}
+	require.NoError(t, k8sClient.Get(context.Background(), key, sharedPolicy))
+	require.Len(t, sharedPolicy.Spec.RateLimit.Global.Rules, 1)
+	rule := sharedPolicy.Spec.RateLimit.Global.Rules[0]
+	assert.Equal(t, gatewayv1alpha1.RateLimitValue{Requests: 10, Unit: "Minute"}, rule.Limit)
+	assert.Equal(t, "^(key-PRODUCTION|key-SANDBOX)$", *rule.ClientSelectors[0].Headers[0].Value)
+
+	// The rules of the organization are removed once its applications are gone
+	managementserver.DeleteApplication("sync-app1")
+	require.NoError(t, SyncApplicationRateLimitRules("", k8sClient))
+	assert.True(t, k8error.IsNotFound(k8sClient.Get(context.Background(), key, sharedPolicy)))
+}
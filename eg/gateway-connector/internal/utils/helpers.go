@@ -88,9 +88,8 @@ func GetBackendConfigForKM(km eventhubTypes.ResolvedKeyManager) (string, string,
 	if err != nil {
 		logger.LoggerSynchronizer.Errorf("Error reading configs: %v", err)
 	}
-	if conf.DataPlane.Namespace != "" {
-		agentNS = conf.DataPlane.Namespace // Use the ns which the dataplane is running
-		// fmt.Printf("\nDataplane Namespace: %s\n", agentNS)
+	if namespace := conf.DataPlane.GetOrganizationNamespace(km.Organization); namespace != "" {
+		agentNS = namespace // Use the ns which the APIs of the KM organization are deployed to
 	}
 
 	backendName := GetSha1Value(fmt.Sprintf("%s-%s", km.Name, km.Organization))
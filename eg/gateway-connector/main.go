@@ -18,10 +18,13 @@
 package egAgent
 
 import (
+	"context"
+
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/agent"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/events"
 	"github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	commonAgent "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,52 +41,59 @@ func (a Agent) PreRun(conf *config.Config, scheme *runtime.Scheme) {
 }
 
 // Run initiates the gateway specific agent
-func (a Agent) Run(conf *config.Config, mgr manager.Manager) {
-	agent.Run(conf, mgr)
+func (a Agent) Run(conf *config.Config, mgr manager.Manager) error {
+	return agent.Run(conf, mgr)
 }
 
 // ProcessEvents handles gateway specific functions need to be triggered on event processing
 func (a Agent) ProcessEvents(conf *config.Config, client client.Client) {}
 
 // HandleLifeCycleEvents handles the events of an api through out the life cycle
-func (a Agent) HandleLifeCycleEvents(data []byte) {
+func (a Agent) HandleLifeCycleEvents(data []byte, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandleLifeCycleEvents")
-	events.HandleLifeCycleEvents(data)
+	return events.HandleLifeCycleEvents(data, client)
 }
 
 // HandleAPIEvents to process api related data
-func (a Agent) HandleAPIEvents(data []byte, eventType string, conf *config.Config, client client.Client) {
+func (a Agent) HandleAPIEvents(ctx context.Context, data []byte, eventType string, conf *config.Config, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandleAPIEvents")
-	events.HandleAPIEvents(data, eventType, conf, client)
+	return events.HandleAPIEvents(ctx, data, eventType, conf, client)
 }
 
 // HandleApplicationEvents to process application related events
-func (a Agent) HandleApplicationEvents(data []byte, eventType string, client client.Client) {
+func (a Agent) HandleApplicationEvents(ctx context.Context, data []byte, eventType string, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandleApplicationEvents")
-	events.HandleApplicationEvents(data, eventType, client)
+	return events.HandleApplicationEvents(ctx, data, eventType, client)
 }
 
 // HandleSubscriptionEvents to process subscription related events
-func (a Agent) HandleSubscriptionEvents(data []byte, eventType string, client client.Client) {
+func (a Agent) HandleSubscriptionEvents(ctx context.Context, data []byte, eventType string, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandleSubscriptionEvents")
-	events.HandleSubscriptionEvents(data, eventType, client)
+	return events.HandleSubscriptionEvents(ctx, data, eventType, client)
 }
 
 // HandlePolicyEvents to process policy related events
-func (a Agent) HandlePolicyEvents(data []byte, eventType string, client client.Client) {
+func (a Agent) HandlePolicyEvents(ctx context.Context, data []byte, eventType string, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandlePolicyEvents")
-	events.HandlePolicyEvents(data, eventType, client)
+	return events.HandlePolicyEvents(ctx, data, eventType, client)
 }
 
 // HandleAIProviderEvents to process AI Provider related events
-func (a Agent) HandleAIProviderEvents(data []byte, eventType string, client client.Client) {
+func (a Agent) HandleAIProviderEvents(data []byte, eventType string, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandleAIProviderEvents")
-	events.HandleAIProviderEvents(data, eventType, client)
+	return events.HandleAIProviderEvents(data, eventType, client)
 }
 
 // HandleScopeEvents to process scope related events
-func (a Agent) HandleScopeEvents(data []byte, eventType string, client client.Client) {
+func (a Agent) HandleScopeEvents(data []byte, eventType string, client client.Client) error {
 	loggers.LoggerAgent.Infof("Triggered: HandleScopeEvents")
+	return nil
+}
+
+// HandleOrganizationPurge removes every gateway resource of a purged organization
+func (a Agent) HandleOrganizationPurge(organization msg.Organization, report *commonAgent.PurgeReport, client client.Client) {
+	loggers.LoggerAgent.Infof("Triggered: HandleOrganizationPurge")
+	events.HandleOrganizationPurge(organization, report, client)
 }
 
 // HandleKMConfiguration to handle Key Manager configurations
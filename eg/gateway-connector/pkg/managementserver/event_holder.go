@@ -17,7 +17,11 @@
 package managementserver
 
 import (
+	"maps"
+	"sync"
+
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/utils"
 )
 
@@ -25,6 +29,7 @@ var (
 	applicationMap           map[string]Application
 	applicationMappingMap    map[string]ApplicationMapping
 	applicationKeyMappingMap map[string]ApplicationKeyMapping
+	applicationMutex         sync.RWMutex // Mutex for the application, application mapping and key mapping maps
 )
 
 func init() {
@@ -35,16 +40,22 @@ func init() {
 
 // AddApplication adds an application to the applicationMap
 func AddApplication(application Application) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMap[application.UUID] = application
 }
 
 // AddApplicationMapping adds an application mapping to the applicationMappingMap
 func AddApplicationMapping(applicationMapping ApplicationMapping) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMappingMap[applicationMapping.UUID] = applicationMapping
 }
 
 // AddApplicationKeyMapping adds an application key mapping to the applicationKeyMappingMap
 func AddApplicationKeyMapping(applicationKeyMapping ApplicationKeyMapping) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	uuid := utils.GetUniqueIDOfApplicationKeyMapping(applicationKeyMapping.ApplicationUUID, applicationKeyMapping.KeyType, applicationKeyMapping.SecurityScheme, applicationKeyMapping.EnvID, applicationKeyMapping.Organization)
 	loggers.LoggerMgtServer.Infof("Adding application key mapping with uuid: %v", uuid)
 	applicationKeyMappingMap[uuid] = applicationKeyMapping
@@ -52,6 +63,8 @@ func AddApplicationKeyMapping(applicationKeyMapping ApplicationKeyMapping) {
 
 // GetAllApplications returns all the applications in the applicationMap
 func GetAllApplications() []ResolvedApplication {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	var applications []ResolvedApplication
 	for _, application := range applicationMap {
 		resolvedApplication := marshalApplication(application)
@@ -60,7 +73,7 @@ func GetAllApplications() []ResolvedApplication {
 	return applications
 }
 func marshalApplication(application Application) ResolvedApplication {
-	resolvedApplication := ResolvedApplication{UUID: application.UUID, Name: application.Name, Owner: application.Owner, Organization: application.Organization, Attributes: application.Attributes, TimeStamp: application.TimeStamp, SecuritySchemes: make([]SecurityScheme, 0)}
+	resolvedApplication := ResolvedApplication{UUID: application.UUID, Name: application.Name, Owner: application.Owner, Organization: application.Organization, Attributes: application.Attributes, Policy: application.Policy, TimeStamp: application.TimeStamp, SecuritySchemes: make([]SecurityScheme, 0)}
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == application.UUID {
 			securityScheme := SecurityScheme{SecurityScheme: applicationKeyMapping.SecurityScheme, KeyType: applicationKeyMapping.KeyType, EnvID: applicationKeyMapping.EnvID, ApplicationIdentifier: applicationKeyMapping.ApplicationIdentifier}
@@ -72,6 +85,8 @@ func marshalApplication(application Application) ResolvedApplication {
 
 // GetAllApplicationMappings returns all the application mappings in the applicationMappingMap
 func GetAllApplicationMappings() []ApplicationMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	var applicationMappings []ApplicationMapping
 	for _, applicationMapping := range applicationMappingMap {
 		applicationMappings = append(applicationMappings, applicationMapping)
@@ -79,54 +94,81 @@ func GetAllApplicationMappings() []ApplicationMapping {
 	return applicationMappings
 }
 
+// GetApplicationCount returns the number of applications in the applicationMap
+func GetApplicationCount() int {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
+	return len(applicationMap)
+}
+
 // GetApplication returns an application from the applicationMap
 func GetApplication(uuid string) Application {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	return applicationMap[uuid]
 }
 
 // GetApplicationMapping returns an application mapping from the applicationMappingMap
 func GetApplicationMapping(uuid string) ApplicationMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	return applicationMappingMap[uuid]
 }
 
 // GetApplicationKeyMapping returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMapping(uuid string) ApplicationKeyMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	return applicationKeyMappingMap[uuid]
 }
 
 // DeleteApplication deletes an application from the applicationMap
 func DeleteApplication(uuid string) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	delete(applicationMap, uuid)
 }
 
 // DeleteApplicationMapping deletes an application mapping from the applicationMappingMap
 func DeleteApplicationMapping(uuid string) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	delete(applicationMappingMap, uuid)
 }
 
 // DeleteApplicationKeyMapping deletes an application key mapping from the applicationKeyMappingMap
 func DeleteApplicationKeyMapping(uuid string) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	loggers.LoggerMgtServer.Infof("Deleting application key mapping with uuid: %v", uuid)
 	delete(applicationKeyMappingMap, uuid)
 }
 
 // UpdateApplication updates an application in the applicationMap
 func UpdateApplication(uuid string, application Application) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMap[uuid] = application
 }
 
 // UpdateApplicationMapping updates an application mapping in the applicationMappingMap
 func UpdateApplicationMapping(uuid string, applicationMapping ApplicationMapping) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMappingMap[uuid] = applicationMapping
 }
 
 // UpdateApplicationKeyMapping updates an application key mapping in the applicationKeyMappingMap
 func UpdateApplicationKeyMapping(uuid string, applicationKeyMapping ApplicationKeyMapping) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationKeyMappingMap[uuid] = applicationKeyMapping
 }
 
 // GetApplicationKeyMappingByApplicationUUID returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUID(uuid string) ApplicationKeyMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid {
 			return applicationKeyMapping
@@ -137,6 +179,8 @@ func GetApplicationKeyMappingByApplicationUUID(uuid string) ApplicationKeyMappin
 
 // GetApplicationKeyMappingByApplicationUUIDAndEnvID returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUIDAndEnvID(uuid string, envID string) ApplicationKeyMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid && applicationKeyMapping.EnvID == envID {
 			return applicationKeyMapping
@@ -147,6 +191,8 @@ func GetApplicationKeyMappingByApplicationUUIDAndEnvID(uuid string, envID string
 
 // GetApplicationKeyMappingByApplicationUUIDAndSecurityScheme returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUIDAndSecurityScheme(uuid string, securityScheme string) ApplicationKeyMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid && applicationKeyMapping.SecurityScheme == securityScheme {
 			return applicationKeyMapping
@@ -157,6 +203,8 @@ func GetApplicationKeyMappingByApplicationUUIDAndSecurityScheme(uuid string, sec
 
 // GetApplicationKeyMappingByApplicationUUIDAndSecuritySchemeAndEnvID returns an application key mapping from the applicationKeyMappingMap
 func GetApplicationKeyMappingByApplicationUUIDAndSecuritySchemeAndEnvID(uuid string, securityScheme string, envID string) ApplicationKeyMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	for _, applicationKeyMapping := range applicationKeyMappingMap {
 		if applicationKeyMapping.ApplicationUUID == uuid && applicationKeyMapping.SecurityScheme == securityScheme && applicationKeyMapping.EnvID == envID {
 			return applicationKeyMapping
@@ -167,6 +215,8 @@ func GetApplicationKeyMappingByApplicationUUIDAndSecuritySchemeAndEnvID(uuid str
 
 // GetApplicationMappingByApplicationUUID returns an application mapping from the applicationMappingMap
 func GetApplicationMappingByApplicationUUID(uuid string) ApplicationMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	for _, applicationMapping := range applicationMappingMap {
 		if applicationMapping.ApplicationRef == uuid {
 			return applicationMapping
@@ -177,6 +227,8 @@ func GetApplicationMappingByApplicationUUID(uuid string) ApplicationMapping {
 
 // GetApplicationMappingByApplicationUUIDAndSubscriptionUUID returns an application mapping from the applicationMappingMap
 func GetApplicationMappingByApplicationUUIDAndSubscriptionUUID(uuid string, subscriptionUUID string) ApplicationMapping {
+	applicationMutex.RLock()
+	defer applicationMutex.RUnlock()
 	for _, applicationMapping := range applicationMappingMap {
 		if applicationMapping.ApplicationRef == uuid && applicationMapping.SubscriptionRef == subscriptionUUID {
 			return applicationMapping
@@ -187,39 +239,117 @@ func GetApplicationMappingByApplicationUUIDAndSubscriptionUUID(uuid string, subs
 
 // DeleteAllApplications deletes all the applications in the applicationMap
 func DeleteAllApplications() {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMap = make(map[string]Application)
 }
 
 // DeleteAllApplicationMappings deletes all the application mappings in the applicationMappingMap
 func DeleteAllApplicationMappings() {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMappingMap = make(map[string]ApplicationMapping)
 }
 
 // DeleteAllApplicationKeyMappings deletes all the application key mappings in the applicationKeyMappingMap
 func DeleteAllApplicationKeyMappings() {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationKeyMappingMap = make(map[string]ApplicationKeyMapping)
 }
 
 // AddAllApplications adds all the applications in the applicationMap
 func AddAllApplications(applicationMapTemp map[string]Application) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMap = applicationMapTemp
 }
 
 // AddAllApplicationMappings adds all the application mappings in the applicationMappingMap
 func AddAllApplicationMappings(applicationMappingMapTemp map[string]ApplicationMapping) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationMappingMap = applicationMappingMapTemp
 }
 
 // AddAllApplicationKeyMappings adds all the application key mappings in the applicationKeyMappingMap
 func AddAllApplicationKeyMappings(applicationKeyMappingMapTemp map[string]ApplicationKeyMapping) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	applicationKeyMappingMap = applicationKeyMappingMapTemp
 }
 
 // DeleteAllApplicationMappingsByApplicationsUUID deletes all the application mappings in the applicationMappingMap
 func DeleteAllApplicationMappingsByApplicationsUUID(uuid string) {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
 	for _, applicationMapping := range applicationMappingMap {
 		if applicationMapping.UUID == uuid {
 			delete(applicationMappingMap, applicationMapping.UUID)
 		}
 	}
 }
+
+// DeleteApplicationsByOrganization deletes the applications of the organization from the applicationMap and
+// returns the deleted applications
+func DeleteApplicationsByOrganization(organization string) []Application {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
+	var deleted []Application
+	for uuid, application := range applicationMap {
+		if application.Organization == organization {
+			delete(applicationMap, uuid)
+			deleted = append(deleted, application)
+		}
+	}
+	return deleted
+}
+
+// DeleteApplicationMappingsByOrganization deletes the application mappings of the organization from the
+// applicationMappingMap and returns the deleted application mappings
+func DeleteApplicationMappingsByOrganization(organization string) []ApplicationMapping {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
+	var deleted []ApplicationMapping
+	for uuid, applicationMapping := range applicationMappingMap {
+		if applicationMapping.Organization == organization {
+			delete(applicationMappingMap, uuid)
+			deleted = append(deleted, applicationMapping)
+		}
+	}
+	return deleted
+}
+
+// DeleteApplicationKeyMappingsByOrganization deletes the application key mappings of the organization from the
+// applicationKeyMappingMap and returns the deleted application key mappings
+func DeleteApplicationKeyMappingsByOrganization(organization string) []ApplicationKeyMapping {
+	applicationMutex.Lock()
+	defer applicationMutex.Unlock()
+	var deleted []ApplicationKeyMapping
+	for uuid, applicationKeyMapping := range applicationKeyMappingMap {
+		if applicationKeyMapping.Organization == organization {
+			delete(applicationKeyMappingMap, uuid)
+			deleted = append(deleted, applicationKeyMapping)
+		}
+	}
+	return deleted
+}
+
+// RegisterSnapshotStores registers the application maps to be persisted in the snapshot
+func RegisterSnapshotStores() {
+	snapshot.Register("applications", func() map[string]Application {
+		applicationMutex.RLock()
+		defer applicationMutex.RUnlock()
+		return maps.Clone(applicationMap)
+	}, AddAllApplications)
+	snapshot.Register("applicationMappings", func() map[string]ApplicationMapping {
+		applicationMutex.RLock()
+		defer applicationMutex.RUnlock()
+		return maps.Clone(applicationMappingMap)
+	}, AddAllApplicationMappings)
+	snapshot.Register("applicationKeyMappings", func() map[string]ApplicationKeyMapping {
+		applicationMutex.RLock()
+		defer applicationMutex.RUnlock()
+		return maps.Clone(applicationKeyMappingMap)
+	}, AddAllApplicationKeyMappings)
+}
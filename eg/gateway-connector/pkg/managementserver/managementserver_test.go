@@ -265,3 +265,28 @@ func TestDeleteAllApplicationMappingsByApplicationsUUID(t *testing.T) {
 		assert.NotEqual(t, uuid, appMapping.ApplicationRef)
 	}
 }
+
+func TestDeleteByOrganization(t *testing.T) {
+	applicationMap = map[string]Application{
+		"app1": {UUID: "app1", Organization: "Org1"},
+		"app2": {UUID: "app2", Organization: "Org2"},
+	}
+	applicationMappingMap = map[string]ApplicationMapping{
+		"mapping1": {UUID: "mapping1", ApplicationRef: "app1", Organization: "Org1"},
+	}
+	applicationKeyMappingMap = map[string]ApplicationKeyMapping{
+		"keyMapping1": {ApplicationUUID: "app1", Organization: "Org1"},
+		"keyMapping2": {ApplicationUUID: "app2", Organization: "Org2"},
+	}
+
+	deletedApplications := DeleteApplicationsByOrganization("Org1")
+	assert.Len(t, deletedApplications, 1)
+	assert.Equal(t, "app1", deletedApplications[0].UUID)
+	assert.Len(t, DeleteApplicationMappingsByOrganization("Org1"), 1)
+	assert.Len(t, DeleteApplicationKeyMappingsByOrganization("Org1"), 1)
+
+	assert.Contains(t, applicationMap, "app2")
+	assert.Empty(t, applicationMappingMap)
+	assert.Len(t, applicationKeyMappingMap, 1)
+	assert.Empty(t, DeleteApplicationsByOrganization("Org1"))
+}
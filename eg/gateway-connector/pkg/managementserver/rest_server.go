@@ -77,7 +77,7 @@ func StartInternalServer(port uint) {
 				return
 			}
 			// Delete the api
-			errorUndeployRevision := utils.DeleteAPIRevision(event.API.APIUUID, event.API.RevisionID, string(jsonPayload))
+			errorUndeployRevision := utils.DeleteAPIRevision(c.Request.Context(), event.API.APIUUID, event.API.RevisionID, string(jsonPayload))
 			if errorUndeployRevision != nil {
 				logger.LoggerMgtServer.Errorf("Error while undeploying api revision. RevisionId: %s, API ID: %s . Sending error response to Adapter.", event.API.RevisionID, event.API.APIUUID)
 				c.JSON(http.StatusServiceUnavailable, errorUndeployRevision.Error())
@@ -138,7 +138,7 @@ func StartInternalServer(port uint) {
 				logger.LoggerMgtServer.Errorf("Error while creating apim zip file for api uuid: %s. Error: %+v", event.API.APIUUID, err)
 			}
 			logger.LoggerMgtServer.Infof("Creating zip file without endpoints")
-			id, revisionID, err := utils.ImportAPI(fmt.Sprintf("admin-%s-%s.zip", event.API.APIName, event.API.APIVersion), &buf)
+			id, revisionID, err := utils.ImportAPI(c.Request.Context(), fmt.Sprintf("admin-%s-%s.zip", event.API.APIName, event.API.APIVersion), &buf)
 			if err != nil {
 				logger.LoggerMgtServer.Errorf("Error while importing API. Sending error response to Adapter.")
 				c.JSON(http.StatusServiceUnavailable, err.Error())
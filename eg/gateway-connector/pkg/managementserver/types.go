@@ -24,6 +24,7 @@ type Application struct {
 	Owner        string            `json:"owner,omitempty"`
 	Organization string            `json:"organization,omitempty"`
 	Attributes   map[string]string `json:"attributes,omitempty"`
+	Policy       string            `json:"policy,omitempty"`
 	TimeStamp    int64             `json:"timeStamp,omitempty"`
 }
 
@@ -44,6 +45,7 @@ type ResolvedApplication struct {
 	Owner           string            `json:"owner,omitempty"`
 	Organization    string            `json:"organization,omitempty"`
 	Attributes      map[string]string `json:"attributes,omitempty"`
+	Policy          string            `json:"policy,omitempty"`
 	TimeStamp       int64             `json:"timeStamp,omitempty"`
 	SecuritySchemes []SecurityScheme  `json:"securitySchemes,omitempty"`
 }
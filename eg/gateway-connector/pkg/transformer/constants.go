@@ -27,4 +27,49 @@ const (
 	k8RevisionField             = "revisionID"
 	k8APIUuidField              = "apiUUID"
 	k8sRateLimitPolicyNameField = "rateLimitPolicyName"
+
+	// Labels and annotations used by the in-process CR generation
+	kgwOrganizationLabel = "kgw.wso2.com/organization"
+	kgwEnvTypeAnnotation = "gateway.envoyproxy.io/kgw-envtype"
+	productionEnvType    = "PRODUCTION"
+	sandboxEnvType       = "SANDBOX"
+
+	// Groups, kinds and versions of the generated CRs
+	dpGroup               = "dp.wso2.com"
+	dpAPIVersion          = "dp.wso2.com/v2alpha1"
+	envoyGatewayGroup     = "gateway.envoyproxy.io"
+	envoyGatewayVersion   = "gateway.envoyproxy.io/v1alpha1"
+	gatewayAPIGroup       = "gateway.networking.k8s.io"
+	routeMetadataKind     = "RouteMetadata"
+	routePolicyKind       = "RoutePolicy"
+	backendKind           = "Backend"
+	httpRouteKind         = "HTTPRoute"
+	grpcRouteKind         = "GRPCRoute"
+	securityPolicyKind    = "SecurityPolicy"
+	backendTrafficKind    = "BackendTrafficPolicy"
+	envoyExtensionKind    = "EnvoyExtensionPolicy"
+	definitionConfigKey   = "definition"
+	defaultDefinitionPath = "/definition"
+
+	// Gateway agent configuration keys and defaults used by the in-process CR generation
+	gatewayNameConfig          = "gatewayName"
+	defaultGatewayName         = "wso2-kgw-default"
+	extProcServiceNameConfig   = "extProcServiceName"
+	extProcServicePortConfig   = "extProcServicePort"
+	defaultExtProcServicePort  = 8081
+	maxRulesPerHTTPRoute       = 16
+	authTypeOAuth2             = "OAuth2"
+	authRequiredOptional       = "optional"
+	rateLimitTypeGlobal        = "Global"
+	tlsWellKnownCACertificates = "System"
+	methodPseudoHeader         = ":method"
+	pathPseudoHeader           = ":path"
+)
+
+const (
+	// ApplicationClientIDHeader is the header the JWT providers of the generated SecurityPolicies set to the client
+	// ID of the access token, which identifies the application of the request in the application rate limit rules
+	ApplicationClientIDHeader = "x-wso2-client-id"
+	// ClientIDClaim is the claim of a JWT access token holding the client ID the token was issued to (RFC 9068)
+	ClientIDClaim = "client_id"
 )
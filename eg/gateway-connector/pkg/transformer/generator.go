@@ -0,0 +1,823 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	asyncGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/async"
+	graphqlGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/graphql"
+	grpcGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/grpc"
+	httpGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/http"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	resourceTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	logger "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/loggers"
+
+	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1a3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+
+	"gopkg.in/yaml.v2"
+)
+
+// generatorContext holds the state shared while generating the CRs of a single API
+type generatorContext struct {
+	k8sArtifact    *K8sArtifacts
+	apkConf        resourceTypes.APKConf
+	organization   resourceTypes.Organization
+	gatewayConfig  resourceTypes.GatewayConfigurations
+	uniqueName     string
+	apiRoutePolicy *dpv2alpha1.RoutePolicy
+	// securedTargets holds the route rules which require authentication when only a subset of the
+	// operations of the API is secured
+	securedTargets []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName
+	allSecured     bool
+	// operationRateLimitRules holds the rate limits of the operations, which are enforced by the API level
+	// BackendTrafficPolicy as Envoy Gateway accepts a single BackendTrafficPolicy per route
+	operationRateLimitRules []gatewayv1alpha1.RateLimitRule
+}
+
+// generateK8sArtifacts generates the Envoy Gateway CRs of an API from its apk-conf in-process using the
+// k8s-resource-lib route generators, without calling the external config deployer
+func generateK8sArtifacts(apkConf string, apiDefinition string, organizationID string, conf *config.Config, k8sArtifact *K8sArtifacts) error {
+	var apkConfig resourceTypes.APKConf
+	if err := yaml.Unmarshal([]byte(apkConf), &apkConfig); err != nil {
+		return fmt.Errorf("unable to parse the apk-conf: %w", err)
+	}
+	// Key managers are resolved from the key manager cache by the common transformer and are not part of the
+	// k8s-resource-lib apk-conf model
+	var keyManagerConfig struct {
+		KeyManagers []transformer.KeyManager `yaml:"keyManagers,omitempty"`
+	}
+	if err := yaml.Unmarshal([]byte(apkConf), &keyManagerConfig); err != nil {
+		return fmt.Errorf("unable to parse the key managers of the apk-conf: %w", err)
+	}
+	if apkConfig.Name == "" || apkConfig.Version == "" || organizationID == "" {
+		return errors.New("api name, version and organization are required to generate the CRs")
+	}
+	endpoints := utils.GetEndpoints(apkConfig)
+	if len(endpoints[constants.ProductionType]) == 0 && len(endpoints[constants.SandboxType]) == 0 {
+		return errors.New("no production or sandbox endpoints found in the apk-conf")
+	}
+
+	k8sArtifact.RouteMetadata = generateRouteMetadata(apkConfig, organizationID)
+	ctx := &generatorContext{
+		k8sArtifact:   k8sArtifact,
+		apkConf:       apkConfig,
+		organization:  resourceTypes.Organization{Name: organizationID},
+		gatewayConfig: resourceTypes.GatewayConfigurations{Name: getGatewayAgentConfig(conf, gatewayNameConfig, defaultGatewayName)},
+		uniqueName:    generateUniqueNameFormAPI(k8sArtifact.RouteMetadata),
+		allSecured:    true,
+	}
+	k8sArtifact.RouteMetadata.Name = ctx.uniqueName
+	addDefinitionConfigMap(ctx, apiDefinition)
+
+	if apkConfig.APIPolicies != nil && (len(apkConfig.APIPolicies.Request) > 0 || len(apkConfig.APIPolicies.Response) > 0) {
+		ctx.apiRoutePolicy = generateRoutePolicy(ctx, ctx.uniqueName+"-api-policy", apkConfig.APIPolicies)
+	}
+
+	for _, endpointType := range []string{constants.ProductionType, constants.SandboxType} {
+		endpointsForType, ok := endpoints[endpointType]
+		if !ok || len(endpointsForType) == 0 {
+			continue
+		}
+		var err error
+		if apkConfig.Type == constants.APITypeGrpc {
+			err = generateGRPCRoutes(ctx, endpointsForType, endpointType)
+		} else {
+			err = generateHTTPRoutes(ctx, endpointsForType, endpointType)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to generate %s routes: %w", endpointType, err)
+		}
+	}
+
+	targetRefs := generateRouteTargetRefs(k8sArtifact)
+	generateSecurityPolicy(ctx, keyManagerConfig.KeyManagers, targetRefs)
+	rateLimitRules := ctx.operationRateLimitRules
+	if apkConfig.RateLimit != nil && apkConfig.RateLimit.RequestsPerUnit > 0 {
+		// The API level rate limit is shared by all the routes of the API
+		apiRateLimitRule := generateRateLimitRule(*apkConfig.RateLimit, nil)
+		apiRateLimitRule.Shared = ptr.To(true)
+		rateLimitRules = append([]gatewayv1alpha1.RateLimitRule{apiRateLimitRule}, rateLimitRules...)
+	}
+	if len(rateLimitRules) > 0 {
+		addRateLimitPolicy(k8sArtifact, ctx.uniqueName+"-api-ratelimit", rateLimitRules, targetRefs)
+	}
+	generateEnvoyExtensionPolicy(ctx, conf, targetRefs)
+
+	logger.LoggerTransformer.Infof("Generated CRs in-process for API %s:%s | HTTPRoutes: %d | GRPCRoutes: %d | Backends: %d | SecurityPolicies: %d | BackendTrafficPolicies: %d | RoutePolicies: %d | EnvoyExtensionPolicies: %d",
+		apkConfig.Name, apkConfig.Version, len(k8sArtifact.HTTPRoutes), len(k8sArtifact.GRPCRoutes), len(k8sArtifact.Backends),
+		len(k8sArtifact.SecurityPolicies), len(k8sArtifact.BackendTrafficPolicies), len(k8sArtifact.RoutePolicies), len(k8sArtifact.EnvoyExtensionPolicies))
+	return nil
+}
+
+// generateRouteMetadata generates the RouteMetadata CR which carries the API level metadata of the routes
+func generateRouteMetadata(apkConf resourceTypes.APKConf, organizationID string) *dpv2alpha1.RouteMetadata {
+	definitionPath := apkConf.DefinitionPath
+	if definitionPath == "" {
+		definitionPath = defaultDefinitionPath
+	}
+	apiProperties := make([]dpv2alpha1.Property, 0)
+	if apkConf.AdditionalProperties != nil {
+		for _, property := range *apkConf.AdditionalProperties {
+			apiProperties = append(apiProperties, dpv2alpha1.Property{Name: property.Name, Value: property.Value})
+		}
+	}
+	return &dpv2alpha1.RouteMetadata{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       routeMetadataKind,
+			APIVersion: dpAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: make(map[string]string),
+		},
+		Spec: dpv2alpha1.RouteMetadataSpec{
+			API: dpv2alpha1.API{
+				Name:           apkConf.Name,
+				Version:        apkConf.Version,
+				Organization:   organizationID,
+				Type:           apkConf.Type,
+				Environment:    apkConf.Environment,
+				Context:        utils.GeneratePath(apkConf.BasePath, apkConf.Version),
+				APIProperties:  apiProperties,
+				DefinitionPath: definitionPath,
+				UUID:           apkConf.ID,
+			},
+		},
+	}
+}
+
+// addDefinitionConfigMap stores the API definition in a ConfigMap referenced by the RouteMetadata
+func addDefinitionConfigMap(ctx *generatorContext, apiDefinition string) {
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ctx.uniqueName + "-definition",
+			Labels: make(map[string]string),
+		},
+		Data: map[string]string{definitionConfigKey: apiDefinition},
+	}
+	ctx.k8sArtifact.ConfigMaps[configMap.Name] = configMap
+	ctx.k8sArtifact.RouteMetadata.Spec.API.DefinitionFileRef = &gwapiv1.LocalObjectReference{
+		Kind: gwapiv1.Kind("ConfigMap"),
+		Name: gwapiv1.ObjectName(configMap.Name),
+	}
+}
+
+// generateHTTPRoutes generates the HTTPRoutes of a REST, GraphQL or streaming API for the given endpoint type
+func generateHTTPRoutes(ctx *generatorContext, endpoints []resourceTypes.EndpointDetails, endpointType string) error {
+	generateBackendRefs := func(_ *httpGenerator.K8sArtifacts, endpoints []resourceTypes.EndpointDetails, _ resourceTypes.Operation, _ string) []gwapiv1.HTTPBackendRef {
+		backendRefs := make([]gwapiv1.HTTPBackendRef, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			backend := generateBackend(ctx, endpoint, endpointType)
+			backendRefs = append(backendRefs, gwapiv1.HTTPBackendRef{BackendRef: gwapiv1.BackendRef{BackendObjectReference: generateBackendObjectReference(backend)}})
+		}
+		return backendRefs
+	}
+
+	operations := make([]resourceTypes.Operation, 0)
+	if ctx.apkConf.Operations != nil {
+		operations = *ctx.apkConf.Operations
+	}
+
+	switch {
+	case ctx.apkConf.Type == constants.APITypeGraphql:
+		gen := graphqlGenerator.Generator()
+		gen.HTTPGenerator.GenerateHTTPBackEndRef = generateBackendRefs
+		artifacts, err := gen.GenerateGraphQLRoute(ctx.apkConf, ctx.organization, ctx.gatewayConfig, &endpoints, endpointType, ctx.uniqueName)
+		if err != nil {
+			return err
+		}
+		finalizeHTTPRoute(ctx, artifacts.HTTPRoute, endpointType, artifacts.Operations)
+	case asyncGenerator.IsStreamingAPI(ctx.apkConf.Type):
+		gen := asyncGenerator.Generator()
+		gen.HTTPGenerator.GenerateHTTPBackEndRef = generateBackendRefs
+		artifacts, err := gen.GenerateAsyncRoute(ctx.apkConf, ctx.organization, ctx.gatewayConfig, operations, &endpoints, endpointType, ctx.uniqueName, 0)
+		if err != nil {
+			return err
+		}
+		finalizeHTTPRoute(ctx, artifacts.HTTPRoute, endpointType, artifacts.Operations)
+	default:
+		gen := httpGenerator.Generator()
+		gen.GenerateHTTPBackEndRef = generateBackendRefs
+		// A HTTPRoute can have at most 16 rules, hence the operations are split across multiple routes
+		for count, start := 0, 0; start < len(operations); count, start = count+1, start+maxRulesPerHTTPRoute {
+			end := min(start+maxRulesPerHTTPRoute, len(operations))
+			artifacts, err := gen.GenerateHTTPRoute(ctx.apkConf, ctx.organization, ctx.gatewayConfig, operations[start:end], &endpoints, endpointType, ctx.uniqueName, count)
+			if err != nil {
+				return err
+			}
+			finalizeHTTPRoute(ctx, artifacts.HTTPRoute, endpointType, operations[start:end])
+		}
+	}
+	return nil
+}
+
+// finalizeHTTPRoute links the generated HTTPRoute to the RouteMetadata and the RoutePolicies and adds the
+// operation level policies. The operations are those the rules of the route are generated from, in the order of the rules.
+// The operation level rate limits are collected to be enforced by the API level BackendTrafficPolicy.
+func finalizeHTTPRoute(ctx *generatorContext, httpRoute *gwapiv1.HTTPRoute, endpointType string, operations []resourceTypes.Operation) {
+	httpRoute.TypeMeta = metav1.TypeMeta{Kind: httpRouteKind, APIVersion: gatewayAPIGroup + "/v1"}
+	httpRoute.Labels = make(map[string]string)
+	httpRoute.Annotations = map[string]string{kgwEnvTypeAnnotation: getEnvType(endpointType)}
+	// Attach the route to all the listeners of the gateway
+	for i := range httpRoute.Spec.ParentRefs {
+		httpRoute.Spec.ParentRefs[i].SectionName = nil
+	}
+	for i := range httpRoute.Spec.Rules {
+		rule := &httpRoute.Spec.Rules[i]
+		rule.Filters = append(rule.Filters, generateHTTPExtensionRefFilter(routeMetadataKind, ctx.k8sArtifact.RouteMetadata.Name))
+		if ctx.apiRoutePolicy != nil {
+			rule.Filters = append(rule.Filters, generateHTTPExtensionRefFilter(routePolicyKind, ctx.apiRoutePolicy.Name))
+		}
+		if operations == nil || i >= len(operations) {
+			continue
+		}
+		operation := operations[i]
+		ruleName := gwapiv1.SectionName("operation-" + strconv.Itoa(i))
+		rule.Name = &ruleName
+		targetRef := gwapiv1a2.LocalPolicyTargetReferenceWithSectionName{
+			LocalPolicyTargetReference: gwapiv1a2.LocalPolicyTargetReference{
+				Group: gwapiv1a2.Group(gatewayAPIGroup),
+				Kind:  gwapiv1a2.Kind(httpRouteKind),
+				Name:  gwapiv1a2.ObjectName(httpRoute.Name),
+			},
+			SectionName: &ruleName,
+		}
+		if operation.Secured {
+			ctx.securedTargets = append(ctx.securedTargets, targetRef)
+		} else {
+			ctx.allSecured = false
+		}
+		// Operation level policies are ignored when API level policies are present
+		if ctx.apiRoutePolicy == nil && operation.OperationPolicies != nil &&
+			(len(operation.OperationPolicies.Request) > 0 || len(operation.OperationPolicies.Response) > 0) {
+			policyName := strings.Join([]string{ctx.uniqueName, generateSHA1Hash(operation.Verb + operation.Target)[:8], "policy"}, "-")
+			routePolicy := generateRoutePolicy(ctx, policyName, operation.OperationPolicies)
+			rule.Filters = append(rule.Filters, generateHTTPExtensionRefFilter(routePolicyKind, routePolicy.Name))
+		}
+		if operation.RateLimit != nil && operation.RateLimit.RequestsPerUnit > 0 {
+			// The rule of the operation is shared by its production and sandbox routes
+			rateLimitRule := generateRateLimitRule(*operation.RateLimit, generateRuleClientSelectors(rule))
+			if !slices.ContainsFunc(ctx.operationRateLimitRules, func(existing gatewayv1alpha1.RateLimitRule) bool {
+				return equality.Semantic.DeepEqual(existing, rateLimitRule)
+			}) {
+				ctx.operationRateLimitRules = append(ctx.operationRateLimitRules, rateLimitRule)
+			}
+		}
+	}
+	ctx.k8sArtifact.HTTPRoutes[httpRoute.Name] = httpRoute
+}
+
+// generateGRPCRoutes generates the GRPCRoutes of a gRPC API for the given endpoint type
+func generateGRPCRoutes(ctx *generatorContext, endpoints []resourceTypes.EndpointDetails, endpointType string) error {
+	gen := grpcGenerator.Generator()
+	gen.GenerateGRPCBackEndRef = func(endpoints []resourceTypes.EndpointDetails, _ resourceTypes.Operation) []gwapiv1.GRPCBackendRef {
+		backendRefs := make([]gwapiv1.GRPCBackendRef, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			backend := generateBackend(ctx, endpoint, endpointType)
+			backendRefs = append(backendRefs, gwapiv1.GRPCBackendRef{BackendRef: gwapiv1.BackendRef{BackendObjectReference: generateBackendObjectReference(backend)}})
+		}
+		return backendRefs
+	}
+
+	operations := make([]resourceTypes.Operation, 0)
+	if ctx.apkConf.Operations != nil {
+		operations = *ctx.apkConf.Operations
+	}
+	for count, start := 0, 0; start < len(operations); count, start = count+1, start+maxRulesPerHTTPRoute {
+		end := min(start+maxRulesPerHTTPRoute, len(operations))
+		route, err := gen.GenerateGRPCRoute(ctx.apkConf, ctx.organization, ctx.gatewayConfig, operations[start:end], &endpoints, endpointType, ctx.uniqueName, count)
+		if err != nil {
+			return err
+		}
+		grpcRoute := gwapiv1a2.GRPCRoute(*route)
+		grpcRoute.TypeMeta = metav1.TypeMeta{Kind: grpcRouteKind, APIVersion: gatewayAPIGroup + "/v1alpha2"}
+		grpcRoute.Labels = make(map[string]string)
+		grpcRoute.Annotations = map[string]string{kgwEnvTypeAnnotation: getEnvType(endpointType)}
+		for i := range grpcRoute.Spec.ParentRefs {
+			grpcRoute.Spec.ParentRefs[i].SectionName = nil
+		}
+		for i := range grpcRoute.Spec.Rules {
+			grpcRoute.Spec.Rules[i].Filters = append(grpcRoute.Spec.Rules[i].Filters, gwapiv1.GRPCRouteFilter{
+				Type:         gwapiv1.GRPCRouteFilterExtensionRef,
+				ExtensionRef: generateExtensionRef(routeMetadataKind, ctx.k8sArtifact.RouteMetadata.Name),
+			})
+		}
+		ctx.k8sArtifact.GRPCRoutes[grpcRoute.Name] = &grpcRoute
+	}
+	return nil
+}
+
+// generateBackend returns the Backend CR of the given endpoint, creating it if it is not generated yet
+func generateBackend(ctx *generatorContext, endpoint resourceTypes.EndpointDetails, endpointType string) *gatewayv1alpha1.Backend {
+	backendName := strings.Join([]string{ctx.uniqueName, endpointType, "backend", generateSHA1Hash(endpoint.URL)[:8]}, "-")
+	if backend, ok := ctx.k8sArtifact.Backends[backendName]; ok {
+		return backend
+	}
+	backend := &gatewayv1alpha1.Backend{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       backendKind,
+			APIVersion: envoyGatewayVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   backendName,
+			Labels: make(map[string]string),
+		},
+		Spec: gatewayv1alpha1.BackendSpec{
+			Endpoints: []gatewayv1alpha1.BackendEndpoint{
+				{
+					FQDN: &gatewayv1alpha1.FQDNEndpoint{
+						Hostname: utils.GetHost(resourceTypes.EndpointURL(endpoint.URL)),
+						Port:     int32(utils.GetPort(endpoint.URL)),
+					},
+				},
+			},
+		},
+	}
+	if utils.GetProtocol(endpoint.URL) == "https" {
+		backend.Spec.TLS = generateBackendTLSSettings(ctx, endpoint, endpointType)
+	}
+	ctx.k8sArtifact.Backends[backendName] = backend
+	return backend
+}
+
+// generateBackendTLSSettings returns the TLS settings of a Backend. The certificate of the endpoint is trusted when
+// one is configured, which is stored in a ConfigMap named after the certificate file, and the system CA
+// certificates otherwise.
+func generateBackendTLSSettings(ctx *generatorContext, endpoint resourceTypes.EndpointDetails, endpointType string) *gatewayv1alpha1.BackendTLSSettings {
+	if certificate := getEndpointCertificate(ctx.apkConf, endpoint, endpointType); certificate.Key != "" {
+		configName := strings.Split(path.Base(certificate.Key), ".")[0]
+		return &gatewayv1alpha1.BackendTLSSettings{
+			CACertificateRefs: []gwapiv1.LocalObjectReference{
+				{
+					Group: gwapiv1.Group(""),
+					Kind:  gwapiv1.Kind("ConfigMap"),
+					Name:  gwapiv1.ObjectName(ctx.uniqueName + "-" + configName),
+				},
+			},
+		}
+	}
+	return &gatewayv1alpha1.BackendTLSSettings{
+		WellKnownCACertificates: ptr.To(gwapiv1a3.WellKnownCACertificatesType(tlsWellKnownCACertificates)),
+	}
+}
+
+// getEndpointCertificate returns the certificate configured for the given endpoint in the apk-conf
+func getEndpointCertificate(apkConf resourceTypes.APKConf, endpoint resourceTypes.EndpointDetails, endpointType string) resourceTypes.EndpointCertificate {
+	if apkConf.EndpointConfigurations == nil {
+		return resourceTypes.EndpointCertificate{}
+	}
+	endpointConfigs := apkConf.EndpointConfigurations.Production
+	if endpointType == constants.SandboxType {
+		endpointConfigs = apkConf.EndpointConfigurations.Sandbox
+	}
+	if endpointConfigs == nil {
+		return resourceTypes.EndpointCertificate{}
+	}
+	for _, endpointConfig := range *endpointConfigs {
+		if endpointURL, ok := endpointConfig.Endpoint.(resourceTypes.EndpointURL); ok && string(endpointURL) == endpoint.URL {
+			return endpointConfig.EndCertificate
+		}
+	}
+	return resourceTypes.EndpointCertificate{}
+}
+
+// generateBackendObjectReference returns the reference used by the routes to point to a Backend CR
+func generateBackendObjectReference(backend *gatewayv1alpha1.Backend) gwapiv1.BackendObjectReference {
+	group := gwapiv1.Group(envoyGatewayGroup)
+	kind := gwapiv1.Kind(backendKind)
+	return gwapiv1.BackendObjectReference{
+		Group: &group,
+		Kind:  &kind,
+		Name:  gwapiv1.ObjectName(backend.Name),
+	}
+}
+
+// generateRoutePolicy generates the RoutePolicy CR carrying the mediation policies of an API or an operation
+func generateRoutePolicy(ctx *generatorContext, name string, policies *resourceTypes.OperationPolicies) *dpv2alpha1.RoutePolicy {
+	if routePolicy, ok := ctx.k8sArtifact.RoutePolicies[name]; ok {
+		return routePolicy
+	}
+	routePolicy := &dpv2alpha1.RoutePolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       routePolicyKind,
+			APIVersion: dpAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: make(map[string]string),
+		},
+		Spec: dpv2alpha1.RoutePolicySpec{
+			RequestMediation:  generateMediations(policies.Request),
+			ResponseMediation: generateMediations(policies.Response),
+		},
+	}
+	ctx.k8sArtifact.RoutePolicies[name] = routePolicy
+	return routePolicy
+}
+
+// generateMediations maps the apk-conf operation policies to RoutePolicy mediations
+func generateMediations(policies []resourceTypes.OperationPolicy) []*dpv2alpha1.Mediation {
+	mediations := make([]*dpv2alpha1.Mediation, 0, len(policies))
+	for _, policy := range policies {
+		mediation := &dpv2alpha1.Mediation{
+			PolicyName:    policy.PolicyName,
+			PolicyID:      policy.PolicyID,
+			PolicyVersion: policy.PolicyVersion,
+			Parameters:    make([]*dpv2alpha1.Parameter, 0),
+		}
+		if parameters, ok := normalizeYAMLValue(policy.Parameters).(map[string]interface{}); ok {
+			keys := make([]string, 0, len(parameters))
+			for key := range parameters {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				mediation.Parameters = append(mediation.Parameters, &dpv2alpha1.Parameter{Key: key, Value: encodeParameterValue(parameters[key])})
+			}
+		}
+		mediations = append(mediations, mediation)
+	}
+	return mediations
+}
+
+// normalizeYAMLValue converts the map[interface{}]interface{} values produced by yaml.v2 to JSON compatible maps
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(typedValue))
+		for key, val := range typedValue {
+			normalized[fmt.Sprint(key)] = normalizeYAMLValue(val)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(typedValue))
+		for key, val := range typedValue {
+			normalized[key] = normalizeYAMLValue(val)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(typedValue))
+		for _, val := range typedValue {
+			normalized = append(normalized, normalizeYAMLValue(val))
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+// encodeParameterValue returns string values as they are and JSON encodes the rest
+func encodeParameterValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		logger.LoggerTransformer.Errorf("Error encoding the policy parameter value %v: %v", value, err)
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// generateSecurityPolicy generates the SecurityPolicy CR carrying the JWT authentication and the CORS configuration
+func generateSecurityPolicy(ctx *generatorContext, keyManagers []transformer.KeyManager, targetRefs []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName) {
+	spec := gatewayv1alpha1.SecurityPolicySpec{}
+	if ctx.apkConf.Authentication != nil {
+		for _, authentication := range *ctx.apkConf.Authentication {
+			if !authentication.Enabled || authentication.AuthType != authTypeOAuth2 {
+				continue
+			}
+			if len(keyManagers) == 0 {
+				logger.LoggerTransformer.Warnf("No key managers found for the API %s:%s. Skipping the JWT configuration.", ctx.apkConf.Name, ctx.apkConf.Version)
+				continue
+			}
+			spec.JWT = generateJWT(authentication, keyManagers)
+		}
+	}
+	corsConfig := ctx.apkConf.CorsConfig
+	if corsConfig != nil && corsConfig.CORSConfigurationEnabled {
+		allowOrigins := make([]gatewayv1alpha1.Origin, 0, len(corsConfig.AccessControlAllowOrigins))
+		for _, origin := range corsConfig.AccessControlAllowOrigins {
+			allowOrigins = append(allowOrigins, gatewayv1alpha1.Origin(origin))
+		}
+		spec.CORS = &gatewayv1alpha1.CORS{
+			AllowOrigins:     allowOrigins,
+			AllowMethods:     corsConfig.AccessControlAllowMethods,
+			AllowHeaders:     corsConfig.AccessControlAllowHeaders,
+			AllowCredentials: ptr.To(corsConfig.AccessControlAllowCredentials),
+		}
+	}
+	if spec.JWT == nil && spec.CORS == nil {
+		return
+	}
+
+	if spec.JWT != nil && !ctx.allSecured {
+		// Authentication is enforced only on the secured operations. As a policy can either carry CORS for the whole API
+		// or JWT for a subset of the rules, the two are split into separate policies in that case.
+		if len(ctx.securedTargets) > 0 {
+			addSecurityPolicy(ctx, ctx.uniqueName+"-jwt-security-policy", gatewayv1alpha1.SecurityPolicySpec{JWT: spec.JWT}, ctx.securedTargets)
+		}
+		spec.JWT = nil
+		if spec.CORS == nil {
+			return
+		}
+	}
+	addSecurityPolicy(ctx, ctx.uniqueName+"-security-policy", spec, targetRefs)
+}
+
+// addSecurityPolicy adds a SecurityPolicy CR with the given spec targeting the given routes
+func addSecurityPolicy(ctx *generatorContext, name string, spec gatewayv1alpha1.SecurityPolicySpec, targetRefs []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName) {
+	spec.PolicyTargetReferences = gatewayv1alpha1.PolicyTargetReferences{TargetRefs: targetRefs}
+	securityPolicy := &gatewayv1alpha1.SecurityPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       securityPolicyKind,
+			APIVersion: envoyGatewayVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			// Used to find the policies of an organization when the key managers are updated
+			Labels: map[string]string{kgwOrganizationLabel: ctx.organization.Name},
+		},
+		Spec: spec,
+	}
+	ctx.k8sArtifact.SecurityPolicies[securityPolicy.Name] = securityPolicy
+}
+
+// generateJWT generates the JWT configuration of a SecurityPolicy from the key managers of the API
+func generateJWT(authentication resourceTypes.AuthConfiguration, keyManagers []transformer.KeyManager) *gatewayv1alpha1.JWT {
+	providers := make([]gatewayv1alpha1.JWTProvider, 0, len(keyManagers))
+	for _, keyManager := range keyManagers {
+		provider := gatewayv1alpha1.JWTProvider{
+			Name:      keyManager.Name,
+			Issuer:    keyManager.Issuer,
+			Audiences: authentication.Audience,
+			RemoteJWKS: &gatewayv1alpha1.RemoteJWKS{
+				URI: keyManager.JWKSEndpoint,
+			},
+		}
+		if keyManager.K8sBackend != nil && keyManager.K8sBackend.Name != "" {
+			group := gwapiv1.Group(envoyGatewayGroup)
+			kind := gwapiv1.Kind(backendKind)
+			backendRef := gatewayv1alpha1.BackendRef{
+				BackendObjectReference: gwapiv1.BackendObjectReference{
+					Group: &group,
+					Kind:  &kind,
+					Name:  gwapiv1.ObjectName(keyManager.K8sBackend.Name),
+				},
+			}
+			if keyManager.K8sBackend.Namespace != "" {
+				backendRef.Namespace = ptr.To(gwapiv1.Namespace(keyManager.K8sBackend.Namespace))
+			}
+			if keyManager.K8sBackend.Port > 0 {
+				backendRef.Port = ptr.To(gwapiv1.PortNumber(keyManager.K8sBackend.Port))
+			}
+			provider.RemoteJWKS.BackendRefs = []gatewayv1alpha1.BackendRef{backendRef}
+		}
+		for _, claim := range keyManager.ClaimMapping {
+			provider.ClaimToHeaders = append(provider.ClaimToHeaders, gatewayv1alpha1.ClaimToHeader{
+				Header: claim.LocalClaim,
+				Claim:  claim.RemoteClaim,
+			})
+		}
+		// The client ID identifies the application of the request in the application rate limit rules
+		provider.ClaimToHeaders = append(provider.ClaimToHeaders, gatewayv1alpha1.ClaimToHeader{
+			Header: ApplicationClientIDHeader,
+			Claim:  ClientIDClaim,
+		})
+		if authentication.HeaderName != "" && !strings.EqualFold(authentication.HeaderName, "Authorization") {
+			provider.ExtractFrom = &gatewayv1alpha1.JWTExtractor{
+				Headers: []gatewayv1alpha1.JWTHeaderExtractor{
+					{Name: authentication.HeaderName, ValuePrefix: ptr.To("Bearer ")},
+				},
+			}
+		}
+		providers = append(providers, provider)
+	}
+	return &gatewayv1alpha1.JWT{
+		Optional:  ptr.To(strings.EqualFold(authentication.Required, authRequiredOptional)),
+		Providers: providers,
+	}
+}
+
+// addRateLimitPolicy adds a BackendTrafficPolicy CR enforcing the given rate limit rules on the given routes
+func addRateLimitPolicy(k8sArtifact *K8sArtifacts, name string, rateLimitRules []gatewayv1alpha1.RateLimitRule, targetRefs []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName) {
+	backendTrafficPolicy := &gatewayv1alpha1.BackendTrafficPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       backendTrafficKind,
+			APIVersion: envoyGatewayVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: make(map[string]string),
+		},
+		Spec: gatewayv1alpha1.BackendTrafficPolicySpec{
+			PolicyTargetReferences: gatewayv1alpha1.PolicyTargetReferences{TargetRefs: targetRefs},
+			RateLimit: &gatewayv1alpha1.RateLimitSpec{
+				Type:   gatewayv1alpha1.RateLimitType(rateLimitTypeGlobal),
+				Global: &gatewayv1alpha1.GlobalRateLimit{Rules: rateLimitRules},
+			},
+		},
+	}
+	k8sArtifact.BackendTrafficPolicies[name] = backendTrafficPolicy
+}
+
+// generateRateLimitRule returns a rate limit rule enforcing the given rate limit on the requests selected by the
+// given client selectors
+func generateRateLimitRule(rateLimit resourceTypes.RateLimit, clientSelectors []gatewayv1alpha1.RateLimitSelectCondition) gatewayv1alpha1.RateLimitRule {
+	return gatewayv1alpha1.RateLimitRule{
+		ClientSelectors: clientSelectors,
+		Limit: gatewayv1alpha1.RateLimitValue{
+			Requests: uint(rateLimit.RequestsPerUnit),
+			Unit:     getRateLimitUnit(rateLimit.Unit),
+		},
+	}
+}
+
+// generateRuleClientSelectors returns the client selectors which select the requests matched by a route rule, as the
+// rate limit rules of a BackendTrafficPolicy can not target the individual rules of a route
+func generateRuleClientSelectors(rule *gwapiv1.HTTPRouteRule) []gatewayv1alpha1.RateLimitSelectCondition {
+	if len(rule.Matches) == 0 {
+		return nil
+	}
+	match := rule.Matches[0]
+	headers := make([]gatewayv1alpha1.HeaderMatch, 0, 2)
+	if match.Method != nil {
+		headers = append(headers, gatewayv1alpha1.HeaderMatch{
+			Type:  ptr.To(gatewayv1alpha1.HeaderMatchExact),
+			Name:  methodPseudoHeader,
+			Value: ptr.To(string(*match.Method)),
+		})
+	}
+	if match.Path != nil && match.Path.Value != nil {
+		pathRegex := regexp.QuoteMeta(*match.Path.Value)
+		if match.Path.Type != nil {
+			switch *match.Path.Type {
+			case gwapiv1.PathMatchRegularExpression:
+				pathRegex = *match.Path.Value
+			case gwapiv1.PathMatchPathPrefix:
+				pathRegex = strings.TrimSuffix(pathRegex, "/") + "(/.*)?"
+			}
+		}
+		// The :path header carries the query of the request
+		headers = append(headers, gatewayv1alpha1.HeaderMatch{
+			Type:  ptr.To(gatewayv1alpha1.HeaderMatchRegularExpression),
+			Name:  pathPseudoHeader,
+			Value: ptr.To(pathRegex + `(\?.*)?`),
+		})
+	}
+	return []gatewayv1alpha1.RateLimitSelectCondition{{Headers: headers}}
+}
+
+// generateEnvoyExtensionPolicy generates the EnvoyExtensionPolicy CR which sends the API traffic through the
+// configured external processor that enforces the RouteMetadata and RoutePolicy CRs
+func generateEnvoyExtensionPolicy(ctx *generatorContext, conf *config.Config, targetRefs []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName) {
+	serviceName := getGatewayAgentConfig(conf, extProcServiceNameConfig, "")
+	if serviceName == "" {
+		logger.LoggerTransformer.Debug("External processor service is not configured. Skipping the EnvoyExtensionPolicy generation.")
+		return
+	}
+	port := gwapiv1.PortNumber(defaultExtProcServicePort)
+	if configuredPort, err := strconv.Atoi(getGatewayAgentConfig(conf, extProcServicePortConfig, "")); err == nil {
+		port = gwapiv1.PortNumber(configuredPort)
+	}
+	envoyExtensionPolicy := &gatewayv1alpha1.EnvoyExtensionPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       envoyExtensionKind,
+			APIVersion: envoyGatewayVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ctx.uniqueName + "-extension-policy",
+			Labels: make(map[string]string),
+		},
+		Spec: gatewayv1alpha1.EnvoyExtensionPolicySpec{
+			PolicyTargetReferences: gatewayv1alpha1.PolicyTargetReferences{TargetRefs: targetRefs},
+			ExtProc: []gatewayv1alpha1.ExtProc{
+				{
+					BackendCluster: gatewayv1alpha1.BackendCluster{
+						BackendRefs: []gatewayv1alpha1.BackendRef{
+							{
+								BackendObjectReference: gwapiv1.BackendObjectReference{
+									Name: gwapiv1.ObjectName(serviceName),
+									Port: &port,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx.k8sArtifact.EnvoyExtensionPolicies[envoyExtensionPolicy.Name] = envoyExtensionPolicy
+}
+
+// generateRouteTargetRefs returns the policy target references of all the routes of the API
+func generateRouteTargetRefs(k8sArtifact *K8sArtifacts) []gwapiv1a2.LocalPolicyTargetReferenceWithSectionName {
+	targetRefs := make([]gwapiv1a2.LocalPolicyTargetReferenceWithSectionName, 0, len(k8sArtifact.HTTPRoutes)+len(k8sArtifact.GRPCRoutes))
+	addTargetRefs := func(kind string, names []string) {
+		sort.Strings(names)
+		for _, name := range names {
+			targetRefs = append(targetRefs, gwapiv1a2.LocalPolicyTargetReferenceWithSectionName{
+				LocalPolicyTargetReference: gwapiv1a2.LocalPolicyTargetReference{
+					Group: gwapiv1a2.Group(gatewayAPIGroup),
+					Kind:  gwapiv1a2.Kind(kind),
+					Name:  gwapiv1a2.ObjectName(name),
+				},
+			})
+		}
+	}
+	httpRouteNames := make([]string, 0, len(k8sArtifact.HTTPRoutes))
+	for name := range k8sArtifact.HTTPRoutes {
+		httpRouteNames = append(httpRouteNames, name)
+	}
+	addTargetRefs(httpRouteKind, httpRouteNames)
+	grpcRouteNames := make([]string, 0, len(k8sArtifact.GRPCRoutes))
+	for name := range k8sArtifact.GRPCRoutes {
+		grpcRouteNames = append(grpcRouteNames, name)
+	}
+	addTargetRefs(grpcRouteKind, grpcRouteNames)
+	return targetRefs
+}
+
+// generateHTTPExtensionRefFilter returns a HTTPRoute filter referring to the given dp.wso2.com CR
+func generateHTTPExtensionRefFilter(kind string, name string) gwapiv1.HTTPRouteFilter {
+	return gwapiv1.HTTPRouteFilter{
+		Type:         gwapiv1.HTTPRouteFilterExtensionRef,
+		ExtensionRef: generateExtensionRef(kind, name),
+	}
+}
+
+// generateExtensionRef returns a reference to the given dp.wso2.com CR
+func generateExtensionRef(kind string, name string) *gwapiv1.LocalObjectReference {
+	return &gwapiv1.LocalObjectReference{
+		Group: gwapiv1.Group(dpGroup),
+		Kind:  gwapiv1.Kind(kind),
+		Name:  gwapiv1.ObjectName(name),
+	}
+}
+
+// getEnvType returns the environment type annotation value of the given endpoint type
+func getEnvType(endpointType string) string {
+	if endpointType == constants.SandboxType {
+		return sandboxEnvType
+	}
+	return productionEnvType
+}
+
+// getRateLimitUnit maps the apk-conf rate limit unit to the Envoy Gateway rate limit unit
+func getRateLimitUnit(unit string) gatewayv1alpha1.RateLimitUnit {
+	if unit == "" {
+		return gatewayv1alpha1.RateLimitUnit("Minute")
+	}
+	return gatewayv1alpha1.RateLimitUnit(strings.ToUpper(unit[:1]) + strings.ToLower(unit[1:]))
+}
+
+// getGatewayAgentConfig returns the string value of the given gatewayAgent configuration
+func getGatewayAgentConfig(conf *config.Config, key string, defaultValue string) string {
+	if conf == nil {
+		return defaultValue
+	}
+	if value := conf.GatewayAgent.Get(key); value != nil {
+		if str := fmt.Sprint(value); str != "" {
+			return str
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,340 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+)
+
+const testAPKConf = `name: "PizzaShackAPI"
+id: "api-uuid"
+version: "1.0.0"
+basePath: "/pizzashack"
+type: "REST"
+defaultVersion: false
+endpointConfigurations:
+  production:
+    - endpoint: "https://prod.pizzashack.com:9443/api"
+  sandbox:
+    - endpoint: "http://sandbox.pizzashack.com/api"
+operations:
+  - target: "/menu"
+    verb: "GET"
+    secured: false
+    scopes: []
+  - target: "/order/{orderId}"
+    verb: "POST"
+    secured: true
+    scopes: []
+    rateLimit:
+      requestsPerUnit: 10
+      unit: "minute"
+    operationPolicies:
+      request:
+        - policyName: "addHeader"
+          policyVersion: "v1"
+          parameters:
+            headerName: "x-order"
+            headerValue: "true"
+authentication:
+  - authType: "OAuth2"
+    enabled: true
+    required: "mandatory"
+corsConfiguration:
+  corsConfigurationEnabled: true
+  accessControlAllowOrigins: ["*"]
+  accessControlAllowMethods: ["GET", "POST"]
+rateLimit:
+  requestsPerUnit: 100
+  unit: "Hour"
+keyManagers:
+  - name: "carbon.super-Resident"
+    issuer: "https://localhost:9443/oauth2/token"
+    JWKSEndpoint: "https://localhost:9443/oauth2/jwks"
+    claimMappings:
+      - remoteClaim: "sub"
+        localClaim: "x-sub"
+`
+
+func TestGenerateK8sArtifacts(t *testing.T) {
+	k8sArtifact := newK8sArtifacts()
+	conf := &config.Config{}
+	err := generateK8sArtifacts(testAPKConf, "{}", "carbon.super", conf, k8sArtifact)
+	assert.NoError(t, err)
+
+	routeMetadata := k8sArtifact.RouteMetadata
+	assert.NotNil(t, routeMetadata)
+	assert.Equal(t, generateUniqueNameFormAPI(routeMetadata), routeMetadata.Name)
+	assert.Equal(t, "/pizzashack/1.0.0", routeMetadata.Spec.API.Context)
+	assert.NotNil(t, routeMetadata.Spec.API.DefinitionFileRef)
+	assert.Contains(t, k8sArtifact.ConfigMaps, string(routeMetadata.Spec.API.DefinitionFileRef.Name))
+
+	assert.Len(t, k8sArtifact.HTTPRoutes, 2)
+	assert.Len(t, k8sArtifact.Backends, 2)
+	envTypes := map[string]bool{}
+	for _, httpRoute := range k8sArtifact.HTTPRoutes {
+		envTypes[httpRoute.Annotations[kgwEnvTypeAnnotation]] = true
+		assert.Len(t, httpRoute.Spec.Rules, 2)
+		for _, rule := range httpRoute.Spec.Rules {
+			assert.NotNil(t, rule.Name)
+			assert.Equal(t, "Backend", string(*rule.BackendRefs[0].Kind))
+			hasRouteMetadataRef := false
+			for _, filter := range rule.Filters {
+				if filter.ExtensionRef != nil && filter.ExtensionRef.Kind == routeMetadataKind {
+					hasRouteMetadataRef = true
+				}
+			}
+			assert.True(t, hasRouteMetadataRef)
+		}
+	}
+	assert.True(t, envTypes[productionEnvType])
+	assert.True(t, envTypes[sandboxEnvType])
+
+	for _, backend := range k8sArtifact.Backends {
+		fqdn := backend.Spec.Endpoints[0].FQDN
+		if fqdn.Hostname == "prod.pizzashack.com" {
+			assert.Equal(t, int32(9443), fqdn.Port)
+			assert.NotNil(t, backend.Spec.TLS)
+		} else {
+			assert.Equal(t, "sandbox.pizzashack.com", fqdn.Hostname)
+			assert.Equal(t, int32(80), fqdn.Port)
+			assert.Nil(t, backend.Spec.TLS)
+		}
+	}
+
+	// Only the secured operation is protected with JWT while CORS applies to the whole API
+	assert.Len(t, k8sArtifact.SecurityPolicies, 2)
+	jwtPolicy := k8sArtifact.SecurityPolicies[routeMetadata.Name+"-jwt-security-policy"]
+	assert.NotNil(t, jwtPolicy)
+	assert.Len(t, jwtPolicy.Spec.TargetRefs, 2)
+	assert.Equal(t, "operation-1", string(*jwtPolicy.Spec.TargetRefs[0].SectionName))
+	assert.Equal(t, "carbon.super-Resident", jwtPolicy.Spec.JWT.Providers[0].Name)
+	assert.Equal(t, "x-sub", jwtPolicy.Spec.JWT.Providers[0].ClaimToHeaders[0].Header)
+	assert.Equal(t, "carbon.super", jwtPolicy.Labels[kgwOrganizationLabel])
+	corsPolicy := k8sArtifact.SecurityPolicies[routeMetadata.Name+"-security-policy"]
+	assert.NotNil(t, corsPolicy)
+	assert.Nil(t, corsPolicy.Spec.JWT)
+	assert.Len(t, corsPolicy.Spec.TargetRefs, 2)
+
+	// The API level and the operation rate limits are enforced by a single policy on the routes
+	assert.Len(t, k8sArtifact.BackendTrafficPolicies, 1)
+	apiRateLimit := k8sArtifact.BackendTrafficPolicies[routeMetadata.Name+"-api-ratelimit"]
+	assert.NotNil(t, apiRateLimit)
+	assert.Len(t, apiRateLimit.Spec.TargetRefs, 2)
+	for _, targetRef := range apiRateLimit.Spec.TargetRefs {
+		assert.Nil(t, targetRef.SectionName)
+	}
+	rules := apiRateLimit.Spec.RateLimit.Global.Rules
+	assert.Len(t, rules, 2)
+	assert.Equal(t, uint(100), rules[0].Limit.Requests)
+	assert.Equal(t, "Hour", string(rules[0].Limit.Unit))
+	assert.True(t, *rules[0].Shared)
+	assert.Empty(t, rules[0].ClientSelectors)
+	for _, rule := range rules[1:] {
+		assert.Equal(t, uint(10), rule.Limit.Requests)
+		assert.Nil(t, rule.Shared)
+		headers := rule.ClientSelectors[0].Headers
+		assert.Equal(t, ":method", headers[0].Name)
+		assert.Equal(t, "POST", *headers[0].Value)
+		assert.Equal(t, ":path", headers[1].Name)
+		assert.Regexp(t, "^"+*headers[1].Value+"$", "/pizzashack/1.0.0/order/10?expand=true")
+		assert.NotRegexp(t, "^"+*headers[1].Value+"$", "/pizzashack/1.0.0/menu")
+	}
+
+	assert.Len(t, k8sArtifact.RoutePolicies, 1)
+	for _, routePolicy := range k8sArtifact.RoutePolicies {
+		assert.Equal(t, "addHeader", routePolicy.Spec.RequestMediation[0].PolicyName)
+		assert.Equal(t, "headerName", routePolicy.Spec.RequestMediation[0].Parameters[0].Key)
+		assert.Equal(t, "x-order", routePolicy.Spec.RequestMediation[0].Parameters[0].Value)
+	}
+	assert.Empty(t, k8sArtifact.EnvoyExtensionPolicies)
+
+	// The generated artifacts must be consumable by UpdateCRS
+	addOrganization(k8sArtifact, "carbon.super")
+	addRevisionAndAPIUUID(k8sArtifact, "api-uuid", "1")
+	assert.Equal(t, "api-uuid", routeMetadata.Labels[k8APIUuidField])
+}
+
+func TestGenerateK8sArtifactsWithExtensionPolicy(t *testing.T) {
+	k8sArtifact := newK8sArtifacts()
+	conf := &config.Config{GatewayAgent: map[string]interface{}{extProcServiceNameConfig: "enforcer", extProcServicePortConfig: 8082}}
+	err := generateK8sArtifacts(testAPKConf, "{}", "carbon.super", conf, k8sArtifact)
+	assert.NoError(t, err)
+	assert.Len(t, k8sArtifact.EnvoyExtensionPolicies, 1)
+	for _, extensionPolicy := range k8sArtifact.EnvoyExtensionPolicies {
+		backendRef := extensionPolicy.Spec.ExtProc[0].BackendRefs[0]
+		assert.Equal(t, "enforcer", string(backendRef.Name))
+		assert.Equal(t, int32(8082), int32(*backendRef.Port))
+		assert.Len(t, extensionPolicy.Spec.TargetRefs, 2)
+	}
+}
+
+func TestGenerateK8sArtifactsQualifiesNamesByOrganization(t *testing.T) {
+	names := make(map[string]string)
+	for _, organizationID := range []string{"org1", "org2"} {
+		k8sArtifact := newK8sArtifacts()
+		assert.NoError(t, generateK8sArtifacts(testAPKConf, "{}", organizationID, &config.Config{}, k8sArtifact))
+		crNames := []string{k8sArtifact.RouteMetadata.Name}
+		for name := range k8sArtifact.HTTPRoutes {
+			crNames = append(crNames, name)
+		}
+		for name := range k8sArtifact.Backends {
+			crNames = append(crNames, name)
+		}
+		for name := range k8sArtifact.SecurityPolicies {
+			crNames = append(crNames, name)
+		}
+		for name := range k8sArtifact.RoutePolicies {
+			crNames = append(crNames, name)
+		}
+		for name := range k8sArtifact.BackendTrafficPolicies {
+			crNames = append(crNames, name)
+		}
+		for name := range k8sArtifact.ConfigMaps {
+			crNames = append(crNames, name)
+		}
+		for _, name := range crNames {
+			assert.NotContains(t, names, name, "CR %s is shared by the organizations", name)
+			names[name] = organizationID
+		}
+	}
+}
+
+func TestGenerateK8sArtifactsWithoutEndpoints(t *testing.T) {
+	err := generateK8sArtifacts("name: test\nversion: 1.0.0\n", "{}", "carbon.super", &config.Config{}, newK8sArtifacts())
+	assert.Error(t, err)
+}
+
+func TestGetRateLimitUnit(t *testing.T) {
+	assert.Equal(t, "Minute", string(getRateLimitUnit("MINUTE")))
+	assert.Equal(t, "Second", string(getRateLimitUnit("second")))
+	assert.Equal(t, "Minute", string(getRateLimitUnit("")))
+}
+
+const testWSAPKConf = `name: "ChatAPI"
+id: "chat-api-uuid"
+version: "1.0.0"
+basePath: "/chat"
+type: "WS"
+endpointConfigurations:
+  production:
+    - endpoint: "ws://chat.example.com:8080"
+operations:
+  - target: "/rooms"
+    verb: "SUBSCRIBE"
+    secured: true
+  - target: "/rooms"
+    verb: "PUBLISH"
+    secured: true
+rateLimit:
+  requestsPerUnit: 100
+  unit: "Minute"
+`
+
+func TestAddStreamingTrafficPolicyMergesIntoRateLimitPolicy(t *testing.T) {
+	k8sArtifact := newK8sArtifacts()
+	err := generateK8sArtifacts(testWSAPKConf, "{}", "carbon.super", &config.Config{}, k8sArtifact)
+	assert.NoError(t, err)
+	addStreamingTrafficPolicy(testWSAPKConf, k8sArtifact)
+
+	// Envoy Gateway accepts a single BackendTrafficPolicy per route
+	assert.Len(t, k8sArtifact.BackendTrafficPolicies, 1)
+	backendTrafficPolicy := k8sArtifact.BackendTrafficPolicies[k8sArtifact.RouteMetadata.Name+"-api-ratelimit"]
+	assert.NotNil(t, backendTrafficPolicy)
+	assert.Equal(t, uint(100), backendTrafficPolicy.Spec.RateLimit.Global.Rules[0].Limit.Requests)
+	assert.Equal(t, "websocket", backendTrafficPolicy.Spec.HTTPUpgrade[0].Type)
+	assert.NotNil(t, backendTrafficPolicy.Spec.Timeout.HTTP.ConnectionIdleTimeout)
+}
+
+func TestAddStreamingTrafficPolicyWithoutOtherPolicies(t *testing.T) {
+	k8sArtifact := newK8sArtifacts()
+	apkConf := strings.Replace(testWSAPKConf, "rateLimit:\n  requestsPerUnit: 100\n  unit: \"Minute\"\n", "", 1)
+	err := generateK8sArtifacts(apkConf, "{}", "carbon.super", &config.Config{}, k8sArtifact)
+	assert.NoError(t, err)
+	addStreamingTrafficPolicy(apkConf, k8sArtifact)
+
+	assert.Len(t, k8sArtifact.BackendTrafficPolicies, 1)
+	for _, backendTrafficPolicy := range k8sArtifact.BackendTrafficPolicies {
+		assert.Nil(t, backendTrafficPolicy.Spec.RateLimit)
+		assert.Len(t, backendTrafficPolicy.Spec.TargetRefs, len(k8sArtifact.HTTPRoutes))
+		assert.Equal(t, "websocket", backendTrafficPolicy.Spec.HTTPUpgrade[0].Type)
+	}
+}
+
+const testGraphQLAPKConf = `name: "ReviewsAPI"
+id: "reviews-api-uuid"
+version: "1.0.0"
+basePath: "/reviews"
+type: "GRAPHQL"
+endpointConfigurations:
+  production:
+    - endpoint: "http://reviews.example.com:8080/graphql"
+operations:
+  - target: "hero"
+    verb: "QUERY"
+    secured: true
+  - target: "reviewAdded"
+    verb: "SUBSCRIPTION"
+    secured: false
+authentication:
+  - authType: "OAuth2"
+    enabled: true
+keyManagers:
+  - name: "carbon.super-Resident"
+    issuer: "https://localhost:9443/oauth2/token"
+    JWKSEndpoint: "https://localhost:9443/oauth2/jwks"
+`
+
+func TestGenerateK8sArtifactsSecuresGraphQLRulesPerOperationType(t *testing.T) {
+	k8sArtifact := newK8sArtifacts()
+	err := generateK8sArtifacts(testGraphQLAPKConf, "{}", "carbon.super", &config.Config{}, k8sArtifact)
+	assert.NoError(t, err)
+
+	jwtPolicy := k8sArtifact.SecurityPolicies[k8sArtifact.RouteMetadata.Name+"-jwt-security-policy"]
+	assert.NotNil(t, jwtPolicy)
+	assert.Len(t, jwtPolicy.Spec.TargetRefs, 1)
+	assert.Equal(t, "operation-0", string(*jwtPolicy.Spec.TargetRefs[0].SectionName))
+}
+
+func TestGenerateK8sArtifactsTrustsEndpointCertificate(t *testing.T) {
+	k8sArtifact := newK8sArtifacts()
+	apkConf := strings.Replace(testAPKConf, `    - endpoint: "https://prod.pizzashack.com:9443/api"
+`, `    - endpoint: "https://prod.pizzashack.com:9443/api"
+      certificate:
+        secretName: "prod-cert"
+        secretKey: "prod-cert.crt"
+`, 1)
+	err := generateK8sArtifacts(apkConf, "{}", "carbon.super", &config.Config{}, k8sArtifact)
+	assert.NoError(t, err)
+
+	for _, backend := range k8sArtifact.Backends {
+		if backend.Spec.TLS == nil {
+			continue
+		}
+		assert.Nil(t, backend.Spec.TLS.WellKnownCACertificates)
+		assert.Equal(t, k8sArtifact.RouteMetadata.Name+"-prod-cert", string(backend.Spec.TLS.CACertificateRefs[0].Name))
+		assert.Equal(t, "ConfigMap", string(backend.Spec.TLS.CACertificateRefs[0].Kind))
+	}
+	// The ConfigMap of the certificate is named the same
+	createConfigMaps(map[string]string{"prod-cert.crt": ""}, k8sArtifact)
+	assert.Contains(t, k8sArtifact.ConfigMaps, k8sArtifact.RouteMetadata.Name+"-prod-cert")
+}
@@ -0,0 +1,319 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	logger "github.com/wso2-extensions/apim-gw-connectors/eg/gateway-connector/pkg/loggers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Lifecycle states of an API which change the behaviour of the gateway
+const (
+	BlockedLifeCycleState    = "BLOCKED"
+	DeprecatedLifeCycleState = "DEPRECATED"
+	RetiredLifeCycleState    = "RETIRED"
+)
+
+const (
+	// LifeCycleStateAnnotation is the RouteMetadata annotation holding the lifecycle state of the API, so that the
+	// state is applied again when a new revision of the API is deployed
+	LifeCycleStateAnnotation = "kgw.wso2.com/lifecycle-state"
+	// DeprecatedAtAnnotation is the RouteMetadata annotation holding the time the API was deprecated in seconds
+	// since the epoch
+	DeprecatedAtAnnotation = "kgw.wso2.com/deprecated-at"
+
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+	// deprecatedAPISunsetPeriodConfig is the gatewayAgent configuration of the period after which a deprecated
+	// API is retired, advertised in the Sunset header. The header is not added when the period is not configured.
+	deprecatedAPISunsetPeriodConfig = "deprecatedAPISunsetPeriod"
+	httpRouteFilterKind             = "HTTPRouteFilter"
+	blockedFilterSuffix             = "-blocked"
+	blockedResponseStatusCode       = http.StatusServiceUnavailable
+	blockedResponseBody             = `{"code":"700700","message":"API blocked","description":"This API has been ` +
+		`blocked temporarily. Please try again later or contact the system administrators."}`
+)
+
+// LifeCycle is the lifecycle state of an API applied to its routes
+type LifeCycle struct {
+	State string
+	// DeprecatedAt is the time the API was deprecated
+	DeprecatedAt time.Time
+	// SunsetPeriod is the period after the deprecation the API is expected to be retired
+	SunsetPeriod time.Duration
+}
+
+// NewLifeCycle returns the lifecycle of an API in the given state, which was changed at the given time in
+// milliseconds since the epoch
+func NewLifeCycle(state string, timeStamp int64) LifeCycle {
+	lifeCycle := LifeCycle{State: strings.ToUpper(state), SunsetPeriod: getDeprecatedAPISunsetPeriod()}
+	if lifeCycle.State == DeprecatedLifeCycleState {
+		lifeCycle.DeprecatedAt = time.UnixMilli(timeStamp)
+		if timeStamp == 0 {
+			lifeCycle.DeprecatedAt = time.Now()
+		}
+	}
+	return lifeCycle
+}
+
+// LifeCycleFromAnnotations returns the lifecycle of an API recorded in the annotations of its RouteMetadata
+func LifeCycleFromAnnotations(annotations map[string]string) LifeCycle {
+	lifeCycle := LifeCycle{State: annotations[LifeCycleStateAnnotation], SunsetPeriod: getDeprecatedAPISunsetPeriod()}
+	if deprecatedAt, err := strconv.ParseInt(annotations[DeprecatedAtAnnotation], 10, 64); err == nil {
+		lifeCycle.DeprecatedAt = time.Unix(deprecatedAt, 0)
+	}
+	return lifeCycle
+}
+
+// Annotations returns the RouteMetadata annotations recording the lifecycle
+func (l LifeCycle) Annotations() map[string]string {
+	annotations := map[string]string{LifeCycleStateAnnotation: l.State}
+	if l.State == DeprecatedLifeCycleState {
+		annotations[DeprecatedAtAnnotation] = strconv.FormatInt(l.DeprecatedAt.Unix(), 10)
+	}
+	return annotations
+}
+
+// BlockedHTTPRouteFilterName returns the name of the HTTPRouteFilter which blocks the routes of an API
+func BlockedHTTPRouteFilterName(routeMetadataName string) string {
+	return routeMetadataName + blockedFilterSuffix
+}
+
+// GenerateBlockedHTTPRouteFilter returns the HTTPRouteFilter which responds to the requests of a blocked API with
+// a 503 direct response
+func GenerateBlockedHTTPRouteFilter(routeMetadataName string, namespace string, labels map[string]string) *gatewayv1alpha1.HTTPRouteFilter {
+	return &gatewayv1alpha1.HTTPRouteFilter{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: envoyGatewayVersion,
+			Kind:       httpRouteFilterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BlockedHTTPRouteFilterName(routeMetadataName),
+			Namespace: namespace,
+			Labels:    maps.Clone(labels),
+		},
+		Spec: gatewayv1alpha1.HTTPRouteFilterSpec{
+			DirectResponse: &gatewayv1alpha1.HTTPDirectResponseFilter{
+				ContentType: ptr.To("application/json"),
+				Body: &gatewayv1alpha1.CustomResponseBody{
+					Type:   ptr.To(gatewayv1alpha1.ResponseValueTypeInline),
+					Inline: ptr.To(blockedResponseBody),
+				},
+				StatusCode: ptr.To(blockedResponseStatusCode),
+			},
+		},
+	}
+}
+
+// ApplyLifeCycleState applies the lifecycle of the API to the HTTPRoutes and GRPCRoutes of the artifacts, and adds
+// the HTTPRouteFilter blocking the routes when the API is blocked
+func ApplyLifeCycleState(k8sArtifact *K8sArtifacts, lifeCycle LifeCycle) {
+	if k8sArtifact.RouteMetadata == nil {
+		return
+	}
+	routeMetadata := k8sArtifact.RouteMetadata
+	blockedFilterName := BlockedHTTPRouteFilterName(routeMetadata.Name)
+	// The routes are generated from the API definition, hence no lifecycle state was applied to them before
+	for _, httpRoute := range k8sArtifact.HTTPRoutes {
+		ApplyLifeCycleStateToHTTPRoute(httpRoute, LifeCycle{}, lifeCycle, blockedFilterName)
+	}
+	for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+		ApplyLifeCycleStateToGRPCRoute((*gwapiv1.GRPCRoute)(grpcRoute), LifeCycle{}, lifeCycle, blockedFilterName)
+	}
+	if lifeCycle.State == BlockedLifeCycleState {
+		k8sArtifact.HTTPRouteFilters[blockedFilterName] = GenerateBlockedHTTPRouteFilter(routeMetadata.Name,
+			routeMetadata.Namespace, routeMetadata.Labels)
+	} else {
+		delete(k8sArtifact.HTTPRouteFilters, blockedFilterName)
+	}
+}
+
+// ApplyLifeCycleStateToHTTPRoute changes the rules of the HTTPRoute to reflect the lifecycle of its API. The filter
+// and the headers applied for the previous lifecycle state are removed, hence the rules of a published API are left
+// as generated.
+func ApplyLifeCycleStateToHTTPRoute(httpRoute *gwapiv1.HTTPRoute, previous LifeCycle, lifeCycle LifeCycle, blockedFilterName string) {
+	for i := range httpRoute.Spec.Rules {
+		rule := &httpRoute.Spec.Rules[i]
+		filters := make([]gwapiv1.HTTPRouteFilter, 0, len(rule.Filters)+1)
+		var responseHeaderModifier *gwapiv1.HTTPHeaderFilter
+		for _, filter := range rule.Filters {
+			switch {
+			case filter.Type == gwapiv1.HTTPRouteFilterExtensionRef && isBlockedFilterRef(filter.ExtensionRef, blockedFilterName):
+				continue
+			case filter.Type == gwapiv1.HTTPRouteFilterResponseHeaderModifier && filter.ResponseHeaderModifier != nil:
+				if !removeDeprecationHeaders(filter.ResponseHeaderModifier, previous) {
+					continue
+				}
+				responseHeaderModifier = filter.ResponseHeaderModifier
+			}
+			filters = append(filters, filter)
+		}
+		switch lifeCycle.State {
+		case BlockedLifeCycleState:
+			filters = append(filters, gwapiv1.HTTPRouteFilter{
+				Type:         gwapiv1.HTTPRouteFilterExtensionRef,
+				ExtensionRef: blockedFilterRef(blockedFilterName),
+			})
+		case DeprecatedLifeCycleState:
+			// A rule can have only one response header modifier, hence the headers are added to the existing
+			// modifier when there is one
+			if responseHeaderModifier == nil {
+				responseHeaderModifier = &gwapiv1.HTTPHeaderFilter{}
+				filters = append(filters, gwapiv1.HTTPRouteFilter{
+					Type:                   gwapiv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: responseHeaderModifier,
+				})
+			}
+			addDeprecationHeaders(responseHeaderModifier, lifeCycle)
+		}
+		rule.Filters = filters
+	}
+	logger.LoggerTransformer.Debugf("Applied the lifecycle state %q to the HTTPRoute %s", lifeCycle.State,
+		httpRoute.Name)
+}
+
+// ApplyLifeCycleStateToGRPCRoute changes the rules of the GRPCRoute to reflect the lifecycle of its API, in the same
+// way as ApplyLifeCycleStateToHTTPRoute
+func ApplyLifeCycleStateToGRPCRoute(grpcRoute *gwapiv1.GRPCRoute, previous LifeCycle, lifeCycle LifeCycle, blockedFilterName string) {
+	for i := range grpcRoute.Spec.Rules {
+		rule := &grpcRoute.Spec.Rules[i]
+		filters := make([]gwapiv1.GRPCRouteFilter, 0, len(rule.Filters)+1)
+		var responseHeaderModifier *gwapiv1.HTTPHeaderFilter
+		for _, filter := range rule.Filters {
+			switch {
+			case filter.Type == gwapiv1.GRPCRouteFilterExtensionRef && isBlockedFilterRef(filter.ExtensionRef, blockedFilterName):
+				continue
+			case filter.Type == gwapiv1.GRPCRouteFilterResponseHeaderModifier && filter.ResponseHeaderModifier != nil:
+				if !removeDeprecationHeaders(filter.ResponseHeaderModifier, previous) {
+					continue
+				}
+				responseHeaderModifier = filter.ResponseHeaderModifier
+			}
+			filters = append(filters, filter)
+		}
+		switch lifeCycle.State {
+		case BlockedLifeCycleState:
+			filters = append(filters, gwapiv1.GRPCRouteFilter{
+				Type:         gwapiv1.GRPCRouteFilterExtensionRef,
+				ExtensionRef: blockedFilterRef(blockedFilterName),
+			})
+		case DeprecatedLifeCycleState:
+			if responseHeaderModifier == nil {
+				responseHeaderModifier = &gwapiv1.HTTPHeaderFilter{}
+				filters = append(filters, gwapiv1.GRPCRouteFilter{
+					Type:                   gwapiv1.GRPCRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: responseHeaderModifier,
+				})
+			}
+			addDeprecationHeaders(responseHeaderModifier, lifeCycle)
+		}
+		rule.Filters = filters
+	}
+	logger.LoggerTransformer.Debugf("Applied the lifecycle state %q to the GRPCRoute %s", lifeCycle.State,
+		grpcRoute.Name)
+}
+
+// blockedFilterRef returns the reference to the HTTPRouteFilter blocking the routes of an API
+func blockedFilterRef(blockedFilterName string) *gwapiv1.LocalObjectReference {
+	return &gwapiv1.LocalObjectReference{
+		Group: gwapiv1.Group(envoyGatewayGroup),
+		Kind:  gwapiv1.Kind(httpRouteFilterKind),
+		Name:  gwapiv1.ObjectName(blockedFilterName),
+	}
+}
+
+// isBlockedFilterRef checks whether the extension reference refers to the HTTPRouteFilter blocking the routes
+func isBlockedFilterRef(extensionRef *gwapiv1.LocalObjectReference, blockedFilterName string) bool {
+	return extensionRef != nil && extensionRef.Kind == httpRouteFilterKind &&
+		string(extensionRef.Name) == blockedFilterName
+}
+
+// deprecationHeaders returns the response headers advertising the deprecation of an API
+func deprecationHeaders(lifeCycle LifeCycle) []gwapiv1.HTTPHeader {
+	if lifeCycle.State != DeprecatedLifeCycleState {
+		return nil
+	}
+	headers := []gwapiv1.HTTPHeader{{
+		Name:  deprecationHeader,
+		Value: fmt.Sprintf("@%d", lifeCycle.DeprecatedAt.Unix()),
+	}}
+	if lifeCycle.SunsetPeriod > 0 {
+		headers = append(headers, gwapiv1.HTTPHeader{
+			Name:  sunsetHeader,
+			Value: lifeCycle.DeprecatedAt.Add(lifeCycle.SunsetPeriod).UTC().Format(http.TimeFormat),
+		})
+	}
+	return headers
+}
+
+// addDeprecationHeaders sets the deprecation headers in the response header modifier. The headers set by the
+// policies of the API take precedence, hence they are not overridden.
+func addDeprecationHeaders(modifier *gwapiv1.HTTPHeaderFilter, lifeCycle LifeCycle) {
+	for _, header := range deprecationHeaders(lifeCycle) {
+		exists := slices.ContainsFunc(modifier.Set, func(set gwapiv1.HTTPHeader) bool {
+			return strings.EqualFold(string(set.Name), string(header.Name))
+		})
+		if !exists {
+			modifier.Set = append(modifier.Set, header)
+		}
+	}
+}
+
+// removeDeprecationHeaders removes the deprecation headers set for the previous lifecycle state from the response
+// header modifier, leaving the headers with the same names set by the policies of the API. It reports whether the
+// modifier still modifies any header.
+func removeDeprecationHeaders(modifier *gwapiv1.HTTPHeaderFilter, previous LifeCycle) bool {
+	applied := deprecationHeaders(previous)
+	modifier.Set = slices.DeleteFunc(modifier.Set, func(header gwapiv1.HTTPHeader) bool {
+		return slices.ContainsFunc(applied, func(appliedHeader gwapiv1.HTTPHeader) bool {
+			return strings.EqualFold(string(header.Name), string(appliedHeader.Name)) && header.Value == appliedHeader.Value
+		})
+	})
+	return len(modifier.Set) != 0 || len(modifier.Add) != 0 || len(modifier.Remove) != 0
+}
+
+// getDeprecatedAPISunsetPeriod returns the configured period after which a deprecated API is retired
+func getDeprecatedAPISunsetPeriod() time.Duration {
+	conf, err := config.ReadConfigs()
+	if err != nil || conf == nil {
+		return 0
+	}
+	value := conf.GatewayAgent.Get(deprecatedAPISunsetPeriodConfig)
+	if value == nil || fmt.Sprint(value) == "" {
+		return 0
+	}
+	period, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil {
+		logger.LoggerTransformer.Warnf("Invalid %s %q, the Sunset header is not added to the deprecated APIs: %v",
+			deprecatedAPISunsetPeriodConfig, value, err)
+		return 0
+	}
+	return period
+}
@@ -0,0 +1,165 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func newLifeCycleTestHTTPRoute() *gwapiv1.HTTPRoute {
+	return &gwapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route1"},
+		Spec: gwapiv1.HTTPRouteSpec{
+			Rules: []gwapiv1.HTTPRouteRule{{
+				Filters: []gwapiv1.HTTPRouteFilter{generateHTTPExtensionRefFilter(routeMetadataKind, "api1")},
+			}, {
+				Filters: []gwapiv1.HTTPRouteFilter{{
+					Type: gwapiv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gwapiv1.HTTPHeaderFilter{
+						Set: []gwapiv1.HTTPHeader{{Name: "x-policy", Value: "true"}},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+func TestApplyBlockedLifeCycleStateToHTTPRoute(t *testing.T) {
+	httpRoute := newLifeCycleTestHTTPRoute()
+
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, LifeCycle{}, LifeCycle{State: BlockedLifeCycleState}, "api1-blocked")
+	for _, rule := range httpRoute.Spec.Rules {
+		blockedFilter := rule.Filters[len(rule.Filters)-1]
+		require.NotNil(t, blockedFilter.ExtensionRef)
+		assert.Equal(t, gwapiv1.Kind(httpRouteFilterKind), blockedFilter.ExtensionRef.Kind)
+		assert.Equal(t, gwapiv1.ObjectName("api1-blocked"), blockedFilter.ExtensionRef.Name)
+	}
+
+	// Applying the state again does not add the filter twice, and publishing the API removes it
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, LifeCycle{State: BlockedLifeCycleState}, LifeCycle{State: BlockedLifeCycleState},
+		"api1-blocked")
+	assert.Len(t, httpRoute.Spec.Rules[0].Filters, 2)
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, LifeCycle{State: BlockedLifeCycleState}, LifeCycle{State: "PUBLISHED"},
+		"api1-blocked")
+	assert.Equal(t, newLifeCycleTestHTTPRoute().Spec, httpRoute.Spec)
+}
+
+func TestApplyDeprecatedLifeCycleStateToHTTPRoute(t *testing.T) {
+	httpRoute := newLifeCycleTestHTTPRoute()
+	deprecatedAt := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	lifeCycle := LifeCycle{State: DeprecatedLifeCycleState, DeprecatedAt: deprecatedAt, SunsetPeriod: 24 * time.Hour}
+
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, LifeCycle{}, lifeCycle, "api1-blocked")
+	deprecation := []gwapiv1.HTTPHeader{
+		{Name: deprecationHeader, Value: "@1740787200"},
+		{Name: sunsetHeader, Value: "Sun, 02 Mar 2025 00:00:00 GMT"},
+	}
+	require.Len(t, httpRoute.Spec.Rules[0].Filters, 2)
+	assert.Equal(t, deprecation, httpRoute.Spec.Rules[0].Filters[1].ResponseHeaderModifier.Set)
+	// The headers are added to the existing response header modifier of the rule
+	require.Len(t, httpRoute.Spec.Rules[1].Filters, 1)
+	assert.Equal(t, append([]gwapiv1.HTTPHeader{{Name: "x-policy", Value: "true"}}, deprecation...),
+		httpRoute.Spec.Rules[1].Filters[0].ResponseHeaderModifier.Set)
+
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, lifeCycle, LifeCycle{State: "PUBLISHED"}, "api1-blocked")
+	assert.Equal(t, newLifeCycleTestHTTPRoute().Spec, httpRoute.Spec)
+}
+
+func TestDeprecationHeadersOfPoliciesAreRetained(t *testing.T) {
+	httpRoute := newLifeCycleTestHTTPRoute()
+	policyHeaders := []gwapiv1.HTTPHeader{{Name: "x-policy", Value: "true"}, {Name: deprecationHeader, Value: "true"}}
+	httpRoute.Spec.Rules[1].Filters[0].ResponseHeaderModifier.Set = slices.Clone(policyHeaders)
+	lifeCycle := LifeCycle{State: DeprecatedLifeCycleState, DeprecatedAt: time.Unix(1740787200, 0)}
+
+	// The Deprecation header set by a policy is not overridden nor removed with the lifecycle state
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, LifeCycle{}, lifeCycle, "api1-blocked")
+	assert.Equal(t, policyHeaders, httpRoute.Spec.Rules[1].Filters[0].ResponseHeaderModifier.Set)
+	ApplyLifeCycleStateToHTTPRoute(httpRoute, lifeCycle, LifeCycle{State: "PUBLISHED"}, "api1-blocked")
+	assert.Equal(t, policyHeaders, httpRoute.Spec.Rules[1].Filters[0].ResponseHeaderModifier.Set)
+	assert.Len(t, httpRoute.Spec.Rules[0].Filters, 1)
+}
+
+func TestApplyLifeCycleStateToGRPCRoute(t *testing.T) {
+	grpcRoute := &gwapiv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route1"},
+		Spec: gwapiv1.GRPCRouteSpec{
+			Rules: []gwapiv1.GRPCRouteRule{{
+				Filters: []gwapiv1.GRPCRouteFilter{{
+					Type:         gwapiv1.GRPCRouteFilterExtensionRef,
+					ExtensionRef: generateExtensionRef(routeMetadataKind, "api1"),
+				}},
+			}},
+		},
+	}
+	generated := grpcRoute.DeepCopy()
+
+	ApplyLifeCycleStateToGRPCRoute(grpcRoute, LifeCycle{}, LifeCycle{State: BlockedLifeCycleState}, "api1-blocked")
+	require.Len(t, grpcRoute.Spec.Rules[0].Filters, 2)
+	assert.Equal(t, gwapiv1.ObjectName("api1-blocked"), grpcRoute.Spec.Rules[0].Filters[1].ExtensionRef.Name)
+
+	lifeCycle := LifeCycle{State: DeprecatedLifeCycleState, DeprecatedAt: time.Unix(1740787200, 0)}
+	ApplyLifeCycleStateToGRPCRoute(grpcRoute, LifeCycle{State: BlockedLifeCycleState}, lifeCycle, "api1-blocked")
+	require.Len(t, grpcRoute.Spec.Rules[0].Filters, 2)
+	assert.Equal(t, []gwapiv1.HTTPHeader{{Name: deprecationHeader, Value: "@1740787200"}},
+		grpcRoute.Spec.Rules[0].Filters[1].ResponseHeaderModifier.Set)
+
+	ApplyLifeCycleStateToGRPCRoute(grpcRoute, lifeCycle, LifeCycle{State: "PUBLISHED"}, "api1-blocked")
+	assert.Equal(t, generated.Spec, grpcRoute.Spec)
+}
+
+func TestApplyLifeCycleState(t *testing.T) {
+	k8sArtifact := K8sArtifacts{
+		RouteMetadata: &dpv2alpha1.RouteMetadata{ObjectMeta: metav1.ObjectMeta{Name: "api1", Namespace: "ns1",
+			Labels: map[string]string{"apiUUID": "uuid1"}}},
+		HTTPRoutes: map[string]*gwapiv1.HTTPRoute{"route1": newLifeCycleTestHTTPRoute()},
+		GRPCRoutes: map[string]*gwapiv1a2.GRPCRoute{"route2": {Spec: gwapiv1.GRPCRouteSpec{
+			Rules: []gwapiv1.GRPCRouteRule{{}}}}},
+		HTTPRouteFilters: make(map[string]*gatewayv1alpha1.HTTPRouteFilter),
+	}
+
+	ApplyLifeCycleState(&k8sArtifact, LifeCycle{State: BlockedLifeCycleState})
+	blockedFilter, exists := k8sArtifact.HTTPRouteFilters["api1-blocked"]
+	require.True(t, exists)
+	assert.Equal(t, "ns1", blockedFilter.Namespace)
+	assert.Equal(t, 503, *blockedFilter.Spec.DirectResponse.StatusCode)
+	assert.Len(t, k8sArtifact.GRPCRoutes["route2"].Spec.Rules[0].Filters, 1)
+
+	ApplyLifeCycleState(&k8sArtifact, LifeCycle{State: DeprecatedLifeCycleState})
+	assert.NotContains(t, k8sArtifact.HTTPRouteFilters, "api1-blocked")
+}
+
+func TestLifeCycleAnnotations(t *testing.T) {
+	lifeCycle := NewLifeCycle("Deprecated", 1740787200000)
+	annotations := lifeCycle.Annotations()
+	assert.Equal(t, map[string]string{LifeCycleStateAnnotation: DeprecatedLifeCycleState,
+		DeprecatedAtAnnotation: "1740787200"}, annotations)
+
+	restored := LifeCycleFromAnnotations(annotations)
+	assert.Equal(t, DeprecatedLifeCycleState, restored.State)
+	assert.True(t, lifeCycle.DeprecatedAt.Equal(restored.DeprecatedAt))
+}
@@ -32,6 +32,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"io"
@@ -41,6 +42,9 @@ import (
 	gatewayv1alpha1 "github.com/envoyproxy/gateway/api/v1alpha1"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	eventHub "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	asyncGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/async"
+	resourceTypes "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tlsutils"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
 
 	dpv2alpha1 "github.com/wso2/apk/common-go-libs/apis/dp/v2alpha1"
@@ -57,9 +61,9 @@ import (
 )
 
 // GenerateCRs takes the .apk-conf, api definition, vHost and the organization for a particular API and then generate and returns
-// the relavant CRD set as a zip
-func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer.CertContainer, k8ResourceGenEndpoint string, organizationID string) (*K8sArtifacts, error) {
-	k8sArtifact := K8sArtifacts{HTTPRoutes: make(map[string]*gwapiv1.HTTPRoute), HTTPRouteFilters: make(map[string]*gatewayv1alpha1.HTTPRouteFilter), Backends: make(map[string]*gatewayv1alpha1.Backend), ConfigMaps: make(map[string]*corev1.ConfigMap), Secrets: make(map[string]*corev1.Secret), SecurityPolicies: make(map[string]*gatewayv1alpha1.SecurityPolicy), BackendTLSPolicies: make(map[string]*gwapiv1a3.BackendTLSPolicy), RoutePolicies: make(map[string]*dpv2alpha1.RoutePolicy), EnvoyExtensionPolicies: make(map[string]*gatewayv1alpha1.EnvoyExtensionPolicy), BackendTrafficPolicies: make(map[string]*gatewayv1alpha1.BackendTrafficPolicy), GRPCRoutes: make(map[string]*gwapiv1a2.GRPCRoute)}
+// the relavant CRD set. The CRs are generated in-process and the k8s resource endpoint is only used as a fallback when it is enabled.
+func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer.CertContainer, conf *config.Config, organizationID string) (*K8sArtifacts, error) {
+	k8sArtifact := newK8sArtifacts()
 	if apkConf == "" {
 		logger.LoggerTransformer.Error("Empty apk-conf parameter provided. Unable to generate CRDs.")
 		return nil, errors.New("Error: APK-Conf can't be empty")
@@ -71,6 +75,48 @@ func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer
 	}
 	logger.LoggerTransformer.Debugf("\nAPI Definition: %s\n", apiDefinition)
 	logger.LoggerTransformer.Debugf("\nAPK Conf: %v\n", apkConf)
+
+	if err := generateK8sArtifacts(apkConf, apiDefinition, organizationID, conf, k8sArtifact); err != nil {
+		logger.LoggerTransformer.Errorf("Error generating the CRs in-process: %v", err)
+		k8ResourceGenEndpoint := ""
+		if conf != nil {
+			k8ResourceGenEndpoint = conf.DataPlane.K8ResourceEndpoint
+		}
+		if conf == nil || !conf.DataPlane.FallbackToK8ResourceEndpoint || k8ResourceGenEndpoint == "" {
+			return nil, err
+		}
+		logger.LoggerTransformer.Warnf("Falling back to the k8s resource endpoint %s to generate the CRs", k8ResourceGenEndpoint)
+		k8sArtifact = newK8sArtifacts()
+		if err := generateK8sArtifactsFromEndpoint(apkConf, apiDefinition, k8ResourceGenEndpoint, organizationID, conf, k8sArtifact); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create ConfigMap to store the cert data if mTLS has enabled
+	if certContainer.ClientCertObj.CertAvailable {
+		createConfigMaps(certContainer.ClientCertObj.ClientCertFiles, k8sArtifact)
+	}
+
+	// Create ConfigMap to store the cert data if endpoint security has enabled
+	if certContainer.EndpointCertObj.CertAvailable {
+		createConfigMaps(certContainer.EndpointCertObj.EndpointCertFiles, k8sArtifact)
+	}
+
+	createEndpointSecrets(certContainer.SecretData, k8sArtifact)
+
+	addStreamingTrafficPolicy(apkConf, k8sArtifact)
+
+	return k8sArtifact, nil
+}
+
+// newK8sArtifacts returns an empty K8sArtifacts with all the CR maps initialized
+func newK8sArtifacts() *K8sArtifacts {
+	return &K8sArtifacts{HTTPRoutes: make(map[string]*gwapiv1.HTTPRoute), HTTPRouteFilters: make(map[string]*gatewayv1alpha1.HTTPRouteFilter), Backends: make(map[string]*gatewayv1alpha1.Backend), ConfigMaps: make(map[string]*corev1.ConfigMap), Secrets: make(map[string]*corev1.Secret), SecurityPolicies: make(map[string]*gatewayv1alpha1.SecurityPolicy), BackendTLSPolicies: make(map[string]*gwapiv1a3.BackendTLSPolicy), RoutePolicies: make(map[string]*dpv2alpha1.RoutePolicy), EnvoyExtensionPolicies: make(map[string]*gatewayv1alpha1.EnvoyExtensionPolicy), BackendTrafficPolicies: make(map[string]*gatewayv1alpha1.BackendTrafficPolicy), GRPCRoutes: make(map[string]*gwapiv1a2.GRPCRoute)}
+}
+
+// generateK8sArtifactsFromEndpoint generates the CRs of an API by sending the apk-conf and the api definition to the
+// k8s resource endpoint and parsing the returned zip of CRs
+func generateK8sArtifactsFromEndpoint(apkConf string, apiDefinition string, k8ResourceGenEndpoint string, organizationID string, conf *config.Config, k8sArtifact *K8sArtifacts) error {
 	// Create a buffer to store the request body
 	var requestBody bytes.Buffer
 	writer := multipart.NewWriter(&requestBody)
@@ -79,24 +125,24 @@ func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer
 	apkPart, err := writer.CreateFormFile("apkConfiguration", "api.apk-conf")
 	if err != nil {
 		logger.LoggerTransformer.Errorf("Error creating form file for apkConfiguration: %+v", err)
-		return nil, err
+		return err
 	}
 	_, err = io.Copy(apkPart, strings.NewReader(apkConf))
 	if err != nil {
 		logger.LoggerTransformer.Errorf("Error writing apkConfiguration content: %v", err)
-		return nil, err
+		return err
 	}
 
 	// Add apiDefinition field and store the passed API Definition file
 	defPart, err := writer.CreateFormFile("definitionFile", "definition.json")
 	if err != nil {
 		logger.LoggerTransformer.Errorf("Error creating form file for definitionFile: %+v", err)
-		return nil, err
+		return err
 	}
 	_, err = io.Copy(defPart, strings.NewReader(apiDefinition))
 	if err != nil {
 		logger.LoggerTransformer.Errorf("Error writing definitionFile content: %v", err)
-		return nil, err
+		return err
 	}
 
 	// Close the multipart writer
@@ -110,15 +156,16 @@ func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer
 	request, err := http.NewRequest(postHTTPMethod, k8sResourceEndpointWithOrg, &requestBody)
 	if err != nil {
 		logger.LoggerTransformer.Error("Error creating HTTP request:", err)
-		return nil, err
+		return err
 	}
 
 	// Set the Content-Type header
 	request.Header.Set(contentTypeHeader, writer.FormDataContentType())
-	// Certificate validation is turned off as linkerd would be used for mTLS between the two components
-	tr := &http.Transport{
-		/* #nosec */
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	tr := &http.Transport{}
+	if conf.DataPlane.SkipSSLVerification {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} /* #nosec */
+	} else {
+		tr.TLSClientConfig = &tls.Config{RootCAs: tlsutils.GetTrustedCertPool(conf.Agent.TrustStore.Location)}
 	}
 
 	// Make the request
@@ -127,7 +174,7 @@ func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer
 	response, err := client.Do(request)
 	if err != nil {
 		logger.LoggerTransformer.Error("Error making HTTP request:", err)
-		return nil, err
+		return err
 	}
 
 	defer response.Body.Close()
@@ -135,44 +182,44 @@ func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer
 	// Check the HTTP status code
 	if response.StatusCode < 200 || response.StatusCode >= 300 {
 		logger.LoggerTransformer.Errorf("HTTP request failed with status code: %d", response.StatusCode)
-		return nil, fmt.Errorf("HTTP request failed with status code: %+v", response.Body)
+		return fmt.Errorf("HTTP request failed with status code: %+v", response.Body)
 	}
 
 	//Extracting response body to get the CRD zipfile
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		logger.LoggerTransformer.Error("Error reading response body:", err)
-		panic(err)
+		return err
 	}
 	zipReader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
 	if err != nil {
 		logger.LoggerTransformer.Error("Unable to transform the initial CRDs:", err)
-		return nil, err
+		return err
 	}
 	for _, zipFile := range zipReader.File {
 		fileReader, err := zipFile.Open()
 		if err != nil {
 			logger.LoggerTransformer.Errorf("Failed to open YAML file inside zip: %v", err)
-			return nil, err
+			return err
 		}
 		defer fileReader.Close()
 
 		yamlData, err := io.ReadAll(fileReader)
 		if err != nil {
 			logger.LoggerTransformer.Errorf("Failed to read YAML file inside zip: %v", err)
-			return nil, err
+			return err
 		}
 
 		var crdData map[string]interface{}
 		if err := yaml.Unmarshal(yamlData, &crdData); err != nil {
 			logger.LoggerTransformer.Errorf("Failed to unmarshal YAML data to parse the Kind: %v", err)
-			return nil, err
+			return err
 		}
 
 		kind, ok := crdData["kind"].(string)
 		if !ok {
 			logger.LoggerTransformer.Errorf("Kind attribute not found in the given yaml file.")
-			return nil, err
+			return errors.New("kind attribute not found in the given yaml file")
 		}
 
 		switch kind {
@@ -287,19 +334,107 @@ func GenerateCRs(apkConf string, apiDefinition string, certContainer transformer
 			logger.LoggerSync.Errorf("[!]Unknown Kind parsed from the YAML File: %v", kind)
 		}
 	}
-	// Create ConfigMap to store the cert data if mTLS has enabled
-	if certContainer.ClientCertObj.CertAvailable {
-		createConfigMaps(certContainer.ClientCertObj.ClientCertFiles, &k8sArtifact)
+	return nil
+}
+
+// addStreamingTrafficPolicy applies the upgrade and timeout settings of WS, SSE and WebSub APIs to all the HTTPRoutes
+// of the API. Envoy Gateway accepts a single BackendTrafficPolicy per route, hence the settings are merged into the
+// BackendTrafficPolicies already targeting the routes and a new policy only targets the remaining routes.
+func addStreamingTrafficPolicy(apkConf string, k8sArtifact *K8sArtifacts) {
+	var apkConfData resourceTypes.APKConf
+	var apkConfType struct {
+		Type string `yaml:"type"`
+	}
+	if err := yaml.Unmarshal([]byte(apkConf), &apkConfType); err != nil {
+		logger.LoggerTransformer.Errorf("Error while reading the api type from apk-conf: %v", err)
+		return
 	}
+	apkConfData.Type = apkConfType.Type
+	if !asyncGenerator.IsStreamingAPI(apkConfData.Type) || len(k8sArtifact.HTTPRoutes) == 0 {
+		return
+	}
+	streamingSettings := asyncGenerator.Generator().RetrieveStreamingSettings(apkConfData)
 
-	// Create ConfigMap to store the cert data if endpoint security has enabled
-	if certContainer.EndpointCertObj.CertAvailable {
-		createConfigMaps(certContainer.EndpointCertObj.EndpointCertFiles, &k8sArtifact)
+	targetedRoutes := make(map[string]bool)
+	for _, backendTrafficPolicy := range k8sArtifact.BackendTrafficPolicies {
+		merged := false
+		for _, targetRef := range backendTrafficPolicy.Spec.TargetRefs {
+			if string(targetRef.Kind) != "HTTPRoute" || targetRef.SectionName != nil {
+				continue
+			}
+			if _, exists := k8sArtifact.HTTPRoutes[string(targetRef.Name)]; exists {
+				targetedRoutes[string(targetRef.Name)] = true
+				merged = true
+			}
+		}
+		if merged {
+			applyStreamingSettings(&backendTrafficPolicy.Spec, streamingSettings)
+			logger.LoggerTransformer.Debugf("Streaming settings merged into the BackendTrafficPolicy %s", backendTrafficPolicy.Name)
+		}
 	}
 
-	createEndpointSecrets(certContainer.SecretData, &k8sArtifact)
+	routeNames := make([]string, 0, len(k8sArtifact.HTTPRoutes))
+	for name := range k8sArtifact.HTTPRoutes {
+		if !targetedRoutes[name] {
+			routeNames = append(routeNames, name)
+		}
+	}
+	if len(routeNames) == 0 {
+		return
+	}
+	sort.Strings(routeNames)
+	targetRefs := make([]gwapiv1a2.LocalPolicyTargetReferenceWithSectionName, 0, len(routeNames))
+	for _, name := range routeNames {
+		targetRefs = append(targetRefs, gwapiv1a2.LocalPolicyTargetReferenceWithSectionName{
+			LocalPolicyTargetReference: gwapiv1a2.LocalPolicyTargetReference{
+				Group: gwapiv1.GroupName,
+				Kind:  "HTTPRoute",
+				Name:  gwapiv1.ObjectName(name),
+			},
+		})
+	}
+
+	backendTrafficPolicy := gatewayv1alpha1.BackendTrafficPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayv1alpha1.KindBackendTrafficPolicy,
+			APIVersion: gatewayv1alpha1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   generateUniqueNameFormAPI(k8sArtifact.RouteMetadata) + "-" + strings.ToLower(apkConfData.Type) + "-streaming",
+			Labels: make(map[string]string),
+		},
+	}
+	backendTrafficPolicy.Spec.TargetRefs = targetRefs
+	applyStreamingSettings(&backendTrafficPolicy.Spec, streamingSettings)
+
+	logger.LoggerTransformer.Debugf("Streaming BackendTrafficPolicy added for %s API: %s", apkConfData.Type, backendTrafficPolicy.Name)
+	k8sArtifact.BackendTrafficPolicies[backendTrafficPolicy.Name] = &backendTrafficPolicy
+}
 
-	return &k8sArtifact, nil
+// applyStreamingSettings sets the upgrade and timeout settings of a streaming API on a BackendTrafficPolicy
+func applyStreamingSettings(spec *gatewayv1alpha1.BackendTrafficPolicySpec, streamingSettings asyncGenerator.StreamingSettings) {
+	if streamingSettings.UpgradeType != "" {
+		spec.HTTPUpgrade = []*gatewayv1alpha1.ProtocolUpgradeConfig{
+			{Type: streamingSettings.UpgradeType},
+		}
+	}
+	httpTimeout := &gatewayv1alpha1.HTTPTimeout{}
+	if streamingSettings.IdleTimeout > 0 {
+		idleTimeout := gwapiv1.Duration(streamingSettings.IdleTimeout.String())
+		httpTimeout.ConnectionIdleTimeout = &idleTimeout
+	}
+	if streamingSettings.RequestTimeout > 0 {
+		requestTimeout := gwapiv1.Duration(streamingSettings.RequestTimeout.String())
+		httpTimeout.RequestTimeout = &requestTimeout
+	} else {
+		// long lived streams must not be cut off by the default route timeout
+		requestTimeout := gwapiv1.Duration("0s")
+		httpTimeout.RequestTimeout = &requestTimeout
+	}
+	if spec.Timeout == nil {
+		spec.Timeout = &gatewayv1alpha1.Timeout{}
+	}
+	spec.Timeout.HTTP = httpTimeout
 }
 
 // UpdateCRS cr update
@@ -337,6 +472,19 @@ func replaceVhost(k8sArtifact *K8sArtifacts, vhost string, deploymentType string
 			}
 		}
 	}
+	for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+		for _, rule := range grpcRoute.Spec.Rules {
+			for _, filter := range rule.Filters {
+				if filter.Type == "ExtensionRef" && filter.ExtensionRef != nil && filter.ExtensionRef.Kind == "RouteMetadata" {
+					if grpcRoute.Annotations["gateway.envoyproxy.io/kgw-envtype"] == "SANDBOX" {
+						grpcRoute.Spec.Hostnames = []gwapiv1.Hostname{gwapiv1.Hostname(sanboxLabel + vhost)}
+					} else {
+						grpcRoute.Spec.Hostnames = []gwapiv1.Hostname{gwapiv1.Hostname(productionLabel + vhost)}
+					}
+				}
+			}
+		}
+	}
 	// TODO: GQLRoutes are not supported in the new envoy config. Check if there any additional
 	// modifications are needed for that
 }
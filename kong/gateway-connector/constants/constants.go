@@ -35,6 +35,13 @@ var (
 		Resource: "httproutes",
 	}
 
+	// GRPCRouteGVR defines the GRPCRoute GroupVersionResource
+	GRPCRouteGVR = schema.GroupVersionResource{
+		Group:    "gateway.networking.k8s.io",
+		Version:  "v1",
+		Resource: "grpcroutes",
+	}
+
 	// ServiceGVR defines the Service GroupVersionResource
 	ServiceGVR = schema.GroupVersionResource{
 		Group:    "",
@@ -66,17 +73,20 @@ var (
 
 // Kubernetes API Versions
 const (
-	KongAPIVersion = "configuration.konghq.com/v1"
-	CoreAPIVersion = "v1"
+	KongAPIVersion     = "configuration.konghq.com/v1"
+	KongBetaAPIVersion = "configuration.konghq.com/v1beta1"
+	CoreAPIVersion     = "v1"
 )
 
 // Kubernetes Resource Kinds
 const (
-	HTTPRouteKind    = "HTTPRoute"
-	ServiceKind      = "Service"
-	KongConsumerKind = "KongConsumer"
-	SecretKind       = "Secret"
-	KongPluginKind   = "KongPlugin"
+	HTTPRouteKind          = "HTTPRoute"
+	GRPCRouteKind          = "GRPCRoute"
+	ServiceKind            = "Service"
+	KongConsumerKind       = "KongConsumer"
+	SecretKind             = "Secret"
+	KongPluginKind         = "KongPlugin"
+	KongUpstreamPolicyKind = "KongUpstreamPolicy"
 )
 
 // Time unit keys and values for Kong Gateway configurations
@@ -208,6 +218,7 @@ const (
 const (
 	APIPolicyType          = "API"
 	SubscriptionPolicyType = "SUBSCRIPTION"
+	ApplicationPolicyType  = "APPLICATION"
 )
 
 // Kong Plugin Types
@@ -217,6 +228,19 @@ const (
 	ACLPlugin          = "acl"
 	KeyAuthPlugin      = "key-auth"
 	JWTPlugin          = "jwt"
+	// RequestTerminationPlugin responds to the requests of blocked APIs without proxying them
+	RequestTerminationPlugin = "request-termination"
+	// ResponseTransformerPlugin adds the deprecation headers to the responses of deprecated APIs
+	ResponseTransformerPlugin = "response-transformer"
+	// RequestTransformerPlugin adds the credentials of the backend to the requests of APIs with endpoint security
+	RequestTransformerPlugin = "request-transformer"
+	// UpstreamOAuthPlugin obtains the OAuth2 tokens of the backend for APIs with OAuth2 endpoint security. The
+	// plugin is available in Kong Gateway Enterprise only.
+	UpstreamOAuthPlugin = "upstream-oauth"
+	// RateLimitingAdvancedPlugin enforces the application policies on the consumers. Kong runs a single instance of
+	// a plugin for a request, so the application limits need a plugin other than the rate-limiting plugin of the
+	// subscription limits. The plugin is available in Kong Gateway Enterprise only.
+	RateLimitingAdvancedPlugin = "rate-limiting-advanced"
 )
 
 // Kong Plugin Configuration Fields
@@ -289,6 +313,81 @@ const (
 	SubscriptionStateUnblocked       = "UNBLOCKED"
 )
 
+// API Lifecycle States
+const (
+	BlockedLifeCycleState    = "BLOCKED"
+	DeprecatedLifeCycleState = "DEPRECATED"
+	RetiredLifeCycleState    = "RETIRED"
+)
+
+// API Lifecycle Configuration
+const (
+	// Annotations of the KongPlugin enforcing the lifecycle state of an API, from which the state is applied again
+	// when a new revision of the API is deployed
+	LifeCycleStateAnnotation = "kgw.wso2.com/lifecycle-state"
+	DeprecatedAtAnnotation   = "kgw.wso2.com/deprecated-at"
+
+	// Gateway agent configurations of the lifecycle states
+	BlockedAPIStatusCodeConfig      = "blockedAPIStatusCode"
+	BlockedAPIMessageConfig         = "blockedAPIMessage"
+	DeprecatedAPISunsetPeriodConfig = "deprecatedAPISunsetPeriod"
+
+	// Default Values
+	DefaultBlockedAPIStatusCode = 503
+	DefaultBlockedAPIMessage    = "API blocked"
+
+	// Plugin Config Fields
+	RequestTerminationStatusCodeField = "status_code"
+	RequestTerminationMessageField    = "message"
+	ResponseTransformerAddField       = "add"
+	ResponseTransformerHeadersField   = "headers"
+
+	// Response Headers
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+)
+
+// Endpoint Security Configuration
+const (
+	// Endpoint Security Types
+	BasicEndpointSecurityType  = "basic"
+	APIKeyEndpointSecurityType = "apikey"
+	OAuthEndpointSecurityType  = "oauth"
+
+	// OAuth2 Grant Types
+	ClientCredentialsGrantType = "client_credentials"
+	PasswordGrantType          = "password"
+
+	// UpstreamOAuthEnabledConfig is the gateway agent configuration enabling the upstream-oauth plugin for the APIs
+	// with OAuth2 endpoint security
+	UpstreamOAuthEnabledConfig = "upstreamOAuthEnabled"
+	EndpointSecuritySecretType = "endpoint-security"
+	AuthorizationHeader        = "Authorization"
+	BasicAuthPrefix            = "Basic "
+
+	// WebSocketProtocolsEnabledConfig is the gateway agent configuration enabling the ws and wss protocols of Kong
+	// Enterprise for the WebSocket APIs, which are carried over http and https otherwise
+	WebSocketProtocolsEnabledConfig = "webSocketProtocolsEnabled"
+
+	// ApplicationRateLimitEnabledConfig is the gateway agent configuration enforcing the application policies with
+	// the rate-limiting-advanced plugin of Kong Enterprise. The application policies of Kong OSS are not enforced, as
+	// the rate-limiting plugin of a consumer enforces its subscription policy and Kong applies a single instance of a
+	// plugin to a consumer.
+	ApplicationRateLimitEnabledConfig = "applicationRateLimitEnabled"
+
+	// Plugin Config Fields
+	RequestTransformerAddField     = "add"
+	RequestTransformerRemoveField  = "remove"
+	RequestTransformerHeadersField = "headers"
+	UpstreamOAuthField             = "oauth"
+	UpstreamOAuthTokenEndpoint     = "token_endpoint"
+	UpstreamOAuthGrantType         = "grant_type"
+	UpstreamOAuthClientID          = "client_id"
+	UpstreamOAuthClientSecret      = "client_secret"
+	UpstreamOAuthUsername          = "username"
+	UpstreamOAuthPassword          = "password"
+)
+
 // Subscription Policy Names
 const (
 	UnlimitedPolicyName = "Unlimited"
@@ -355,6 +454,11 @@ const (
 	RateLimitingTypeKey = "rate-limiting"
 )
 
+// Application Policy Types
+const (
+	ApplicationTypeKey = "application"
+)
+
 // Quota Types
 const (
 	AIAPIQuotaType   = "aiApiQuota"
@@ -362,6 +466,22 @@ const (
 	EventCountType   = "eventCount"
 )
 
+// Endpoint Resiliency Configuration
+const (
+	// Transport error codes of the control plane on which an endpoint is suspended for timing out
+	ConnectionTimeoutErrorCode = "101504"
+	ConnectTimeoutErrorCode    = "101508"
+
+	DefaultSuspendDurationMillis = 30000
+	TCPHealthCheckType           = "tcp"
+)
+
+// Endpoint Load Balancing Configuration
+const (
+	// Weights of the backends of the load balanced endpoints
+	LoadBalanceEndpointWeight = 1
+)
+
 // Rate Limit Configuration
 const (
 	ServiceLimitBy    = "service"
@@ -371,6 +491,62 @@ const (
 	SubscriberTypeKey = "subscriber"
 )
 
+// Rate Limit Policies, which define where the counters of the rate limits are kept
+const (
+	LocalRateLimitPolicy   = "local"
+	ClusterRateLimitPolicy = "cluster"
+	RedisRateLimitPolicy   = "redis"
+)
+
+// Rate Limit Policy Configuration
+const (
+	// Gateway agent configurations of the rate limit policy
+	RateLimitPolicyConfig          = "rateLimitPolicy"
+	RateLimitRedisHostConfig       = "rateLimitRedisHost"
+	RateLimitRedisPortConfig       = "rateLimitRedisPort"
+	RateLimitRedisUsernameConfig   = "rateLimitRedisUsername"
+	RateLimitRedisPasswordConfig   = "rateLimitRedisPassword"
+	RateLimitRedisDatabaseConfig   = "rateLimitRedisDatabase"
+	RateLimitRedisSSLConfig        = "rateLimitRedisSSL"
+	RateLimitRedisSSLVerifyConfig  = "rateLimitRedisSSLVerify"
+	RateLimitRedisTimeoutConfig    = "rateLimitRedisTimeout"
+	RateLimitRedisSecretConfig     = "rateLimitRedisSecret"
+	RateLimitRedisSecretKeyConfig  = "rateLimitRedisSecretKey"
+	DefaultRateLimitRedisPort      = 6379
+	DefaultRateLimitRedisSecretKey = "redis"
+
+	// Plugin Config Fields
+	PluginPolicyField    = "policy"
+	PluginRedisField     = "redis"
+	RedisHostField       = "host"
+	RedisPortField       = "port"
+	RedisDatabaseField   = "database"
+	RedisSSLField        = "ssl"
+	RedisSSLVerifyField  = "ssl_verify"
+	RedisTimeoutField    = "timeout"
+	RedisConfigPatchPath = "/redis"
+
+	// Config fields of the rate-limiting-advanced plugin
+	RateLimitAdvancedLimitField      = "limit"
+	RateLimitAdvancedWindowSizeField = "window_size"
+	RateLimitAdvancedIdentifierField = "identifier"
+	RateLimitAdvancedStrategyField   = "strategy"
+	RateLimitAdvancedSyncRateField   = "sync_rate"
+	// LocalRateLimitSyncRate keeps the counters of the local strategy in the memory of each Kong pod, while the
+	// counters of the other strategies are synchronized on each request
+	LocalRateLimitSyncRate = -1
+)
+
+// RateLimitWindowSizes maps the time units of the rate limits to the window sizes of the rate-limiting-advanced
+// plugin in seconds
+var RateLimitWindowSizes = map[string]int{
+	TimeUnitMinute: 60,
+	TimeUnitHour:   3600,
+	TimeUnitDay:    86400,
+	TimeUnitMonth:  2592000,
+	TimeUnitYear:   31536000,
+}
+
 // HTTP Methods
 const (
 	HTTPMethodOptions = "OPTIONS"
@@ -432,6 +608,8 @@ const (
 	SecretPrefix   = "secret-"
 	APIPrefix      = "api-"
 	PolicyPrefix   = "policy-"
+	// EnvironmentPrefix prefixes the ACL group of the consumers of an environment
+	EnvironmentPrefix = "environment-"
 )
 
 // Retry Configuration
@@ -448,6 +626,7 @@ const (
 	UpdateConsumerCredentialProdBlockedTask = "UpdateKongConsumerCredential-PROD_ONLY_BLOCKED"
 	UpdateConsumerCredentialUnblockedTask   = "UpdateKongConsumerCredential-UNBLOCKED"
 	UpdateConsumerCredentialRemoveTask      = "UpdateKongConsumerCredential-Remove"
+	UpdateRouteLifeCycleTask                = "UpdateRoutePluginAnnotation-LifeCycle"
 )
 
 // Error Messages
@@ -467,6 +646,10 @@ const (
 	// Protocols
 	HTTPProtocol  = "http"
 	HTTPSProtocol = "https"
+	GRPCProtocol  = "grpc"
+	GRPCSProtocol = "grpcs"
+	WSProtocol    = "ws"
+	WSSProtocol   = "wss"
 
 	// Standard Ports
 	HTTPPort  = 80
@@ -479,7 +662,13 @@ const (
 	ServiceTypeClusterIP    = "ClusterIP"
 
 	// Kong Annotations
-	KongProtocolAnnotation = "konghq.com/protocol"
+	KongProtocolAnnotation       = "konghq.com/protocol"
+	KongProtocolsAnnotation      = "konghq.com/protocols"
+	KongReadTimeoutAnnotation    = "konghq.com/read-timeout"
+	KongWriteTimeoutAnnotation   = "konghq.com/write-timeout"
+	KongConnectTimeoutAnnotation = "konghq.com/connect-timeout"
+	KongRetriesAnnotation        = "konghq.com/retries"
+	KongUpstreamPolicyAnnotation = "konghq.com/upstream-policy"
 
 	// Service Spec Paths
 	ServiceSpecType         = "type"
@@ -18,15 +18,20 @@
 package agent
 
 import (
+	"context"
+
 	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
 	v1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
 	v1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/discovery"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/utils"
+	kongMgtServer "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/managementserver"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/synchronizer"
+	kongTransformer "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -37,6 +42,7 @@ func PreRun(conf *config.Config, scheme *runtime.Scheme) {
 	utilruntime.Must(v1.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(v1beta1.AddToScheme(scheme))
+	kongMgtServer.RegisterSnapshotStores()
 }
 
 // initializeKongIntegrations sets up Kong-specific integrations with the common agent
@@ -51,17 +57,33 @@ func initializeKongIntegrations() {
 }
 
 // Run handles any configurations that runs on agent start.
-func Run(conf *config.Config, mgr manager.Manager) {
+func Run(conf *config.Config, mgr manager.Manager) error {
 	loggers.LoggerAgent.Infof("Starting Kong agent")
 
+	// The rate-limiting plugins are not generated with a rate limit policy other than the configured one
+	if _, err := kongTransformer.GetRateLimitPolicy(); err != nil {
+		loggers.LoggerAgent.Errorf("Invalid rate limit policy configuration: %v", err)
+		return err
+	}
+
 	loggers.LoggerAgent.Infof("Initializing Kong-specific integrations")
 	initializeKongIntegrations()
+	metrics.RegisterCacheSize("applications", kongMgtServer.GetProcessedApplicationCount)
 
 	loggers.LoggerAgent.Infof("Fetching rate limit policies from control plane")
-	synchronizer.FetchRateLimitPoliciesOnEvent("", "", mgr.GetClient())
+	if err := synchronizer.FetchRateLimitPoliciesOnEvent("", "", mgr.GetClient()); err != nil {
+		loggers.LoggerAgent.Errorf("Failed to deploy the rate limit policies: %v", err)
+	}
 
 	loggers.LoggerAgent.Infof("Fetching subscription rate limit policies from control plane")
-	synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent("", "", mgr.GetClient(), true)
+	if err := synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent("", "", mgr.GetClient(), true); err != nil {
+		loggers.LoggerAgent.Errorf("Failed to deploy the subscription rate limit policies: %v", err)
+	}
+
+	loggers.LoggerAgent.Infof("Fetching application rate limit policies from control plane")
+	if err := synchronizer.FetchApplicationRateLimitPoliciesOnEvent("", "", mgr.GetClient()); err != nil {
+		loggers.LoggerAgent.Errorf("Failed to deploy the application rate limit policies: %v", err)
+	}
 
 	loggers.LoggerAgent.Infof("Fetching key managers on startup")
 	synchronizer.FetchKeyManagersOnStartUp(mgr.GetClient())
@@ -69,7 +91,7 @@ func Run(conf *config.Config, mgr manager.Manager) {
 	loggers.LoggerAgent.Infof("Initializing Kong CR Watcher")
 	if err := discovery.CRWatcher.Initialize(); err != nil {
 		loggers.LoggerAgent.Errorf("Failed to initialize Kong CR Watcher: %v", err)
-		return
+		return err
 	}
 
 	loggers.LoggerAgent.Infof("Initializing HTTPRoutes and Services state")
@@ -79,10 +101,21 @@ func Run(conf *config.Config, mgr manager.Manager) {
 	go discovery.CRWatcher.Watch()
 
 	loggers.LoggerAgent.Infof("Fetching APIs on startup")
-	synchronizer.FetchAPIsOnEvent(conf, nil, mgr.GetClient())
+	// The APIs which failed to deploy are logged, as they are deployed again on their next deployment event
+	if apis, err := synchronizer.FetchAPIsOnEvent(context.Background(), conf, nil, mgr.GetClient()); err != nil {
+		if apis == nil {
+			loggers.LoggerAgent.Errorf("Failed to fetch the APIs on startup: %v", err)
+			return err
+		}
+		loggers.LoggerAgent.Errorf("Failed to deploy some of the APIs on startup: %v", err)
+	}
+
+	loggers.LoggerAgent.Infof("Fetching application policies of applications on startup")
+	synchronizer.FetchApplicationPoliciesOnStartUp(mgr.GetClient())
 
 	loggers.LoggerAgent.Infof("Fetching subscriptions on startup")
 	synchronizer.FetchAndProcessSubscriptionsOnStartUp(mgr.GetClient())
 
 	loggers.LoggerAgent.Infof("Kong agent startup completed successfully")
+	return nil
 }
@@ -18,14 +18,18 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	eventConstants "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
@@ -38,170 +42,247 @@ import (
 // var variables
 var (
 	ScopeList = make([]types.Scope, 0)
-	// timestamps needs to be maintained as it is not guranteed to receive them in order,
-	// hence older events should be discarded
-	apiListTimeStampMap          = make(map[string]int64, 0)
-	subsriptionsListTimeStampMap = make(map[string]int64, 0)
-	applicationListTimeStampMap  = make(map[string]int64, 0)
 )
 
-// HandleLifeCycleEvents handles the events of an api through out the life cycle
-func HandleLifeCycleEvents(data []byte) {
+// lifeCycleSequenceKeySuffix separates the lifecycle events of an API from its deployment events when sequencing
+const lifeCycleSequenceKeySuffix = "lifecycle"
+
+// HandleLifeCycleEvents handles the events of an api through out the life cycle. The routes of a blocked API are
+// terminated with the configured response, the responses of a deprecated API carry the deprecation headers and the
+// resources of a retired API are removed.
+func HandleLifeCycleEvents(data []byte, c client.Client) (err error) {
 	logger.LoggerEvents.Infof("Processing API lifecycle event with data length: %d bytes", len(data))
 
 	var apiEvent msg.APIEvent
 	if err := json.Unmarshal(data, &apiEvent); err != nil {
 		logger.LoggerEvents.Errorf("%s: %v", constants.UnmarshalErrorLifecycle, err)
-		return
+		return nil
 	}
 
 	logger.LoggerEvents.Debugf("%s: %+v", "API lifecycle event received", apiEvent)
+
+	if !belongsToTenant(apiEvent.TenantDomain) {
+		logger.LoggerEvents.Debugf("API lifecycle event for the API %s:%s is dropped due to having non related tenantDomain : %s",
+			apiEvent.APIName, apiEvent.APIVersion, apiEvent.TenantDomain)
+		return nil
+	}
+
+	if !sequencer.AcceptEvent(sequencer.API, apiEvent.UUID+":"+lifeCycleSequenceKeySuffix, apiEvent.Event) {
+		logger.LoggerEvents.Infof("Skipping older or redelivered lifecycle event changing the state of API %s to %s",
+			apiEvent.UUID, apiEvent.APIStatus)
+		return nil
+	}
+	defer sequencer.ForgetOnFailure(sequencer.API, &err, apiEvent.UUID+":"+lifeCycleSequenceKeySuffix)
+
+	if strings.EqualFold(apiEvent.APIStatus, constants.RetiredLifeCycleState) {
+		internalk8sClient.UndeployAPICRs(apiEvent.UUID, c)
+		kongMgtServer.RemoveProcessedAPI(apiEvent.UUID)
+		logger.LoggerEvents.Infof("Removed the resources of the retired API %s", apiEvent.UUID)
+		return nil
+	}
+
+	lifeCycle := transformer.NewAPILifeCycle(apiEvent.APIStatus, apiEvent.TimeStamp)
+	lifeCyclePlugin := transformer.GenerateLifeCyclePlugin(apiEvent.UUID, lifeCycle)
+	if err := internalk8sClient.UpdateAPILifeCyclePlugin(apiEvent.UUID, apiEvent.TenantDomain, lifeCyclePlugin,
+		transformer.LifeCyclePluginNames(apiEvent.UUID), c); err != nil {
+		return fmt.Errorf("error applying the lifecycle state %s to the API %s: %w", apiEvent.APIStatus,
+			apiEvent.UUID, err)
+	}
+	logger.LoggerEvents.Infof("Applied the lifecycle state %s to the API %s", lifeCycle.State, apiEvent.UUID)
+	return nil
 }
 
 // HandleAPIEvents to process api related data
-func HandleAPIEvents(data []byte, eventType string, conf *config.Config, c client.Client) {
-	logger.LoggerEvents.Infof("Processing API event with EventType: %s, data length: %d bytes", eventType, len(data))
+func HandleAPIEvents(ctx context.Context, data []byte, eventType string, conf *config.Config, c client.Client) (err error) {
+	logger.LoggerEvents.FromContext(ctx).Infof("Processing API event with EventType: %s, data length: %d bytes", eventType, len(data))
 
 	var apiEvent msg.APIEvent
 	if err := json.Unmarshal(data, &apiEvent); err != nil {
-		logger.LoggerEvents.Errorf("%s: %v", constants.UnmarshalErrorAPI, err)
-		return
+		logger.LoggerEvents.FromContext(ctx).Errorf("%s: %v", constants.UnmarshalErrorAPI, err)
+		return nil
 	}
+	ctx = logging.ContextWithFields(ctx, logging.Fields{logging.APIUUID: apiEvent.UUID})
 
 	if !belongsToTenant(apiEvent.TenantDomain) {
-		logger.LoggerEvents.Debugf("API event for the API %s:%s is dropped due to having non related tenantDomain : %s",
+		logger.LoggerEvents.FromContext(ctx).Debugf("API event for the API %s:%s is dropped due to having non related tenantDomain : %s",
 			apiEvent.APIName, apiEvent.Version, apiEvent.TenantDomain)
-		return
+		return nil
 	}
 
-	currentTimeStamp := apiEvent.Event.TimeStamp
+	// The events are not guaranteed to be received in order, hence the older and redelivered events are discarded
+	acceptedKeys := sequencer.AcceptAPIEvent(apiEvent)
+	if len(acceptedKeys) == 0 {
+		logger.LoggerEvents.FromContext(ctx).Infof("Skipping older or redelivered %s event for API %s", apiEvent.Event.Type, apiEvent.UUID)
+		return nil
+	}
+	defer sequencer.ForgetOnFailure(sequencer.API, &err, acceptedKeys...)
 
 	if strings.EqualFold(eventConstants.DeployAPIToGateway, apiEvent.Event.Type) {
-		internalk8sClient.UndeployAPICRs(apiEvent.UUID, c)
-		go synchronizer.FetchAPIsOnEvent(conf, &apiEvent.UUID, c)
+		internalk8sClient.UndeployAPIRevisionCRs(apiEvent.UUID, c)
+		if _, err := synchronizer.FetchAPIsOnEvent(ctx, conf, &apiEvent.UUID, c); err != nil {
+			return fmt.Errorf("error deploying the API %s: %w", apiEvent.UUID, err)
+		}
 	}
 
-	for _, env := range apiEvent.GatewayLabels {
-		mapKey := apiEvent.UUID + ":" + env
-		if isLaterEvent(apiListTimeStampMap, mapKey, currentTimeStamp) {
-			logger.LoggerEvents.Debugf("Skipping older event for API %s, environment %s", apiEvent.UUID, env)
-			break
-		}
-		if strings.EqualFold(eventConstants.RemoveAPIFromGateway, apiEvent.Event.Type) {
-			internalk8sClient.UndeployAPICRs(apiEvent.UUID, c)
-			kongMgtServer.RemoveProcessedAPI(apiEvent.UUID)
-			break
-		}
+	if strings.EqualFold(eventConstants.RemoveAPIFromGateway, apiEvent.Event.Type) {
+		internalk8sClient.UndeployAPICRs(apiEvent.UUID, c)
+		kongMgtServer.RemoveProcessedAPI(apiEvent.UUID)
 	}
+	return nil
 }
 
 // HandlePolicyEvents to process policy related events
-func HandlePolicyEvents(data []byte, eventType string, c client.Client) {
-	logger.LoggerEvents.Infof("Processing Policy event with EventType: %s, data length: %d bytes", eventType, len(data))
+func HandlePolicyEvents(ctx context.Context, data []byte, eventType string, c client.Client) (err error) {
+	logger.LoggerEvents.FromContext(ctx).Infof("Processing Policy event with EventType: %s, data length: %d bytes", eventType, len(data))
 
 	conf, _ := config.ReadConfigs()
 
 	var policyEvent msg.PolicyInfo
 	if err := json.Unmarshal(data, &policyEvent); err != nil {
-		logger.LoggerEvents.Errorf("%s: %v", constants.UnmarshalErrorPolicy, err)
-		return
+		logger.LoggerEvents.FromContext(ctx).Errorf("%s: %v", constants.UnmarshalErrorPolicy, err)
+		return nil
 	}
 
-	processPolicyEvent(policyEvent, eventType, c, conf)
+	if !sequencer.AcceptEvent(sequencer.Policy, sequencer.PolicyKey(policyEvent), policyEvent.Event) {
+		logger.LoggerEvents.FromContext(ctx).Infof("Skipping older or redelivered %s event for policy %s", eventType, policyEvent.PolicyName)
+		return nil
+	}
+	defer sequencer.ForgetOnFailure(sequencer.Policy, &err, sequencer.PolicyKey(policyEvent))
+
+	return processPolicyEvent(policyEvent, eventType, c, conf)
 }
 
 // processPolicyEvent handles common policy event processing logic
-func processPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) {
+func processPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) error {
 	switch {
 	case strings.EqualFold(policyEvent.PolicyType, constants.APIPolicyType):
-		handleAPIPolicyEvent(policyEvent, eventType, c, conf)
+		return handleAPIPolicyEvent(policyEvent, eventType, c, conf)
 	case strings.EqualFold(policyEvent.PolicyType, constants.SubscriptionPolicyType):
-		handleSubscriptionPolicyEvent(policyEvent, eventType, c, conf)
+		return handleSubscriptionPolicyEvent(policyEvent, eventType, c, conf)
+	case strings.EqualFold(policyEvent.PolicyType, constants.ApplicationPolicyType):
+		return handleApplicationPolicyEvent(policyEvent, eventType, c, conf)
 	default:
 		logger.LoggerEvents.Warnf("Unknown policy type: %s", policyEvent.PolicyType)
 	}
+	return nil
 }
 
 // handleAPIPolicyEvent processes API policy events
-func handleAPIPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) {
+func handleAPIPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) error {
 	logger.LoggerEvents.Infof("Policy: %s for policy type: %s for tenant: %s",
 		policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
 
 	switch eventType {
 	case eventConstants.PolicyCreate, eventConstants.PolicyUpdate:
-		synchronizer.FetchRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c)
+		if err := synchronizer.FetchRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c); err != nil {
+			return fmt.Errorf("error deploying the API policy %s: %w", policyEvent.PolicyName, err)
+		}
 		logger.LoggerEvents.Debugf("Successfully processed %s event for API policy: %s", eventType, policyEvent.PolicyName)
 	case eventConstants.PolicyDelete:
 		managementserver.DeleteRateLimitPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
 		crName := transformer.GeneratePolicyCRName(policyEvent.PolicyName, policyEvent.TenantDomain, constants.RateLimitingPlugin, constants.PolicyTypeKey)
-		internalk8sClient.UnDeployKongPluginCR(crName, c, conf)
+		if err := internalk8sClient.UnDeployKongPluginCR(crName, conf.DataPlane.GetOrganizationNamespace(policyEvent.TenantDomain), c); err != nil {
+			return fmt.Errorf("error undeploying the API policy %s: %w", policyEvent.PolicyName, err)
+		}
 		logger.LoggerEvents.Debugf("Successfully deleted API policy: %s and undeployed CR: %s", policyEvent.PolicyName, crName)
 	}
 
 	ratelimitPolicies := managementserver.GetAllRateLimitPolicies()
 	logger.LoggerEvents.Debugf("%s: %v", "Rate Limit Policies Internal Map", ratelimitPolicies)
+	return nil
 }
 
 // handleSubscriptionPolicyEvent processes subscription policy events
-func handleSubscriptionPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) {
+func handleSubscriptionPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) error {
 	logger.LoggerEvents.Infof("Policy: %s for policy type: %s for tenant: %s",
 		policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
 
 	switch eventType {
 	case eventConstants.PolicyCreate, eventConstants.PolicyUpdate:
-		synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c, false)
+		if err := synchronizer.FetchSubscriptionRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c, false); err != nil {
+			return fmt.Errorf("error deploying the subscription policy %s: %w", policyEvent.PolicyName, err)
+		}
 		logger.LoggerEvents.Debugf("Successfully processed %s event for subscription policy: %s", eventType, policyEvent.PolicyName)
 	case eventConstants.PolicyDelete:
 		managementserver.DeleteSubscriptionPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
 		crName := transformer.GeneratePolicyCRName(policyEvent.PolicyName, policyEvent.TenantDomain,
 			constants.RateLimitingTypeKey, constants.SubscriptionTypeKey)
-		internalk8sClient.UnDeployKongPluginCR(crName, c, conf)
+		if err := internalk8sClient.UnDeployKongPluginCR(crName, conf.DataPlane.GetOrganizationNamespace(policyEvent.TenantDomain), c); err != nil {
+			return fmt.Errorf("error undeploying the subscription policy %s: %w", policyEvent.PolicyName, err)
+		}
 		logger.LoggerEvents.Debugf("Successfully deleted subscription policy: %s and undeployed CR: %s", policyEvent.PolicyName, crName)
 	}
 
 	ratelimitPolicies := managementserver.GetAllRateLimitPolicies()
 	logger.LoggerEvents.Debugf("%s: %v", "Rate Limit Policies Internal Map", ratelimitPolicies)
+	return nil
+}
+
+// handleApplicationPolicyEvent processes application policy events
+func handleApplicationPolicyEvent(policyEvent msg.PolicyInfo, eventType string, c client.Client, conf *config.Config) error {
+	logger.LoggerEvents.Infof("Policy: %s for policy type: %s for tenant: %s",
+		policyEvent.PolicyName, policyEvent.PolicyType, policyEvent.TenantDomain)
+
+	switch eventType {
+	case eventConstants.PolicyCreate, eventConstants.PolicyUpdate:
+		if err := synchronizer.FetchApplicationRateLimitPoliciesOnEvent(policyEvent.PolicyName, policyEvent.TenantDomain, c); err != nil {
+			return fmt.Errorf("error deploying the application policy %s: %w", policyEvent.PolicyName, err)
+		}
+		if err := synchronizer.ApplyApplicationPolicyToApplications(policyEvent.PolicyName, policyEvent.TenantDomain, c, conf); err != nil {
+			return fmt.Errorf("error applying the application policy %s to its applications: %w", policyEvent.PolicyName, err)
+		}
+		logger.LoggerEvents.Debugf("Successfully processed %s event for application policy: %s", eventType, policyEvent.PolicyName)
+	case eventConstants.PolicyDelete:
+		managementserver.DeleteApplicationPolicy(policyEvent.PolicyName, policyEvent.TenantDomain)
+		applications, err := synchronizer.RemoveApplicationPolicyFromApplications(policyEvent.PolicyName, policyEvent.TenantDomain, c, conf)
+		if err != nil {
+			return fmt.Errorf("error removing the application policy %s from its applications: %w", policyEvent.PolicyName, err)
+		}
+		crName := transformer.GeneratePolicyCRName(policyEvent.PolicyName, policyEvent.TenantDomain,
+			constants.RateLimitingAdvancedPlugin, constants.ApplicationTypeKey)
+		if err := internalk8sClient.UnDeployKongPluginCR(crName, conf.DataPlane.GetOrganizationNamespace(policyEvent.TenantDomain), c); err != nil {
+			return fmt.Errorf("error undeploying the application policy %s: %w", policyEvent.PolicyName, err)
+		}
+		logger.LoggerEvents.Debugf("Successfully deleted application policy: %s, undeployed CR: %s and removed it from applications %v",
+			policyEvent.PolicyName, crName, applications)
+	}
+	return nil
 }
 
 // HandleAIProviderEvents to process AI Provider related events
-func HandleAIProviderEvents(data []byte, eventType string, client client.Client) {
+func HandleAIProviderEvents(data []byte, eventType string, client client.Client) error {
 	logger.LoggerEvents.Infof("Processing AI Provider event with EventType: %s, data length: %d bytes", eventType, len(data))
 
 	var aiProviderEvent msg.AIProviderEvent
 	if err := json.Unmarshal(data, &aiProviderEvent); err != nil {
 		logger.LoggerEvents.Errorf("%s: %v", constants.UnmarshalErrorAIProvider, err)
-		return
+		return nil
 	}
 
 	logger.LoggerEvents.Debugf("%s: %+v", "AI provider event received", aiProviderEvent)
+	return nil
 }
 
 // HandleScopeEvents to process scope related events
-func HandleScopeEvents(data []byte, eventType string, client client.Client) {
+func HandleScopeEvents(data []byte, eventType string, client client.Client) error {
 	logger.LoggerEvents.Infof("Processing Scope event with EventType: %s, data length: %d bytes", eventType, len(data))
 
 	var scopeEvent msg.ScopeEvent
 	if err := json.Unmarshal(data, &scopeEvent); err != nil {
 		logger.LoggerEvents.Errorf("%s: %v", constants.UnmarshalErrorScope, err)
-		return
+		return nil
 	}
 
 	logger.LoggerEvents.Debugf("%s: %+v", "Scope event received", scopeEvent)
+	return nil
 }
 
-// belongsToTenant checks if the tenant domain belongs to the connected tenant
+// belongsToTenant checks if the tenant domain belongs to an organization served by this agent
 func belongsToTenant(tenantDomain string) bool {
-	// TODO : enable this once the events are fixed in apim
-	// return config.GetControlPlaneConnectedTenantDomain() == tenantDomain
-	return true
-}
-
-// isLaterEvent checks if the current event is later than the stored timestamp
-// and updates the timestamp map accordingly
-func isLaterEvent(timeStampMap map[string]int64, mapKey string, currentTimeStamp int64) bool {
-	if timeStamp, exists := timeStampMap[mapKey]; exists && timeStamp > currentTimeStamp {
-		return true
+	conf, err := config.ReadConfigs()
+	if err != nil {
+		logger.LoggerEvents.Errorf("Error while reading the configs, checking the organization %s against the default configs: %v",
+			tenantDomain, err)
 	}
-	timeStampMap[mapKey] = currentTimeStamp
-	return false
+	return conf.DataPlane.ServesOrganization(tenantDomain)
 }
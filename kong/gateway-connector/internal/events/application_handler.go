@@ -18,6 +18,7 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	eventConstants "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/constants"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
 	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
@@ -36,51 +38,52 @@ import (
 )
 
 // HandleApplicationEvents to process application related events
-func HandleApplicationEvents(data []byte, eventType string, c client.Client) {
-	logger.LoggerEvents.Infof("Processing application event with EventType: %s, data length: %d bytes", eventType, len(data))
+func HandleApplicationEvents(ctx context.Context, data []byte, eventType string, c client.Client) error {
+	logger.LoggerEvents.FromContext(ctx).Infof("Processing application event with EventType: %s, data length: %d bytes", eventType, len(data))
 
 	conf, _ := config.ReadConfigs()
 
 	switch {
 	case strings.EqualFold(eventConstants.ApplicationRegistration, eventType):
-		handleApplicationRegistration(data, c, conf)
+		return handleApplicationRegistration(data, c, conf)
 	case strings.EqualFold(eventConstants.RemoveApplicationKeyMapping, eventType):
-		handleRemoveApplicationKeyMapping(data, c, conf)
+		return handleRemoveApplicationKeyMapping(data, c, conf)
 	default:
-		handleApplicationEvent(data, c, conf)
+		return handleApplicationEvent(data, c, conf)
 	}
 }
 
 // handleApplicationRegistration processes application registration events
-func handleApplicationRegistration(data []byte, c client.Client, conf *config.Config) {
+func handleApplicationRegistration(data []byte, c client.Client, conf *config.Config) error {
 	var applicationRegistrationEvent msg.ApplicationRegistrationEvent
 	if err := json.Unmarshal(data, &applicationRegistrationEvent); err != nil {
 		logger.LoggerEvents.Errorf("%s: %v", kongConstants.UnmarshalErrorApplication, err)
-		return
+		return nil
 	}
 
 	consumerName := transformer.GenerateConsumerName(applicationRegistrationEvent.ApplicationUUID, strings.ToLower(applicationRegistrationEvent.KeyType))
-	consumer := internalk8sClient.GetKongConsumerCR(consumerName, c, conf)
+	consumer := internalk8sClient.GetKongConsumerCR(consumerName,
+		conf.DataPlane.GetOrganizationNamespace(applicationRegistrationEvent.TenantDomain), c)
 
 	if consumer == nil {
 		logger.LoggerEvents.Debugf("Application Registration consumer not found for application UUID %s, skipping creation",
 			applicationRegistrationEvent.ApplicationUUID)
-		return
+		return nil
 	}
 
 	if !belongsToTenant(applicationRegistrationEvent.TenantDomain) {
 		logger.LoggerEvents.Debugf("Application Registration event is dropped due to having non related tenantDomain : %s",
 			applicationRegistrationEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	if !kongMgtServer.IsApplicationProcessed(applicationRegistrationEvent.ApplicationUUID) {
 		logger.LoggerEvents.Infof("Application %s is not processed. skipping application registration event",
 			applicationRegistrationEvent.ApplicationUUID)
-		return
+		return nil
 	}
 
-	synchronizer.ProcessApplicationRegistration(
+	return synchronizer.ProcessApplicationRegistration(
 		applicationRegistrationEvent.ApplicationUUID,
 		applicationRegistrationEvent.ConsumerKey,
 		applicationRegistrationEvent.KeyManager,
@@ -92,17 +95,17 @@ func handleApplicationRegistration(data []byte, c client.Client, conf *config.Co
 }
 
 // handleRemoveApplicationKeyMapping processes application key removal events
-func handleRemoveApplicationKeyMapping(data []byte, c client.Client, conf *config.Config) {
+func handleRemoveApplicationKeyMapping(data []byte, c client.Client, conf *config.Config) error {
 	var applicationRegistrationEvent msg.ApplicationRegistrationEvent
 	if err := json.Unmarshal(data, &applicationRegistrationEvent); err != nil {
 		logger.LoggerEvents.Errorf("Error occurred while unmarshalling Application Registration event data %v", err)
-		return
+		return nil
 	}
 
 	if !belongsToTenant(applicationRegistrationEvent.TenantDomain) {
 		logger.LoggerEvents.Debugf("Application Registration event is dropped due to having non related tenantDomain : %s",
 			applicationRegistrationEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	logger.LoggerEvents.Debugf("Received Remove Application Key Mapping Event: %+v", applicationRegistrationEvent)
@@ -110,7 +113,7 @@ func handleRemoveApplicationKeyMapping(data []byte, c client.Client, conf *confi
 	if !kongMgtServer.IsApplicationProcessed(applicationRegistrationEvent.ApplicationUUID) {
 		logger.LoggerEvents.Infof("Application %s is not processed. skipping remove application key mapping event",
 			applicationRegistrationEvent.ApplicationUUID)
-		return
+		return nil
 	}
 
 	jwtCredentialSecretName := transformer.GenerateSecretName(
@@ -119,48 +122,56 @@ func handleRemoveApplicationKeyMapping(data []byte, c client.Client, conf *confi
 		kongConstants.JWTCredentialType)
 	removeCredentials := []string{jwtCredentialSecretName}
 
-	utils.RetryKongCRUpdate(func() error {
-		internalk8sClient.UpdateKongConsumerCredential(
+	if err := utils.RetryKongCRUpdate(func() error {
+		return internalk8sClient.UpdateKongConsumerCredential(
 			applicationRegistrationEvent.ApplicationUUID,
 			kongConstants.EmptyString,
 			c, conf, nil, removeCredentials)
-		return nil
-	}, kongConstants.RemoveApplicationKeyTaskName, kongConstants.MaxRetries)
+	}, kongConstants.RemoveApplicationKeyTaskName, kongConstants.MaxRetries); err != nil {
+		return fmt.Errorf("error removing the credential %s of application %s: %w", jwtCredentialSecretName,
+			applicationRegistrationEvent.ApplicationUUID, err)
+	}
 
-	internalk8sClient.UnDeploySecretCR(jwtCredentialSecretName, c, conf)
+	return internalk8sClient.UnDeploySecretCR(jwtCredentialSecretName,
+		conf.DataPlane.GetOrganizationNamespace(applicationRegistrationEvent.TenantDomain), c)
 }
 
 // handleApplicationEvent processes general application events
-func handleApplicationEvent(data []byte, c client.Client, conf *config.Config) {
+func handleApplicationEvent(data []byte, c client.Client, conf *config.Config) (err error) {
 	var applicationEvent msg.ApplicationEvent
 	if err := json.Unmarshal(data, &applicationEvent); err != nil {
 		logger.LoggerEvents.Errorf("Error occurred while unmarshalling Application event data %v", err)
-		return
+		return nil
 	}
 
 	if !belongsToTenant(applicationEvent.TenantDomain) {
 		logger.LoggerEvents.Debugf("Application event for the Application : %s (with uuid %s) is dropped due to having non related tenantDomain : %s",
 			applicationEvent.ApplicationName, applicationEvent.UUID, applicationEvent.TenantDomain)
-		return
+		return nil
 	}
 
-	if isLaterEvent(applicationListTimeStampMap, fmt.Sprint(applicationEvent.ApplicationID), applicationEvent.TimeStamp) {
-		return
+	if !sequencer.AcceptEvent(sequencer.Application, fmt.Sprint(applicationEvent.ApplicationID), applicationEvent.Event) {
+		logger.LoggerEvents.Infof("Skipping older or redelivered %s event for application %s", applicationEvent.Event.Type, applicationEvent.UUID)
+		return nil
 	}
+	defer sequencer.ForgetOnFailure(sequencer.Application, &err, fmt.Sprint(applicationEvent.ApplicationID))
 
 	logger.LoggerEvents.Debugf("Received Application Event: %+v", applicationEvent)
 
 	switch applicationEvent.Event.Type {
 	case eventConstants.ApplicationCreate:
 		logger.LoggerEvents.Debugf("Application Create for application UUID %s", applicationEvent.UUID)
+		return synchronizer.UpdateApplicationPolicy(applicationEvent.UUID, applicationEvent.TenantDomain, applicationEvent.ApplicationPolicy, c, conf)
 	case eventConstants.ApplicationUpdate:
 		logger.LoggerEvents.Debugf("Application Update for application UUID %s", applicationEvent.UUID)
+		return synchronizer.UpdateApplicationPolicy(applicationEvent.UUID, applicationEvent.TenantDomain, applicationEvent.ApplicationPolicy, c, conf)
 	case eventConstants.ApplicationDelete:
 		logger.LoggerEvents.Debugf("Application Delete for application UUID %s", applicationEvent.UUID)
+		kongMgtServer.RemoveApplicationPolicy(applicationEvent.UUID)
 		if !kongMgtServer.IsApplicationProcessed(applicationEvent.UUID) {
 			logger.LoggerEvents.Infof("Application %s is not processed. skipping deletion event",
 				applicationEvent.UUID)
-			return
+			return nil
 		}
 		internalk8sClient.UndeployAPPCRs(applicationEvent.UUID, c)
 		kongMgtServer.RemoveProcessedApplication(applicationEvent.UUID)
@@ -168,4 +179,5 @@ func handleApplicationEvent(data []byte, c client.Client, conf *config.Config) {
 		logger.LoggerEvents.Warnf("Application Event Type '%s' is not recognized for the Event under Application UUID %s",
 			applicationEvent.Event.Type, applicationEvent.UUID)
 	}
+	return nil
 }
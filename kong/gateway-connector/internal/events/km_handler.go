@@ -45,10 +45,11 @@ func HandleKMConfiguration(keyManager *types.KeyManager, notification msg.EventK
 	}
 
 	action := notification.Event.PayloadData.Action
+	namespace := conf.DataPlane.GetOrganizationNamespace(notification.Event.PayloadData.TenantDomain)
 	name := notification.Event.PayloadData.Name
 
 	if strings.EqualFold(msg.ActionDelete, action) {
-		k8sclient.UnDeploySecretCR(name, c, conf)
+		k8sclient.UnDeploySecretCR(name, namespace, c)
 		return
 	}
 
@@ -64,7 +65,7 @@ func HandleKMConfiguration(keyManager *types.KeyManager, notification msg.EventK
 
 	if !strings.EqualFold(constants.PEMCertificateType, resolvedKeyManager.KeyManagerConfig.CertificateType) {
 		logger.LoggerEvents.Infoln("Only PEM certificate type is supported")
-		k8sclient.UnDeploySecretCR(name, c, conf)
+		k8sclient.UnDeploySecretCR(name, namespace, c)
 		return
 	}
 
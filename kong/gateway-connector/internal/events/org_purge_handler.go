@@ -0,0 +1,56 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package events
+
+import (
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
+	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
+	kongMgtServer "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Kinds of the cached data removed when an organization is purged
+const (
+	processedAPIKind         = "ProcessedAPI"
+	processedApplicationKind = "ProcessedApplication"
+)
+
+// HandleOrganizationPurge removes the routes, services, plugins, consumers and credential secrets of a purged
+// organization along with the APIs and applications tracked for it
+func HandleOrganizationPurge(organization msg.Organization, report *agent.PurgeReport, c client.Client) {
+	logger.LoggerEvents.Infof("Processing organization purge event for organization: %s", report.Organization)
+
+	for _, identifier := range organization.Identifiers() {
+		apiIDs, appIDs := internalk8sClient.UndeployOrganizationCRs(transformer.GenerateSHA1Hash(identifier), c, report)
+		for _, apiID := range apiIDs {
+			if kongMgtServer.IsAPIProcessed(apiID) {
+				kongMgtServer.RemoveProcessedAPI(apiID)
+				report.AddDeleted(processedAPIKind, apiID)
+			}
+		}
+		for _, appID := range appIDs {
+			if kongMgtServer.IsApplicationProcessed(appID) {
+				kongMgtServer.RemoveProcessedApplication(appID)
+				report.AddDeleted(processedApplicationKind, appID)
+			}
+		}
+	}
+}
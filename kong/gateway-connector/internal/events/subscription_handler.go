@@ -20,6 +20,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/managementserver"
 	msg "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/messaging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/sequencer"
 	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/discovery"
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
@@ -44,56 +46,63 @@ import (
 )
 
 // HandleSubscriptionEvents to process subscription related events
-func HandleSubscriptionEvents(data []byte, eventType string, c client.Client) {
-	logger.LoggerEvents.Infof("Processing subscription event processing with EventType: %s, data length: %d bytes", eventType, len(data))
+func HandleSubscriptionEvents(ctx context.Context, data []byte, eventType string, c client.Client) (err error) {
+	logger.LoggerEvents.FromContext(ctx).Infof("Processing subscription event processing with EventType: %s, data length: %d bytes", eventType, len(data))
 
 	conf, errReadConfig := config.ReadConfigs()
 	if errReadConfig != nil {
-		logger.LoggerEvents.Errorf("Error reading configs: %v", errReadConfig)
-		return
+		return fmt.Errorf("error reading configs: %w", errReadConfig)
 	}
 
 	var subscriptionEvent msg.SubscriptionEvent
 	if subEventErr := json.Unmarshal(data, &subscriptionEvent); subEventErr != nil {
-		logger.LoggerEvents.Errorf("%s: %v", kongConstants.UnmarshalErrorSubscription, subEventErr)
-		return
+		logger.LoggerEvents.FromContext(ctx).Errorf("%s: %v", kongConstants.UnmarshalErrorSubscription, subEventErr)
+		return nil
 	}
 
 	if !belongsToTenant(subscriptionEvent.TenantDomain) {
-		logger.LoggerEvents.Debugf("Subscription event for the Application: %s and API %s is dropped due to having non related tenantDomain: %s",
+		logger.LoggerEvents.FromContext(ctx).Debugf("Subscription event for the Application: %s and API %s is dropped due to having non related tenantDomain: %s",
 			subscriptionEvent.ApplicationUUID, subscriptionEvent.APIUUID, subscriptionEvent.TenantDomain)
-		return
+		return nil
 	}
 
-	if isLaterEvent(subsriptionsListTimeStampMap, fmt.Sprint(subscriptionEvent.SubscriptionID), subscriptionEvent.TimeStamp) {
-		return
+	if !sequencer.AcceptEvent(sequencer.Subscription, fmt.Sprint(subscriptionEvent.SubscriptionID), subscriptionEvent.Event) {
+		logger.LoggerEvents.FromContext(ctx).Infof("Skipping older or redelivered %s event for subscription %s", subscriptionEvent.Event.Type, subscriptionEvent.SubscriptionUUID)
+		return nil
 	}
+	defer sequencer.ForgetOnFailure(sequencer.Subscription, &err, fmt.Sprint(subscriptionEvent.SubscriptionID))
 
 	if !kongMgtServer.IsAPIProcessed(subscriptionEvent.APIUUID) {
-		logger.LoggerEvents.Infof("API %s is not processed. skipping subscription event", subscriptionEvent.APIUUID)
-		return
+		logger.LoggerEvents.FromContext(ctx).Infof("API %s is not processed. skipping subscription event", subscriptionEvent.APIUUID)
+		return nil
 	}
 
-	logger.LoggerEvents.Debugf("Received Subscription Event: %+v", subscriptionEvent)
+	logger.LoggerEvents.FromContext(ctx).Debugf("Received Subscription Event: %+v", subscriptionEvent)
 	switch subscriptionEvent.Event.Type {
 	case eventConstants.SubscriptionCreate:
 		if !kongMgtServer.IsApplicationProcessed(subscriptionEvent.ApplicationUUID) {
+			if err := synchronizer.CreateApplicationConsumerForBothEnvironments(subscriptionEvent.ApplicationUUID,
+				subscriptionEvent.TenantDomain, c, conf); err != nil {
+				return fmt.Errorf("error creating the consumers of application %s: %w", subscriptionEvent.ApplicationUUID, err)
+			}
 			kongMgtServer.AddProcessedApplication(subscriptionEvent.ApplicationUUID)
-			synchronizer.CreateApplicationConsumerForBothEnvironments(subscriptionEvent.ApplicationUUID, c, conf)
 		}
-		createSecretsForKeyGeneration(subscriptionEvent, c, conf)
-		createSubscription(subscriptionEvent, c, conf, constants.ProductionType)
-		createSubscription(subscriptionEvent, c, conf, constants.SandboxType)
+		if err := createSecretsForKeyGeneration(subscriptionEvent, c, conf); err != nil {
+			return err
+		}
+		return errors.Join(createSubscription(subscriptionEvent, c, conf, constants.ProductionType),
+			createSubscription(subscriptionEvent, c, conf, constants.SandboxType))
 	case eventConstants.SubscriptionUpdate:
-		updateSubscription(subscriptionEvent, c, conf, constants.ProductionType)
-		updateSubscription(subscriptionEvent, c, conf, constants.SandboxType)
+		return errors.Join(updateSubscription(subscriptionEvent, c, conf, constants.ProductionType),
+			updateSubscription(subscriptionEvent, c, conf, constants.SandboxType))
 	case eventConstants.SubscriptionDelete:
-		removeSubscription(subscriptionEvent, c, conf, constants.ProductionType)
-		removeSubscription(subscriptionEvent, c, conf, constants.SandboxType)
+		return errors.Join(removeSubscription(subscriptionEvent, c, conf, constants.ProductionType),
+			removeSubscription(subscriptionEvent, c, conf, constants.SandboxType))
 	}
+	return nil
 }
 
-func createSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config, environment string) {
+func createSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config, environment string) error {
 	logger.LoggerEvents.Debugf("Creating subscription|ApplicationUUID:%s Environment:%s\n", subscriptionEvent.ApplicationUUID, environment)
 
 	aclGroupNames, err := generateACLGroupNameFromK8s(subscriptionEvent.APIName, environment, c, conf)
@@ -103,18 +112,20 @@ func createSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client
 		aclGroupNames = []string{transformer.GenerateACLGroupName(subscriptionEvent.APIName, environment)}
 	}
 
-	synchronizer.CreateSubscription(subscriptionEvent.ApplicationUUID, subscriptionEvent.APIUUID, subscriptionEvent.PolicyID,
-		subscriptionEvent.TenantDomain, aclGroupNames, c, conf, environment, false)
+	return synchronizer.CreateSubscription(subscriptionEvent.ApplicationUUID, subscriptionEvent.APIUUID, subscriptionEvent.PolicyID,
+		subscriptionEvent.TenantDomain, aclGroupNames, c, conf, environment,
+		synchronizer.IsSubscriptionBlocked(subscriptionEvent.SubscriptionState, environment))
 }
 
-func updateSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config, environment string) {
+func updateSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config, environment string) error {
 	logger.LoggerEvents.Debugf("Updating subscription|ApplicationUUID:%s Environment:%s\n", subscriptionEvent.ApplicationUUID, environment)
 
 	var removeAnnotations []string
 	var addAnnotations []string
 	// retrieving current production subscription policy
 	consumerName := transformer.GenerateConsumerName(subscriptionEvent.ApplicationUUID, environment)
-	consumer := internalk8sClient.GetKongConsumerCR(consumerName, c, conf)
+	consumer := internalk8sClient.GetKongConsumerCR(consumerName,
+		conf.DataPlane.GetOrganizationNamespace(subscriptionEvent.TenantDomain), c)
 
 	if consumer == nil {
 		logger.LoggerEvents.Infof("Kong consumer credential not found for %v", environment)
@@ -143,7 +154,8 @@ func updateSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client
 				}, kongConstants.UpdateConsumerPluginAnnotationTask, kongConstants.MaxRetries)
 				if err != nil {
 					logger.LoggerEvents.Errorf("Failed to update consumer plugin annotations: %v", err)
-					return
+					return fmt.Errorf("error updating the %s consumer plugins of application %s: %w",
+						environment, subscriptionEvent.ApplicationUUID, err)
 				}
 			}
 		}
@@ -159,7 +171,8 @@ func updateSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client
 				return internalk8sClient.UpdateKongConsumerCredential(subscriptionEvent.ApplicationUUID, environment, c, conf, nil, credentials)
 			}, kongConstants.UpdateConsumerCredentialBlockedTask+environment, kongConstants.MaxRetries); err != nil {
 				logger.LoggerEvents.Errorf("Failed to block %s credentials: %v", environment, err)
-				return
+				return fmt.Errorf("error blocking the %s credentials of application %s: %w",
+					environment, subscriptionEvent.ApplicationUUID, err)
 			}
 		case kongConstants.SubscriptionStateProdOnlyBlocked:
 			if environment == constants.ProductionType {
@@ -167,14 +180,16 @@ func updateSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client
 					return internalk8sClient.UpdateKongConsumerCredential(subscriptionEvent.ApplicationUUID, environment, c, conf, nil, credentials)
 				}, kongConstants.UpdateConsumerCredentialProdBlockedTask+environment, kongConstants.MaxRetries); err != nil {
 					logger.LoggerEvents.Errorf("Failed to enable %s credentials for prod-only-blocked: %v", environment, err)
-					return
+					return fmt.Errorf("error blocking the %s credentials of application %s: %w",
+						environment, subscriptionEvent.ApplicationUUID, err)
 				}
 			} else {
 				if err := utils.RetryKongCRUpdate(func() error {
 					return internalk8sClient.UpdateKongConsumerCredential(subscriptionEvent.ApplicationUUID, environment, c, conf, credentials, nil)
 				}, kongConstants.UpdateConsumerCredentialProdBlockedTask+environment, kongConstants.MaxRetries); err != nil {
 					logger.LoggerEvents.Errorf("Failed to enable %s credentials for prod-only-blocked: %v", environment, err)
-					return
+					return fmt.Errorf("error enabling the %s credentials of application %s: %w",
+						environment, subscriptionEvent.ApplicationUUID, err)
 				}
 
 			}
@@ -183,14 +198,15 @@ func updateSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client
 				return internalk8sClient.UpdateKongConsumerCredential(subscriptionEvent.ApplicationUUID, environment, c, conf, credentials, nil)
 			}, kongConstants.UpdateConsumerCredentialUnblockedTask+environment, kongConstants.MaxRetries); err != nil {
 				logger.LoggerEvents.Errorf("Failed to unblock %s credentials: %v", environment, err)
-				return
+				return fmt.Errorf("error unblocking the %s credentials of application %s: %w",
+					environment, subscriptionEvent.ApplicationUUID, err)
 			}
 		}
 	}
-
+	return nil
 }
 
-func removeSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config, environment string) {
+func removeSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config, environment string) error {
 	logger.LoggerEvents.Debugf("Removing subscription | ApplicationUUID:%s | Environment:%s", subscriptionEvent.ApplicationUUID, environment)
 
 	appUUID := subscriptionEvent.ApplicationUUID
@@ -205,36 +221,41 @@ func removeSubscription(subscriptionEvent msg.SubscriptionEvent, c client.Client
 		return internalk8sClient.UpdateKongConsumerCredential(appUUID, environment, c, conf, nil, []string{aclSecretCredentialName})
 	}, kongConstants.UpdateConsumerCredentialRemoveTask, kongConstants.MaxRetries); err != nil {
 		logger.LoggerEvents.Errorf("Failed to remove Kong consumer credential secret (%s): %v", aclSecretCredentialName, err)
-		return
+		return fmt.Errorf("error removing the credential %s of application %s: %w", aclSecretCredentialName, appUUID, err)
 	}
 
+	namespace := conf.DataPlane.GetOrganizationNamespace(subscriptionEvent.TenantDomain)
 	// Undeploy ACL secret credential
-	internalk8sClient.UnDeploySecretCR(aclSecretCredentialName, c, conf)
+	if err := internalk8sClient.UnDeploySecretCR(aclSecretCredentialName, namespace, c); err != nil {
+		return err
+	}
 
 	// Get the consumer CR
-	consumer := internalk8sClient.GetKongConsumerCR(consumerName, c, conf)
+	consumer := internalk8sClient.GetKongConsumerCR(consumerName, namespace, c)
 	if consumer == nil {
 		logger.LoggerEvents.Infof("Kong consumer CR not found for environment %s", environment)
-		return
+		return nil
 	}
 
 	// Handle consumer plugin annotations
-	handleConsumerAnnotations(subscriptionEvent, consumer, c, conf, environment)
+	if err := handleConsumerAnnotations(subscriptionEvent, consumer, c, conf, environment); err != nil {
+		return err
+	}
 
 	// Handle consumer credentials cleanup
-	handleConsumerCredentials(consumer, consumerName, appUUID, c, conf)
+	return handleConsumerCredentials(consumer, consumerName, appUUID, c)
 }
 
-func handleConsumerAnnotations(subscriptionEvent msg.SubscriptionEvent, consumer *v1.KongConsumer, c client.Client, conf *config.Config, environment string) {
+func handleConsumerAnnotations(subscriptionEvent msg.SubscriptionEvent, consumer *v1.KongConsumer, c client.Client, conf *config.Config, environment string) error {
 	annotations, ok := consumer.Annotations[kongConstants.KongPluginsAnnotation]
 	if !ok || annotations == "" {
-		return
+		return nil
 	}
 
 	subscriptionPolicy := managementserver.GetSubscriptionPolicy(subscriptionEvent.PolicyID, subscriptionEvent.TenantDomain)
 	if subscriptionPolicy.Name == kongConstants.EmptyString {
 		logger.LoggerEvents.Warnf("Subscription policy not found for PolicyID:%s Tenant:%s", subscriptionEvent.PolicyID, subscriptionEvent.TenantDomain)
-		return
+		return nil
 	}
 
 	rateLimitCRName := transformer.GeneratePolicyCRName(subscriptionPolicy.Name, subscriptionPolicy.TenantDomain,
@@ -242,7 +263,7 @@ func handleConsumerAnnotations(subscriptionEvent msg.SubscriptionEvent, consumer
 
 	annotationsArr := strings.Split(annotations, kongConstants.CommaString)
 	if !slices.Contains(annotationsArr, rateLimitCRName) {
-		return
+		return nil
 	}
 
 	removeAnnotations := []string{rateLimitCRName}
@@ -252,16 +273,20 @@ func handleConsumerAnnotations(subscriptionEvent msg.SubscriptionEvent, consumer
 	}, kongConstants.UpdateConsumerPluginAnnotationTask, kongConstants.MaxRetries); err != nil {
 		logger.LoggerEvents.Errorf("Failed to remove rate-limit annotation (%s) for consumer %s: %v",
 			rateLimitCRName, consumer.Name, err)
+		return fmt.Errorf("error removing the rate limit plugin %s from consumer %s: %w", rateLimitCRName, consumer.Name, err)
 	}
+	return nil
 }
 
-func handleConsumerCredentials(consumer *v1.KongConsumer, consumerName string, applicationUUID string, c client.Client, conf *config.Config) {
+func handleConsumerCredentials(consumer *v1.KongConsumer, consumerName string, applicationUUID string, c client.Client) error {
 	credentials := consumer.Credentials
 	if len(credentials) == 0 {
 		logger.LoggerEvents.Debugf("No credentials found for consumer %s, deleting consumer", consumerName)
-		internalk8sClient.UnDeployKongConsumerCR(consumerName, c, conf)
+		if err := internalk8sClient.UnDeployKongConsumerCR(consumerName, consumer.Namespace, c); err != nil {
+			return err
+		}
 		kongMgtServer.RemoveProcessedApplication(applicationUUID)
-		return
+		return nil
 	}
 
 	hasACLCredential := false
@@ -274,31 +299,39 @@ func handleConsumerCredentials(consumer *v1.KongConsumer, consumerName string, a
 
 	if !hasACLCredential {
 		logger.LoggerEvents.Debugf("No ACL credentials for consumer %s, undeploying all credential secrets and deleting consumer", consumerName)
+		var undeployErrs []error
 		for _, credential := range credentials {
 			if strings.Contains(credential, kongConstants.SecretPrefix) {
 				logger.LoggerEvents.Debugf("Undeploying credential secret: %s", credential)
-				internalk8sClient.UnDeploySecretCR(credential, c, conf)
+				undeployErrs = append(undeployErrs, internalk8sClient.UnDeploySecretCR(credential, consumer.Namespace, c))
 			}
 		}
-		internalk8sClient.UnDeployKongConsumerCR(consumerName, c, conf)
+		if err := errors.Join(undeployErrs...); err != nil {
+			return err
+		}
+		if err := internalk8sClient.UnDeployKongConsumerCR(consumerName, consumer.Namespace, c); err != nil {
+			return err
+		}
 		kongMgtServer.RemoveProcessedApplication(applicationUUID)
-		return
+		return nil
 	}
 
 	logger.LoggerEvents.Debugf("ACL credentials found for consumer %s, keeping consumer", consumerName)
+	return nil
 }
 
 // createSecretsForKeyGeneration creates secrets for both production and sandbox environments
-func createSecretsForKeyGeneration(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config) {
+func createSecretsForKeyGeneration(subscriptionEvent msg.SubscriptionEvent, c client.Client, conf *config.Config) error {
 	logger.LoggerEvents.Debugf("Creating secrets for application key mappings for ApplicationUUID: %s, Environment: %s",
 		subscriptionEvent.ApplicationUUID, subscriptionEvent.TenantDomain)
 
 	applicationKeyMappings, _ := synchronizer.FetchApplicationKeyMappingsOnEvent(subscriptionEvent.ApplicationUUID, subscriptionEvent.TenantDomain, c)
 
+	var registrationErrs []error
 	if len(applicationKeyMappings) > 0 {
 		logger.LoggerEvents.Debugf("Fetched %d application key mappings for application %s", len(applicationKeyMappings), subscriptionEvent.ApplicationUUID)
 		for _, applicationKeyMapping := range applicationKeyMappings {
-			synchronizer.ProcessApplicationRegistration(
+			registrationErrs = append(registrationErrs, synchronizer.ProcessApplicationRegistration(
 				applicationKeyMapping.ApplicationUUID,
 				applicationKeyMapping.ConsumerKey,
 				applicationKeyMapping.KeyManager,
@@ -306,9 +339,10 @@ func createSecretsForKeyGeneration(subscriptionEvent msg.SubscriptionEvent, c cl
 				strings.ToLower(applicationKeyMapping.KeyType),
 				c,
 				conf,
-			)
+			))
 		}
 	}
+	return errors.Join(registrationErrs...)
 }
 
 // generateACLGroupNameFromK8s generates ACL group names by discovering services and their ACL plugins
@@ -349,7 +383,7 @@ func findServiceByAPIName(apiName string, c client.Client, conf *config.Config)
 		return nil, fmt.Errorf("API name cannot be empty")
 	}
 
-	namespace := conf.DataPlane.Namespace
+	namespace := conf.DataPlane.GetLookupNamespace()
 	serviceList := &corev1.ServiceList{}
 	ctx := context.Background()
 
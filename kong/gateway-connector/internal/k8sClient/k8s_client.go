@@ -21,21 +21,27 @@ package k8sclient
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	v1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/agent"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
 	corev1 "k8s.io/api/core/v1"
 	k8error "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
-// DeployHTTPRouteCR applies the given HttpRoute struct to the Kubernetes cluster.
-func DeployHTTPRouteCR(httpRoute *gwapiv1.HTTPRoute, k8sClient client.Client) {
+// DeployHTTPRouteCR applies the given HttpRoute struct to the Kubernetes cluster and returns the error of applying it.
+func DeployHTTPRouteCR(httpRoute *gwapiv1.HTTPRoute, k8sClient client.Client) error {
 	loggers.LoggerK8sClient.Debugf("Deploying HTTPRoute CR|Name:%s Namespace:%s\n", httpRoute.Name, httpRoute.ObjectMeta.Namespace)
 
 	crHTTPRoute := &gwapiv1.HTTPRoute{}
@@ -47,21 +53,62 @@ func DeployHTTPRouteCR(httpRoute *gwapiv1.HTTPRoute, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), httpRoute); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create HTTPRoute CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("HTTPRoute CR created: " + httpRoute.Name)
+			return fmt.Errorf("failed to create HTTPRoute CR %s: %w", httpRoute.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("HTTPRoute CR created: " + httpRoute.Name)
 	} else {
 		crHTTPRoute.Spec = httpRoute.Spec
 		if err := k8sClient.Update(context.Background(), crHTTPRoute); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update HTTPRoute CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("HTTPRoute CR updated: " + crHTTPRoute.Name)
+			return fmt.Errorf("failed to update HTTPRoute CR %s: %w", crHTTPRoute.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("HTTPRoute CR updated: " + crHTTPRoute.Name)
 	}
+	return nil
 }
 
-// DeployServiceCR applies the given Service struct to the Kubernetes cluster.
-func DeployServiceCR(service *corev1.Service, k8sClient client.Client) {
+// DeployGRPCRouteCR applies the given GRPCRoute struct to the Kubernetes cluster and returns the error of applying it.
+func DeployGRPCRouteCR(grpcRoute *gwapiv1.GRPCRoute, k8sClient client.Client) error {
+	loggers.LoggerK8sClient.Debugf("Deploying GRPCRoute CR|Name:%s Namespace:%s\n", grpcRoute.Name, grpcRoute.ObjectMeta.Namespace)
+
+	crGRPCRoute := &gwapiv1.GRPCRoute{}
+	objKey := client.ObjectKey{Namespace: grpcRoute.ObjectMeta.Namespace, Name: grpcRoute.Name}
+	// Retrieve CR from Kubernetes cluster
+	if err := k8sClient.Get(context.Background(), objKey, crGRPCRoute); err != nil {
+		if !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Error("Unable to get GRPCRoute CR: " + err.Error())
+		}
+		if err := k8sClient.Create(context.Background(), grpcRoute); err != nil {
+			loggers.LoggerK8sClient.Error("Unable to create GRPCRoute CR: " + err.Error())
+			return fmt.Errorf("failed to create GRPCRoute CR %s: %w", grpcRoute.Name, err)
+		}
+		loggers.LoggerK8sClient.Info("GRPCRoute CR created: " + grpcRoute.Name)
+	} else {
+		crGRPCRoute.Spec = grpcRoute.Spec
+		if err := k8sClient.Update(context.Background(), crGRPCRoute); err != nil {
+			loggers.LoggerK8sClient.Error("Unable to update GRPCRoute CR: " + err.Error())
+			return fmt.Errorf("failed to update GRPCRoute CR %s: %w", crGRPCRoute.Name, err)
+		}
+		loggers.LoggerK8sClient.Info("GRPCRoute CR updated: " + crGRPCRoute.Name)
+	}
+	return nil
+}
+
+// managedServiceAnnotations are the Kong annotations of the Services set from the API, which are replaced on update.
+// The retries annotation is no longer set and is removed from the Services deployed before.
+var managedServiceAnnotations = []string{
+	constants.KongProtocolAnnotation,
+	constants.KongConnectTimeoutAnnotation,
+	constants.KongReadTimeoutAnnotation,
+	constants.KongWriteTimeoutAnnotation,
+	constants.KongRetriesAnnotation,
+	constants.KongUpstreamPolicyAnnotation,
+}
+
+// DeployServiceCR applies the given Service struct to the Kubernetes cluster. On update, the managed Kong annotations
+// of the Service are replaced along with its spec, while the other annotations are retained. The error of applying the
+// Service is returned.
+func DeployServiceCR(service *corev1.Service, k8sClient client.Client) error {
 	loggers.LoggerK8sClient.Debugf("Deploying Service CR|Name:%s Namespace:%s\n", service.Name, service.ObjectMeta.Namespace)
 
 	crService := &corev1.Service{}
@@ -73,21 +120,32 @@ func DeployServiceCR(service *corev1.Service, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), service); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create Service CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Service CR created: " + service.Name)
+			return fmt.Errorf("failed to create Service CR %s: %w", service.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("Service CR created: " + service.Name)
 	} else {
 		crService.Spec = service.Spec
+		for _, annotation := range managedServiceAnnotations {
+			if value, exists := service.Annotations[annotation]; exists {
+				if crService.Annotations == nil {
+					crService.Annotations = make(map[string]string)
+				}
+				crService.Annotations[annotation] = value
+			} else {
+				delete(crService.Annotations, annotation)
+			}
+		}
 		if err := k8sClient.Update(context.Background(), crService); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update Service CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Service CR updated: " + crService.Name)
+			return fmt.Errorf("failed to update Service CR %s: %w", crService.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("Service CR updated: " + crService.Name)
 	}
+	return nil
 }
 
-// DeployKongPluginCR applies the given KongPlugin struct to the Kubernetes cluster.
-func DeployKongPluginCR(plugin *v1.KongPlugin, k8sClient client.Client) {
+// DeployKongPluginCR applies the given KongPlugin struct to the Kubernetes cluster and returns the error of applying it.
+func DeployKongPluginCR(plugin *v1.KongPlugin, k8sClient client.Client) error {
 	loggers.LoggerK8sClient.Debugf("Deploying KongPlugin CR|Name:%s Namespace:%s\n", plugin.Name, plugin.ObjectMeta.Namespace)
 
 	crKongPlugin := &v1.KongPlugin{}
@@ -99,21 +157,57 @@ func DeployKongPluginCR(plugin *v1.KongPlugin, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), plugin); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create KongPlugin CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("KongPlugin CR created: " + plugin.Name)
+			return fmt.Errorf("failed to create KongPlugin CR %s: %w", plugin.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("KongPlugin CR created: " + plugin.Name)
 	} else {
 		crKongPlugin.Config = plugin.Config
+		crKongPlugin.ConfigPatches = plugin.ConfigPatches
+		if len(plugin.Annotations) > 0 {
+			if crKongPlugin.Annotations == nil {
+				crKongPlugin.Annotations = make(map[string]string)
+			}
+			maps.Copy(crKongPlugin.Annotations, plugin.Annotations)
+		}
 		if err := k8sClient.Update(context.Background(), crKongPlugin); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update KongPlugin CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("KongPlugin CR updated: " + crKongPlugin.Name)
+			return fmt.Errorf("failed to update KongPlugin CR %s: %w", crKongPlugin.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("KongPlugin CR updated: " + crKongPlugin.Name)
 	}
+	return nil
+}
+
+// DeployKongUpstreamPolicyCR applies the given KongUpstreamPolicy struct to the Kubernetes cluster and returns the error
+// of applying it.
+func DeployKongUpstreamPolicyCR(upstreamPolicy *v1beta1.KongUpstreamPolicy, k8sClient client.Client) error {
+	loggers.LoggerK8sClient.Debugf("Deploying KongUpstreamPolicy CR|Name:%s Namespace:%s\n", upstreamPolicy.Name, upstreamPolicy.ObjectMeta.Namespace)
+
+	crUpstreamPolicy := &v1beta1.KongUpstreamPolicy{}
+	objKey := client.ObjectKey{Namespace: upstreamPolicy.ObjectMeta.Namespace, Name: upstreamPolicy.Name}
+	// Retrieve CR from Kubernetes cluster
+	if err := k8sClient.Get(context.Background(), objKey, crUpstreamPolicy); err != nil {
+		if !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Error("Unable to get KongUpstreamPolicy CR: " + err.Error())
+		}
+		if err := k8sClient.Create(context.Background(), upstreamPolicy); err != nil {
+			loggers.LoggerK8sClient.Error("Unable to create KongUpstreamPolicy CR: " + err.Error())
+			return fmt.Errorf("failed to create KongUpstreamPolicy CR %s: %w", upstreamPolicy.Name, err)
+		}
+		loggers.LoggerK8sClient.Info("KongUpstreamPolicy CR created: " + upstreamPolicy.Name)
+	} else {
+		crUpstreamPolicy.Spec = upstreamPolicy.Spec
+		if err := k8sClient.Update(context.Background(), crUpstreamPolicy); err != nil {
+			loggers.LoggerK8sClient.Error("Unable to update KongUpstreamPolicy CR: " + err.Error())
+			return fmt.Errorf("failed to update KongUpstreamPolicy CR %s: %w", crUpstreamPolicy.Name, err)
+		}
+		loggers.LoggerK8sClient.Info("KongUpstreamPolicy CR updated: " + crUpstreamPolicy.Name)
+	}
+	return nil
 }
 
-// DeployKongConsumerCR applies the given KongConsumer struct to the Kubernetes cluster.
-func DeployKongConsumerCR(consumer *v1.KongConsumer, k8sClient client.Client) {
+// DeployKongConsumerCR applies the given KongConsumer struct to the Kubernetes cluster and returns the error of applying it.
+func DeployKongConsumerCR(consumer *v1.KongConsumer, k8sClient client.Client) error {
 	loggers.LoggerK8sClient.Debugf("Deploying KongConsumer CR|Name:%s Namespace:%s\n", consumer.Name, consumer.ObjectMeta.Namespace)
 
 	crKongConsumer := &v1.KongConsumer{}
@@ -125,22 +219,25 @@ func DeployKongConsumerCR(consumer *v1.KongConsumer, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), consumer); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create KongConsumer CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("KongConsumer CR created: " + consumer.Name)
+			return fmt.Errorf("failed to create KongConsumer CR %s: %w", consumer.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("KongConsumer CR created: " + consumer.Name)
 	} else {
 		crKongConsumer.CustomID = consumer.CustomID
 		crKongConsumer.Username = consumer.Username
+		// the credentials added to the consumer since it was created are retained
+		crKongConsumer.Credentials = utils.PrepareCredentials(crKongConsumer.Credentials, consumer.Credentials, nil)
 		if err := k8sClient.Update(context.Background(), crKongConsumer); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update KongConsumer CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("KongConsumer CR updated: " + crKongConsumer.Name)
+			return fmt.Errorf("failed to update KongConsumer CR %s: %w", crKongConsumer.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("KongConsumer CR updated: " + crKongConsumer.Name)
 	}
+	return nil
 }
 
-// DeploySecretCR applies the given Secret struct to the Kubernetes cluster.
-func DeploySecretCR(k8sSecret *corev1.Secret, k8sClient client.Client) {
+// DeploySecretCR applies the given Secret struct to the Kubernetes cluster and returns the error of applying it.
+func DeploySecretCR(k8sSecret *corev1.Secret, k8sClient client.Client) error {
 	loggers.LoggerK8sClient.Debugf("Deploying Secret CR|Name:%s Namespace:%s\n", k8sSecret.Name, k8sSecret.ObjectMeta.Namespace)
 
 	crSecret := &corev1.Secret{}
@@ -152,97 +249,112 @@ func DeploySecretCR(k8sSecret *corev1.Secret, k8sClient client.Client) {
 		}
 		if err := k8sClient.Create(context.Background(), k8sSecret); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to create Secret CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Secret CR created: " + k8sSecret.Name)
+			return fmt.Errorf("failed to create Secret CR %s: %w", k8sSecret.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("Secret CR created: " + k8sSecret.Name)
 	} else {
-		crSecret.StringData = k8sSecret.StringData
+		// The data is replaced, so that the keys removed from the Secret do not linger in the CR
+		data := make(map[string][]byte, len(k8sSecret.Data)+len(k8sSecret.StringData))
+		maps.Copy(data, k8sSecret.Data)
+		for key, value := range k8sSecret.StringData {
+			data[key] = []byte(value)
+		}
+		crSecret.Data = data
+		crSecret.StringData = nil
 		if err := k8sClient.Update(context.Background(), crSecret); err != nil {
 			loggers.LoggerK8sClient.Error("Unable to update Secret CR: " + err.Error())
-		} else {
-			loggers.LoggerK8sClient.Info("Secret CR updated: " + crSecret.Name)
+			return fmt.Errorf("failed to update Secret CR %s: %w", crSecret.Name, err)
 		}
+		loggers.LoggerK8sClient.Info("Secret CR updated: " + crSecret.Name)
 	}
+	return nil
 }
 
 // UnDeploySecretCR removes the Secret Resources from the Kubernetes cluster based on name.
-func UnDeploySecretCR(name string, k8sClient client.Client, conf *config.Config) {
-	loggers.LoggerK8sClient.Debugf("Undeploying Secret CR|Name:%s Namespace:%s\n", name, conf.DataPlane.Namespace)
+func UnDeploySecretCR(name string, namespace string, k8sClient client.Client) error {
+	loggers.LoggerK8sClient.Debugf("Undeploying Secret CR|Name:%s Namespace:%s\n", name, namespace)
 
 	resource := &corev1.Secret{}
-	objKey := client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: name}
+	objKey := client.ObjectKey{Namespace: namespace, Name: name}
 	// Retrieve CR from Kubernetes cluster
 	err := k8sClient.Get(context.Background(), objKey, resource)
 	if err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Warnf("Secret CR %s already deleted or not found", name)
-			return
+			return nil
 		}
 		loggers.LoggerK8sClient.Error("Unable to get Secret CR: " + err.Error())
-		return
+		return fmt.Errorf("failed to get Secret CR %s: %w", name, err)
 	}
 	if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Warnf("Secret CR %s already deleted or not found", name)
-		} else {
-			loggers.LoggerK8sClient.Errorf("Unable to delete Secret CR: %v", err)
+			return nil
 		}
-	} else {
-		loggers.LoggerK8sClient.Infof("Deleted Secret CR: %s", resource.Name)
+		loggers.LoggerK8sClient.Errorf("Unable to delete Secret CR: %v", err)
+		return fmt.Errorf("failed to delete Secret CR %s: %w", name, err)
 	}
+	loggers.LoggerK8sClient.Infof("Deleted Secret CR: %s", resource.Name)
+	return nil
 }
 
 // UnDeployKongPluginCR removes the Kong plugin CR Resources from the Kubernetes cluster based on name.
-func UnDeployKongPluginCR(name string, k8sClient client.Client, conf *config.Config) {
-	loggers.LoggerK8sClient.Debugf("Undeploying KongPlugin CR|Name:%s Namespace:%s\n", name, conf.DataPlane.Namespace)
+func UnDeployKongPluginCR(name string, namespace string, k8sClient client.Client) error {
+	loggers.LoggerK8sClient.Debugf("Undeploying KongPlugin CR|Name:%s Namespace:%s\n", name, namespace)
 
 	resource := &v1.KongPlugin{}
-	objKey := client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: name}
+	objKey := client.ObjectKey{Namespace: namespace, Name: name}
 	// Retrieve CR from Kubernetes cluster
 	err := k8sClient.Get(context.Background(), objKey, resource)
 	if err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Warnf("Kong Plugin CR %s already deleted or not found", name)
-			return
+			return nil
 		}
 		loggers.LoggerK8sClient.Error("Unable to get Kong Plugin CR: " + err.Error())
-		return
+		return fmt.Errorf("failed to get Kong Plugin CR %s: %w", name, err)
 	}
 	if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil {
 		if k8error.IsNotFound(err) {
 			loggers.LoggerK8sClient.Warnf("Kong Plugin CR %s already deleted or not found", name)
-		} else {
-			loggers.LoggerK8sClient.Errorf("Unable to delete Kong Plugin CR: %v", err)
+			return nil
 		}
-	} else {
-		loggers.LoggerK8sClient.Infof("Deleted Kong Plugin CR: %s", resource.Name)
+		loggers.LoggerK8sClient.Errorf("Unable to delete Kong Plugin CR: %v", err)
+		return fmt.Errorf("failed to delete Kong Plugin CR %s: %w", name, err)
 	}
+	loggers.LoggerK8sClient.Infof("Deleted Kong Plugin CR: %s", resource.Name)
+	return nil
 }
 
 // UnDeployKongConsumerCR removes the Kong consumer CR Resources from the Kubernetes cluster based on name.
-func UnDeployKongConsumerCR(name string, k8sClient client.Client, conf *config.Config) {
-	loggers.LoggerK8sClient.Debugf("Undeploying KongConsumer CR|Name:%s Namespace:%s\n", name, conf.DataPlane.Namespace)
+func UnDeployKongConsumerCR(name string, namespace string, k8sClient client.Client) error {
+	loggers.LoggerK8sClient.Debugf("Undeploying KongConsumer CR|Name:%s Namespace:%s\n", name, namespace)
 
 	resource := &v1.KongConsumer{}
-	objKey := client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: name}
+	objKey := client.ObjectKey{Namespace: namespace, Name: name}
 	// Retrieve CR from Kubernetes cluster
 	if err := k8sClient.Get(context.Background(), objKey, resource); err != nil {
-		loggers.LoggerK8sClient.Error("Unable to get Kong Consumer CR: " + err.Error())
-	} else {
-		if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil {
-			loggers.LoggerK8sClient.Errorf("Unable to delete Kong Consumer CR: %v", err)
-		} else {
-			loggers.LoggerK8sClient.Infof("Deleted Kong Consumer CR: %s", resource.Name)
+		if k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Warnf("Kong Consumer CR %s already deleted or not found", name)
+			return nil
 		}
+		loggers.LoggerK8sClient.Error("Unable to get Kong Consumer CR: " + err.Error())
+		return fmt.Errorf("failed to get Kong Consumer CR %s: %w", name, err)
+	}
+	if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+		loggers.LoggerK8sClient.Errorf("Unable to delete Kong Consumer CR: %v", err)
+		return fmt.Errorf("failed to delete Kong Consumer CR %s: %w", name, err)
 	}
+	loggers.LoggerK8sClient.Infof("Deleted Kong Consumer CR: %s", resource.Name)
+	return nil
 }
 
 // GetKongConsumerCR gets Kong consumer CR Resources from the Kubernetes cluster based on name.
-func GetKongConsumerCR(name string, k8sClient client.Client, conf *config.Config) *v1.KongConsumer {
-	loggers.LoggerK8sClient.Debugf("Getting KongConsumer CR|Name:%s Namespace:%s\n", name, conf.DataPlane.Namespace)
+func GetKongConsumerCR(name string, namespace string, k8sClient client.Client) *v1.KongConsumer {
+	loggers.LoggerK8sClient.Debugf("Getting KongConsumer CR|Name:%s Namespace:%s\n", name, namespace)
 
 	resource := &v1.KongConsumer{}
-	objKey := client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: name}
+	objKey := client.ObjectKey{Namespace: namespace, Name: name}
 	// Retrieve CR from Kubernetes cluster
 	err := k8sClient.Get(context.Background(), objKey, resource)
 	if err != nil {
@@ -256,10 +368,10 @@ func GetKongConsumerCR(name string, k8sClient client.Client, conf *config.Config
 
 // GetK8sSecrets gets k8s secret CR Resources from the Kubernetes cluster based on given labels.
 func GetK8sSecrets(labelSelectors map[string]string, k8sClient client.Client, conf *config.Config) []corev1.Secret {
-	loggers.LoggerK8sClient.Debugf("Getting k8s secrets|Labels:%d Namespace:%s\n", len(labelSelectors), conf.DataPlane.Namespace)
+	loggers.LoggerK8sClient.Debugf("Getting k8s secrets|Labels:%d Namespace:%s\n", len(labelSelectors), conf.DataPlane.GetLookupNamespace())
 
 	resourceList := &corev1.SecretList{}
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(labelSelectors)}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(labelSelectors)}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -271,11 +383,11 @@ func GetK8sSecrets(labelSelectors map[string]string, k8sClient client.Client, co
 }
 
 // GetK8sSecret gets k8s secret resource from the Kubernetes cluster based on given name.
-func GetK8sSecret(name string, k8sClient client.Client, conf *config.Config) *corev1.Secret {
-	loggers.LoggerK8sClient.Debugf("Getting k8s secret|Name:%s Namespace:%s\n", name, conf.DataPlane.Namespace)
+func GetK8sSecret(name string, namespace string, k8sClient client.Client) *corev1.Secret {
+	loggers.LoggerK8sClient.Debugf("Getting k8s secret|Name:%s Namespace:%s\n", name, namespace)
 
 	resource := &corev1.Secret{}
-	objKey := client.ObjectKey{Namespace: conf.DataPlane.Namespace, Name: name}
+	objKey := client.ObjectKey{Namespace: namespace, Name: name}
 	// Retrieve CR from the Kubernetes cluster
 	err := k8sClient.Get(context.Background(), objKey, resource)
 	if err != nil {
@@ -296,8 +408,55 @@ func UndeployAPICRs(apiID string, k8sClient client.Client) {
 	}
 
 	undeployHTTPRoutes(apiID, k8sClient, conf)
+	undeployGRPCRoutes(apiID, k8sClient, conf)
 	undeployServices(apiID, k8sClient, conf)
-	undeployKongPlugins(k8sClient, conf, labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID}))
+	undeployUpstreamPolicies(apiID, k8sClient, conf)
+	undeployKongPlugins(k8sClient, conf, labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID}), nil)
+	undeployAPISecrets(apiID, k8sClient, conf)
+}
+
+// UndeployAPIRevisionCRs removes the API Custom Resources of the deployed revision of an API before a new revision
+// is deployed. The KongPlugins enforcing the lifecycle state of the API are retained, so that the state is applied
+// to the routes of the new revision.
+func UndeployAPIRevisionCRs(apiID string, k8sClient client.Client) {
+	loggers.LoggerK8sClient.Debugf("Undeploying API revision CRs|APIID:%s\n", apiID)
+
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
+	}
+
+	undeployHTTPRoutes(apiID, k8sClient, conf)
+	undeployGRPCRoutes(apiID, k8sClient, conf)
+	undeployServices(apiID, k8sClient, conf)
+	undeployUpstreamPolicies(apiID, k8sClient, conf)
+	undeployKongPlugins(k8sClient, conf, labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID}), isLifeCyclePlugin)
+	undeployAPISecrets(apiID, k8sClient, conf)
+}
+
+// RetrieveAPILifeCycle returns the lifecycle state of an API recorded in the annotations of the KongPlugin enforcing
+// it. The lifecycle is empty when no state is enforced on the API.
+func RetrieveAPILifeCycle(apiID string, k8sClient client.Client) (transformer.APILifeCycle, error) {
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		return transformer.APILifeCycle{}, fmt.Errorf("failed to read configurations: %w", errReadConfig)
+	}
+	resourceList := &v1.KongPluginList{}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
+	if err := k8sClient.List(context.Background(), resourceList, listOpts); err != nil {
+		return transformer.APILifeCycle{}, fmt.Errorf("failed to list KongPlugin CRs of API %s: %w", apiID, err)
+	}
+	for _, resource := range resourceList.Items {
+		if isLifeCyclePlugin(resource) {
+			return transformer.LifeCycleFromAnnotations(resource.Annotations), nil
+		}
+	}
+	return transformer.APILifeCycle{}, nil
+}
+
+// isLifeCyclePlugin checks whether the KongPlugin enforces the lifecycle state of an API
+func isLifeCyclePlugin(plugin v1.KongPlugin) bool {
+	return plugin.Annotations[constants.LifeCycleStateAnnotation] != constants.EmptyString
 }
 
 // UndeployAPPCRs removes the APP Custom Resources from the Kubernetes cluster based on Application ID label.
@@ -309,7 +468,7 @@ func UndeployAPPCRs(appID string, k8sClient client.Client) {
 		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
 	}
 	undeployKongConsumers(appID, k8sClient, conf)
-	undeployKongPlugins(k8sClient, conf, labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID}))
+	undeployKongPlugins(k8sClient, conf, labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID}), nil)
 	unDeploySecrets(appID, k8sClient, conf)
 }
 
@@ -318,7 +477,7 @@ func undeployHTTPRoutes(apiID string, k8sClient client.Client, conf *config.Conf
 	loggers.LoggerK8sClient.Debugf("Undeploying HTTPRoutes|APIID:%s\n", apiID)
 
 	resourceList := &gwapiv1.HTTPRouteList{}
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -340,12 +499,39 @@ func undeployHTTPRoutes(apiID string, k8sClient client.Client, conf *config.Conf
 	}
 }
 
+// undeployGRPCRoutes removes the GRPCRoute Resources from the Kubernetes cluster based on API ID label.
+func undeployGRPCRoutes(apiID string, k8sClient client.Client, conf *config.Config) {
+	loggers.LoggerK8sClient.Debugf("Undeploying GRPCRoutes|APIID:%s\n", apiID)
+
+	resourceList := &gwapiv1.GRPCRouteList{}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
+	// Retrieve all CRs from the Kubernetes cluster
+	err := k8sClient.List(context.Background(), resourceList, listOpts)
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list GRPCRoute CRs: %v", err)
+	} else {
+		for _, resource := range resourceList.Items {
+			if origin, exists := resource.GetLabels()[constants.K8sInitiatedFromField]; !exists {
+				continue
+			} else if origin == constants.DataPlaneOrigin {
+				continue
+			}
+			err := k8sClient.Delete(context.Background(), &resource, &client.DeleteOptions{})
+			if err != nil {
+				loggers.LoggerK8sClient.Errorf("Unable to delete GRPCRoute CR: %v", err)
+			} else {
+				loggers.LoggerK8sClient.Infof("Deleted GRPCRoute CR: %s", resource.Name)
+			}
+		}
+	}
+}
+
 // undeployServices removes the Service Resources from the Kubernetes cluster based on API ID label.
 func undeployServices(apiID string, k8sClient client.Client, conf *config.Config) {
 	loggers.LoggerK8sClient.Debugf("Undeploying Services|APIID:%s\n", apiID)
 
 	resourceList := &corev1.ServiceList{}
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -367,12 +553,37 @@ func undeployServices(apiID string, k8sClient client.Client, conf *config.Config
 	}
 }
 
+// undeployUpstreamPolicies removes the KongUpstreamPolicy Resources from the Kubernetes cluster based on API ID label.
+func undeployUpstreamPolicies(apiID string, k8sClient client.Client, conf *config.Config) {
+	loggers.LoggerK8sClient.Debugf("Undeploying KongUpstreamPolicies|APIID:%s\n", apiID)
+
+	resourceList := &v1beta1.KongUpstreamPolicyList{}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
+	// Retrieve all CRs from the Kubernetes cluster
+	err := k8sClient.List(context.Background(), resourceList, listOpts)
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list KongUpstreamPolicy CRs: %v", err)
+	} else {
+		for _, resource := range resourceList.Items {
+			if resource.GetLabels()[constants.K8sInitiatedFromField] != constants.ControlPlaneOrigin {
+				continue
+			}
+			err := k8sClient.Delete(context.Background(), &resource, &client.DeleteOptions{})
+			if err != nil {
+				loggers.LoggerK8sClient.Errorf("Unable to delete KongUpstreamPolicy CR: %v", err)
+			} else {
+				loggers.LoggerK8sClient.Infof("Deleted KongUpstreamPolicy CR: %s", resource.Name)
+			}
+		}
+	}
+}
+
 // undeployKongPlugins removes the KongPlugin Resources from the Kubernetes cluster based on label selector.
-func undeployKongPlugins(k8sClient client.Client, conf *config.Config, labelSelector labels.Selector) {
+func undeployKongPlugins(k8sClient client.Client, conf *config.Config, labelSelector labels.Selector, retain func(v1.KongPlugin) bool) {
 	loggers.LoggerK8sClient.Debugf("Undeploying KongPlugins|LabelSelector:%s\n", labelSelector.String())
 
 	resourceList := &v1.KongPluginList{}
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labelSelector}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labelSelector}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -384,6 +595,9 @@ func undeployKongPlugins(k8sClient client.Client, conf *config.Config, labelSele
 			} else if origin == constants.DataPlaneOrigin {
 				continue
 			}
+			if retain != nil && retain(resource) {
+				continue
+			}
 			err := k8sClient.Delete(context.Background(), &resource, &client.DeleteOptions{})
 			if err != nil {
 				loggers.LoggerK8sClient.Errorf("Unable to delete KongPlugin CR: %v", err)
@@ -399,7 +613,7 @@ func undeployKongConsumers(appID string, k8sClient client.Client, conf *config.C
 	loggers.LoggerK8sClient.Debugf("Undeploying KongConsumers|AppID:%s\n", appID)
 
 	resourceList := &v1.KongConsumerList{}
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID})}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID})}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -421,13 +635,39 @@ func unDeploySecrets(appID string, k8sClient client.Client, conf *config.Config)
 	loggers.LoggerK8sClient.Debugf("Undeploying Secrets|AppID:%s\n", appID)
 
 	resourceList := &corev1.SecretList{}
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID})}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID})}
+	// Retrieve all CRs from the Kubernetes cluster
+	err := k8sClient.List(context.Background(), resourceList, listOpts)
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list Secret CRs: %v", err)
+	} else {
+		for _, resource := range resourceList.Items {
+			err := k8sClient.Delete(context.Background(), &resource, &client.DeleteOptions{})
+			if err != nil {
+				loggers.LoggerK8sClient.Errorf("Unable to delete Secret CR: %v", err)
+			} else {
+				loggers.LoggerK8sClient.Infof("Deleted Secret CR: %s", resource.Name)
+			}
+		}
+	}
+}
+
+// undeployAPISecrets removes the Secret Resources holding the endpoint credentials of an API from the Kubernetes
+// cluster based on API ID label.
+func undeployAPISecrets(apiID string, k8sClient client.Client, conf *config.Config) {
+	loggers.LoggerK8sClient.Debugf("Undeploying API Secrets|APIID:%s\n", apiID)
+
+	resourceList := &corev1.SecretList{}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
 		loggers.LoggerK8sClient.Errorf("Unable to list Secret CRs: %v", err)
 	} else {
 		for _, resource := range resourceList.Items {
+			if resource.GetLabels()[constants.K8sInitiatedFromField] != constants.ControlPlaneOrigin {
+				continue
+			}
 			err := k8sClient.Delete(context.Background(), &resource, &client.DeleteOptions{})
 			if err != nil {
 				loggers.LoggerK8sClient.Errorf("Unable to delete Secret CR: %v", err)
@@ -448,7 +688,7 @@ func UpdateKongConsumerCredential(appID string, env string, k8sClient client.Cli
 		labelSelectors[constants.EnvironmentLabel] = env
 	}
 
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(labelSelectors)}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(labelSelectors)}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -480,7 +720,7 @@ func UpdateKongConsumerPluginAnnotation(appID string, env string, k8sClient clie
 		labelSelectors[constants.EnvironmentLabel] = env
 	}
 
-	listOpts := &client.ListOptions{Namespace: conf.DataPlane.Namespace, LabelSelector: labels.SelectorFromSet(labelSelectors)}
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(labelSelectors)}
 	// Retrieve all CRs from the Kubernetes cluster
 	err := k8sClient.List(context.Background(), resourceList, listOpts)
 	if err != nil {
@@ -501,3 +741,203 @@ func UpdateKongConsumerPluginAnnotation(appID string, env string, k8sClient clie
 	}
 	return nil
 }
+
+// UpdateAPILifeCyclePlugin attaches the KongPlugin enforcing the lifecycle state of an API to the routes of the API
+// created from the control plane and detaches the other lifecycle plugins, which are then removed. The plugin is
+// deployed to the namespaces of the routes, and a nil plugin only detaches the existing ones. The plugin records the
+// state of an API which is not deployed yet in the namespace of its organization, so that it is applied once the API
+// is deployed.
+func UpdateAPILifeCyclePlugin(apiID string, organization string, lifeCyclePlugin *v1.KongPlugin, lifeCyclePluginNames []string, k8sClient client.Client) error {
+	loggers.LoggerK8sClient.Debugf("Updating lifecycle plugin of API routes|APIID:%s Plugins:%d\n", apiID, len(lifeCyclePluginNames))
+
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		return fmt.Errorf("failed to read configurations: %w", errReadConfig)
+	}
+	var addPlugins []string
+	if lifeCyclePlugin != nil {
+		addPlugins = []string{lifeCyclePlugin.Name}
+	}
+	removePlugins := make([]string, 0, len(lifeCyclePluginNames))
+	for _, pluginName := range lifeCyclePluginNames {
+		if lifeCyclePlugin == nil || pluginName != lifeCyclePlugin.Name {
+			removePlugins = append(removePlugins, pluginName)
+		}
+	}
+
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: labels.SelectorFromSet(map[string]string{constants.APIUUIDLabel: apiID})}
+	namespaces := make(map[string]bool)
+	for _, resourceList := range []client.ObjectList{&gwapiv1.HTTPRouteList{}, &gwapiv1.GRPCRouteList{}} {
+		// Retrieve all CRs from the Kubernetes cluster
+		if err := k8sClient.List(context.Background(), resourceList, listOpts); err != nil {
+			loggers.LoggerK8sClient.Errorf("Unable to list route CRs: %v", err)
+			return fmt.Errorf("failed to list route CRs of API %s: %w", apiID, err)
+		}
+		err := meta.EachListItem(resourceList, func(item runtime.Object) error {
+			route, ok := item.(client.Object)
+			if !ok || route.GetLabels()[constants.K8sInitiatedFromField] != constants.ControlPlaneOrigin {
+				return nil
+			}
+			if lifeCyclePlugin != nil && !namespaces[route.GetNamespace()] {
+				plugin := lifeCyclePlugin.DeepCopy()
+				plugin.Namespace = route.GetNamespace()
+				plugin.Labels = map[string]string{
+					constants.OrganizationLabel:     route.GetLabels()[constants.OrganizationLabel],
+					constants.APIUUIDLabel:          apiID,
+					constants.K8sInitiatedFromField: constants.ControlPlaneOrigin,
+				}
+				DeployKongPluginCR(plugin, k8sClient)
+			}
+			namespaces[route.GetNamespace()] = true
+			return utils.RetryKongCRUpdate(func() error {
+				return updateRoutePluginAnnotation(route, k8sClient, addPlugins, removePlugins)
+			}, constants.UpdateRouteLifeCycleTask, constants.MaxRetries)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update the lifecycle plugin of API %s: %w", apiID, err)
+		}
+	}
+
+	if lifeCyclePlugin != nil && len(namespaces) == 0 {
+		plugin := lifeCyclePlugin.DeepCopy()
+		plugin.Namespace = conf.DataPlane.GetOrganizationNamespace(organization)
+		plugin.Labels = map[string]string{
+			constants.APIUUIDLabel:          apiID,
+			constants.K8sInitiatedFromField: constants.ControlPlaneOrigin,
+		}
+		DeployKongPluginCR(plugin, k8sClient)
+	}
+
+	// The detached plugins are removed once no route refers to them, along with the ones recording the state of the
+	// API before it was deployed
+	namespaces[conf.DataPlane.GetOrganizationNamespace(organization)] = true
+	for namespace := range namespaces {
+		for _, pluginName := range removePlugins {
+			plugin := &v1.KongPlugin{}
+			plugin.Name = pluginName
+			plugin.Namespace = namespace
+			if err := k8sClient.Delete(context.Background(), plugin, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+				loggers.LoggerK8sClient.Errorf("Unable to delete KongPlugin CR: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// updateRoutePluginAnnotation updates the plugins annotation of the latest version of a route
+func updateRoutePluginAnnotation(route client.Object, k8sClient client.Client, addPlugins []string, removePlugins []string) error {
+	latest, ok := route.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected route type %T", route)
+	}
+	if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(route), latest); err != nil {
+		return err
+	}
+	annotations := latest.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	plugins := utils.PrepareAnnotations(annotations[constants.KongPluginsAnnotation], addPlugins, removePlugins)
+	if plugins == annotations[constants.KongPluginsAnnotation] {
+		return nil
+	}
+	annotations[constants.KongPluginsAnnotation] = plugins
+	latest.SetAnnotations(annotations)
+	if err := k8sClient.Update(context.Background(), latest, &client.UpdateOptions{}); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to update route CR annotations: %v", err)
+		return err
+	}
+	loggers.LoggerK8sClient.Infof("Updated route CR plugins annotation: %s", latest.GetName())
+	return nil
+}
+
+// UndeployOrganizationCRs removes the Custom Resources of an organization from the Kubernetes cluster based on the
+// organization label and records them in the purge report. The API routes, services, upstream policies and plugins
+// are removed only when they were created from the control plane. The UUIDs of the removed APIs and applications are returned.
+func UndeployOrganizationCRs(organizationHash string, k8sClient client.Client, report *agent.PurgeReport) ([]string, []string) {
+	loggers.LoggerK8sClient.Debugf("Undeploying organization CRs|OrganizationHash:%s\n", organizationHash)
+
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		loggers.LoggerK8sClient.Errorf("Error reading configurations: %v", errReadConfig)
+	}
+	selector := labels.SelectorFromSet(map[string]string{constants.OrganizationLabel: organizationHash})
+	isControlPlaneResource := func(resource client.Object) bool {
+		return resource.GetLabels()[constants.K8sInitiatedFromField] == constants.ControlPlaneOrigin
+	}
+	anyResource := func(client.Object) bool { return true }
+
+	apiIDs := map[string]bool{}
+	for _, resourceType := range []struct {
+		list client.ObjectList
+		kind string
+	}{
+		{&gwapiv1.HTTPRouteList{}, constants.HTTPRouteKind},
+		{&gwapiv1.GRPCRouteList{}, constants.GRPCRouteKind},
+		{&corev1.ServiceList{}, constants.ServiceKind},
+		{&v1beta1.KongUpstreamPolicyList{}, constants.KongUpstreamPolicyKind},
+		{&v1.KongPluginList{}, constants.KongPluginKind},
+	} {
+		for _, resource := range purgeResources(resourceType.list, resourceType.kind, selector, isControlPlaneResource, k8sClient, conf, report) {
+			if apiID := resource.GetLabels()[constants.APIUUIDLabel]; apiID != constants.EmptyString {
+				apiIDs[apiID] = true
+			}
+		}
+	}
+
+	appIDs := map[string]bool{}
+	for _, consumer := range purgeResources(&v1.KongConsumerList{}, constants.KongConsumerKind, selector, anyResource, k8sClient, conf, report) {
+		if appID := consumer.GetLabels()[constants.ApplicationUUIDLabel]; appID != constants.EmptyString {
+			appIDs[appID] = true
+		}
+	}
+	purgeResources(&corev1.SecretList{}, constants.SecretKind, selector, anyResource, k8sClient, conf, report)
+	// Credentials created before the organization label was introduced are found through their applications
+	for appID := range appIDs {
+		appSelector := labels.SelectorFromSet(map[string]string{constants.ApplicationUUIDLabel: appID})
+		purgeResources(&corev1.SecretList{}, constants.SecretKind, appSelector, anyResource, k8sClient, conf, report)
+		purgeResources(&v1.KongPluginList{}, constants.KongPluginKind, appSelector, isControlPlaneResource, k8sClient, conf, report)
+	}
+	return mapKeys(apiIDs), mapKeys(appIDs)
+}
+
+// purgeResources deletes the resources of the given kind that match the label selector and the filter and
+// returns the deleted resources
+func purgeResources(resourceList client.ObjectList, kind string, selector labels.Selector, filter func(client.Object) bool,
+	k8sClient client.Client, conf *config.Config, report *agent.PurgeReport) []client.Object {
+	listOpts := &client.ListOptions{Namespace: conf.DataPlane.GetLookupNamespace(), LabelSelector: selector}
+	if err := k8sClient.List(context.Background(), resourceList, listOpts); err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to list %s CRs: %v", kind, err)
+		report.AddFailed(kind, selector.String())
+		return nil
+	}
+	deleted := make([]client.Object, 0)
+	err := meta.EachListItem(resourceList, func(item runtime.Object) error {
+		resource, ok := item.(client.Object)
+		if !ok || !filter(resource) {
+			return nil
+		}
+		if err := k8sClient.Delete(context.Background(), resource, &client.DeleteOptions{}); err != nil && !k8error.IsNotFound(err) {
+			loggers.LoggerK8sClient.Errorf("Unable to delete %s CR %s: %v", kind, resource.GetName(), err)
+			report.AddFailed(kind, resource.GetName())
+			return nil
+		}
+		loggers.LoggerK8sClient.Infof("Deleted %s CR: %s", kind, resource.GetName())
+		report.AddDeleted(kind, resource.GetName())
+		deleted = append(deleted, resource)
+		return nil
+	})
+	if err != nil {
+		loggers.LoggerK8sClient.Errorf("Unable to read the listed %s CRs: %v", kind, err)
+		report.AddFailed(kind, selector.String())
+	}
+	return deleted
+}
+
+func mapKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	return keys
+}
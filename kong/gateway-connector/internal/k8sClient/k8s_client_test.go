@@ -0,0 +1,173 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package k8sclient
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// newTestK8sClient returns a fake client holding the given objects
+func newTestK8sClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, gwapiv1.Install(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestRetrieveAPILifeCycle(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	namespace := conf.DataPlane.GetOrganizationNamespace("org1")
+	k8sClient := newTestK8sClient(t)
+	pluginNames := transformer.LifeCyclePluginNames("api1")
+
+	lifeCycle, err := RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Empty(t, lifeCycle.State)
+
+	// The state of an API which is not deployed yet is recorded in the namespace of its organization
+	blocked := transformer.NewAPILifeCycle(constants.BlockedLifeCycleState, 0)
+	require.NoError(t, UpdateAPILifeCyclePlugin("api1", "org1", transformer.GenerateLifeCyclePlugin("api1", blocked),
+		pluginNames, k8sClient))
+	lifeCycle, err = RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, blocked, lifeCycle)
+
+	// The lifecycle plugin is retained when a new revision of the API is deployed
+	UndeployAPIRevisionCRs("api1", k8sClient)
+	lifeCycle, err = RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, blocked, lifeCycle)
+
+	// Publishing the API again removes the recorded state
+	require.NoError(t, UpdateAPILifeCyclePlugin("api1", "org1", nil, pluginNames, k8sClient))
+	lifeCycle, err = RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Empty(t, lifeCycle.State)
+	plugins := &v1.KongPluginList{}
+	require.NoError(t, k8sClient.List(context.Background(), plugins, client.InNamespace(namespace)))
+	assert.Empty(t, plugins.Items)
+}
+
+func TestUpdateAPILifeCyclePluginOfDeployedAPI(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	namespace := conf.DataPlane.GetOrganizationNamespace("org1")
+	route := &gwapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "route1", Namespace: namespace,
+		Labels:      map[string]string{constants.APIUUIDLabel: "api1", constants.K8sInitiatedFromField: constants.ControlPlaneOrigin},
+		Annotations: map[string]string{constants.KongPluginsAnnotation: "cors"}}}
+	k8sClient := newTestK8sClient(t, route)
+	pluginNames := transformer.LifeCyclePluginNames("api1")
+
+	deprecated := transformer.NewAPILifeCycle(constants.DeprecatedLifeCycleState, 1740787200000)
+	deprecatedPlugin := transformer.GenerateLifeCyclePlugin("api1", deprecated)
+	require.NoError(t, UpdateAPILifeCyclePlugin("api1", "org1", deprecatedPlugin, pluginNames, k8sClient))
+	updatedRoute := &gwapiv1.HTTPRoute{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(route), updatedRoute))
+	assert.Equal(t, "cors,"+deprecatedPlugin.Name, updatedRoute.Annotations[constants.KongPluginsAnnotation])
+	lifeCycle, err := RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, deprecated, lifeCycle)
+
+	// Deprecating the API again updates the recorded deprecation time
+	redeprecated := transformer.NewAPILifeCycle(constants.DeprecatedLifeCycleState, 1740873600000)
+	require.NoError(t, UpdateAPILifeCyclePlugin("api1", "org1", transformer.GenerateLifeCyclePlugin("api1", redeprecated),
+		pluginNames, k8sClient))
+	lifeCycle, err = RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, redeprecated, lifeCycle)
+
+	// Removing the API removes the lifecycle plugin
+	UndeployAPICRs("api1", k8sClient)
+	lifeCycle, err = RetrieveAPILifeCycle("api1", k8sClient)
+	require.NoError(t, err)
+	assert.Empty(t, lifeCycle.State)
+}
+
+func TestDeploySecretCRReplacesData(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "endpoint-security", Namespace: "kong"},
+		Data: map[string][]byte{"username": []byte("admin"), "password": []byte("admin")}}
+	k8sClient := newTestK8sClient(t, secret)
+
+	DeploySecretCR(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "endpoint-security", Namespace: "kong"},
+		StringData: map[string]string{"apiKey": "key"}}, k8sClient)
+	updated := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(secret), updated))
+	assert.Equal(t, map[string][]byte{"apiKey": []byte("key")}, updated.Data)
+
+	DeploySecretCR(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "endpoint-security", Namespace: "kong"},
+		Data: map[string][]byte{"token": []byte("token")}}, k8sClient)
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(secret), updated))
+	assert.Equal(t, map[string][]byte{"token": []byte("token")}, updated.Data)
+}
+
+func TestDeployServiceCRReplacesManagedAnnotations(t *testing.T) {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "kong",
+		Annotations: map[string]string{
+			constants.KongRetriesAnnotation:        "2",
+			constants.KongUpstreamPolicyAnnotation: "backend",
+			constants.KongReadTimeoutAnnotation:    "30000",
+			"example.com/owner":                    "team",
+		}}}
+	k8sClient := newTestK8sClient(t, service)
+
+	DeployServiceCR(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "kong",
+		Annotations: map[string]string{
+			constants.KongProtocolAnnotation:    "https",
+			constants.KongReadTimeoutAnnotation: "60000",
+		}}, Spec: corev1.ServiceSpec{ExternalName: "backend.example.com"}}, k8sClient)
+	updated := &corev1.Service{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(service), updated))
+	assert.Equal(t, "backend.example.com", updated.Spec.ExternalName)
+	assert.Equal(t, map[string]string{
+		constants.KongProtocolAnnotation:    "https",
+		constants.KongReadTimeoutAnnotation: "60000",
+		"example.com/owner":                 "team",
+	}, updated.Annotations)
+}
+
+func TestDeployKongConsumerCRRetainsCredentials(t *testing.T) {
+	consumer := &v1.KongConsumer{ObjectMeta: metav1.ObjectMeta{Name: "app1-production", Namespace: "kong"},
+		Username: "app1", Credentials: []string{"app1-production-acl", "app1-production-jwt"}}
+	k8sClient := newTestK8sClient(t, consumer)
+
+	DeployKongConsumerCR(&v1.KongConsumer{ObjectMeta: metav1.ObjectMeta{Name: "app1-production", Namespace: "kong"},
+		Username: "app1-renamed", CustomID: "app1", Credentials: []string{"app1-production-acl", "app1-production-key"}},
+		k8sClient)
+	updated := &v1.KongConsumer{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(consumer), updated))
+	assert.Equal(t, "app1-renamed", updated.Username)
+	assert.Equal(t, "app1", updated.CustomID)
+	assert.ElementsMatch(t, []string{"app1-production-acl", "app1-production-jwt", "app1-production-key"},
+		updated.Credentials)
+}
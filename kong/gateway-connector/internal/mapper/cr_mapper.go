@@ -24,40 +24,82 @@
 package mapper
 
 import (
+	"context"
+	"errors"
+
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // MapAndCreateCR will read the CRD YAML and based on the Kind of the CR, unmarshal and maps the
-// data and sends to the K8-Client for creating the respective CR inside the cluster
-func MapAndCreateCR(k8sArtifact transformer.K8sArtifacts, k8sClient client.Client) *error {
-	namespace, err := getDeploymentNamespace()
+// data and sends to the K8-Client for creating the respective CR inside the cluster. The errors of creating the CRs
+// are returned together.
+func MapAndCreateCR(ctx context.Context, k8sArtifact transformer.K8sArtifacts, k8sClient client.Client) *error {
+	namespace, gatewayNamespace, err := getDeploymentNamespace(ctx, k8sArtifact)
 	if err != nil {
 		return &err
 	}
+	logger.LoggerMapper.FromContext(ctx).Debugf("Creating the CRs of the API in namespace %s", namespace)
+	var deployErrs []error
 	for _, httpRoutes := range k8sArtifact.HTTPRoutes {
 		httpRoutes.Namespace = namespace
-		internalk8sClient.DeployHTTPRouteCR(httpRoutes, k8sClient)
+		setGatewayNamespace(httpRoutes.Spec.ParentRefs, namespace, gatewayNamespace)
+		deployErrs = append(deployErrs, internalk8sClient.DeployHTTPRouteCR(httpRoutes, k8sClient))
+	}
+	for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+		grpcRoute.Namespace = namespace
+		setGatewayNamespace(grpcRoute.Spec.ParentRefs, namespace, gatewayNamespace)
+		deployErrs = append(deployErrs, internalk8sClient.DeployGRPCRouteCR(grpcRoute, k8sClient))
 	}
 	for _, service := range k8sArtifact.Services {
 		service.Namespace = namespace
-		internalk8sClient.DeployServiceCR(service, k8sClient)
+		deployErrs = append(deployErrs, internalk8sClient.DeployServiceCR(service, k8sClient))
+	}
+	for _, upstreamPolicy := range k8sArtifact.UpstreamPolicies {
+		upstreamPolicy.Namespace = namespace
+		deployErrs = append(deployErrs, internalk8sClient.DeployKongUpstreamPolicyCR(upstreamPolicy, k8sClient))
+	}
+	for _, secret := range k8sArtifact.Secrets {
+		secret.Namespace = namespace
+		deployErrs = append(deployErrs, internalk8sClient.DeploySecretCR(secret, k8sClient))
 	}
 	for _, kongPlugin := range k8sArtifact.KongPlugins {
 		kongPlugin.Namespace = namespace
-		internalk8sClient.DeployKongPluginCR(kongPlugin, k8sClient)
+		deployErrs = append(deployErrs, internalk8sClient.DeployKongPluginCR(kongPlugin, k8sClient))
+	}
+	if err := errors.Join(deployErrs...); err != nil {
+		return &err
 	}
 	return nil
 }
 
-func getDeploymentNamespace() (string, error) {
+// getDeploymentNamespace returns the namespace of the organization the API belongs to along with the namespace
+// of the gateway
+func getDeploymentNamespace(ctx context.Context, k8sArtifact transformer.K8sArtifacts) (string, string, error) {
 	conf, errReadConfig := config.ReadConfigs()
 	if errReadConfig != nil {
-		logger.LoggerMapper.Errorf("Error reading configs: %v", errReadConfig)
-		return "", errReadConfig
+		logger.LoggerMapper.FromContext(ctx).Errorf("Error reading configs: %v", errReadConfig)
+		return "", "", errReadConfig
+	}
+	if k8sArtifact.Namespace == "" {
+		return conf.DataPlane.Namespace, conf.DataPlane.Namespace, nil
+	}
+	return k8sArtifact.Namespace, conf.DataPlane.Namespace, nil
+}
+
+// setGatewayNamespace points the routes deployed outside the gateway namespace to the gateway
+func setGatewayNamespace(parentRefs []gwapiv1.ParentReference, namespace string, gatewayNamespace string) {
+	if namespace == gatewayNamespace {
+		return
+	}
+	for i := range parentRefs {
+		if parentRefs[i].Namespace == nil {
+			parentNamespace := gwapiv1.Namespace(gatewayNamespace)
+			parentRefs[i].Namespace = &parentNamespace
+		}
 	}
-	return conf.DataPlane.Namespace, nil
 }
@@ -17,18 +17,34 @@
 
 package managementserver
 
-import "sync"
+import (
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/snapshot"
+)
 
 var (
 	processedAPIUUIDs map[string]struct{} // Hash set for processed API UUIDs
 	processedAppUUIDs map[string]struct{} // Hash set for processed Application UUIDs
 	apiMutex          sync.RWMutex        // Mutex for API UUID operations
 	appMutex          sync.RWMutex        // Mutex for Application UUID operations
+	// applicationPolicies maps Application UUIDs to the application policies throttling them
+	applicationPolicies    map[string]ApplicationPolicyBinding
+	applicationPolicyMutex sync.RWMutex // Mutex for application policy operations
 )
 
+// ApplicationPolicyBinding is the application policy of an application
+type ApplicationPolicyBinding struct {
+	PolicyName   string `json:"policyName"`
+	Organization string `json:"organization"`
+}
+
 func init() {
 	processedAPIUUIDs = make(map[string]struct{})
 	processedAppUUIDs = make(map[string]struct{})
+	applicationPolicies = make(map[string]ApplicationPolicyBinding)
 }
 
 // AddProcessedAPI marks an API UUID as processed
@@ -100,3 +116,86 @@ func GetAllProcessedApplications() []string {
 	}
 	return appUUIDs
 }
+
+// GetProcessedApplicationCount returns the number of processed Application UUIDs
+func GetProcessedApplicationCount() int {
+	appMutex.RLock()
+	defer appMutex.RUnlock()
+	return len(processedAppUUIDs)
+}
+
+// SetApplicationPolicy records the application policy of an application and returns the previous one
+func SetApplicationPolicy(appUUID string, binding ApplicationPolicyBinding) ApplicationPolicyBinding {
+	applicationPolicyMutex.Lock()
+	defer applicationPolicyMutex.Unlock()
+	previous := applicationPolicies[appUUID]
+	applicationPolicies[appUUID] = binding
+	return previous
+}
+
+// GetApplicationPolicy returns the application policy of an application
+func GetApplicationPolicy(appUUID string) ApplicationPolicyBinding {
+	applicationPolicyMutex.RLock()
+	defer applicationPolicyMutex.RUnlock()
+	return applicationPolicies[appUUID]
+}
+
+// RemoveApplicationPolicy removes the application policy of an application
+func RemoveApplicationPolicy(appUUID string) {
+	applicationPolicyMutex.Lock()
+	defer applicationPolicyMutex.Unlock()
+	delete(applicationPolicies, appUUID)
+}
+
+// GetApplicationsOfPolicy returns the UUIDs of the applications of the organization throttled by the application
+// policy
+func GetApplicationsOfPolicy(policyName string, organization string) []string {
+	applicationPolicyMutex.RLock()
+	defer applicationPolicyMutex.RUnlock()
+	var appUUIDs []string
+	for uuid, binding := range applicationPolicies {
+		if binding.PolicyName == policyName && binding.Organization == organization {
+			appUUIDs = append(appUUIDs, uuid)
+		}
+	}
+	sort.Strings(appUUIDs)
+	return appUUIDs
+}
+
+// RegisterSnapshotStores registers the processed API and Application UUIDs and the application policies to be
+// persisted in the snapshot
+func RegisterSnapshotStores() {
+	snapshot.Register("processedAPIs", func() []string {
+		apiUUIDs := GetAllProcessedAPIs()
+		sort.Strings(apiUUIDs)
+		return apiUUIDs
+	}, func(apiUUIDs []string) {
+		apiMutex.Lock()
+		defer apiMutex.Unlock()
+		processedAPIUUIDs = make(map[string]struct{}, len(apiUUIDs))
+		for _, uuid := range apiUUIDs {
+			processedAPIUUIDs[uuid] = struct{}{}
+		}
+	})
+	snapshot.Register("processedApplications", func() []string {
+		appUUIDs := GetAllProcessedApplications()
+		sort.Strings(appUUIDs)
+		return appUUIDs
+	}, func(appUUIDs []string) {
+		appMutex.Lock()
+		defer appMutex.Unlock()
+		processedAppUUIDs = make(map[string]struct{}, len(appUUIDs))
+		for _, uuid := range appUUIDs {
+			processedAppUUIDs[uuid] = struct{}{}
+		}
+	})
+	snapshot.Register("applicationPolicies", func() map[string]ApplicationPolicyBinding {
+		applicationPolicyMutex.RLock()
+		defer applicationPolicyMutex.RUnlock()
+		return maps.Clone(applicationPolicies)
+	}, func(bindings map[string]ApplicationPolicyBinding) {
+		applicationPolicyMutex.Lock()
+		defer applicationPolicyMutex.Unlock()
+		applicationPolicies = bindings
+	})
+}
@@ -24,15 +24,22 @@
 package synchronizer
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/logging"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/metrics"
 	sync "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/synchronizer"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/tracing"
 	transformer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
 	mapperUtil "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/mapper"
 	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/loggers"
 	kongTransformer "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
+	"go.opentelemetry.io/otel/attribute"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -48,19 +55,21 @@ func init() {
 		poolConfig.PauseTimeAfterFailure, conf.Agent.TrustStore.Location,
 		conf.ControlPlane.SkipSSLVerification, conf.ControlPlane.HTTPClient.RequestTimeOut,
 		conf.ControlPlane.RetryInterval, conf.ControlPlane.ServiceURL,
-		conf.ControlPlane.Username, conf.ControlPlane.Password)
+		conf.ControlPlane.Username, conf.ControlPlane.Password,
+		sync.WorkerPoolOptionsFromConfig(conf))
 }
 
-// FetchAPIsOnEvent will fetch API from control plane during the API Notification Event
-func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Client) (*[]string, error) {
+// FetchAPIsOnEvent will fetch API from control plane during the API Notification Event and returns the UUIDs of the
+// deployed APIs along with the errors of deploying the others
+func FetchAPIsOnEvent(ctx context.Context, conf *config.Config, apiUUID *string, k8sClient client.Client) (*[]string, error) {
 	apis := make([]string, 0)
-	apiResult, err := sync.FetchAPIsOnEvent(conf, apiUUID, k8sClient)
+	apiResult, err := sync.FetchAPIsOnEvent(ctx, conf, apiUUID, k8sClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch APIs from control plane: %w", err)
 	}
 
 	if apiResult == nil {
-		logger.LoggerSynchronizer.Debug("No API result received from control plane")
+		logger.LoggerSynchronizer.FromContext(ctx).Debug("No API result received from control plane")
 		return &apis, nil
 	}
 
@@ -68,20 +77,24 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 		apis = append(apis, *apiResult.APIs...)
 	}
 
+	var deployErrs []error
 	if apiResult.APIDeployments != nil {
 		deployments := *apiResult.APIDeployments
-		logger.LoggerSynchronizer.Debugf("Processing %d API deployments", len(deployments))
+		logger.LoggerSynchronizer.FromContext(ctx).Debugf("Processing %d API deployments", len(deployments))
 
 		for i, apiDeployment := range deployments {
+			deployCtx := logging.ContextWithFields(ctx, logging.Fields{logging.ORGANIZATION: apiDeployment.OrganizationID})
 			apiZip, exists := apiResult.APIFiles[apiDeployment.APIFile]
 			if !exists {
-				logger.LoggerSynchronizer.Warnf("API file %s not found for deployment %d", apiDeployment.APIFile, i+1)
+				logger.LoggerSynchronizer.FromContext(deployCtx).Warnf("API file %s not found for deployment %d", apiDeployment.APIFile, i+1)
+				deployErrs = append(deployErrs, fmt.Errorf("API file %s not found for deployment %d", apiDeployment.APIFile, i+1))
 				continue
 			}
 
 			artifact, decodingError := transformer.DecodeAPIArtifact(apiZip)
 			if decodingError != nil {
-				logger.LoggerSynchronizer.Errorf("Error while decoding the API Project Artifact: %v", decodingError)
+				logger.LoggerSynchronizer.FromContext(deployCtx).Errorf("Error while decoding the API Project Artifact: %v", decodingError)
+				deployErrs = append(deployErrs, fmt.Errorf("error decoding the API file %s: %w", apiDeployment.APIFile, decodingError))
 				continue
 			}
 
@@ -92,31 +105,76 @@ func FetchAPIsOnEvent(conf *config.Config, apiUUID *string, k8sClient client.Cli
 					envLabel = firstEnv.Name
 				}
 			}
-			logger.LoggerSynchronizer.Infof("Selected Environment Label: %s", envLabel)
+			logger.LoggerSynchronizer.FromContext(deployCtx).Infof("Selected Environment Label: %s", envLabel)
 
-			api, apiName, generatedAPIUUID, revisionID, configuredRateLimitPoliciesMap, _, _, _, _, kongErr := transformer.GenerateConf(
+			transformCtx, transformSpan := tracing.StartSpan(deployCtx, "TransformAPI",
+				attribute.String("organization", apiDeployment.OrganizationID))
+			api, apiName, generatedAPIUUID, revisionID, configuredRateLimitPoliciesMap, endpointSecurityData, apiModel, _, _, kongErr := transformer.GenerateConf(
 				artifact.APIJson, artifact.CertArtifact, artifact.Endpoints, apiDeployment.OrganizationID, envLabel)
 			if kongErr != nil {
-				logger.LoggerSynchronizer.Errorf("Error while generating Kong-Conf: %v", kongErr)
+				tracing.EndSpan(transformSpan, kongErr)
+				logger.LoggerSynchronizer.FromContext(deployCtx).Errorf("Error while generating Kong-Conf: %v", kongErr)
+				deployErrs = append(deployErrs, fmt.Errorf("error transforming the API file %s: %w", apiDeployment.APIFile, kongErr))
 				continue
 			}
+			deployCtx = logging.ContextWithFields(deployCtx, logging.Fields{
+				logging.APIUUID:    generatedAPIUUID,
+				logging.REVISIONID: fmt.Sprint(revisionID),
+			})
 
-			logger.LoggerSynchronizer.Debugf("Generated API Value : %+v\n", api)
+			logger.LoggerSynchronizer.FromContext(deployCtx).Debugf("Generated API Value : %+v\n", api)
 
+			_, generateSpan := tracing.StartSpan(transformCtx, "GenerateCR")
 			crResources := kongTransformer.GenerateCR(api, apiDeployment.OrganizationID, generatedAPIUUID, conf)
+			generateSpan.End()
+			transformSpan.End()
 			if crResources != nil {
+				if err := kongTransformer.ApplyEndpointSecurity(crResources, endpointSecurityData); err != nil {
+					logger.LoggerSynchronizer.FromContext(deployCtx).Errorf("Error while applying the endpoint security of the API: %v", err)
+					deployErrs = append(deployErrs, fmt.Errorf("error applying the endpoint security of the API %s: %w", generatedAPIUUID, err))
+					continue
+				}
+				kongTransformer.ApplyEndpointResiliency(crResources, apiModel, apiDeployment.OrganizationID)
+				// The endpoint Services are copied from the resilient Services, hence they share their configuration
+				if err := kongTransformer.ApplyEndpointLoadBalancing(crResources, apiModel, apiDeployment.OrganizationID); err != nil {
+					logger.LoggerSynchronizer.FromContext(deployCtx).Errorf("Error while balancing the endpoints of the API: %v", err)
+					deployErrs = append(deployErrs, fmt.Errorf("error balancing the endpoints of the API %s: %w", generatedAPIUUID, err))
+					continue
+				}
+				lifeCycle, err := internalk8sClient.RetrieveAPILifeCycle(generatedAPIUUID, k8sClient)
+				if err != nil {
+					logger.LoggerSynchronizer.FromContext(deployCtx).Errorf("Error while retrieving the lifecycle state of the API: %v", err)
+					deployErrs = append(deployErrs, fmt.Errorf("error retrieving the lifecycle state of the API %s: %w", generatedAPIUUID, err))
+					continue
+				}
+				// The lifecycle state of the API is applied again to the routes of the new revision
+				kongTransformer.ApplyLifeCycleState(crResources, lifeCycle)
 				kongTransformer.UpdateCRS(crResources, apiDeployment.Environments,
 					apiDeployment.OrganizationID, generatedAPIUUID, apiName,
 					fmt.Sprint(revisionID), constants.DefaultKongNamespace,
 					configuredRateLimitPoliciesMap)
 
-				mapperUtil.MapAndCreateCR(*crResources, k8sClient)
+				applyCtx, applySpan := tracing.StartSpan(deployCtx, "MapAndCreateCR", attribute.String("api.uuid", generatedAPIUUID))
+				var applyErr error
+				if errMapping := mapperUtil.MapAndCreateCR(applyCtx, *crResources, k8sClient); errMapping != nil {
+					applyErr = *errMapping
+				}
+				tracing.EndSpan(applySpan, applyErr)
+				if applyErr != nil {
+					logger.LoggerSynchronizer.FromContext(deployCtx).Errorf("Error while applying the CRs of the API %s: %v",
+						generatedAPIUUID, applyErr)
+					deployErrs = append(deployErrs, fmt.Errorf("error applying the CRs of the API %s: %w", generatedAPIUUID, applyErr))
+					continue
+				}
+				metrics.ObserveDeploymentLatency(ctx)
 				apis = append(apis, generatedAPIUUID)
-				logger.LoggerSynchronizer.Infof("API Applied Successfully: %s", generatedAPIUUID)
+				logger.LoggerSynchronizer.FromContext(deployCtx).Infof("API Applied Successfully: %s", generatedAPIUUID)
+			} else {
+				deployErrs = append(deployErrs, fmt.Errorf("error generating the CRs of the API %s", generatedAPIUUID))
 			}
 		}
 	}
 
-	logger.LoggerSynchronizer.Infof("Total APIs processed: %d", len(apis))
-	return &apis, nil
+	logger.LoggerSynchronizer.FromContext(ctx).Infof("Total APIs processed: %d", len(apis))
+	return &apis, errors.Join(deployErrs...)
 }
@@ -0,0 +1,151 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	sync "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/synchronizer"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/utils"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/loggers"
+	kongMgtServer "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/managementserver"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FetchApplicationPoliciesOnStartUp records the application policies of the applications in the control plane, so
+// that the consumers created for the applications are throttled by their application policies. The consumers of the
+// processed applications restored from the snapshot are moved to the application policies changed in the meantime.
+func FetchApplicationPoliciesOnStartUp(c client.Client) {
+	logger.LoggerSynchronizer.Debugf("Starting application fetch for application policies")
+
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		logger.LoggerSynchronizer.Errorf("Error reading configs for application fetch, Error: %v", errReadConfig)
+	}
+
+	applications, errorMsg := sync.FetchApplications(constants.EmptyString)
+	if errorMsg != constants.EmptyString {
+		logger.LoggerSynchronizer.Errorf("Failed to fetch applications: %s", errorMsg)
+		return
+	}
+	for _, application := range applications {
+		if err := UpdateApplicationPolicy(application.UUID, application.Organization, application.Policy, c, conf); err != nil {
+			logger.LoggerSynchronizer.Errorf("Failed to update the application policy of application %s: %v", application.UUID, err)
+		}
+	}
+	logger.LoggerSynchronizer.Debugf("Recorded the application policies of %d applications", len(applications))
+}
+
+// GetApplicationPolicyPluginName returns the name of the rate limit plugin of an application policy, or an empty
+// string when the policy does not throttle the applications or the application policies are not enforced
+func GetApplicationPolicyPluginName(binding kongMgtServer.ApplicationPolicyBinding) string {
+	if binding.PolicyName == constants.EmptyString || binding.PolicyName == constants.UnlimitedPolicyName ||
+		!transformer.IsApplicationRateLimitEnabled() {
+		return constants.EmptyString
+	}
+	return transformer.GeneratePolicyCRName(binding.PolicyName, binding.Organization, constants.RateLimitingAdvancedPlugin,
+		constants.ApplicationTypeKey)
+}
+
+// UpdateApplicationPolicy records the application policy of an application and moves the consumers of a processed
+// application from the rate limit plugin of the previous policy to the one of the new policy. The previous policy is
+// recorded again when the consumers are not moved, so that the policy is applied again on a retry.
+func UpdateApplicationPolicy(applicationUUID string, organization string, policyName string, c client.Client, conf *config.Config) error {
+	binding := kongMgtServer.ApplicationPolicyBinding{PolicyName: policyName, Organization: organization}
+	previous := kongMgtServer.SetApplicationPolicy(applicationUUID, binding)
+	if previous == binding || !kongMgtServer.IsApplicationProcessed(applicationUUID) {
+		return nil
+	}
+	if err := updateApplicationPolicyAnnotation(applicationUUID, constants.EmptyString, GetApplicationPolicyPluginName(binding),
+		GetApplicationPolicyPluginName(previous), c, conf); err != nil {
+		kongMgtServer.SetApplicationPolicy(applicationUUID, previous)
+		return err
+	}
+	logger.LoggerSynchronizer.Infof("Application %s moved from application policy %s to %s",
+		applicationUUID, previous.PolicyName, policyName)
+	return nil
+}
+
+// applyApplicationPolicy annotates the consumers of the application in the environment, or in all environments when
+// the environment is empty, with the rate limit plugin of the application policy of the application
+func applyApplicationPolicy(applicationUUID string, environment string, c client.Client, conf *config.Config) error {
+	pluginName := GetApplicationPolicyPluginName(kongMgtServer.GetApplicationPolicy(applicationUUID))
+	return updateApplicationPolicyAnnotation(applicationUUID, environment, pluginName, constants.EmptyString, c, conf)
+}
+
+// ApplyApplicationPolicyToApplications annotates the consumers of the processed applications throttled by the
+// application policy with the rate limit plugin of the policy and returns the errors of annotating them
+func ApplyApplicationPolicyToApplications(policyName string, organization string, c client.Client, conf *config.Config) error {
+	var applyErrs []error
+	for _, applicationUUID := range kongMgtServer.GetApplicationsOfPolicy(policyName, organization) {
+		if kongMgtServer.IsApplicationProcessed(applicationUUID) {
+			applyErrs = append(applyErrs, applyApplicationPolicy(applicationUUID, constants.EmptyString, c, conf))
+		}
+	}
+	return errors.Join(applyErrs...)
+}
+
+// RemoveApplicationPolicyFromApplications removes the rate limit plugin of a deleted application policy from the
+// consumers of the processed applications throttled by the policy and returns the UUIDs of the updated applications
+// along with the errors of updating the others
+func RemoveApplicationPolicyFromApplications(policyName string, organization string, c client.Client, conf *config.Config) ([]string, error) {
+	pluginName := GetApplicationPolicyPluginName(kongMgtServer.ApplicationPolicyBinding{PolicyName: policyName, Organization: organization})
+	var updated []string
+	var updateErrs []error
+	for _, applicationUUID := range kongMgtServer.GetApplicationsOfPolicy(policyName, organization) {
+		if kongMgtServer.IsApplicationProcessed(applicationUUID) {
+			if err := updateApplicationPolicyAnnotation(applicationUUID, constants.EmptyString, constants.EmptyString, pluginName, c, conf); err != nil {
+				updateErrs = append(updateErrs, err)
+				continue
+			}
+			updated = append(updated, applicationUUID)
+		}
+	}
+	return updated, errors.Join(updateErrs...)
+}
+
+// updateApplicationPolicyAnnotation adds and removes the rate limit plugins of application policies on the consumers
+// of the application
+func updateApplicationPolicyAnnotation(applicationUUID string, environment string, addPlugin string, removePlugin string,
+	c client.Client, conf *config.Config) error {
+	var addAnnotations, removeAnnotations []string
+	if addPlugin != constants.EmptyString {
+		addAnnotations = append(addAnnotations, addPlugin)
+	}
+	if removePlugin != constants.EmptyString && removePlugin != addPlugin {
+		removeAnnotations = append(removeAnnotations, removePlugin)
+	}
+	if len(addAnnotations) == 0 && len(removeAnnotations) == 0 {
+		return nil
+	}
+	updateErr := utils.RetryKongCRUpdate(func() error {
+		return internalk8sClient.UpdateKongConsumerPluginAnnotation(applicationUUID, environment, c, conf, addAnnotations, removeAnnotations)
+	}, constants.UpdateConsumerPluginAnnotationTask, constants.MaxRetries)
+	if updateErr != nil {
+		logger.LoggerSynchronizer.Errorf("Failed to update the application policy of the consumers of application %s: %v",
+			applicationUUID, updateErr)
+		return fmt.Errorf("error updating the application policy of the consumers of application %s: %w",
+			applicationUUID, updateErr)
+	}
+	return nil
+}
@@ -105,7 +105,7 @@ func CreateAndDeployKeyManagerSecret(resolvedKeyManager eventhubTypes.ResolvedKe
 		constants.KeyManagerNameLabel: transformer.PrepareDashedName(resolvedKeyManager.Name),
 	}
 	keyManagerSecret := transformer.GenerateK8sSecret(resolvedKeyManager.Name, resolvedKeyManager.Organization, secretLabels, config)
-	keyManagerSecret.Namespace = conf.DataPlane.Namespace
+	keyManagerSecret.Namespace = conf.DataPlane.GetOrganizationNamespace(resolvedKeyManager.Organization)
 	k8sclient.DeploySecretCR(keyManagerSecret, c)
 	logger.LoggerSynchronizer.Infof("Successfully deployed key manager secret for: %s", resolvedKeyManager.Name)
 	return nil
@@ -24,19 +24,26 @@
 package synchronizer
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	eventhub "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	sync "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/synchronizer"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
 	internalk8sClient "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/k8sClient"
 	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/loggers"
+	kongMgtServer "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/managementserver"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/pkg/transformer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // FetchRateLimitPoliciesOnEvent fetches the policies from the control plane on the start up and notification event updates
-func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c client.Client) {
+// and returns the errors of deploying their plugins. The policies are fetched again in the background when the
+// control plane fails.
+func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c client.Client) error {
 	logger.LoggerSynchronizer.Debugf("Starting rate limit policy fetch|ratelimitName:%s organization:%s\n", ratelimitName, organization)
 
 	conf, errReadConfig := config.ReadConfigs()
@@ -44,6 +51,7 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c
 		logger.LoggerSynchronizer.Errorf("Error reading configs: %v", errReadConfig)
 	}
 
+	var deployErrs []error
 	rateLimitPolicies, errorMsg := sync.FetchRateLimitPoliciesOnEvent(ratelimitName, organization)
 	if rateLimitPolicies != nil {
 		if len(rateLimitPolicies) == 0 && errorMsg != constants.EmptyString {
@@ -55,19 +63,21 @@ func FetchRateLimitPoliciesOnEvent(ratelimitName string, organization string, c
 				case constants.AIAPIQuotaType:
 					logger.LoggerSynchronizer.Infof("AI rate limits are not yet implemented for policy: %s", policy.Name)
 				case constants.RequestCountType:
-					deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ServiceLimitBy, constants.ConsumerLimitBy, constants.PolicyTypeKey,
-						policy.DefaultLimit.RequestCount.TimeUnit, policy.DefaultLimit.RequestCount.UnitTime, policy.DefaultLimit.RequestCount.RequestCount, c)
+					deployErrs = append(deployErrs, deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ServiceLimitBy, constants.ConsumerLimitBy, constants.PolicyTypeKey,
+						policy.DefaultLimit.RequestCount.TimeUnit, policy.DefaultLimit.RequestCount.UnitTime, policy.DefaultLimit.RequestCount.RequestCount, c))
 				case constants.EventCountType:
-					deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ServiceLimitBy, constants.ConsumerLimitBy, constants.PolicyTypeKey,
-						policy.DefaultLimit.EventCount.TimeUnit, policy.DefaultLimit.EventCount.UnitTime, policy.DefaultLimit.EventCount.EventCount, c)
+					deployErrs = append(deployErrs, deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ServiceLimitBy, constants.ConsumerLimitBy, constants.PolicyTypeKey,
+						policy.DefaultLimit.EventCount.TimeUnit, policy.DefaultLimit.EventCount.UnitTime, policy.DefaultLimit.EventCount.EventCount, c))
 				}
 			}
 		}
 	}
+	return errors.Join(deployErrs...)
 }
 
 // FetchSubscriptionRateLimitPoliciesOnEvent fetches the policies from the control plane on the start up and notification event updates
-func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organization string, c client.Client, cleanupDeletedPolicies bool) {
+// and returns the errors of deploying their plugins
+func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organization string, c client.Client, cleanupDeletedPolicies bool) error {
 	logger.LoggerSynchronizer.Debugf("Starting subscription rate limit policy fetch|ratelimitName:%s organization:%s cleanupDeletedPolicies:%v\n",
 		ratelimitName, organization, cleanupDeletedPolicies)
 
@@ -76,6 +86,7 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 		logger.LoggerSynchronizer.Errorf("Error reading configs: %v", errReadConfig)
 	}
 
+	var deployErrs []error
 	rateLimitPolicies, errorMsg := sync.FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName, organization)
 	if rateLimitPolicies != nil {
 		if len(rateLimitPolicies) == 0 && errorMsg != constants.EmptyString {
@@ -86,22 +97,86 @@ func FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName string, organizatio
 				case constants.AIAPIQuotaType:
 					logger.LoggerSynchronizer.Infof("AI rate limits are not yet implemented for subscription policy: %s", policy.Name)
 				case constants.RequestCountType:
-					deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ConsumerLimitBy, constants.SubscriberTypeKey, constants.SubscriptionTypeKey,
-						policy.DefaultLimit.RequestCount.TimeUnit, policy.DefaultLimit.RequestCount.UnitTime, policy.DefaultLimit.RequestCount.RequestCount, c)
+					deployErrs = append(deployErrs, deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ConsumerLimitBy, constants.SubscriberTypeKey, constants.SubscriptionTypeKey,
+						policy.DefaultLimit.RequestCount.TimeUnit, policy.DefaultLimit.RequestCount.UnitTime, policy.DefaultLimit.RequestCount.RequestCount, c))
 				case constants.EventCountType:
-					deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ConsumerLimitBy, constants.SubscriberTypeKey, constants.SubscriptionTypeKey,
-						policy.DefaultLimit.EventCount.TimeUnit, policy.DefaultLimit.EventCount.UnitTime, policy.DefaultLimit.EventCount.EventCount, c)
+					deployErrs = append(deployErrs, deployRateLimitPlugin(conf, policy.Name, policy.TenantDomain, constants.ConsumerLimitBy, constants.SubscriberTypeKey, constants.SubscriptionTypeKey,
+						policy.DefaultLimit.EventCount.TimeUnit, policy.DefaultLimit.EventCount.UnitTime, policy.DefaultLimit.EventCount.EventCount, c))
 				}
 			}
 		}
 	}
+	return errors.Join(deployErrs...)
+}
+
+// FetchApplicationRateLimitPoliciesOnEvent fetches the application policies from the control plane on the start up
+// and notification event updates, and deploys a rate-limiting-advanced plugin limiting each consumer for each of them.
+// The errors of deploying the plugins are returned.
+func FetchApplicationRateLimitPoliciesOnEvent(ratelimitName string, organization string, c client.Client) error {
+	logger.LoggerSynchronizer.Debugf("Starting application rate limit policy fetch|ratelimitName:%s organization:%s\n", ratelimitName, organization)
+
+	if !transformer.IsApplicationRateLimitEnabled() {
+		logger.LoggerSynchronizer.Warnf("Application policies are not enforced as they require the %s plugin of Kong "+
+			"Enterprise, which is enabled with %s", constants.RateLimitingAdvancedPlugin, constants.ApplicationRateLimitEnabledConfig)
+		return nil
+	}
+	conf, errReadConfig := config.ReadConfigs()
+	if errReadConfig != nil {
+		logger.LoggerSynchronizer.Errorf("Error reading configs: %v", errReadConfig)
+	}
+
+	applicationPolicies, errorMsg := sync.FetchApplicationRateLimitPoliciesOnEvent(ratelimitName, organization)
+	if applicationPolicies == nil {
+		return nil
+	}
+	if len(applicationPolicies) == 0 && errorMsg != constants.EmptyString {
+		go retryApplicationRLPFetchData(ratelimitName, organization, conf, errorMsg, c)
+		return nil
+	}
+	return deployApplicationRateLimitPlugins(conf, applicationPolicies, c)
+}
+
+// deployApplicationRateLimitPlugins deploys the rate-limiting-advanced plugins of the application policies
+func deployApplicationRateLimitPlugins(conf *config.Config, applicationPolicies []eventhub.ApplicationPolicy, c client.Client) error {
+	var deployErrs []error
+	for _, policy := range applicationPolicies {
+		var rateLimitPlugin *v1.KongPlugin
+		switch policy.QuotaType {
+		case constants.AIAPIQuotaType:
+			logger.LoggerSynchronizer.Infof("AI rate limits are not yet implemented for application policy: %s", policy.Name)
+		case constants.RequestCountType:
+			rateLimitPlugin = transformer.GenerateApplicationRateLimitPlugin(policy.DefaultLimit.RequestCount.TimeUnit,
+				policy.DefaultLimit.RequestCount.UnitTime, policy.DefaultLimit.RequestCount.RequestCount)
+		case constants.EventCountType:
+			rateLimitPlugin = transformer.GenerateApplicationRateLimitPlugin(policy.DefaultLimit.EventCount.TimeUnit,
+				policy.DefaultLimit.EventCount.UnitTime, policy.DefaultLimit.EventCount.EventCount)
+		}
+		if rateLimitPlugin == nil {
+			continue
+		}
+		rateLimitPlugin.ObjectMeta.Name = GetApplicationPolicyPluginName(kongMgtServer.ApplicationPolicyBinding{
+			PolicyName: policy.Name, Organization: policy.TenantDomain})
+		rateLimitPlugin.Namespace = conf.DataPlane.GetOrganizationNamespace(policy.TenantDomain)
+		rateLimitPlugin.Labels = map[string]string{constants.K8sInitiatedFromField: constants.ControlPlaneOrigin}
+		setOrganizationLabel(rateLimitPlugin.Labels, policy.TenantDomain)
+		if err := internalk8sClient.DeployKongPluginCR(rateLimitPlugin, c); err != nil {
+			deployErrs = append(deployErrs, fmt.Errorf("error deploying the rate limit plugin of application policy %s: %w",
+				policy.Name, err))
+			continue
+		}
+		logger.LoggerSynchronizer.Infof("Successfully deployed the rate limit plugin of application policy: %s, tenant: %s",
+			policy.Name, policy.TenantDomain)
+	}
+	return errors.Join(deployErrs...)
 }
 
 func retryRLPFetchData(ratelimitName string, organization string, conf *config.Config, errorMessage string, c client.Client) {
 	logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
 		conf.ControlPlane.RetryInterval*time.Second)
 	time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
-	FetchRateLimitPoliciesOnEvent(ratelimitName, organization, c)
+	if err := FetchRateLimitPoliciesOnEvent(ratelimitName, organization, c); err != nil {
+		logger.LoggerSynchronizer.Errorf("Error deploying the rate limit policies: %v", err)
+	}
 	retryAttempt++
 	if retryAttempt > constants.MaxRetries {
 		logger.LoggerSynchronizer.Error(errorMessage)
@@ -113,7 +188,9 @@ func retrySubscriptionRLPFetchData(ratelimitName string, organization string, co
 	logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
 		conf.ControlPlane.RetryInterval*time.Second)
 	time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
-	FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName, organization, c, false)
+	if err := FetchSubscriptionRateLimitPoliciesOnEvent(ratelimitName, organization, c, false); err != nil {
+		logger.LoggerSynchronizer.Errorf("Error deploying the subscription rate limit policies: %v", err)
+	}
 	retryAttempt++
 	if retryAttempt > constants.MaxRetries {
 		logger.LoggerSynchronizer.Error(errorMessage)
@@ -121,12 +198,34 @@ func retrySubscriptionRLPFetchData(ratelimitName string, organization string, co
 	}
 }
 
-// deployRateLimitPlugin creates and deploys a rate limit plugin with the given configuration
-func deployRateLimitPlugin(conf *config.Config, policyName, tenantDomain, limitBy, pluginType, policyType, timeUnit string, unitTime, count int, c client.Client) {
+// retryApplicationRLPFetchData retries fetching the application policies until it succeeds or the retries run out
+func retryApplicationRLPFetchData(ratelimitName string, organization string, conf *config.Config, errorMessage string, c client.Client) {
+	for attempt := 1; attempt <= constants.MaxRetries; attempt++ {
+		logger.LoggerSynchronizer.Debugf("Time Duration for retrying: %v",
+			conf.ControlPlane.RetryInterval*time.Second)
+		time.Sleep(conf.ControlPlane.RetryInterval * time.Second)
+		applicationPolicies, errorMsg := sync.FetchApplicationRateLimitPoliciesOnEvent(ratelimitName, organization)
+		if applicationPolicies == nil {
+			return
+		}
+		if len(applicationPolicies) != 0 || errorMsg == constants.EmptyString {
+			if err := deployApplicationRateLimitPlugins(conf, applicationPolicies, c); err != nil {
+				logger.LoggerSynchronizer.Errorf("Error deploying the application rate limit policies: %v", err)
+			}
+			return
+		}
+		errorMessage = errorMsg
+	}
+	logger.LoggerSynchronizer.Error(errorMessage)
+}
+
+// deployRateLimitPlugin creates and deploys a rate limit plugin with the given configuration and returns the error of
+// deploying it
+func deployRateLimitPlugin(conf *config.Config, policyName, tenantDomain, limitBy, pluginType, policyType, timeUnit string, unitTime, count int, c client.Client) error {
 	logger.LoggerSynchronizer.Infof("Request to deploy rate limit plugin for policy: %s, tenant: %s, type: %s", policyName, tenantDomain, policyType)
 
 	if timeUnit == constants.EmptyString {
-		return
+		return nil
 	}
 
 	rateLimitConfig := transformer.KongPluginConfig{
@@ -135,9 +234,14 @@ func deployRateLimitPlugin(conf *config.Config, policyName, tenantDomain, limitB
 
 	transformer.PrepareRateLimit(&rateLimitConfig, timeUnit, unitTime, count)
 
-	ratelimitPlugin := transformer.GenerateKongPlugin(nil, constants.RateLimitingPlugin, pluginType, rateLimitConfig, true)
+	ratelimitPlugin := transformer.GenerateRateLimitPlugin(nil, pluginType, rateLimitConfig)
 	ratelimitPlugin.ObjectMeta.Name = transformer.GeneratePolicyCRName(policyName, tenantDomain, constants.RateLimitingPlugin, policyType)
-	ratelimitPlugin.Namespace = conf.DataPlane.Namespace
-	internalk8sClient.DeployKongPluginCR(ratelimitPlugin, c)
+	ratelimitPlugin.Namespace = conf.DataPlane.GetOrganizationNamespace(tenantDomain)
+	ratelimitPlugin.Labels = map[string]string{constants.K8sInitiatedFromField: constants.ControlPlaneOrigin}
+	setOrganizationLabel(ratelimitPlugin.Labels, tenantDomain)
+	if err := internalk8sClient.DeployKongPluginCR(ratelimitPlugin, c); err != nil {
+		return fmt.Errorf("error deploying the rate limit plugin of %s policy %s: %w", policyType, policyName, err)
+	}
 	logger.LoggerSynchronizer.Infof("Successfully deployed rate limit plugin for policy: %s, tenant: %s, type: %s", policyName, tenantDomain, policyType)
+	return nil
 }
@@ -24,6 +24,8 @@
 package synchronizer
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -110,15 +112,19 @@ func FetchAndProcessSubscriptionsOnStartUp(c client.Client) {
 				subscription.APIUUID, subscription.ApplicationUUID)
 
 			if !kongMgtServer.IsApplicationProcessed(subscription.ApplicationUUID) {
-				kongMgtServer.AddProcessedApplication(subscription.ApplicationUUID)
-
 				if _, exists := applicationOrganizations[subscription.ApplicationUUID]; !exists {
 					applicationOrganizations[subscription.ApplicationUUID] = subscription.ApplicationOrganization
 				}
 
 				logger.LoggerSynchronizer.Debugf("Creating application consumer for application %s",
 					subscription.ApplicationUUID)
-				CreateApplicationConsumerForBothEnvironments(subscription.ApplicationUUID, c, conf)
+				if err := CreateApplicationConsumerForBothEnvironments(subscription.ApplicationUUID,
+					subscription.ApplicationOrganization, c, conf); err != nil {
+					logger.LoggerSynchronizer.Errorf("Failed to create the application consumer for %s: %v",
+						subscription.ApplicationUUID, err)
+					continue
+				}
+				kongMgtServer.AddProcessedApplication(subscription.ApplicationUUID)
 				logger.LoggerSynchronizer.Debugf("Successfully created application consumer for %s",
 					subscription.ApplicationUUID)
 			} else {
@@ -149,7 +155,7 @@ func FetchAndProcessSubscriptionsOnStartUp(c client.Client) {
 					applicationTenantDomain = applicationKeyMapping.TenantDomain
 				}
 
-				ProcessApplicationRegistration(
+				if err := ProcessApplicationRegistration(
 					applicationKeyMapping.ApplicationUUID,
 					applicationKeyMapping.ConsumerKey,
 					applicationKeyMapping.KeyManager,
@@ -157,7 +163,10 @@ func FetchAndProcessSubscriptionsOnStartUp(c client.Client) {
 					strings.ToLower(applicationKeyMapping.KeyType),
 					c,
 					conf,
-				)
+				); err != nil {
+					logger.LoggerSynchronizer.Errorf("Failed to process the key mapping of application %s: %v",
+						applicationKeyMapping.ApplicationUUID, err)
+				}
 			}
 		}
 	}
@@ -177,21 +186,18 @@ func FetchAndProcessSubscriptionsOnStartUp(c client.Client) {
 				applicationTenantDomain = subscription.ApplicationOrganization
 			}
 
-			isProductionBlocked := false
-			isSandboxBlocked := false
-			switch subscription.SubscriptionState {
-			case constants.SubscriptionStateBlocked:
-				isProductionBlocked = true
-				isSandboxBlocked = true
-			case constants.SubscriptionStateProdOnlyBlocked:
-				isProductionBlocked = true
-			}
 			productionACLGroupNames := []string{transformer.GenerateACLGroupName(subscription.APIName, constants.EnvironmentProduction)}
-			CreateSubscription(subscription.ApplicationUUID, subscription.APIUUID, subscription.PolicyID,
-				applicationTenantDomain, productionACLGroupNames, c, conf, constants.EnvironmentProduction, isProductionBlocked)
+			productionErr := CreateSubscription(subscription.ApplicationUUID, subscription.APIUUID, subscription.PolicyID,
+				applicationTenantDomain, productionACLGroupNames, c, conf, constants.EnvironmentProduction,
+				IsSubscriptionBlocked(subscription.SubscriptionState, constants.EnvironmentProduction))
 			sandboxACLGroupNames := []string{transformer.GenerateACLGroupName(subscription.APIName, constants.EnvironmentSandbox)}
-			CreateSubscription(subscription.ApplicationUUID, subscription.APIUUID, subscription.PolicyID,
-				applicationTenantDomain, sandboxACLGroupNames, c, conf, constants.EnvironmentSandbox, isSandboxBlocked)
+			sandboxErr := CreateSubscription(subscription.ApplicationUUID, subscription.APIUUID, subscription.PolicyID,
+				applicationTenantDomain, sandboxACLGroupNames, c, conf, constants.EnvironmentSandbox,
+				IsSubscriptionBlocked(subscription.SubscriptionState, constants.EnvironmentSandbox))
+			if err := errors.Join(productionErr, sandboxErr); err != nil {
+				logger.LoggerSynchronizer.Errorf("Failed to create the subscription of application %s to API %s: %v",
+					subscription.ApplicationUUID, subscription.APIUUID, err)
+			}
 		} else {
 			logger.LoggerSynchronizer.Debugf("API %s not processed in Kong, skipping subscription %s",
 				subscription.APIUUID, subscription.ApplicationUUID)
@@ -201,22 +207,41 @@ func FetchAndProcessSubscriptionsOnStartUp(c client.Client) {
 }
 
 // CreateApplicationConsumerForBothEnvironments creates consumers for both production and sandbox environments
-func CreateApplicationConsumerForBothEnvironments(applicationUUID string, c client.Client, conf *config.Config) {
-	CreateApplicationConsumer(applicationUUID, c, conf, constants.EnvironmentProduction)
-	CreateApplicationConsumer(applicationUUID, c, conf, constants.EnvironmentSandbox)
+func CreateApplicationConsumerForBothEnvironments(applicationUUID string, organization string, c client.Client, conf *config.Config) error {
+	return errors.Join(
+		CreateApplicationConsumer(applicationUUID, organization, c, conf, constants.EnvironmentProduction),
+		CreateApplicationConsumer(applicationUUID, organization, c, conf, constants.EnvironmentSandbox),
+	)
 }
 
-func CreateApplicationConsumer(applicationUUID string, c client.Client, conf *config.Config, environment string) {
+// CreateApplicationConsumer creates the consumer of the application in the environment and returns the error of
+// deploying it
+func CreateApplicationConsumer(applicationUUID string, organization string, c client.Client, conf *config.Config, environment string) error {
 	logger.LoggerSynchronizer.Debugf("Creating application consumer for ApplicationUUID: %s, Environment: %s", applicationUUID, environment)
 
 	consumer := transformer.CreateConsumer(applicationUUID, environment, conf)
-	consumer.Namespace = conf.DataPlane.Namespace
+	consumer.Namespace = conf.DataPlane.GetOrganizationNamespace(organization)
+	setOrganizationLabel(consumer.Labels, organization)
+	// the consumer is granted the routes of its environment which are not restricted to the subscribers of the API
+	aclCredentialSecret := transformer.GenerateK8sCredentialSecret(applicationUUID, environment, constants.ACLCredentialType,
+		map[string]string{constants.GroupField: transformer.GenerateEnvironmentACLGroupName(environment)})
+	aclCredentialSecret.Labels[constants.EnvironmentLabel] = strings.ToLower(environment)
+	setOrganizationLabel(aclCredentialSecret.Labels, organization)
+	aclCredentialSecret.Namespace = consumer.Namespace
+	if err := internalk8sClient.DeploySecretCR(aclCredentialSecret, c); err != nil {
+		return err
+	}
+	consumer.Credentials = append(consumer.Credentials, aclCredentialSecret.ObjectMeta.Name)
+	// throttle the new consumer by the application policy of the application
+	if pluginName := GetApplicationPolicyPluginName(kongMgtServer.GetApplicationPolicy(applicationUUID)); pluginName != constants.EmptyString {
+		consumer.Annotations[constants.KongPluginsAnnotation] = pluginName
+	}
 
-	internalk8sClient.DeployKongConsumerCR(consumer, c)
+	return internalk8sClient.DeployKongConsumerCR(consumer, c)
 }
 
 // ProcessApplicationRegistration handles issuer secrets, credentials, and consumer updates
-func ProcessApplicationRegistration(applicationUUID, consumerKey, keyManagerName, tenantOrg, environment string, c client.Client, conf *config.Config) {
+func ProcessApplicationRegistration(applicationUUID, consumerKey, keyManagerName, tenantOrg, environment string, c client.Client, conf *config.Config) error {
 	logger.LoggerSynchronizer.Debugf("Received Application Registration Event: applicationUUID=%s, consumerKey=%s, environment=%s, keyManagerName=%s, tenantOrg=%s",
 		applicationUUID, consumerKey, environment, keyManagerName, tenantOrg)
 
@@ -230,34 +255,38 @@ func ProcessApplicationRegistration(applicationUUID, consumerKey, keyManagerName
 	)
 	if len(issuerSecrets) == 0 {
 		logger.LoggerSynchronizer.Errorf("No issuers are found")
-		return
+		return fmt.Errorf("no issuers are found for the key manager %s of organization %s", keyManagerName, tenantOrg)
 	}
 
 	addCredentials := make([]string, 0, len(issuerSecrets))
 	for _, issuerSecret := range issuerSecrets {
 		jwtCredentialSecret := transformer.CreateIssuerKongSecretCredential(
-			issuerSecret, conf,
+			issuerSecret,
 			applicationUUID,
 			consumerKey,
 			environment,
 		)
-		internalk8sClient.DeploySecretCR(jwtCredentialSecret, c)
+		setOrganizationLabel(jwtCredentialSecret.Labels, tenantOrg)
+		if err := internalk8sClient.DeploySecretCR(jwtCredentialSecret, c); err != nil {
+			return err
+		}
 		addCredentials = append(addCredentials, jwtCredentialSecret.ObjectMeta.Name)
 	}
 
-	utils.RetryKongCRUpdate(func() error {
-		internalk8sClient.UpdateKongConsumerCredential(
+	return utils.RetryKongCRUpdate(func() error {
+		return internalk8sClient.UpdateKongConsumerCredential(
 			applicationUUID,
 			strings.ToLower(environment),
 			c, conf,
 			addCredentials,
 			nil,
 		)
-		return nil
 	}, constants.AddApplicationKeyTaskName, constants.MaxRetries)
 }
 
-func CreateSubscription(applicationUUID, apiUUID, policyID, tenantDomain string, aclGroupNames []string, c client.Client, conf *config.Config, environment string, isBlocked bool) {
+// CreateSubscription grants the consumer of the application in the environment the routes of the API and throttles
+// it by the subscription policy, returning the error of updating the consumer
+func CreateSubscription(applicationUUID, apiUUID, policyID, tenantDomain string, aclGroupNames []string, c client.Client, conf *config.Config, environment string, isBlocked bool) error {
 	logger.LoggerSynchronizer.Debugf("Creating subscriptions for api:%s of application:%s in environment:%s\n", apiUUID, applicationUUID, environment)
 
 	addCredentials := []string{}
@@ -269,9 +298,12 @@ func CreateSubscription(applicationUUID, apiUUID, policyID, tenantDomain string,
 		}
 		aclCredentialSecret := transformer.GenerateK8sCredentialSecret(applicationUUID, apiUUID+environment, constants.ACLCredentialType, aclCredentialSecretConfig)
 		aclCredentialSecret.Labels[constants.EnvironmentLabel] = strings.ToLower(environment)
-		aclCredentialSecret.Namespace = conf.DataPlane.Namespace
+		setOrganizationLabel(aclCredentialSecret.Labels, tenantDomain)
+		aclCredentialSecret.Namespace = conf.DataPlane.GetOrganizationNamespace(tenantDomain)
 		addCredentials = append(addCredentials, aclCredentialSecret.ObjectMeta.Name)
-		internalk8sClient.DeploySecretCR(aclCredentialSecret, c)
+		if err := internalk8sClient.DeploySecretCR(aclCredentialSecret, c); err != nil {
+			return err
+		}
 	}
 
 	if len(addCredentials) > 0 {
@@ -280,7 +312,7 @@ func CreateSubscription(applicationUUID, apiUUID, policyID, tenantDomain string,
 		}, constants.UpdateConsumerCredentialTask, constants.MaxRetries)
 		if updateErr != nil {
 			logger.LoggerSynchronizer.Errorf("Failed to update Kong consumer credentials: %v", updateErr)
-			return
+			return fmt.Errorf("error updating the credentials of the consumer of application %s: %w", applicationUUID, updateErr)
 		}
 	}
 
@@ -298,7 +330,28 @@ func CreateSubscription(applicationUUID, apiUUID, policyID, tenantDomain string,
 		}, constants.UpdateConsumerPluginAnnotationTask, constants.MaxRetries)
 		if updateErr != nil {
 			logger.LoggerSynchronizer.Errorf("Failed to update Kong consumer plugin annotations: %v", updateErr)
-			return
+			return fmt.Errorf("error updating the plugins of the consumer of application %s: %w", applicationUUID, updateErr)
 		}
 	}
+	return nil
+}
+
+// IsSubscriptionBlocked reports whether the subscription in the given state is blocked in the environment. The
+// PROD_ONLY_BLOCKED subscriptions are blocked in the production environment only.
+func IsSubscriptionBlocked(subscriptionState string, environment string) bool {
+	switch subscriptionState {
+	case constants.SubscriptionStateBlocked:
+		return true
+	case constants.SubscriptionStateProdOnlyBlocked:
+		return strings.ToLower(environment) == constants.EnvironmentProduction
+	}
+	return false
+}
+
+// setOrganizationLabel labels an application resource with its organization so that it is removed when the
+// organization is purged
+func setOrganizationLabel(resourceLabels map[string]string, organization string) {
+	if organization != constants.EmptyString {
+		resourceLabels[constants.OrganizationLabel] = transformer.GenerateSHA1Hash(organization)
+	}
 }
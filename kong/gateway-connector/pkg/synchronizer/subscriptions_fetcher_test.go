@@ -0,0 +1,43 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package synchronizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+)
+
+func TestIsSubscriptionBlocked(t *testing.T) {
+	for _, test := range []struct {
+		state             string
+		productionBlocked bool
+		sandboxBlocked    bool
+	}{
+		{state: constants.SubscriptionStateBlocked, productionBlocked: true, sandboxBlocked: true},
+		{state: constants.SubscriptionStateProdOnlyBlocked, productionBlocked: true, sandboxBlocked: false},
+		{state: constants.SubscriptionStateUnblocked, productionBlocked: false, sandboxBlocked: false},
+		{state: "", productionBlocked: false, sandboxBlocked: false},
+	} {
+		assert.Equal(t, test.productionBlocked, IsSubscriptionBlocked(test.state, constants.EnvironmentProduction), test.state)
+		assert.Equal(t, test.sandboxBlocked, IsSubscriptionBlocked(test.state, constants.EnvironmentSandbox), test.state)
+	}
+	// The environment of the PROD_ONLY_BLOCKED subscriptions is matched regardless of its case
+	assert.True(t, IsSubscriptionBlocked(constants.SubscriptionStateProdOnlyBlocked, "PRODUCTION"))
+}
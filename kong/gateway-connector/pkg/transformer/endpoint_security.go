@@ -0,0 +1,189 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	apimTransformer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyEndpointSecurity adds the KongPlugins passing the credentials of the backends to the routes of the API
+// environments with endpoint security. The credentials are kept in a Secret and patched into the configuration of
+// the plugins by Kong, hence they are never placed in the KongPlugins. The security of the first secured endpoint of
+// an environment applies to its routes. An error is returned when the security of an environment cannot be applied,
+// as the backends would receive the requests without their credentials.
+func ApplyEndpointSecurity(k8sArtifact *K8sArtifacts, securityConfigs []apimTransformer.EndpointSecurityConfig) error {
+	for _, environment := range []string{constants.ProductionType, constants.SandboxType} {
+		for _, securityConfig := range securityConfigs {
+			security := securityConfig.Production
+			if environment == constants.SandboxType {
+				security = securityConfig.Sandbox
+			}
+			if !security.Enabled {
+				continue
+			}
+			plugin, secret, err := generateEndpointSecurityCRs(k8sArtifact.APIUUID, environment, security)
+			if err != nil {
+				return fmt.Errorf("endpoint security of the %s endpoints of API %s cannot be applied: %w",
+					environment, k8sArtifact.APIUUID, err)
+			}
+			secret.Namespace = k8sArtifact.Namespace
+			k8sArtifact.Secrets[secret.ObjectMeta.Name] = secret
+			k8sArtifact.KongPlugins[plugin.ObjectMeta.Name] = plugin
+			for _, httpRoute := range k8sArtifact.HTTPRoutes {
+				if httpRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] == environment {
+					httpRoute.ObjectMeta.Annotations = addPluginAnnotation(httpRoute.ObjectMeta.Annotations, plugin.ObjectMeta.Name)
+				}
+			}
+			for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+				if grpcRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] == environment {
+					grpcRoute.ObjectMeta.Annotations = addPluginAnnotation(grpcRoute.ObjectMeta.Annotations, plugin.ObjectMeta.Name)
+				}
+			}
+			logger.LoggerUtils.Infof("Applied %s endpoint security to the %s routes of API %s", security.Type,
+				environment, k8sArtifact.APIUUID)
+			break
+		}
+	}
+	return nil
+}
+
+// generateEndpointSecurityCRs generates the KongPlugin passing the credentials of a backend along with the Secret
+// holding the credentials. The values of the Secret are JSON encoded as required by the configuration patches.
+func generateEndpointSecurityCRs(apiUUID string, environment string, security apimTransformer.SecurityObj) (*v1.KongPlugin, *corev1.Secret, error) {
+	targetRef := environment + kongConstants.DashSeparatorString + apiUUID
+	secretName := GenerateSecretName(apiUUID, environment, kongConstants.EndpointSecuritySecretType)
+	securityType := strings.ReplaceAll(strings.ToLower(security.Type), "_", kongConstants.EmptyString)
+
+	switch securityType {
+	case kongConstants.BasicEndpointSecurityType, kongConstants.APIKeyEndpointSecurityType:
+		headerName, headerValue := kongConstants.AuthorizationHeader, kongConstants.BasicAuthPrefix+
+			base64.StdEncoding.EncodeToString([]byte(security.Username+":"+security.Password))
+		if securityType == kongConstants.APIKeyEndpointSecurityType {
+			if security.APIKeyIdentifier == kongConstants.EmptyString {
+				return nil, nil, fmt.Errorf("the API key header name is not defined")
+			}
+			headerName, headerValue = security.APIKeyIdentifier, security.APIKeyValue
+		}
+		headers, err := json.Marshal([]string{headerName + ":" + headerValue})
+		if err != nil {
+			return nil, nil, err
+		}
+		// The header sent by the client is removed, as the transformer adds the header only when it is absent
+		requestTransformerConfig := KongPluginConfig{
+			kongConstants.RequestTransformerRemoveField: map[string]interface{}{
+				kongConstants.RequestTransformerHeadersField: []string{headerName},
+			},
+			kongConstants.RequestTransformerAddField: map[string]interface{}{},
+		}
+		plugin := GenerateKongPlugin(nil, kongConstants.RequestTransformerPlugin, targetRef, requestTransformerConfig, true)
+		plugin.ConfigPatches = []v1.ConfigPatch{
+			secretConfigPatch("/"+kongConstants.RequestTransformerAddField+"/"+kongConstants.RequestTransformerHeadersField,
+				secretName, kongConstants.RequestTransformerHeadersField),
+		}
+		return plugin, generateEndpointSecuritySecret(secretName,
+			map[string]string{kongConstants.RequestTransformerHeadersField: string(headers)}), nil
+
+	case kongConstants.OAuthEndpointSecurityType, kongConstants.OAuthEndpointSecurityType + "2":
+		if !isUpstreamOAuthEnabled() {
+			return nil, nil, fmt.Errorf("OAuth2 endpoint security requires the %s plugin, which is enabled with %s",
+				kongConstants.UpstreamOAuthPlugin, kongConstants.UpstreamOAuthEnabledConfig)
+		}
+		grantType := strings.ToLower(security.GrantType)
+		if grantType == kongConstants.EmptyString {
+			grantType = kongConstants.ClientCredentialsGrantType
+		}
+		credentials := map[string]string{
+			kongConstants.UpstreamOAuthClientID:     security.ClientID,
+			kongConstants.UpstreamOAuthClientSecret: security.ClientSecret,
+		}
+		switch grantType {
+		case kongConstants.ClientCredentialsGrantType:
+		case kongConstants.PasswordGrantType:
+			credentials[kongConstants.UpstreamOAuthUsername] = security.Username
+			credentials[kongConstants.UpstreamOAuthPassword] = security.Password
+		default:
+			return nil, nil, fmt.Errorf("unsupported OAuth2 grant type %q", security.GrantType)
+		}
+		upstreamOAuthConfig := KongPluginConfig{
+			kongConstants.UpstreamOAuthField: map[string]interface{}{
+				kongConstants.UpstreamOAuthTokenEndpoint: security.TokenURL,
+				kongConstants.UpstreamOAuthGrantType:     grantType,
+			},
+		}
+		plugin := GenerateKongPlugin(nil, kongConstants.UpstreamOAuthPlugin, targetRef, upstreamOAuthConfig, true)
+		secretData := make(map[string]string, len(credentials))
+		for _, field := range []string{kongConstants.UpstreamOAuthClientID, kongConstants.UpstreamOAuthClientSecret,
+			kongConstants.UpstreamOAuthUsername, kongConstants.UpstreamOAuthPassword} {
+			value, exists := credentials[field]
+			if !exists {
+				continue
+			}
+			encodedValue, err := json.Marshal(value)
+			if err != nil {
+				return nil, nil, err
+			}
+			secretData[field] = string(encodedValue)
+			plugin.ConfigPatches = append(plugin.ConfigPatches,
+				secretConfigPatch("/"+kongConstants.UpstreamOAuthField+"/"+field, secretName, field))
+		}
+		return plugin, generateEndpointSecuritySecret(secretName, secretData), nil
+	}
+	return nil, nil, fmt.Errorf("unsupported endpoint security type %q", security.Type)
+}
+
+// generateEndpointSecuritySecret generates the Secret holding the credentials of a backend
+func generateEndpointSecuritySecret(name string, data map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       kongConstants.SecretKind,
+			APIVersion: kongConstants.CoreAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		StringData: data,
+	}
+}
+
+// secretConfigPatch returns the patch of the plugin configuration at the path with the value of the Secret key
+func secretConfigPatch(path string, secretName string, key string) v1.ConfigPatch {
+	return v1.ConfigPatch{
+		Path: path,
+		ValueFrom: v1.ConfigSource{SecretValue: v1.SecretValueFromSource{
+			Secret: secretName,
+			Key:    key,
+		}},
+	}
+}
+
+// isUpstreamOAuthEnabled reports whether the upstream-oauth plugin is available to the APIs with OAuth2 endpoint
+// security
+func isUpstreamOAuthEnabled() bool {
+	return isGatewayAgentConfigEnabled(kongConstants.UpstreamOAuthEnabledConfig)
+}
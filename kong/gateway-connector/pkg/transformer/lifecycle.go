@@ -0,0 +1,183 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
+)
+
+// APILifeCycle is the lifecycle state of an API enforced on its routes
+type APILifeCycle struct {
+	State string
+	// DeprecatedAt is the time the API was deprecated in seconds since the epoch
+	DeprecatedAt int64
+}
+
+// NewAPILifeCycle returns the lifecycle of an API in the given state, which was changed at the given time in
+// milliseconds since the epoch
+func NewAPILifeCycle(state string, timeStamp int64) APILifeCycle {
+	lifeCycle := APILifeCycle{State: strings.ToUpper(state)}
+	if lifeCycle.State == kongConstants.DeprecatedLifeCycleState {
+		lifeCycle.DeprecatedAt = time.UnixMilli(timeStamp).Unix()
+		if timeStamp == 0 {
+			lifeCycle.DeprecatedAt = time.Now().Unix()
+		}
+	}
+	return lifeCycle
+}
+
+// LifeCycleFromAnnotations returns the lifecycle of an API recorded in the annotations of its lifecycle plugin
+func LifeCycleFromAnnotations(annotations map[string]string) APILifeCycle {
+	lifeCycle := APILifeCycle{State: annotations[kongConstants.LifeCycleStateAnnotation]}
+	if deprecatedAt, err := strconv.ParseInt(annotations[kongConstants.DeprecatedAtAnnotation], 10, 64); err == nil {
+		lifeCycle.DeprecatedAt = deprecatedAt
+	}
+	return lifeCycle
+}
+
+// Annotations returns the annotations of the lifecycle plugin recording the lifecycle
+func (l APILifeCycle) Annotations() map[string]string {
+	annotations := map[string]string{kongConstants.LifeCycleStateAnnotation: l.State}
+	if l.State == kongConstants.DeprecatedLifeCycleState {
+		annotations[kongConstants.DeprecatedAtAnnotation] = strconv.FormatInt(l.DeprecatedAt, 10)
+	}
+	return annotations
+}
+
+// LifeCyclePluginNames returns the names of the KongPlugins enforcing the lifecycle states of an API
+func LifeCyclePluginNames(apiUUID string) []string {
+	return []string{
+		GeneratePluginCRName(nil, apiUUID, kongConstants.RequestTerminationPlugin),
+		GeneratePluginCRName(nil, apiUUID, kongConstants.ResponseTransformerPlugin),
+	}
+}
+
+// GenerateLifeCyclePlugin generates the KongPlugin enforcing the lifecycle state of an API. A blocked API is
+// terminated with the configured response and the responses of a deprecated API carry the deprecation headers. No
+// plugin is generated for the other states. The lifecycle is recorded in the annotations of the plugin.
+func GenerateLifeCyclePlugin(apiUUID string, lifeCycle APILifeCycle) *v1.KongPlugin {
+	logger.LoggerUtils.Debugf("Generating lifecycle plugin|API:%s State:%s\n", apiUUID, lifeCycle.State)
+
+	switch lifeCycle.State {
+	case kongConstants.BlockedLifeCycleState:
+		statusCode, message := getBlockedAPIResponse()
+		requestTerminationConfig := KongPluginConfig{
+			kongConstants.RequestTerminationStatusCodeField: statusCode,
+			kongConstants.RequestTerminationMessageField:    message,
+		}
+		plugin := GenerateKongPlugin(nil, kongConstants.RequestTerminationPlugin, apiUUID, requestTerminationConfig, true)
+		plugin.Annotations = lifeCycle.Annotations()
+		return plugin
+	case kongConstants.DeprecatedLifeCycleState:
+		deprecatedAt := time.Unix(lifeCycle.DeprecatedAt, 0)
+		headers := []string{fmt.Sprintf("%s:@%d", kongConstants.DeprecationHeader, deprecatedAt.Unix())}
+		if sunsetPeriod := getDeprecatedAPISunsetPeriod(); sunsetPeriod > 0 {
+			headers = append(headers, kongConstants.SunsetHeader+":"+
+				deprecatedAt.Add(sunsetPeriod).UTC().Format(http.TimeFormat))
+		}
+		responseTransformerConfig := KongPluginConfig{
+			kongConstants.ResponseTransformerAddField: map[string]interface{}{
+				kongConstants.ResponseTransformerHeadersField: headers,
+			},
+		}
+		plugin := GenerateKongPlugin(nil, kongConstants.ResponseTransformerPlugin, apiUUID, responseTransformerConfig, true)
+		plugin.Annotations = lifeCycle.Annotations()
+		return plugin
+	}
+	return nil
+}
+
+// ApplyLifeCycleState adds the KongPlugin enforcing the lifecycle state of the API to the artifacts and attaches it
+// to all the routes of the API
+func ApplyLifeCycleState(k8sArtifact *K8sArtifacts, lifeCycle APILifeCycle) {
+	lifeCyclePlugin := GenerateLifeCyclePlugin(k8sArtifact.APIUUID, lifeCycle)
+	if lifeCyclePlugin == nil {
+		return
+	}
+	k8sArtifact.KongPlugins[lifeCyclePlugin.ObjectMeta.Name] = lifeCyclePlugin
+	for _, httpRoute := range k8sArtifact.HTTPRoutes {
+		httpRoute.ObjectMeta.Annotations = addPluginAnnotation(httpRoute.ObjectMeta.Annotations, lifeCyclePlugin.ObjectMeta.Name)
+	}
+	for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+		grpcRoute.ObjectMeta.Annotations = addPluginAnnotation(grpcRoute.ObjectMeta.Annotations, lifeCyclePlugin.ObjectMeta.Name)
+	}
+	logger.LoggerUtils.Infof("Applied the lifecycle state %s to the routes of the API %s", lifeCycle.State,
+		k8sArtifact.APIUUID)
+}
+
+// addPluginAnnotation adds the plugin to the plugins annotation of a route
+func addPluginAnnotation(annotations map[string]string, pluginName string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if plugins := annotations[kongConstants.KongPluginsAnnotation]; plugins != kongConstants.EmptyString {
+		annotations[kongConstants.KongPluginsAnnotation] = plugins + kongConstants.CommaString + pluginName
+	} else {
+		annotations[kongConstants.KongPluginsAnnotation] = pluginName
+	}
+	return annotations
+}
+
+// getBlockedAPIResponse returns the configured status code and message of the responses to the blocked APIs
+func getBlockedAPIResponse() (int, string) {
+	statusCode, message := kongConstants.DefaultBlockedAPIStatusCode, kongConstants.DefaultBlockedAPIMessage
+	conf, err := config.ReadConfigs()
+	if err != nil || conf == nil {
+		return statusCode, message
+	}
+	if value := conf.GatewayAgent.Get(kongConstants.BlockedAPIStatusCodeConfig); value != nil && fmt.Sprint(value) != "" {
+		if configured, err := strconv.Atoi(fmt.Sprint(value)); err == nil && configured >= 100 && configured <= 599 {
+			statusCode = configured
+		} else {
+			logger.LoggerUtils.Warnf("Invalid %s %q, the blocked APIs respond with %d",
+				kongConstants.BlockedAPIStatusCodeConfig, value, statusCode)
+		}
+	}
+	if value := conf.GatewayAgent.Get(kongConstants.BlockedAPIMessageConfig); value != nil && fmt.Sprint(value) != "" {
+		message = fmt.Sprint(value)
+	}
+	return statusCode, message
+}
+
+// getDeprecatedAPISunsetPeriod returns the configured period after which a deprecated API is retired
+func getDeprecatedAPISunsetPeriod() time.Duration {
+	conf, err := config.ReadConfigs()
+	if err != nil || conf == nil {
+		return 0
+	}
+	value := conf.GatewayAgent.Get(kongConstants.DeprecatedAPISunsetPeriodConfig)
+	if value == nil || fmt.Sprint(value) == "" {
+		return 0
+	}
+	period, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil {
+		logger.LoggerUtils.Warnf("Invalid %s %q, the Sunset header is not added to the deprecated APIs: %v",
+			kongConstants.DeprecatedAPISunsetPeriodConfig, value, err)
+		return 0
+	}
+	return period
+}
@@ -0,0 +1,177 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+	apimTransformer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
+	corev1 "k8s.io/api/core/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ApplyEndpointLoadBalancing balances the requests of the routes of each environment across its load balanced
+// endpoints. A Service is added for each endpoint, which the routes refer to with weighted backends. The Services of
+// an environment share the KongUpstreamPolicy of the Service of its endpoint, as Kong balances the requests of a route
+// across a single upstream. The upstream of a route has a single protocol, hence the endpoints of another protocol are
+// not balanced. Kong has no standby targets, as the targets of an upstream which are weighted out are never selected
+// even when the others are unhealthy, hence an API with failover endpoints is rejected.
+func ApplyEndpointLoadBalancing(k8sArtifact *K8sArtifacts, api *apimTransformer.API, organizationID string) error {
+	if api == nil || api.EndpointConfigurations == nil {
+		return nil
+	}
+	environmentEndpointConfigs := map[string]*[]apimTransformer.EndpointConfiguration{
+		constants.ProductionType: api.EndpointConfigurations.Production,
+		constants.SandboxType:    api.EndpointConfigurations.Sandbox,
+	}
+	for environment, endpointConfigs := range environmentEndpointConfigs {
+		if endpointConfigs != nil && len(*endpointConfigs) > 0 && len((*endpointConfigs)[0].FailoverEndpoints) > 0 {
+			return fmt.Errorf("the %s failover endpoints of API %s are not supported as Kong cannot keep them out "+
+				"of rotation while the primary endpoint is healthy", environment, k8sArtifact.APIUUID)
+		}
+	}
+	for environment, endpointConfigs := range environmentEndpointConfigs {
+		if endpointConfigs == nil || len(*endpointConfigs) == 0 {
+			continue
+		}
+		endpointConfig := (*endpointConfigs)[0]
+		if len(endpointConfig.LoadBalanceEndpoints) == 0 {
+			continue
+		}
+		serviceName := utils.GenerateServiceName(api.Name, api.Version, organizationID, environment)
+		service, exists := k8sArtifact.Services[serviceName]
+		if !exists {
+			logger.LoggerUtils.Warnf("The %s endpoints of API %s are not balanced as their Service is not found",
+				environment, k8sArtifact.APIUUID)
+			continue
+		}
+
+		services := []*corev1.Service{service}
+		for i, endpointURL := range endpointConfig.LoadBalanceEndpoints {
+			endpointService := generateEndpointService(service, serviceName+kongConstants.DashSeparatorString+strconv.Itoa(i+1), endpointURL)
+			if protocol := endpointService.ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation]; protocol != service.ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation] {
+				logger.LoggerUtils.Warnf("The %s endpoint %s of API %s is not balanced as its protocol %s differs from "+
+					"the protocol of the primary endpoint", environment, endpointURL, k8sArtifact.APIUUID, protocol)
+				continue
+			}
+			k8sArtifact.Services[endpointService.ObjectMeta.Name] = endpointService
+			services = append(services, endpointService)
+		}
+		if len(services) == 1 {
+			continue
+		}
+		for _, httpRoute := range k8sArtifact.HTTPRoutes {
+			if httpRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] != environment {
+				continue
+			}
+			for i := range httpRoute.Spec.Rules {
+				httpRoute.Spec.Rules[i].BackendRefs = balanceBackendRefs(httpRoute.Spec.Rules[i].BackendRefs, services,
+					func(backendRef *gwapiv1.HTTPBackendRef) *gwapiv1.BackendRef { return &backendRef.BackendRef })
+			}
+		}
+		for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+			if grpcRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] != environment {
+				continue
+			}
+			for i := range grpcRoute.Spec.Rules {
+				grpcRoute.Spec.Rules[i].BackendRefs = balanceBackendRefs(grpcRoute.Spec.Rules[i].BackendRefs, services,
+					func(backendRef *gwapiv1.GRPCBackendRef) *gwapiv1.BackendRef { return &backendRef.BackendRef })
+			}
+		}
+		logger.LoggerUtils.Infof("Balanced the %s routes of API %s across %d endpoints", environment,
+			k8sArtifact.APIUUID, len(services))
+	}
+	return nil
+}
+
+// generateEndpointService generates the Service of an endpoint from the Service of the endpoint it is balanced with,
+// retaining its annotations
+func generateEndpointService(service *corev1.Service, name string, endpointURL string) *corev1.Service {
+	endpointService := service.DeepCopy()
+	endpointService.ObjectMeta.Name = name
+	endpointService.Spec.ExternalName = utils.GetHost(types.EndpointURL(endpointURL))
+	endpointService.Spec.Ports = []corev1.ServicePort{
+		{
+			Port:     int32(utils.GetPort(endpointURL)),
+			Protocol: corev1.ProtocolTCP,
+		},
+	}
+	if endpointService.ObjectMeta.Annotations == nil {
+		endpointService.ObjectMeta.Annotations = make(map[string]string)
+	}
+	switch service.ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation] {
+	case kongConstants.GRPCProtocol, kongConstants.GRPCSProtocol:
+		endpointService.ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation] = GetGRPCProtocol(endpointURL)
+	case kongConstants.WSProtocol, kongConstants.WSSProtocol:
+		endpointService.ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation] = GetWSProtocol(endpointURL)
+	default:
+		endpointService.ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation] = utils.GetProtocol(endpointURL)
+	}
+	return endpointService
+}
+
+// deepCopyable is the pointer type of a backend of a route, which is deep copied to refer to another Service
+type deepCopyable[T any] interface {
+	*T
+	DeepCopy() *T
+}
+
+// balanceBackendRefs replaces the backend referring to the Service of the endpoint with weighted backends
+// referring to the Services of all the balanced endpoints. The backend reference of an HTTP or gRPC backend is
+// read with backendRefOf.
+func balanceBackendRefs[T any, P deepCopyable[T]](backendRefs []T, services []*corev1.Service,
+	backendRefOf func(*T) *gwapiv1.BackendRef) []T {
+	balancedBackendRefs := make([]T, 0, len(backendRefs)+len(services))
+	balanced := false
+	for i := range backendRefs {
+		backendRef := backendRefOf(&backendRefs[i])
+		if string(backendRef.Name) != services[0].ObjectMeta.Name {
+			balancedBackendRefs = append(balancedBackendRefs, backendRefs[i])
+			continue
+		}
+		if balanced {
+			continue
+		}
+		for _, service := range services {
+			weight := int32(kongConstants.LoadBalanceEndpointWeight)
+			balancedBackendRef := *P(&backendRefs[i]).DeepCopy()
+			balancedReference := backendRefOf(&balancedBackendRef)
+			balancedReference.BackendObjectReference = weightedBackendObjectReference(backendRef.BackendObjectReference, service)
+			balancedReference.Weight = &weight
+			balancedBackendRefs = append(balancedBackendRefs, balancedBackendRef)
+		}
+		balanced = true
+	}
+	return balancedBackendRefs
+}
+
+// weightedBackendObjectReference returns the reference of a backend to the given Service
+func weightedBackendObjectReference(reference gwapiv1.BackendObjectReference, service *corev1.Service) gwapiv1.BackendObjectReference {
+	reference.Name = gwapiv1.ObjectName(service.ObjectMeta.Name)
+	if len(service.Spec.Ports) > 0 {
+		port := gwapiv1.PortNumber(service.Spec.Ports[0].Port)
+		reference.Port = &port
+	}
+	return reference
+}
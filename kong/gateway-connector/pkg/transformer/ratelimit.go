@@ -0,0 +1,255 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
+)
+
+// RateLimitPolicy defines where the rate-limiting plugins keep their counters. The local policy counts the
+// requests in each Kong pod, hence the limits scale with the replica count, while the cluster and redis policies
+// share the counters among all the pods.
+type RateLimitPolicy struct {
+	Policy string
+	// Redis is the connection configuration of the redis policy, which carries no credentials
+	Redis KongPluginConfig
+	// RedisSecret is the Secret holding the connection configuration of the redis policy as a JSON object, which
+	// takes precedence over the Redis configuration
+	RedisSecret    string
+	RedisSecretKey string
+}
+
+// GetRateLimitPolicy returns the rate limit policy configured in the gateway agent configurations
+func GetRateLimitPolicy() (RateLimitPolicy, error) {
+	rateLimitPolicy := RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}
+	conf, err := config.ReadConfigs()
+	if err != nil || conf == nil {
+		return rateLimitPolicy, err
+	}
+	getConfig := func(key string) string {
+		if value := conf.GatewayAgent.Get(key); value != nil {
+			return strings.TrimSpace(fmt.Sprint(value))
+		}
+		return kongConstants.EmptyString
+	}
+
+	if policy := strings.ToLower(getConfig(kongConstants.RateLimitPolicyConfig)); policy != kongConstants.EmptyString {
+		rateLimitPolicy.Policy = policy
+	}
+	switch rateLimitPolicy.Policy {
+	case kongConstants.LocalRateLimitPolicy, kongConstants.ClusterRateLimitPolicy:
+		return rateLimitPolicy, nil
+	case kongConstants.RedisRateLimitPolicy:
+	default:
+		return RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}, fmt.Errorf("unsupported %s %q",
+			kongConstants.RateLimitPolicyConfig, rateLimitPolicy.Policy)
+	}
+
+	if secret := getConfig(kongConstants.RateLimitRedisSecretConfig); secret != kongConstants.EmptyString {
+		rateLimitPolicy.RedisSecret = secret
+		rateLimitPolicy.RedisSecretKey = getConfig(kongConstants.RateLimitRedisSecretKeyConfig)
+		if rateLimitPolicy.RedisSecretKey == kongConstants.EmptyString {
+			rateLimitPolicy.RedisSecretKey = kongConstants.DefaultRateLimitRedisSecretKey
+		}
+		return rateLimitPolicy, nil
+	}
+
+	// The credentials are not written to the plugin configurations, which are readable by all the users of the
+	// KongPlugins, hence they are only read from the Secret
+	for _, key := range []string{kongConstants.RateLimitRedisUsernameConfig, kongConstants.RateLimitRedisPasswordConfig} {
+		if getConfig(key) != kongConstants.EmptyString {
+			return RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}, fmt.Errorf("%s is not supported, the "+
+				"redis credentials are read from the Secret named by %s", key, kongConstants.RateLimitRedisSecretConfig)
+		}
+	}
+	host := getConfig(kongConstants.RateLimitRedisHostConfig)
+	if host == kongConstants.EmptyString {
+		return RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}, fmt.Errorf("%s or %s is required by the %s "+
+			"rate limit policy", kongConstants.RateLimitRedisHostConfig, kongConstants.RateLimitRedisSecretConfig,
+			kongConstants.RedisRateLimitPolicy)
+	}
+	rateLimitPolicy.Redis = KongPluginConfig{
+		kongConstants.RedisHostField: host,
+		kongConstants.RedisPortField: kongConstants.DefaultRateLimitRedisPort,
+	}
+	for key, field := range map[string]string{
+		kongConstants.RateLimitRedisPortConfig:     kongConstants.RedisPortField,
+		kongConstants.RateLimitRedisDatabaseConfig: kongConstants.RedisDatabaseField,
+		kongConstants.RateLimitRedisTimeoutConfig:  kongConstants.RedisTimeoutField,
+	} {
+		if value := getConfig(key); value != kongConstants.EmptyString {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}, fmt.Errorf("invalid %s %q: %w",
+					key, value, err)
+			}
+			rateLimitPolicy.Redis[field] = number
+		}
+	}
+	if port := rateLimitPolicy.Redis[kongConstants.RedisPortField].(int); port < 1 || port > 65535 {
+		return RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}, fmt.Errorf("invalid %s %d: the port "+
+			"is out of range", kongConstants.RateLimitRedisPortConfig, port)
+	}
+	for key, field := range map[string]string{
+		kongConstants.RateLimitRedisSSLConfig:       kongConstants.RedisSSLField,
+		kongConstants.RateLimitRedisSSLVerifyConfig: kongConstants.RedisSSLVerifyField,
+	} {
+		if value := getConfig(key); value != kongConstants.EmptyString {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return RateLimitPolicy{Policy: kongConstants.LocalRateLimitPolicy}, fmt.Errorf("invalid %s %q: %w",
+					key, value, err)
+			}
+			rateLimitPolicy.Redis[field] = enabled
+		}
+	}
+	return rateLimitPolicy, nil
+}
+
+// ValidateRateLimitPolicies validates that all the rate-limiting plugins of an API keep their counters with the
+// same policy, as the limits of an API which are counted differently are enforced inconsistently
+func ValidateRateLimitPolicies(kongPlugins map[string]*v1.KongPlugin) error {
+	policies := make(map[string][]string)
+	for name, plugin := range kongPlugins {
+		if plugin.PluginName != kongConstants.RateLimitingPlugin {
+			continue
+		}
+		policy := rateLimitPluginPolicy(plugin)
+		policies[policy] = append(policies[policy], name)
+	}
+	if len(policies) <= 1 {
+		return nil
+	}
+	descriptions := make([]string, 0, len(policies))
+	for policy, names := range policies {
+		sort.Strings(names)
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", policy, strings.Join(names, kongConstants.CommaString)))
+	}
+	sort.Strings(descriptions)
+	return fmt.Errorf("rate-limiting plugins use different policies (%s)", strings.Join(descriptions, "; "))
+}
+
+// rateLimitPluginPolicy returns the policy of a rate-limiting plugin along with the Secret its redis connection is
+// read from
+func rateLimitPluginPolicy(plugin *v1.KongPlugin) string {
+	pluginConfig := KongPluginConfig{}
+	if len(plugin.Config.Raw) > 0 {
+		if err := json.Unmarshal(plugin.Config.Raw, &pluginConfig); err != nil {
+			logger.LoggerUtils.Warnf("Unable to read the configuration of the KongPlugin %s: %v", plugin.Name, err)
+		}
+	}
+	policy := kongConstants.LocalRateLimitPolicy
+	if configured, ok := pluginConfig[kongConstants.PluginPolicyField].(string); ok && configured != kongConstants.EmptyString {
+		policy = configured
+	}
+	for _, patch := range plugin.ConfigPatches {
+		if patch.Path == kongConstants.RedisConfigPatchPath {
+			policy += "(" + patch.ValueFrom.SecretValue.Secret + "/" + patch.ValueFrom.SecretValue.Key + ")"
+		}
+	}
+	return policy
+}
+
+// GenerateRateLimitPlugin generates a rate-limiting KongPlugin with the given limits which keeps its counters as
+// defined by the configured rate limit policy. The local policy is used when the configured policy is invalid.
+func GenerateRateLimitPlugin(operation *types.Operation, targetRef string, rateLimitConfig KongPluginConfig) *v1.KongPlugin {
+	rateLimitPolicy, err := GetRateLimitPolicy()
+	if err != nil {
+		logger.LoggerUtils.Errorf("Invalid rate limit policy configuration, using the %s policy: %v",
+			rateLimitPolicy.Policy, err)
+	}
+	rateLimitConfig[kongConstants.PluginPolicyField] = rateLimitPolicy.Policy
+	if rateLimitPolicy.Redis != nil && rateLimitPolicy.RedisSecret == kongConstants.EmptyString {
+		rateLimitConfig[kongConstants.PluginRedisField] = rateLimitPolicy.Redis
+	}
+
+	rateLimitPlugin := GenerateKongPlugin(operation, kongConstants.RateLimitingPlugin, targetRef, rateLimitConfig, true)
+	if rateLimitPolicy.RedisSecret != kongConstants.EmptyString {
+		// The Secret is looked up in the namespace of the plugin
+		rateLimitPlugin.ConfigPatches = []v1.ConfigPatch{{
+			Path: kongConstants.RedisConfigPatchPath,
+			ValueFrom: v1.ConfigSource{SecretValue: v1.SecretValueFromSource{
+				Secret: rateLimitPolicy.RedisSecret,
+				Key:    rateLimitPolicy.RedisSecretKey,
+			}},
+		}}
+	}
+	return rateLimitPlugin
+}
+
+// GenerateApplicationRateLimitPlugin generates a rate-limiting-advanced KongPlugin limiting each consumer to the given
+// number of requests per the given time, which keeps its counters as defined by the configured rate limit policy. It
+// returns nil when the time unit is not supported.
+func GenerateApplicationRateLimitPlugin(timeUnit string, unitTime int, requestCount int) *v1.KongPlugin {
+	windowSize, exists := kongConstants.RateLimitWindowSizes[kongConstants.TransformerTimeUnits[strings.ToLower(timeUnit)]]
+	if !exists {
+		logger.LoggerUtils.Errorf("Time unit value not found: %v", timeUnit)
+		return nil
+	}
+	if unitTime > 0 {
+		windowSize *= unitTime
+	}
+	rateLimitPolicy, err := GetRateLimitPolicy()
+	if err != nil {
+		logger.LoggerUtils.Errorf("Invalid rate limit policy configuration, using the %s policy: %v",
+			rateLimitPolicy.Policy, err)
+	}
+	rateLimitConfig := KongPluginConfig{
+		kongConstants.RateLimitAdvancedLimitField:      []int{requestCount},
+		kongConstants.RateLimitAdvancedWindowSizeField: []int{windowSize},
+		kongConstants.RateLimitAdvancedIdentifierField: kongConstants.ConsumerLimitBy,
+		kongConstants.RateLimitAdvancedStrategyField:   rateLimitPolicy.Policy,
+		kongConstants.RateLimitAdvancedSyncRateField:   0,
+	}
+	if rateLimitPolicy.Policy == kongConstants.LocalRateLimitPolicy {
+		rateLimitConfig[kongConstants.RateLimitAdvancedSyncRateField] = kongConstants.LocalRateLimitSyncRate
+	}
+	if rateLimitPolicy.Redis != nil && rateLimitPolicy.RedisSecret == kongConstants.EmptyString {
+		rateLimitConfig[kongConstants.PluginRedisField] = rateLimitPolicy.Redis
+	}
+
+	rateLimitPlugin := GenerateKongPlugin(nil, kongConstants.RateLimitingAdvancedPlugin, kongConstants.ApplicationTypeKey,
+		rateLimitConfig, true)
+	if rateLimitPolicy.RedisSecret != kongConstants.EmptyString {
+		rateLimitPlugin.ConfigPatches = []v1.ConfigPatch{{
+			Path: kongConstants.RedisConfigPatchPath,
+			ValueFrom: v1.ConfigSource{SecretValue: v1.SecretValueFromSource{
+				Secret: rateLimitPolicy.RedisSecret,
+				Key:    rateLimitPolicy.RedisSecretKey,
+			}},
+		}}
+	}
+	return rateLimitPlugin
+}
+
+// IsApplicationRateLimitEnabled reports whether the application policies are enforced with the
+// rate-limiting-advanced plugin of Kong Enterprise. They are not enforced otherwise, as the rate-limiting plugin of
+// Kong OSS enforces the subscription policies of the consumers.
+func IsApplicationRateLimitEnabled() bool {
+	return isGatewayAgentConfigEnabled(kongConstants.ApplicationRateLimitEnabledConfig)
+}
@@ -0,0 +1,149 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"strconv"
+
+	v1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	apimTransformer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	logger "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/internal/loggers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyEndpointResiliency applies the resiliency configuration of the endpoints of the API to the Services of the
+// endpoints. The timeouts are set with the Service annotations, while the endpoints are suspended by
+// the health checks of a KongUpstreamPolicy attached to the Service. The endpoints of an environment share a Service,
+// hence the configuration of the first endpoint of an environment applies.
+func ApplyEndpointResiliency(k8sArtifact *K8sArtifacts, api *apimTransformer.API, organizationID string) {
+	if api == nil || api.EndpointConfigurations == nil {
+		return
+	}
+	for environment, endpointConfigs := range map[string]*[]apimTransformer.EndpointConfiguration{
+		constants.ProductionType: api.EndpointConfigurations.Production,
+		constants.SandboxType:    api.EndpointConfigurations.Sandbox,
+	} {
+		if endpointConfigs == nil || len(*endpointConfigs) == 0 || (*endpointConfigs)[0].Resiliency == nil {
+			continue
+		}
+		serviceName := utils.GenerateServiceName(api.Name, api.Version, organizationID, environment)
+		service, exists := k8sArtifact.Services[serviceName]
+		if !exists {
+			continue
+		}
+		applyServiceResiliency(k8sArtifact, service, *(*endpointConfigs)[0].Resiliency)
+		logger.LoggerUtils.Infof("Applied the resiliency configuration to the %s endpoints of API %s", environment,
+			k8sArtifact.APIUUID)
+	}
+}
+
+// applyServiceResiliency applies the resiliency configuration of the endpoints to their Service. The timeouts of the
+// streaming APIs set on the Service are retained.
+func applyServiceResiliency(k8sArtifact *K8sArtifacts, service *corev1.Service, resiliency apimTransformer.Resiliency) {
+	if service.ObjectMeta.Annotations == nil {
+		service.ObjectMeta.Annotations = make(map[string]string)
+	}
+	if resiliency.Timeout != nil && resiliency.Timeout.UpstreamResponseTimeoutMillis > 0 {
+		timeout := strconv.Itoa(resiliency.Timeout.UpstreamResponseTimeoutMillis)
+		for _, annotation := range []string{kongConstants.KongConnectTimeoutAnnotation,
+			kongConstants.KongReadTimeoutAnnotation, kongConstants.KongWriteTimeoutAnnotation} {
+			if _, exists := service.ObjectMeta.Annotations[annotation]; !exists {
+				service.ObjectMeta.Annotations[annotation] = timeout
+			}
+		}
+	}
+	if resiliency.CircuitBreaker != nil {
+		upstreamPolicy := GenerateUpstreamPolicy(service.ObjectMeta.Name, *resiliency.CircuitBreaker)
+		upstreamPolicy.Namespace = k8sArtifact.Namespace
+		k8sArtifact.UpstreamPolicies[upstreamPolicy.ObjectMeta.Name] = upstreamPolicy
+		service.ObjectMeta.Annotations[kongConstants.KongUpstreamPolicyAnnotation] = upstreamPolicy.ObjectMeta.Name
+	}
+}
+
+// GenerateUpstreamPolicy generates the KongUpstreamPolicy suspending the endpoints of a Service. The passive health
+// checks mark an endpoint unhealthy once its retries before suspension fail with a suspend error code, and the active
+// TCP health checks restore it once it accepts connections, probing every suspend duration. An endpoint with a retry
+// delay is also probed while healthy, every retry delay, and marked unhealthy once as many probes fail. Kong probes at
+// a fixed interval, hence the maximum suspend duration and the suspend factor are not applied.
+func GenerateUpstreamPolicy(name string, circuitBreaker apimTransformer.CircuitBreaker) *v1beta1.KongUpstreamPolicy {
+	// The first failure and its retries are counted before the endpoint is suspended
+	failures := max(circuitBreaker.RetriesBeforeSuspension, 0) + 1
+	timeouts, tcpFailures := 0, 0
+	for _, errorCode := range circuitBreaker.SuspendErrorCodes {
+		if errorCode == kongConstants.ConnectionTimeoutErrorCode || errorCode == kongConstants.ConnectTimeoutErrorCode {
+			timeouts = failures
+		} else {
+			tcpFailures = failures
+		}
+	}
+	// The endpoints are suspended on all the failures when no error codes are given
+	if len(circuitBreaker.SuspendErrorCodes) == 0 {
+		timeouts, tcpFailures = failures, failures
+	}
+	suspendDurationMillis := circuitBreaker.SuspendDurationMillis
+	if suspendDurationMillis <= 0 {
+		suspendDurationMillis = kongConstants.DefaultSuspendDurationMillis
+	}
+	// The probe intervals are in seconds and a zero interval disables the probes
+	probeInterval := max((suspendDurationMillis+999)/1000, 1)
+	retryInterval := 0
+	if circuitBreaker.RetryDelayMillis > 0 {
+		retryInterval = max((circuitBreaker.RetryDelayMillis+999)/1000, 1)
+	}
+	if circuitBreaker.MaxSuspendDurationMillis > 0 || circuitBreaker.SuspendFactor > 0 {
+		logger.LoggerUtils.Debugf("The maximum suspend duration and the suspend factor are not applied to %s\n", name)
+	}
+
+	healthCheckType := kongConstants.TCPHealthCheckType
+	successes := 1
+	return &v1beta1.KongUpstreamPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       kongConstants.KongUpstreamPolicyKind,
+			APIVersion: kongConstants.KongBetaAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1beta1.KongUpstreamPolicySpec{
+			Healthchecks: &v1beta1.KongUpstreamHealthcheck{
+				Passive: &v1beta1.KongUpstreamPassiveHealthcheck{
+					Unhealthy: &v1beta1.KongUpstreamHealthcheckUnhealthy{
+						Timeouts:    &timeouts,
+						TCPFailures: &tcpFailures,
+					},
+				},
+				Active: &v1beta1.KongUpstreamActiveHealthcheck{
+					Type: &healthCheckType,
+					Healthy: &v1beta1.KongUpstreamHealthcheckHealthy{
+						Interval:  &retryInterval,
+						Successes: &successes,
+					},
+					Unhealthy: &v1beta1.KongUpstreamHealthcheckUnhealthy{
+						Interval:    &probeInterval,
+						TCPFailures: &failures,
+						Timeouts:    &failures,
+					},
+				},
+			},
+		},
+	}
+}
@@ -18,12 +18,16 @@
 package transformer
 
 import (
+	"strconv"
 	"strings"
 
 	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	v1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	eventHub "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/eventhub/types"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	asyncGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/async"
+	grpcGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/grpc"
 	httpGenerator "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/generators/http"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
@@ -51,13 +55,19 @@ func GenerateCR(api string, organizationID string, apiUUID string, conf *config.
 
 	logger.LoggerUtils.Debugf("GenerateCR|Parsed KongConf: %+v\n", api)
 
+	// The CRs of the API are named after its unique ID, which hashes its organization, as the organizations sharing
+	// a namespace may have APIs of the same name and version
 	apiUniqueID := GetUniqueIDForAPI(kongConf.Name, kongConf.Version, organizationID)
 	k8sArtifact := K8sArtifacts{
-		APIName:     kongConf.Name,
-		APIUUID:     apiUUID,
-		HTTPRoutes:  make(map[string]*gwapiv1.HTTPRoute),
-		Services:    make(map[string]*corev1.Service),
-		KongPlugins: map[string]*v1.KongPlugin{},
+		APIName:          kongConf.Name,
+		APIUUID:          apiUUID,
+		Namespace:        conf.DataPlane.GetOrganizationNamespace(organizationID),
+		HTTPRoutes:       make(map[string]*gwapiv1.HTTPRoute),
+		GRPCRoutes:       make(map[string]*gwapiv1.GRPCRoute),
+		Services:         make(map[string]*corev1.Service),
+		KongPlugins:      map[string]*v1.KongPlugin{},
+		Secrets:          make(map[string]*corev1.Secret),
+		UpstreamPolicies: make(map[string]*v1beta1.KongUpstreamPolicy),
 	}
 
 	// create endpoints
@@ -84,58 +94,78 @@ func GenerateCR(api string, organizationID string, apiUUID string, conf *config.
 			kongConstants.PluginLimitByField: kongConstants.ServiceLimitBy,
 		}
 		PrepareRateLimit(&rateLimitConfig, kongConf.RateLimit.Unit, 1, kongConf.RateLimit.RequestsPerUnit)
-		kongRateLimitPlugin := GenerateKongPlugin(nil, kongConstants.RateLimitingPlugin, kongConstants.APISuffix, rateLimitConfig, true)
+		kongRateLimitPlugin := GenerateRateLimitPlugin(nil, apiUniqueID+kongConstants.DashSeparatorString+kongConstants.APISuffix, rateLimitConfig)
 
 		k8sArtifact.KongPlugins[kongRateLimitPlugin.ObjectMeta.Name] = kongRateLimitPlugin
 		kongPlugins = append(kongPlugins, kongRateLimitPlugin.ObjectMeta.Name)
 		logger.LoggerUtils.Debugf("GenerateCR|Rate limit plugin added|%s\n", kongRateLimitPlugin.ObjectMeta.Name)
 	}
 
-	// create cors configurations
-	var corsConfig KongPluginConfig
-	var corsEnabled bool
+	// create cors configurations (cors plugin does not apply to grpc routes)
+	if kongConf.Type != constants.APITypeGrpc {
+		var corsConfig KongPluginConfig
+		var corsEnabled bool
 
-	if kongConf.CorsConfig != nil {
-		kongCorsConf := kongConf.CorsConfig
-		corsConfig = KongPluginConfig{
-			kongConstants.CORSOriginsField:     kongCorsConf.AccessControlAllowOrigins,
-			kongConstants.CORSCredentialsField: kongCorsConf.AccessControlAllowCredentials,
-			kongConstants.CORSHeadersField:     kongCorsConf.AccessControlAllowHeaders,
-			kongConstants.CORSMethodsField:     kongCorsConf.AccessControlAllowMethods,
+		if kongConf.CorsConfig != nil {
+			kongCorsConf := kongConf.CorsConfig
+			corsConfig = KongPluginConfig{
+				kongConstants.CORSOriginsField:     kongCorsConf.AccessControlAllowOrigins,
+				kongConstants.CORSCredentialsField: kongCorsConf.AccessControlAllowCredentials,
+				kongConstants.CORSHeadersField:     kongCorsConf.AccessControlAllowHeaders,
+				kongConstants.CORSMethodsField:     kongCorsConf.AccessControlAllowMethods,
+			}
+			corsEnabled = kongCorsConf.CORSConfigurationEnabled
+			logger.LoggerUtils.Debugf("GenerateCR|Using provided CORS configuration|Enabled:%v\n", corsEnabled)
+		} else {
+			// Create default CORS configuration when none is provided
+			corsConfig = KongPluginConfig{
+				kongConstants.CORSOriginsField:     kongConstants.DefaultCORSOrigins,
+				kongConstants.CORSCredentialsField: kongConstants.DefaultCORSCredentials,
+				kongConstants.CORSHeadersField:     kongConstants.DefaultCORSHeaders,
+				kongConstants.CORSMethodsField:     kongConstants.DefaultCORSMethods,
+			}
+			corsEnabled = true
+			logger.LoggerUtils.Debugf("GenerateCR|Using default CORS configuration|Enabled:%v\n", corsEnabled)
+		}
+
+		kongCorsPlugin := GenerateKongPlugin(nil, kongConstants.CORSPlugin, apiUniqueID+kongConstants.DashSeparatorString+kongConstants.APISuffix, corsConfig, corsEnabled)
+		k8sArtifact.KongPlugins[kongCorsPlugin.ObjectMeta.Name] = kongCorsPlugin
+		kongPlugins = append(kongPlugins, kongCorsPlugin.ObjectMeta.Name)
+		logger.LoggerUtils.Debugf("GenerateCR|CORS plugin added|%s\n", kongCorsPlugin.ObjectMeta.Name)
+	}
+
+	if kongConf.Type == constants.APITypeGrpc {
+		// generate production grpc routes
+		if endpoints, ok := createdEndpoints[constants.ProductionType]; ok {
+			generateGRPCRoutes(&k8sArtifact, &kongConf, organizationID, endpoints, constants.ProductionType, apiUniqueID, kongPlugins, conf)
+			logger.LoggerUtils.Debugf("GenerateCR|Production GRPCRoutes generated|%d endpoints\n", len(endpoints))
+		}
+		// generate sandbox grpc routes
+		if endpoints, ok := createdEndpoints[constants.SandboxType]; ok {
+			generateGRPCRoutes(&k8sArtifact, &kongConf, organizationID, endpoints, constants.SandboxType, apiUniqueID, kongPlugins, conf)
+			logger.LoggerUtils.Debugf("GenerateCR|Sandbox GRPCRoutes generated|%d endpoints\n", len(endpoints))
 		}
-		corsEnabled = kongCorsConf.CORSConfigurationEnabled
-		logger.LoggerUtils.Debugf("GenerateCR|Using provided CORS configuration|Enabled:%v\n", corsEnabled)
 	} else {
-		// Create default CORS configuration when none is provided
-		corsConfig = KongPluginConfig{
-			kongConstants.CORSOriginsField:     kongConstants.DefaultCORSOrigins,
-			kongConstants.CORSCredentialsField: kongConstants.DefaultCORSCredentials,
-			kongConstants.CORSHeadersField:     kongConstants.DefaultCORSHeaders,
-			kongConstants.CORSMethodsField:     kongConstants.DefaultCORSMethods,
+		// generate production http routes
+		if endpoints, ok := createdEndpoints[constants.ProductionType]; ok {
+			generateHTTPRoutes(&k8sArtifact, &kongConf, organizationID, endpoints, constants.ProductionType, apiUniqueID, kongPlugins, conf)
+			logger.LoggerUtils.Debugf("GenerateCR|Production HTTPRoutes generated|%d endpoints\n", len(endpoints))
+		}
+		// generate sandbox http routes
+		if endpoints, ok := createdEndpoints[constants.SandboxType]; ok {
+			generateHTTPRoutes(&k8sArtifact, &kongConf, organizationID, endpoints, constants.SandboxType, apiUniqueID, kongPlugins, conf)
+			logger.LoggerUtils.Debugf("GenerateCR|Sandbox HTTPRoutes generated|%d endpoints\n", len(endpoints))
 		}
-		corsEnabled = true
-		logger.LoggerUtils.Debugf("GenerateCR|Using default CORS configuration|Enabled:%v\n", corsEnabled)
 	}
 
-	kongCorsPlugin := GenerateKongPlugin(nil, kongConstants.CORSPlugin, kongConstants.APISuffix, corsConfig, corsEnabled)
-	k8sArtifact.KongPlugins[kongCorsPlugin.ObjectMeta.Name] = kongCorsPlugin
-	kongPlugins = append(kongPlugins, kongCorsPlugin.ObjectMeta.Name)
-	logger.LoggerUtils.Debugf("GenerateCR|CORS plugin added|%s\n", kongCorsPlugin.ObjectMeta.Name)
+	logger.LoggerUtils.Infof("GenerateCR|CR generation completed|HTTPRoutes:%d GRPCRoutes:%d Services:%d Plugins:%d\n",
+		len(k8sArtifact.HTTPRoutes), len(k8sArtifact.GRPCRoutes), len(k8sArtifact.Services), len(k8sArtifact.KongPlugins))
 
-	// generate production http routes
-	if endpoints, ok := createdEndpoints[constants.ProductionType]; ok {
-		generateHTTPRoutes(&k8sArtifact, &kongConf, organizationID, endpoints, constants.ProductionType, apiUniqueID, kongPlugins, conf)
-		logger.LoggerUtils.Debugf("GenerateCR|Production HTTPRoutes generated|%d endpoints\n", len(endpoints))
-	}
-	// generate sandbox http routes
-	if endpoints, ok := createdEndpoints[constants.SandboxType]; ok {
-		generateHTTPRoutes(&k8sArtifact, &kongConf, organizationID, endpoints, constants.SandboxType, apiUniqueID, kongPlugins, conf)
-		logger.LoggerUtils.Debugf("GenerateCR|Sandbox HTTPRoutes generated|%d endpoints\n", len(endpoints))
+	if err := ValidateRateLimitPolicies(k8sArtifact.KongPlugins); err != nil {
+		logger.LoggerUtils.Errorf("GenerateCR|API %s is not deployed as its rate limits are inconsistent: %v\n", apiUUID, err)
+		return nil
 	}
 
-	logger.LoggerUtils.Infof("GenerateCR|CR generation completed|HTTPRoutes:%d Services:%d Plugins:%d\n",
-		len(k8sArtifact.HTTPRoutes), len(k8sArtifact.Services), len(k8sArtifact.KongPlugins))
-
 	kongMgtServer.AddProcessedAPI(apiUUID)
 	return &k8sArtifact
 }
@@ -165,6 +195,24 @@ func UpdateCRS(k8sArtifact *K8sArtifacts, environments *[]apimTransformer.Enviro
 			}
 		}
 	}
+	for _, grpcRoute := range k8sArtifact.GRPCRoutes {
+		grpcRoute.ObjectMeta.Labels[kongConstants.OrganizationLabel] = organizationHash
+		grpcRoute.ObjectMeta.Labels[kongConstants.APIUUIDLabel] = apiUUID
+		grpcRoute.ObjectMeta.Labels[kongConstants.RevisionIDLabel] = revisionID
+		grpcRoute.ObjectMeta.Labels[kongConstants.APINameLabel] = apiName
+		grpcRoute.ObjectMeta.Labels[kongConstants.K8sInitiatedFromField] = kongConstants.ControlPlaneOrigin
+
+		for _, environment := range *environments {
+			vhost := environment.Vhost
+
+			if grpcRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] == constants.ProductionType {
+				grpcRoute.Spec.Hostnames = []gwapiv1.Hostname{gwapiv1.Hostname(vhost)}
+			}
+			if grpcRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] == constants.SandboxType {
+				grpcRoute.Spec.Hostnames = []gwapiv1.Hostname{gwapiv1.Hostname(kongConstants.SandboxHostPrefix + vhost)}
+			}
+		}
+	}
 	for _, service := range k8sArtifact.Services {
 		service.ObjectMeta.Labels = make(map[string]string)
 		service.ObjectMeta.Labels[kongConstants.OrganizationLabel] = organizationHash
@@ -173,6 +221,22 @@ func UpdateCRS(k8sArtifact *K8sArtifacts, environments *[]apimTransformer.Enviro
 		service.ObjectMeta.Labels[kongConstants.APINameLabel] = apiName
 		service.ObjectMeta.Labels[kongConstants.K8sInitiatedFromField] = kongConstants.ControlPlaneOrigin
 	}
+	for _, secret := range k8sArtifact.Secrets {
+		secret.ObjectMeta.Labels = make(map[string]string)
+		secret.ObjectMeta.Labels[kongConstants.OrganizationLabel] = organizationHash
+		secret.ObjectMeta.Labels[kongConstants.APIUUIDLabel] = apiUUID
+		secret.ObjectMeta.Labels[kongConstants.RevisionIDLabel] = revisionID
+		secret.ObjectMeta.Labels[kongConstants.APINameLabel] = apiName
+		secret.ObjectMeta.Labels[kongConstants.K8sInitiatedFromField] = kongConstants.ControlPlaneOrigin
+	}
+	for _, upstreamPolicy := range k8sArtifact.UpstreamPolicies {
+		upstreamPolicy.ObjectMeta.Labels = make(map[string]string)
+		upstreamPolicy.ObjectMeta.Labels[kongConstants.OrganizationLabel] = organizationHash
+		upstreamPolicy.ObjectMeta.Labels[kongConstants.APIUUIDLabel] = apiUUID
+		upstreamPolicy.ObjectMeta.Labels[kongConstants.RevisionIDLabel] = revisionID
+		upstreamPolicy.ObjectMeta.Labels[kongConstants.APINameLabel] = apiName
+		upstreamPolicy.ObjectMeta.Labels[kongConstants.K8sInitiatedFromField] = kongConstants.ControlPlaneOrigin
+	}
 	for _, kongPlugin := range k8sArtifact.KongPlugins {
 		kongPlugin.ObjectMeta.Labels = make(map[string]string)
 		kongPlugin.ObjectMeta.Labels[kongConstants.OrganizationLabel] = organizationHash
@@ -195,9 +259,14 @@ func generateHTTPRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, orga
 		kongPlugins = append(kongPlugins, kongACLPlugin.ObjectMeta.Name)
 		logger.LoggerUtils.Debugf("ACL plugin added for subscription validation - API Name: %s, Endpoint Type: %s, Plugin Name: %s, API Environment Group: %s, Allow List: %v",
 			k8sArtifact.APIName, endpointType, kongACLPlugin.ObjectMeta.Name, apiEnvironmentGroup, allowList)
+	} else if isConsumerIdentified(kongConf) {
+		kongACLPlugin := createAndAddEnvironmentACLPlugin(k8sArtifact, endpointType)
+		kongPlugins = append(kongPlugins, kongACLPlugin.ObjectMeta.Name)
 	}
 
 	gen := httpGenerator.Generator()
+	asyncGen := asyncGenerator.Generator()
+	isStreamingAPI := asyncGenerator.IsStreamingAPI(kongConf.Type)
 	organization := types.Organization{
 		Name: organizationID,
 	}
@@ -215,7 +284,19 @@ func generateHTTPRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, orga
 	for i, operations := range operationsArray {
 		logger.LoggerUtils.Debugf("Processing operations array - Index: %d, Operations: %+v, Organization ID: %s, Gateway Name: %s, Listener Name: %s",
 			i, operations, organizationID, gatewayConfigurations.Name, gatewayConfigurations.ListenerName)
-		httpK8sArtifact, err := gen.GenerateHTTPRoute(*kongConf, organization, gatewayConfigurations, operations, &endpoints, endpointType, uniqueID, i)
+		var httpK8sArtifact *httpGenerator.K8sArtifacts
+		var streamingSettings *asyncGenerator.StreamingSettings
+		var err error
+		if isStreamingAPI {
+			var asyncK8sArtifact *asyncGenerator.K8sArtifacts
+			asyncK8sArtifact, err = asyncGen.GenerateAsyncRoute(*kongConf, organization, gatewayConfigurations, operations, &endpoints, endpointType, uniqueID, i)
+			if err == nil {
+				httpK8sArtifact = asyncK8sArtifact.K8sArtifacts
+				streamingSettings = &asyncK8sArtifact.StreamingSettings
+			}
+		} else {
+			httpK8sArtifact, err = gen.GenerateHTTPRoute(*kongConf, organization, gatewayConfigurations, operations, &endpoints, endpointType, uniqueID, i)
+		}
 		if err != nil {
 			logger.LoggerUtils.Errorf("Failed to generate HTTPRoute - API Name: %s, API UUID: %s, Organization ID: %s, Endpoint Type: %s, Operations Index: %d, Error: %v",
 				k8sArtifact.APIName, k8sArtifact.APIUUID, organizationID, endpointType, i, err)
@@ -249,7 +330,7 @@ func generateHTTPRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, orga
 						kongConstants.PluginPathField:    utils.RetrievePathPrefix(operationTarget, basePath),
 					}
 					PrepareRateLimit(&rateLimitConfig, operation.RateLimit.Unit, 1, operation.RateLimit.RequestsPerUnit)
-					rateLimitPlugin := GenerateKongPlugin(&operation, kongConstants.RateLimitingPlugin, kongConstants.PathLimitBy, rateLimitConfig, true)
+					rateLimitPlugin := GenerateRateLimitPlugin(&operation, uniqueID+kongConstants.DashSeparatorString+kongConstants.PathLimitBy, rateLimitConfig)
 					k8sArtifact.KongPlugins[rateLimitPlugin.ObjectMeta.Name] = rateLimitPlugin
 
 					routeKongPlugins = append(routeKongPlugins, rateLimitPlugin.ObjectMeta.Name)
@@ -267,6 +348,9 @@ func generateHTTPRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, orga
 				kongAnnotations := map[string]string{
 					kongConstants.KongProtocolAnnotation: utils.GetProtocol(endpoints[0].URL),
 				}
+				if streamingSettings != nil {
+					addStreamingServiceAnnotations(kongAnnotations, *streamingSettings, endpoints[0].URL)
+				}
 				for kongKey, kongValue := range kongAnnotations {
 					service.ObjectMeta.Annotations[kongKey] = kongValue
 				}
@@ -278,6 +362,9 @@ func generateHTTPRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, orga
 				kongConstants.KongStripPathAnnotation: kongConstants.DefaultStripPathValue,
 				kongConstants.KongPluginsAnnotation:   strings.Join(routeKongPlugins, kongConstants.CommaString),
 			}
+			if streamingSettings != nil && streamingSettings.UpgradeType == constants.WebSocketUpgradeType && IsWebSocketProtocolsEnabled() {
+				annotationMap[kongConstants.KongProtocolsAnnotation] = kongConstants.WSProtocol + kongConstants.CommaString + kongConstants.WSSProtocol
+			}
 			updateHTTPRouteAnnotations(httpRoute, annotationMap)
 			httpRoute.Labels[kongConstants.RouteTypeField] = kongConstants.APIRouteType
 			httpRoute.Labels[kongConstants.K8sInitiatedFromField] = kongConstants.ControlPlaneOrigin
@@ -289,6 +376,158 @@ func generateHTTPRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, orga
 		k8sArtifact.APIName, k8sArtifact.APIUUID, endpointType, len(operationsArray), len(k8sArtifact.HTTPRoutes), len(k8sArtifact.Services))
 }
 
+// generateGRPCRoutes handles the generation of grpc route resources from kong conf
+func generateGRPCRoutes(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, organizationID string, endpoints []types.EndpointDetails, endpointType string, uniqueID string, kongPlugins []string, conf *config.Config) {
+	logger.LoggerUtils.Debugf("Starting GRPCRoute generation - API Name: %s, API UUID: %s, Organization ID: %s, Endpoint Type: %s, Unique ID: %s, Kong Plugins: %v, Endpoints: %+v",
+		k8sArtifact.APIName, k8sArtifact.APIUUID, organizationID, endpointType, uniqueID, kongPlugins, endpoints)
+
+	if kongConf.SubscriptionValidation {
+		apiEnvironmentGroup := GenerateACLGroupName(k8sArtifact.APIName, endpointType)
+		allowList := []string{apiEnvironmentGroup}
+		kongACLPlugin := createAndAddACLPlugin(k8sArtifact, nil, kongConstants.APISuffix, endpointType, allowList)
+		kongPlugins = append(kongPlugins, kongACLPlugin.ObjectMeta.Name)
+		logger.LoggerUtils.Debugf("ACL plugin added for subscription validation - API Name: %s, Endpoint Type: %s, Plugin Name: %s",
+			k8sArtifact.APIName, endpointType, kongACLPlugin.ObjectMeta.Name)
+	} else if isConsumerIdentified(kongConf) {
+		kongACLPlugin := createAndAddEnvironmentACLPlugin(k8sArtifact, endpointType)
+		kongPlugins = append(kongPlugins, kongACLPlugin.ObjectMeta.Name)
+	}
+
+	gen := grpcGenerator.Generator()
+	gen.GenerateGRPCBackEndRef = func(endpoints []types.EndpointDetails, operation types.Operation) []gwapiv1.GRPCBackendRef {
+		return generateGRPCBackendRefs(k8sArtifact, kongConf, organizationID, endpoints, endpointType)
+	}
+	organization := types.Organization{
+		Name: organizationID,
+	}
+
+	ingressClassName := conf.DataPlane.GatewayClassName
+	if ingressClassName == kongConstants.EmptyString {
+		ingressClassName = kongConstants.DefaultIngressClassName
+	}
+	gatewayConfigurations := types.GatewayConfigurations{
+		Name: ingressClassName,
+	}
+
+	operationsArray := prepareOperationsArray(kongConf)
+
+	for i, operations := range operationsArray {
+		grpcRoute, err := gen.GenerateGRPCRoute(*kongConf, organization, gatewayConfigurations, operations, &endpoints, endpointType, uniqueID, i)
+		if err != nil {
+			logger.LoggerUtils.Errorf("Failed to generate GRPCRoute - API Name: %s, API UUID: %s, Organization ID: %s, Endpoint Type: %s, Operations Index: %d, Error: %v",
+				k8sArtifact.APIName, k8sArtifact.APIUUID, organizationID, endpointType, i, err)
+			continue
+		}
+		routeKongPlugins := kongPlugins
+		grpcRoute.Spec.ParentRefs[0].SectionName = nil
+		if grpcRoute.ObjectMeta.Labels == nil {
+			grpcRoute.ObjectMeta.Labels = make(map[string]string)
+		}
+		grpcRoute.ObjectMeta.Labels[kongConstants.EnvironmentLabel] = endpointType
+
+		for _, operation := range operations {
+			// create and add a ratelimit plugin scoped to the grpc method path
+			if operation.RateLimit != nil {
+				rateLimitConfig := KongPluginConfig{
+					kongConstants.PluginLimitByField: kongConstants.PathLimitBy,
+					kongConstants.PluginPathField:    GenerateGRPCMethodPath(operation),
+				}
+				PrepareRateLimit(&rateLimitConfig, operation.RateLimit.Unit, 1, operation.RateLimit.RequestsPerUnit)
+				rateLimitPlugin := GenerateRateLimitPlugin(&operation, uniqueID+kongConstants.DashSeparatorString+kongConstants.PathLimitBy, rateLimitConfig)
+				k8sArtifact.KongPlugins[rateLimitPlugin.ObjectMeta.Name] = rateLimitPlugin
+
+				routeKongPlugins = append(routeKongPlugins, rateLimitPlugin.ObjectMeta.Name)
+				logger.LoggerUtils.Debugf("Operation rate limit plugin added - API Name: %s, Service: %s, Method: %s, Plugin Name: %s",
+					k8sArtifact.APIName, operation.Target, operation.Verb, rateLimitPlugin.ObjectMeta.Name)
+			}
+		}
+
+		annotationMap := map[string]string{
+			kongConstants.KongPluginsAnnotation: strings.Join(routeKongPlugins, kongConstants.CommaString),
+		}
+		updateGRPCRouteAnnotations(grpcRoute, annotationMap)
+		grpcRoute.Labels[kongConstants.RouteTypeField] = kongConstants.APIRouteType
+		grpcRoute.Labels[kongConstants.K8sInitiatedFromField] = kongConstants.ControlPlaneOrigin
+		k8sArtifact.GRPCRoutes[grpcRoute.ObjectMeta.Name] = grpcRoute
+	}
+
+	logger.LoggerUtils.Infof("GRPCRoute generation completed - API Name: %s, API UUID: %s, Endpoint Type: %s, Total Operations Arrays: %d, Total GRPCRoutes: %d, Total Services: %d",
+		k8sArtifact.APIName, k8sArtifact.APIUUID, endpointType, len(operationsArray), len(k8sArtifact.GRPCRoutes), len(k8sArtifact.Services))
+}
+
+// generateGRPCBackendRefs creates the backend references of a grpc route along with the Kong services they point to
+func generateGRPCBackendRefs(k8sArtifact *K8sArtifacts, kongConf *types.APKConf, organizationID string, endpoints []types.EndpointDetails, endpointType string) []gwapiv1.GRPCBackendRef {
+	kind := gwapiv1.Kind(kongConstants.ServiceKind)
+	grpcBackendRefs := []gwapiv1.GRPCBackendRef{}
+
+	for i, endpoint := range endpoints {
+		portNumber := gwapiv1.PortNumber(utils.GetPort(endpoint.URL))
+		serviceName := utils.GetHost(types.EndpointURL(endpoint.URL))
+		if !endpoint.ServiceEntry {
+			service := &corev1.Service{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       kongConstants.ServiceKind,
+					APIVersion: kongConstants.CoreAPIVersion,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: generateEndpointServiceName(kongConf, organizationID, endpointType, i),
+					Annotations: map[string]string{
+						kongConstants.KongProtocolAnnotation: GetGRPCProtocol(endpoint.URL),
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:         kongConstants.ServiceTypeExternalName,
+					ExternalName: serviceName,
+					Ports: []corev1.ServicePort{
+						{
+							Port:     int32(portNumber),
+							Protocol: corev1.ProtocolTCP,
+						},
+					},
+				},
+			}
+			k8sArtifact.Services[service.ObjectMeta.Name] = service
+			serviceName = service.ObjectMeta.Name
+		}
+		grpcBackendRefs = append(grpcBackendRefs, gwapiv1.GRPCBackendRef{
+			BackendRef: gwapiv1.BackendRef{
+				BackendObjectReference: gwapiv1.BackendObjectReference{
+					Kind: &kind,
+					Name: gwapiv1.ObjectName(serviceName),
+					Port: &portNumber,
+				},
+			},
+		})
+	}
+	return grpcBackendRefs
+}
+
+// generateEndpointServiceName generates the name of the Service of an endpoint. The first endpoint of an environment
+// is named after the environment, as the endpoint configurations are applied to its Service, while the names of the
+// other endpoints are unique to their position.
+func generateEndpointServiceName(kongConf *types.APKConf, organizationID string, endpointType string, index int) string {
+	if index == 0 {
+		return utils.GenerateServiceName(kongConf.Name, kongConf.Version, organizationID, endpointType)
+	}
+	return utils.GenerateServiceName(kongConf.Name, kongConf.Version, organizationID,
+		endpointType+kongConstants.DashSeparatorString+strconv.Itoa(index))
+}
+
+// addStreamingServiceAnnotations adds the Kong protocol and timeout annotations needed by streaming (WS, SSE, WebSub) backends
+func addStreamingServiceAnnotations(annotations map[string]string, streamingSettings asyncGenerator.StreamingSettings, endpointURL string) {
+	if streamingSettings.UpgradeType == constants.WebSocketUpgradeType {
+		annotations[kongConstants.KongProtocolAnnotation] = GetWSProtocol(endpointURL)
+	}
+	if streamingSettings.IdleTimeout > 0 {
+		idleTimeout := strconv.FormatInt(streamingSettings.IdleTimeout.Milliseconds(), 10)
+		annotations[kongConstants.KongReadTimeoutAnnotation] = idleTimeout
+		annotations[kongConstants.KongWriteTimeoutAnnotation] = idleTimeout
+	}
+	if streamingSettings.RequestTimeout > 0 {
+		annotations[kongConstants.KongReadTimeoutAnnotation] = strconv.FormatInt(streamingSettings.RequestTimeout.Milliseconds(), 10)
+	}
+}
+
 // prepareOptionsHTTPRoute creates an OPTIONS HTTPRoute based on an existing HTTPRoute
 func prepareOptionsHTTPRoute(httpRoute *gwapiv1.HTTPRoute) *gwapiv1.HTTPRoute {
 	logger.LoggerUtils.Debugf("Preparing OPTIONS HTTPRoute|Original:%s\n", httpRoute.Name)
@@ -367,6 +606,32 @@ func createAndAddACLPlugin(k8sArtifact *K8sArtifacts, operation *types.Operation
 	return aclPlugin
 }
 
+// createAndAddEnvironmentACLPlugin handles the Kong ACL plugin generation restricting the routes of an environment to
+// the consumers of the environment, so that the keys of one environment are rejected by the routes of the other
+// environment when the subscriptions are not validated
+func createAndAddEnvironmentACLPlugin(k8sArtifact *K8sArtifacts, environment string) *v1.KongPlugin {
+	allowList := []string{GenerateEnvironmentACLGroupName(environment)}
+	kongACLPlugin := createAndAddACLPlugin(k8sArtifact, nil, kongConstants.APISuffix, environment, allowList)
+	logger.LoggerUtils.Debugf("ACL plugin added for environment - API Name: %s, Endpoint Type: %s, Plugin Name: %s",
+		k8sArtifact.APIName, environment, kongACLPlugin.ObjectMeta.Name)
+	return kongACLPlugin
+}
+
+// isConsumerIdentified reports whether the consumers of the API are identified by their OAuth2 tokens or their API
+// keys
+func isConsumerIdentified(kongConf *types.APKConf) bool {
+	if kongConf.Authentication == nil {
+		return false
+	}
+	for _, authentication := range *kongConf.Authentication {
+		if authentication.Enabled && (authentication.AuthType == kongConstants.OAuth2AuthenticationType ||
+			authentication.AuthType == kongConstants.APIKeyAuthenticationType) {
+			return true
+		}
+	}
+	return false
+}
+
 // createAndAddJWTPlugin handles the Kong JWT credential plugin generation and adding to k8s resources
 func createAndAddJWTPlugin(k8sArtifact *K8sArtifacts, operation *types.Operation, targetRef string, authentication types.AuthConfiguration) *v1.KongPlugin {
 	logger.LoggerUtils.Debugf("Creating JWT plugin|TargetRef:%s Enabled:%v\n", targetRef, authentication.Enabled)
@@ -409,6 +674,17 @@ func updateHTTPRouteAnnotations(httpRoute *gwapiv1.HTTPRoute, annotations map[st
 	}
 }
 
+// updateGRPCRouteAnnotations updates the annotations of grpcroutes
+func updateGRPCRouteAnnotations(grpcRoute *gwapiv1.GRPCRoute, annotations map[string]string) {
+	logger.LoggerUtils.Debugf("Updating GRPCRoute annotations|Route:%s Annotations:%d\n",
+		grpcRoute.Name, len(annotations))
+
+	grpcRoute.Annotations = make(map[string]string, len(annotations))
+	for key, annotation := range annotations {
+		grpcRoute.Annotations[key] = annotation
+	}
+}
+
 // CreateConsumer handles the Kong consumer generation
 func CreateConsumer(applicationUUID string, environment string, conf *config.Config) *v1.KongConsumer {
 	logger.LoggerUtils.Debugf("Creating Kong consumer|App:%s Env:%s\n", applicationUUID, environment)
@@ -498,7 +774,7 @@ func GenerateKongPlugin(operation *types.Operation, pluginName string, targetRef
 	}
 }
 
-func CreateIssuerKongSecretCredential(issuerSecret corev1.Secret, conf *config.Config, applicationUUID string, consumerKey string, environment string) *corev1.Secret {
+func CreateIssuerKongSecretCredential(issuerSecret corev1.Secret, applicationUUID string, consumerKey string, environment string) *corev1.Secret {
 	logger.LoggerEvents.Debugf("Creating issuer Kong secret credential for ApplicationUUID: %s, Environment: %s", applicationUUID, environment)
 
 	rsaPublicKey, exists := issuerSecret.Data[kongConstants.PublicKeyField]
@@ -519,7 +795,8 @@ func CreateIssuerKongSecretCredential(issuerSecret corev1.Secret, conf *config.C
 		jwtCredentialSecret.Labels = make(map[string]string, 1)
 	}
 	jwtCredentialSecret.Labels[kongConstants.EnvironmentLabel] = strings.ToLower(environment)
-	jwtCredentialSecret.Namespace = conf.DataPlane.Namespace
+	// The credential is kept next to the issuer which is deployed to the namespace of the key manager organization
+	jwtCredentialSecret.Namespace = issuerSecret.Namespace
 
 	return jwtCredentialSecret
 }
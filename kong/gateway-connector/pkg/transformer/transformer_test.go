@@ -0,0 +1,454 @@
+/*
+ *  Copyright (c) 2025, WSO2 LLC. (http://www.wso2.org) All Rights Reserved.
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ *
+ */
+
+package transformer
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	v1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/constants"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/pkg/utils"
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
+	apimTransformer "github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/transformer"
+	kongConstants "github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
+	corev1 "k8s.io/api/core/v1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	testOrganizationID = "org1"
+	testAPIUUID        = "api-uuid"
+)
+
+// newTestK8sArtifacts returns empty K8sArtifacts of the test API
+func newTestK8sArtifacts() *K8sArtifacts {
+	return &K8sArtifacts{
+		APIName:          "TestAPI",
+		APIUUID:          testAPIUUID,
+		Namespace:        "default",
+		HTTPRoutes:       make(map[string]*gwapiv1.HTTPRoute),
+		GRPCRoutes:       make(map[string]*gwapiv1.GRPCRoute),
+		Services:         make(map[string]*corev1.Service),
+		KongPlugins:      map[string]*v1.KongPlugin{},
+		Secrets:          make(map[string]*corev1.Secret),
+		UpstreamPolicies: make(map[string]*v1beta1.KongUpstreamPolicy),
+	}
+}
+
+func TestGenerateGRPCBackendRefsNamesEachEndpointService(t *testing.T) {
+	k8sArtifact := newTestK8sArtifacts()
+	kongConf := &types.APKConf{Name: "TestAPI", Version: "v1"}
+	endpoints := []types.EndpointDetails{
+		{URL: "grpc://backend-1:50051"},
+		{URL: "grpcs://backend-2:50052"},
+	}
+
+	backendRefs := generateGRPCBackendRefs(k8sArtifact, kongConf, testOrganizationID, endpoints, constants.ProductionType)
+
+	assert.Len(t, backendRefs, 2)
+	assert.Len(t, k8sArtifact.Services, 2)
+	assert.Equal(t, utils.GenerateServiceName("TestAPI", "v1", testOrganizationID, constants.ProductionType),
+		string(backendRefs[0].Name))
+	assert.NotEqual(t, backendRefs[0].Name, backendRefs[1].Name)
+	for i, backendRef := range backendRefs {
+		service, exists := k8sArtifact.Services[string(backendRef.Name)]
+		assert.True(t, exists)
+		assert.Equal(t, utils.GetHost(types.EndpointURL(endpoints[i].URL)), service.Spec.ExternalName)
+		assert.Equal(t, int32(*backendRef.Port), service.Spec.Ports[0].Port)
+	}
+}
+
+func TestGetWSProtocolCarriesUpgradesOverHTTPByDefault(t *testing.T) {
+	assert.False(t, IsWebSocketProtocolsEnabled())
+	assert.Equal(t, "http", GetWSProtocol("ws://backend:8080"))
+	assert.Equal(t, "https", GetWSProtocol("wss://backend:8443"))
+	assert.Equal(t, "https", GetWSProtocol("https://backend:8443"))
+}
+
+func TestGenerateApplicationRateLimitPlugin(t *testing.T) {
+	assert.False(t, IsApplicationRateLimitEnabled())
+	plugin := GenerateApplicationRateLimitPlugin("min", 2, 100)
+	require.NotNil(t, plugin)
+	assert.Equal(t, "rate-limiting-advanced", plugin.PluginName)
+	pluginConfig := KongPluginConfig{}
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &pluginConfig))
+	assert.Equal(t, []interface{}{float64(100)}, pluginConfig["limit"])
+	assert.Equal(t, []interface{}{float64(120)}, pluginConfig["window_size"])
+	assert.Equal(t, "consumer", pluginConfig["identifier"])
+	assert.Equal(t, "local", pluginConfig["strategy"])
+	assert.Equal(t, float64(-1), pluginConfig["sync_rate"])
+
+	assert.Nil(t, GenerateApplicationRateLimitPlugin("fortnight", 1, 100))
+}
+
+func TestGenerateLifeCyclePluginRecordsLifeCycle(t *testing.T) {
+	lifeCycle := NewAPILifeCycle("Deprecated", 1740787200000)
+	plugin := GenerateLifeCyclePlugin(testAPIUUID, lifeCycle)
+	require.NotNil(t, plugin)
+	assert.Equal(t, "response-transformer", plugin.PluginName)
+	assert.Equal(t, map[string]string{"kgw.wso2.com/lifecycle-state": "DEPRECATED",
+		"kgw.wso2.com/deprecated-at": "1740787200"}, plugin.Annotations)
+	assert.Equal(t, lifeCycle, LifeCycleFromAnnotations(plugin.Annotations))
+
+	plugin = GenerateLifeCyclePlugin(testAPIUUID, NewAPILifeCycle("BLOCKED", 0))
+	require.NotNil(t, plugin)
+	assert.Equal(t, APILifeCycle{State: "BLOCKED"}, LifeCycleFromAnnotations(plugin.Annotations))
+
+	assert.Nil(t, GenerateLifeCyclePlugin(testAPIUUID, NewAPILifeCycle("PUBLISHED", 0)))
+	assert.Equal(t, APILifeCycle{}, LifeCycleFromAnnotations(nil))
+}
+
+// setGatewayAgentConfig replaces the gateway agent configurations for the duration of the test
+func setGatewayAgentConfig(t *testing.T, values map[string]interface{}) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	previous := conf.GatewayAgent
+	conf.GatewayAgent = values
+	t.Cleanup(func() { conf.GatewayAgent = previous })
+}
+
+func TestGetRateLimitPolicyReadsRedisCredentialsFromSecret(t *testing.T) {
+	setGatewayAgentConfig(t, map[string]interface{}{"rateLimitPolicy": "redis", "rateLimitRedisHost": "redis",
+		"rateLimitRedisPassword": "secret"})
+	rateLimitPolicy, err := GetRateLimitPolicy()
+	require.Error(t, err)
+	assert.Equal(t, RateLimitPolicy{Policy: "local"}, rateLimitPolicy)
+
+	setGatewayAgentConfig(t, map[string]interface{}{"rateLimitPolicy": "redis", "rateLimitRedisHost": "redis",
+		"rateLimitRedisPort": "6380"})
+	rateLimitPolicy, err = GetRateLimitPolicy()
+	require.NoError(t, err)
+	assert.Equal(t, KongPluginConfig{"host": "redis", "port": 6380}, rateLimitPolicy.Redis)
+
+	setGatewayAgentConfig(t, map[string]interface{}{"rateLimitPolicy": "redis", "rateLimitRedisSecret": "redis-conn"})
+	plugin := GenerateRateLimitPlugin(nil, "api", KongPluginConfig{"minute": 10})
+	pluginConfig := KongPluginConfig{}
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &pluginConfig))
+	assert.Equal(t, "redis", pluginConfig["policy"])
+	assert.NotContains(t, pluginConfig, "redis")
+	require.Len(t, plugin.ConfigPatches, 1)
+	assert.Equal(t, v1.SecretValueFromSource{Secret: "redis-conn", Key: "redis"}, plugin.ConfigPatches[0].ValueFrom.SecretValue)
+}
+
+func TestGetRateLimitPolicyRejectsInvalidRedisConfig(t *testing.T) {
+	for _, gatewayAgent := range []map[string]interface{}{
+		{"rateLimitPolicy": "redis"},
+		{"rateLimitPolicy": "redis", "rateLimitRedisHost": "redis", "rateLimitRedisUsername": "admin"},
+		{"rateLimitPolicy": "redis", "rateLimitRedisHost": "redis", "rateLimitRedisPort": "redis-port"},
+		{"rateLimitPolicy": "redis", "rateLimitRedisHost": "redis", "rateLimitRedisPort": "70000"},
+		{"rateLimitPolicy": "sliding"},
+	} {
+		setGatewayAgentConfig(t, gatewayAgent)
+		_, err := GetRateLimitPolicy()
+		assert.Error(t, err, gatewayAgent)
+	}
+}
+
+func TestValidateRateLimitPoliciesRejectsMixedPolicies(t *testing.T) {
+	setGatewayAgentConfig(t, map[string]interface{}{"rateLimitPolicy": "cluster"})
+	kongPlugins := map[string]*v1.KongPlugin{
+		"api": GenerateRateLimitPlugin(nil, "api", KongPluginConfig{"minute": 10}),
+		"get": GenerateRateLimitPlugin(nil, "get", KongPluginConfig{"minute": 5}),
+	}
+	assert.NoError(t, ValidateRateLimitPolicies(kongPlugins))
+
+	setGatewayAgentConfig(t, map[string]interface{}{"rateLimitPolicy": "redis", "rateLimitRedisSecret": "redis-conn"})
+	kongPlugins["post"] = GenerateRateLimitPlugin(nil, "post", KongPluginConfig{"minute": 5})
+	err := ValidateRateLimitPolicies(kongPlugins)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cluster: api,get")
+	assert.Contains(t, err.Error(), "redis(redis-conn/redis): post")
+}
+
+func TestGenerateUpstreamPolicyMapsCircuitBreaker(t *testing.T) {
+	upstreamPolicy := GenerateUpstreamPolicy("backend", apimTransformer.CircuitBreaker{
+		RetriesBeforeSuspension: 2,
+		RetryDelayMillis:        1500,
+		SuspendErrorCodes:       []string{"101504"},
+		SuspendDurationMillis:   60000,
+	})
+
+	healthchecks := upstreamPolicy.Spec.Healthchecks
+	assert.Equal(t, 3, *healthchecks.Passive.Unhealthy.Timeouts)
+	assert.Equal(t, 0, *healthchecks.Passive.Unhealthy.TCPFailures)
+	assert.Equal(t, 2, *healthchecks.Active.Healthy.Interval)
+	assert.Equal(t, 60, *healthchecks.Active.Unhealthy.Interval)
+	assert.Equal(t, 3, *healthchecks.Active.Unhealthy.TCPFailures)
+	assert.Equal(t, 3, *healthchecks.Active.Unhealthy.Timeouts)
+
+	// The endpoints are suspended on the first failure of all kinds and are not probed while healthy by default
+	healthchecks = GenerateUpstreamPolicy("backend", apimTransformer.CircuitBreaker{}).Spec.Healthchecks
+	assert.Equal(t, 1, *healthchecks.Passive.Unhealthy.Timeouts)
+	assert.Equal(t, 1, *healthchecks.Passive.Unhealthy.TCPFailures)
+	assert.Equal(t, 0, *healthchecks.Active.Healthy.Interval)
+	assert.Equal(t, 30, *healthchecks.Active.Unhealthy.Interval)
+}
+
+func TestApplyServiceResiliencyAnnotatesService(t *testing.T) {
+	k8sArtifact := newTestK8sArtifacts()
+	service := &corev1.Service{}
+	service.ObjectMeta.Name = "backend"
+	service.ObjectMeta.Annotations = map[string]string{kongConstants.KongReadTimeoutAnnotation: "60000"}
+
+	applyServiceResiliency(k8sArtifact, service, apimTransformer.Resiliency{
+		Timeout:        &apimTransformer.Timeout{UpstreamResponseTimeoutMillis: 30000},
+		CircuitBreaker: &apimTransformer.CircuitBreaker{RetriesBeforeSuspension: 2},
+	})
+
+	assert.Equal(t, "30000", service.ObjectMeta.Annotations[kongConstants.KongConnectTimeoutAnnotation])
+	assert.Equal(t, "60000", service.ObjectMeta.Annotations[kongConstants.KongReadTimeoutAnnotation])
+	assert.Equal(t, "30000", service.ObjectMeta.Annotations[kongConstants.KongWriteTimeoutAnnotation])
+	assert.NotContains(t, service.ObjectMeta.Annotations, kongConstants.KongRetriesAnnotation)
+	assert.Equal(t, "backend", service.ObjectMeta.Annotations[kongConstants.KongUpstreamPolicyAnnotation])
+	require.Contains(t, k8sArtifact.UpstreamPolicies, "backend")
+	assert.Equal(t, "default", k8sArtifact.UpstreamPolicies["backend"].Namespace)
+}
+
+// newTestBalancedArtifacts returns the K8sArtifacts of the test API with an HTTPRoute of the given environment
+// referring to the Service of its endpoint
+func newTestBalancedArtifacts(environment string, endpointURL string) (*K8sArtifacts, string) {
+	k8sArtifact := newTestK8sArtifacts()
+	serviceName := utils.GenerateServiceName("TestAPI", "v1", testOrganizationID, environment)
+	service := &corev1.Service{}
+	service.ObjectMeta.Name = serviceName
+	service.ObjectMeta.Annotations = map[string]string{kongConstants.KongProtocolAnnotation: utils.GetProtocol(endpointURL)}
+	service.Spec.ExternalName = utils.GetHost(types.EndpointURL(endpointURL))
+	k8sArtifact.Services[serviceName] = service
+	httpRoute := &gwapiv1.HTTPRoute{}
+	httpRoute.ObjectMeta.Name = "route-" + environment
+	httpRoute.ObjectMeta.Labels = map[string]string{kongConstants.EnvironmentLabel: environment}
+	httpRoute.Spec.Rules = []gwapiv1.HTTPRouteRule{{BackendRefs: []gwapiv1.HTTPBackendRef{{
+		BackendRef: gwapiv1.BackendRef{BackendObjectReference: gwapiv1.BackendObjectReference{
+			Name: gwapiv1.ObjectName(serviceName)}}}}}}
+	k8sArtifact.HTTPRoutes[httpRoute.ObjectMeta.Name] = httpRoute
+	return k8sArtifact, serviceName
+}
+
+func TestApplyEndpointLoadBalancingSkipsEndpointsOfOtherProtocols(t *testing.T) {
+	k8sArtifact, serviceName := newTestBalancedArtifacts(constants.ProductionType, "https://backend-1:8443")
+	api := &apimTransformer.API{Name: "TestAPI", Version: "v1", EndpointConfigurations: &apimTransformer.EndpointConfigurations{
+		Production: &[]apimTransformer.EndpointConfiguration{{
+			Endpoint:             "https://backend-1:8443",
+			LoadBalanceEndpoints: []string{"http://backend-2:8080", "https://backend-3:9443"},
+		}},
+	}}
+
+	require.NoError(t, ApplyEndpointLoadBalancing(k8sArtifact, api, testOrganizationID))
+
+	backendRefs := k8sArtifact.HTTPRoutes["route-"+constants.ProductionType].Spec.Rules[0].BackendRefs
+	require.Len(t, backendRefs, 2)
+	assert.Equal(t, serviceName, string(backendRefs[0].Name))
+	assert.Equal(t, serviceName+"-2", string(backendRefs[1].Name))
+	assert.Equal(t, int32(9443), int32(*backendRefs[1].Port))
+	for _, backendRef := range backendRefs {
+		assert.Equal(t, int32(kongConstants.LoadBalanceEndpointWeight), *backendRef.Weight)
+	}
+	assert.NotContains(t, k8sArtifact.Services, serviceName+"-1")
+	assert.Equal(t, "https", k8sArtifact.Services[serviceName+"-2"].ObjectMeta.Annotations[kongConstants.KongProtocolAnnotation])
+}
+
+func TestBalanceBackendRefsBalancesGRPCBackends(t *testing.T) {
+	services := []*corev1.Service{{}, {}}
+	services[0].ObjectMeta.Name = "backend"
+	services[1].ObjectMeta.Name = "backend-1"
+	backendRefs := []gwapiv1.GRPCBackendRef{
+		{BackendRef: gwapiv1.BackendRef{BackendObjectReference: gwapiv1.BackendObjectReference{Name: "backend"}}},
+		{BackendRef: gwapiv1.BackendRef{BackendObjectReference: gwapiv1.BackendObjectReference{Name: "other"}}},
+	}
+
+	balancedBackendRefs := balanceBackendRefs(backendRefs, services,
+		func(backendRef *gwapiv1.GRPCBackendRef) *gwapiv1.BackendRef { return &backendRef.BackendRef })
+
+	require.Len(t, balancedBackendRefs, 3)
+	assert.Equal(t, "backend", string(balancedBackendRefs[0].Name))
+	assert.Equal(t, "backend-1", string(balancedBackendRefs[1].Name))
+	assert.Equal(t, "other", string(balancedBackendRefs[2].Name))
+	assert.Nil(t, balancedBackendRefs[2].Weight)
+	assert.Nil(t, backendRefs[0].Weight)
+}
+
+func TestApplyEndpointLoadBalancingRejectsFailoverEndpoints(t *testing.T) {
+	k8sArtifact, serviceName := newTestBalancedArtifacts(constants.SandboxType, "https://backend-1:8443")
+	api := &apimTransformer.API{Name: "TestAPI", Version: "v1", EndpointConfigurations: &apimTransformer.EndpointConfigurations{
+		Sandbox: &[]apimTransformer.EndpointConfiguration{{
+			Endpoint:          "https://backend-1:8443",
+			FailoverEndpoints: []string{"https://backend-2:8443"},
+		}},
+	}}
+
+	assert.Error(t, ApplyEndpointLoadBalancing(k8sArtifact, api, testOrganizationID))
+
+	backendRefs := k8sArtifact.HTTPRoutes["route-"+constants.SandboxType].Spec.Rules[0].BackendRefs
+	require.Len(t, backendRefs, 1)
+	assert.Equal(t, serviceName, string(backendRefs[0].Name))
+	assert.Nil(t, backendRefs[0].Weight)
+	assert.Len(t, k8sArtifact.Services, 1)
+	assert.Empty(t, k8sArtifact.UpstreamPolicies)
+}
+
+func TestApplyEndpointSecurityKeepsBasicCredentialsInSecret(t *testing.T) {
+	k8sArtifact, _ := newTestBalancedArtifacts(constants.ProductionType, "https://backend:8443")
+	securityConfigs := []apimTransformer.EndpointSecurityConfig{{Production: apimTransformer.SecurityObj{
+		Enabled: true, Type: "BASIC", Username: "admin", Password: "s3cr3t"}}}
+
+	require.NoError(t, ApplyEndpointSecurity(k8sArtifact, securityConfigs))
+
+	require.Len(t, k8sArtifact.KongPlugins, 1)
+	require.Len(t, k8sArtifact.Secrets, 1)
+	pluginName := k8sArtifact.HTTPRoutes["route-"+constants.ProductionType].ObjectMeta.Annotations[kongConstants.KongPluginsAnnotation]
+	require.Contains(t, k8sArtifact.KongPlugins, pluginName)
+	plugin := k8sArtifact.KongPlugins[pluginName]
+	secret := k8sArtifact.Secrets[GenerateSecretName(testAPIUUID, constants.ProductionType, kongConstants.EndpointSecuritySecretType)]
+	require.NotNil(t, secret)
+	assert.Equal(t, "default", secret.Namespace)
+	assert.Equal(t, `["Authorization:Basic YWRtaW46czNjcjN0"]`, secret.StringData["headers"])
+	assert.Equal(t, []v1.ConfigPatch{secretConfigPatch("/add/headers", secret.ObjectMeta.Name, "headers")},
+		plugin.ConfigPatches)
+	assert.NotContains(t, string(plugin.Config.Raw), "YWRtaW46czNjcjN0")
+	assert.NotContains(t, string(plugin.Config.Raw), "s3cr3t")
+}
+
+func TestGenerateEndpointSecurityCRsKeepsOAuthCredentialsInSecret(t *testing.T) {
+	security := apimTransformer.SecurityObj{Enabled: true, Type: "OAUTH", GrantType: "PASSWORD",
+		TokenURL: "https://idp/token", ClientID: "client", ClientSecret: "client-s3cr3t", Username: "admin",
+		Password: "s3cr3t"}
+	_, _, err := generateEndpointSecurityCRs(testAPIUUID, constants.ProductionType, security)
+	assert.Error(t, err)
+
+	setGatewayAgentConfig(t, map[string]interface{}{"upstreamOAuthEnabled": true})
+	plugin, secret, err := generateEndpointSecurityCRs(testAPIUUID, constants.ProductionType, security)
+	require.NoError(t, err)
+	assert.Equal(t, "upstream-oauth", plugin.PluginName)
+	assert.Equal(t, map[string]string{"client_id": `"client"`, "client_secret": `"client-s3cr3t"`,
+		"username": `"admin"`, "password": `"s3cr3t"`}, secret.StringData)
+	require.Len(t, plugin.ConfigPatches, 4)
+	for _, patch := range plugin.ConfigPatches {
+		field := patch.ValueFrom.SecretValue.Key
+		assert.Equal(t, "/oauth/"+field, patch.Path)
+		assert.Equal(t, secret.ObjectMeta.Name, patch.ValueFrom.SecretValue.Secret)
+	}
+	pluginConfig := KongPluginConfig{}
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &pluginConfig))
+	assert.Equal(t, map[string]interface{}{"token_endpoint": "https://idp/token", "grant_type": "password"},
+		pluginConfig["oauth"])
+	assert.NotContains(t, string(plugin.Config.Raw), "s3cr3t")
+}
+
+func TestApplyEndpointSecurityRejectsUnappliableSecurity(t *testing.T) {
+	for _, security := range []apimTransformer.SecurityObj{
+		{Enabled: true, Type: "DIGEST"},
+		{Enabled: true, Type: "API_KEY", APIKeyValue: "key"},
+		{Enabled: true, Type: "OAUTH", ClientID: "client", ClientSecret: "client-s3cr3t"},
+	} {
+		k8sArtifact, _ := newTestBalancedArtifacts(constants.SandboxType, "https://backend:8443")
+		err := ApplyEndpointSecurity(k8sArtifact, []apimTransformer.EndpointSecurityConfig{{Sandbox: security}})
+		assert.Error(t, err, security.Type)
+		assert.Empty(t, k8sArtifact.KongPlugins)
+		assert.Empty(t, k8sArtifact.Secrets)
+	}
+}
+
+func TestGenerateCRQualifiesNamesByOrganization(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	api := `name: "TestAPI"
+version: "v1"
+basePath: "/test"
+type: "REST"
+endpointConfigurations:
+  production:
+    - endpoint: "https://backend:8443"
+operations:
+  - target: "/orders"
+    verb: "GET"
+    rateLimit:
+      requestsPerUnit: 10
+      unit: "Minute"
+rateLimit:
+  requestsPerUnit: 100
+  unit: "Hour"
+authentication:
+  - authType: "OAuth2"
+    enabled: true
+`
+	names := make(map[string]string)
+	for _, organizationID := range []string{"org1", "org2"} {
+		k8sArtifact := GenerateCR(api, organizationID, organizationID+"-api-uuid", conf)
+		require.NotNil(t, k8sArtifact)
+		require.NotEmpty(t, k8sArtifact.KongPlugins)
+		for name := range k8sArtifact.KongPlugins {
+			assert.NotContains(t, names, name, "KongPlugin %s is shared by the organizations", name)
+			names[name] = organizationID
+		}
+		for name := range k8sArtifact.HTTPRoutes {
+			assert.NotContains(t, names, name, "HTTPRoute %s is shared by the organizations", name)
+			names[name] = organizationID
+		}
+		for name := range k8sArtifact.Services {
+			assert.NotContains(t, names, name, "Service %s is shared by the organizations", name)
+			names[name] = organizationID
+		}
+	}
+}
+
+func TestGenerateHTTPRoutesRestrictsIdentifiedConsumersToEnvironment(t *testing.T) {
+	conf, err := config.ReadConfigs()
+	require.NoError(t, err)
+	operations := []types.Operation{{Target: "/*", Verb: "GET", Secured: true}}
+	endpoints := []types.EndpointDetails{{URL: "https://backend:8443"}}
+	for authType, restricted := range map[string]bool{
+		kongConstants.APIKeyAuthenticationType: true,
+		kongConstants.OAuth2AuthenticationType: true,
+		kongConstants.MTLSAuthenticationType:   false,
+	} {
+		k8sArtifact := newTestK8sArtifacts()
+		kongConf := &types.APKConf{Name: "TestAPI", Version: "v1", BasePath: "/test", Operations: &operations,
+			Authentication: &[]types.AuthConfiguration{{AuthType: authType, Enabled: true}}}
+
+		generateHTTPRoutes(k8sArtifact, kongConf, testOrganizationID, endpoints, constants.SandboxType, "uid", nil, conf)
+
+		var aclPlugins []*v1.KongPlugin
+		for _, kongPlugin := range k8sArtifact.KongPlugins {
+			if kongPlugin.PluginName == kongConstants.ACLPlugin {
+				aclPlugins = append(aclPlugins, kongPlugin)
+			}
+		}
+		if !restricted {
+			assert.Empty(t, aclPlugins, authType)
+			continue
+		}
+		require.Len(t, aclPlugins, 1, authType)
+		var aclConfig map[string][]string
+		require.NoError(t, json.Unmarshal(aclPlugins[0].Config.Raw, &aclConfig))
+		assert.Equal(t, []string{GenerateEnvironmentACLGroupName(constants.SandboxType)}, aclConfig[kongConstants.AllowField], authType)
+		// The preflight route carries no plugins
+		restrictedRoutes := 0
+		for _, httpRoute := range k8sArtifact.HTTPRoutes {
+			if routePlugins := httpRoute.ObjectMeta.Annotations[kongConstants.KongPluginsAnnotation]; routePlugins != "" {
+				assert.Contains(t, routePlugins, aclPlugins[0].ObjectMeta.Name, authType)
+				restrictedRoutes++
+			}
+		}
+		assert.Positive(t, restrictedRoutes, authType)
+	}
+}
@@ -19,18 +19,22 @@ package transformer
 
 import (
 	v1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	v1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // K8sArtifacts k8s artifact representation of API
 type K8sArtifacts struct {
-	APIName     string
-	APIUUID     string
-	Namespace   string
-	KongPlugins map[string]*v1.KongPlugin
-	Services    map[string]*corev1.Service
-	HTTPRoutes  map[string]*gwapiv1.HTTPRoute
+	APIName          string
+	APIUUID          string
+	Namespace        string
+	KongPlugins      map[string]*v1.KongPlugin
+	Services         map[string]*corev1.Service
+	HTTPRoutes       map[string]*gwapiv1.HTTPRoute
+	GRPCRoutes       map[string]*gwapiv1.GRPCRoute
+	Secrets          map[string]*corev1.Secret
+	UpstreamPolicies map[string]*v1beta1.KongUpstreamPolicy
 }
 
 // KongPluginConfig defines the type for config of a kong plugin
@@ -21,9 +21,12 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/wso2-extensions/apim-gw-connectors/common-agent/config"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/k8s-resource-lib/types"
 	"github.com/wso2-extensions/apim-gw-connectors/common-agent/pkg/loggers"
 	"github.com/wso2-extensions/apim-gw-connectors/kong/gateway-connector/constants"
@@ -111,6 +114,11 @@ func GenerateACLGroupName(apiName string, environment string) string {
 	return constants.APIPrefix + GenerateSHA1Hash(apiName) + constants.DashSeparatorString + environment
 }
 
+// GenerateEnvironmentACLGroupName generates the kong acl group name of the consumers of an environment
+func GenerateEnvironmentACLGroupName(environment string) string {
+	return constants.EnvironmentPrefix + strings.ToLower(environment)
+}
+
 // GenerateJSON converts go struct to json
 func GenerateJSON(data KongPluginConfig) []byte {
 	jsonBytes, err := json.Marshal(data)
@@ -142,6 +150,54 @@ func PrepareRateLimit(rateLimitConfig *KongPluginConfig, unit string, unitTime i
 	}
 }
 
+// GenerateGRPCMethodPath returns the request path Kong matches for a grpc operation (/<service>/<method>)
+func GenerateGRPCMethodPath(operation types.Operation) string {
+	return constants.SlashString + operation.Target + constants.SlashString + operation.Verb
+}
+
+// GetGRPCProtocol maps an endpoint url to the Kong upstream protocol for grpc services
+func GetGRPCProtocol(endpointURL string) string {
+	if strings.HasPrefix(endpointURL, constants.HTTPSProtocol+"://") || strings.HasPrefix(endpointURL, constants.GRPCSProtocol+"://") {
+		return constants.GRPCSProtocol
+	}
+	return constants.GRPCProtocol
+}
+
+// GetWSProtocol maps an endpoint url to the Kong upstream protocol for websocket services. The ws and wss protocols
+// are available in Kong Enterprise only, hence the upgrades are carried over http and https unless they are enabled.
+func GetWSProtocol(endpointURL string) string {
+	secure := strings.HasPrefix(endpointURL, constants.HTTPSProtocol+"://") || strings.HasPrefix(endpointURL, constants.WSSProtocol+"://")
+	if !IsWebSocketProtocolsEnabled() {
+		if secure {
+			return constants.HTTPSProtocol
+		}
+		return constants.HTTPProtocol
+	}
+	if secure {
+		return constants.WSSProtocol
+	}
+	return constants.WSProtocol
+}
+
+// IsWebSocketProtocolsEnabled reports whether the ws and wss protocols of Kong Enterprise are enabled
+func IsWebSocketProtocolsEnabled() bool {
+	return isGatewayAgentConfigEnabled(constants.WebSocketProtocolsEnabledConfig)
+}
+
+// isGatewayAgentConfigEnabled reports whether the boolean gateway agent configuration is enabled
+func isGatewayAgentConfigEnabled(key string) bool {
+	conf, err := config.ReadConfigs()
+	if err != nil || conf == nil {
+		return false
+	}
+	value := conf.GatewayAgent.Get(key)
+	if value == nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(fmt.Sprint(value))
+	return err == nil && enabled
+}
+
 // GenerateSHA1Hash returns the SHA1 hash for the given string
 func GenerateSHA1Hash(input string) string {
 	h := sha1.New()